@@ -1,5 +1,18 @@
 \connect api;
 
+-- Organization -----------------------------------------------------------------------------------------------------------------------------------
+-- DROP SEQUENCE IF EXISTS public.seq_usr_organization_id;
+CREATE SEQUENCE if not exists public.seq_usr_organization_id INCREMENT BY 1 MINVALUE 1 MAXVALUE 9223372036854775807 START 1 CACHE 1 NO CYCLE;
+
+-- DROP TABLE public.usr_organization;
+CREATE TABLE if not exists public.usr_organization (
+    id bigint PRIMARY KEY DEFAULT nextval('seq_usr_organization_id':: regclass) NOT NULL,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    updated_at timestamptz DEFAULT NOW() NOT NULL,
+    "name" varchar(100) NOT NULL,
+    CONSTRAINT uni_usr_organization UNIQUE ("name")
+);
+
 -- User Profile -------------------------------------------------------------------------------------------------------------------------------------
 -- DROP SEQUENCE IF EXISTS public.seq_usr_profile_id;
 CREATE SEQUENCE if not exists public.seq_usr_profile_id INCREMENT BY 1 MINVALUE 1 MAXVALUE 9223372036854775807 START 1 CACHE 1 NO CYCLE;
@@ -9,11 +22,21 @@ CREATE TABLE if not exists public.usr_profile (
     id bigint PRIMARY KEY DEFAULT nextval('seq_usr_profile_id':: regclass) NOT NULL,
     created_at timestamptz DEFAULT NOW() NOT NULL,
     updated_at timestamptz DEFAULT NOW() NOT NULL,
+    organization_id bigint NULL,
     "name" varchar(100) NOT NULL,
     permissions text [ ] NOT NULL,
-    CONSTRAINT uni_usr_profile UNIQUE ("name")
+    require_2fa bool DEFAULT false NOT NULL,
+    require_verified_email bool DEFAULT false NOT NULL,
+    password_policy jsonb NULL,
+    deleted_at timestamptz NULL,
+    "version" bigint DEFAULT 1 NOT NULL,
+    CONSTRAINT fk_usr_profile_organization FOREIGN KEY (organization_id) REFERENCES public.usr_organization (id)
 );
 
+-- Names are unique within an organization, and among the profiles of super-admins, out of the trash
+CREATE UNIQUE INDEX if not exists uni_usr_profile ON public.usr_profile USING btree (organization_id, "name") NULLS NOT DISTINCT WHERE deleted_at IS NULL;
+CREATE INDEX if not exists idx_usr_profile_deleted_at ON public.usr_profile USING btree (deleted_at);
+
 INSERT INTO
     public.usr_profile (id, "name", permissions)
 VALUES
@@ -31,22 +54,25 @@ CREATE TABLE if not exists public.usr_auth (
     created_at timestamptz DEFAULT NOW() NOT NULL,
     updated_at timestamptz DEFAULT NOW() NOT NULL,
     "status" bool NOT NULL,
+    pending bool DEFAULT false NOT NULL,
     profile_id bigint NOT NULL,
-    token varchar(255) NULL,
     "password" varchar(255) NULL,
-    CONSTRAINT uni_usr_auth UNIQUE (token),
+    password_changed_at timestamptz NULL,
+    password_history text [ ] NULL,
+    totp_secret varchar(64) NULL,
+    totp_enabled bool DEFAULT false NOT NULL,
+    totp_last_step bigint DEFAULT 0 NOT NULL,
+    recovery_codes text [ ] NULL,
     CONSTRAINT fk_usr_auth_profile FOREIGN KEY (profile_id) REFERENCES public.usr_profile (id)
 );
 
 CREATE INDEX if not exists idx_usr_auth_profile_id ON public.usr_auth USING btree (profile_id);
 
-CREATE INDEX if not exists idx_usr_auth_token ON public.usr_auth USING btree (token);
-
 -- Password: 12345678
 INSERT INTO
-    public.usr_auth (id, "status", profile_id, token, "password")
+    public.usr_auth (id, "status", profile_id, "password")
 VALUES
-    (1, true, 1, 'd048aee9-dd65-4ca0-aee7-230c1bf19d8c', '$2a$10$vqkyIvgHRU2sl2FGtlbkNeGFeTsJHQYz18abMJiLlGyJt.Ge99zYy');
+    (1, true, 1, '$2a$10$vqkyIvgHRU2sl2FGtlbkNeGFeTsJHQYz18abMJiLlGyJt.Ge99zYy');
 
 ALTER SEQUENCE public.seq_usr_auth_id RESTART WITH 10;
 
@@ -59,18 +85,189 @@ CREATE TABLE if not exists public.usr_user (
     id bigint PRIMARY KEY DEFAULT nextval('seq_usr_user_id':: regclass) NOT NULL,
     created_at timestamptz DEFAULT NOW() NOT NULL,
     updated_at timestamptz DEFAULT NOW() NOT NULL,
+    organization_id bigint NULL,
     "name" varchar(255) NOT NULL,
     username varchar(255) NOT NULL,
     mail varchar(255) NOT NULL,
+    email_verified_at timestamptz NULL,
+    pending_email varchar(255) NULL,
     auth_id bigint NOT NULL,
+    deleted_at timestamptz NULL,
+    "version" bigint DEFAULT 1 NOT NULL,
     CONSTRAINT fk_usr_user_auth FOREIGN KEY (auth_id) REFERENCES public.usr_auth (id) ON DELETE CASCADE,
-    CONSTRAINT uni_usr_user UNIQUE (mail),
-    CONSTRAINT uni_usr_user_username UNIQUE (username)
+    CONSTRAINT fk_usr_user_organization FOREIGN KEY (organization_id) REFERENCES public.usr_organization (id)
 );
 
+-- Emails and usernames of users in the trash can be taken, which keeps them from being restored
+CREATE UNIQUE INDEX if not exists uni_usr_user ON public.usr_user USING btree (mail) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX if not exists uni_usr_user_username ON public.usr_user USING btree (username) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists idx_usr_user_organization_id ON public.usr_user USING btree (organization_id);
+CREATE INDEX if not exists idx_usr_user_deleted_at ON public.usr_user USING btree (deleted_at);
+
+-- Case-insensitive logins
+CREATE INDEX if not exists idx_usr_user_lower_username ON public.usr_user USING btree (LOWER(username));
+CREATE INDEX if not exists idx_usr_user_lower_mail ON public.usr_user USING btree (LOWER(mail));
+
 INSERT INTO
-    public.usr_user (id, auth_id, "name", mail, username)
+    public.usr_user (id, auth_id, "name", mail, username, email_verified_at)
 VALUES
-    (1, 1, 'Administrator', 'admin@admin.com', 'admin');
+    (1, 1, 'Administrator', 'admin@admin.com', 'admin', NOW());
+
+ALTER SEQUENCE public.seq_usr_user_id RESTART WITH 10;
+
+-- OAuth Client -------------------------------------------------------------------------------------------------------------------------------------
+-- DROP SEQUENCE IF EXISTS public.seq_usr_oauth_client_id;
+CREATE SEQUENCE if not exists public.seq_usr_oauth_client_id INCREMENT BY 1 MINVALUE 1 MAXVALUE 9223372036854775807 START 1 CACHE 1 NO CYCLE;
+
+-- DROP TABLE public.usr_oauth_client;
+CREATE TABLE if not exists public.usr_oauth_client (
+    id bigint PRIMARY KEY DEFAULT nextval('seq_usr_oauth_client_id':: regclass) NOT NULL,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    updated_at timestamptz DEFAULT NOW() NOT NULL,
+    client_id varchar(36) NOT NULL,
+    name varchar(100) NOT NULL,
+    secret_hash varchar(64) NULL,
+    redirect_uris text[] NOT NULL,
+    scopes text[] NOT NULL,
+    grant_types text[] NOT NULL,
+    CONSTRAINT uni_usr_oauth_client_client_id UNIQUE (client_id)
+);
+
+-- User Session -------------------------------------------------------------------------------------------------------------------------------------
+-- DROP TABLE public.usr_session;
+CREATE TABLE if not exists public.usr_session (
+    id varchar(36) PRIMARY KEY NOT NULL,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    updated_at timestamptz DEFAULT NOW() NOT NULL,
+    user_id bigint NOT NULL,
+    refresh_token_id varchar(36) NOT NULL,
+    user_agent varchar(255) NULL,
+    ip varchar(45) NULL,
+    client_id varchar(36) NULL,
+    scopes text[] NULL,
+    actor_id bigint NULL,
+    last_active_at timestamptz NULL,
+    expires_at timestamptz NULL,
+    revoked_at timestamptz NULL,
+    CONSTRAINT fk_usr_session_user FOREIGN KEY (user_id) REFERENCES public.usr_user (id) ON DELETE CASCADE,
+    CONSTRAINT fk_usr_session_actor FOREIGN KEY (actor_id) REFERENCES public.usr_user (id) ON DELETE CASCADE,
+    CONSTRAINT fk_usr_session_oauth_client FOREIGN KEY (client_id) REFERENCES public.usr_oauth_client (client_id) ON DELETE CASCADE
+);
+
+CREATE INDEX if not exists idx_usr_session_user_id ON public.usr_session USING btree (user_id);
+
+-- User Token ---------------------------------------------------------------------------------------------------------------------------------------
+-- DROP SEQUENCE IF EXISTS public.seq_usr_token_id;
+CREATE SEQUENCE if not exists public.seq_usr_token_id INCREMENT BY 1 MINVALUE 1 MAXVALUE 9223372036854775807 START 1 CACHE 1 NO CYCLE;
+
+-- DROP TABLE public.usr_token;
+CREATE TABLE if not exists public.usr_token (
+    id bigint PRIMARY KEY DEFAULT nextval('seq_usr_token_id':: regclass) NOT NULL,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    user_id bigint NOT NULL,
+    purpose varchar(50) NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    expires_at timestamptz NOT NULL,
+    used_at timestamptz NULL,
+    CONSTRAINT fk_usr_token_user FOREIGN KEY (user_id) REFERENCES public.usr_user (id) ON DELETE CASCADE,
+    CONSTRAINT uni_usr_token_hash UNIQUE (token_hash)
+);
+
+CREATE INDEX if not exists idx_usr_token_user_id ON public.usr_token USING btree (user_id);
+
+-- Login Attempt ------------------------------------------------------------------------------------------------------------------------------------
+-- DROP SEQUENCE IF EXISTS public.seq_usr_login_attempt_id;
+CREATE SEQUENCE if not exists public.seq_usr_login_attempt_id INCREMENT BY 1 MINVALUE 1 MAXVALUE 9223372036854775807 START 1 CACHE 1 NO CYCLE;
+
+-- DROP TABLE public.usr_login_attempt;
+CREATE TABLE if not exists public.usr_login_attempt (
+    id bigint PRIMARY KEY DEFAULT nextval('seq_usr_login_attempt_id':: regclass) NOT NULL,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    user_id bigint NULL,
+    login varchar(255) NOT NULL,
+    ip varchar(45) NULL,
+    user_agent varchar(255) NULL,
+    success bool NOT NULL,
+    reason varchar(50) NULL,
+    CONSTRAINT fk_usr_login_attempt_user FOREIGN KEY (user_id) REFERENCES public.usr_user (id) ON DELETE SET NULL
+);
+
+CREATE INDEX if not exists idx_usr_login_attempt_user_id ON public.usr_login_attempt USING btree (user_id);
+CREATE INDEX if not exists idx_usr_login_attempt_created_at ON public.usr_login_attempt USING btree (created_at);
+
+-- API Key ------------------------------------------------------------------------------------------------------------------------------------------
+-- DROP SEQUENCE IF EXISTS public.seq_usr_api_key_id;
+CREATE SEQUENCE if not exists public.seq_usr_api_key_id INCREMENT BY 1 MINVALUE 1 MAXVALUE 9223372036854775807 START 1 CACHE 1 NO CYCLE;
+
+-- DROP TABLE public.usr_api_key;
+CREATE TABLE if not exists public.usr_api_key (
+    id bigint PRIMARY KEY DEFAULT nextval('seq_usr_api_key_id':: regclass) NOT NULL,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    user_id bigint NOT NULL,
+    name varchar(100) NOT NULL,
+    prefix varchar(12) NOT NULL,
+    key_hash varchar(64) NOT NULL,
+    scopes text[] NOT NULL,
+    expires_at timestamptz NULL,
+    last_used_at timestamptz NULL,
+    revoked_at timestamptz NULL,
+    CONSTRAINT fk_usr_api_key_user FOREIGN KEY (user_id) REFERENCES public.usr_user (id) ON DELETE CASCADE,
+    CONSTRAINT uni_usr_api_key_hash UNIQUE (key_hash)
+);
+
+CREATE INDEX if not exists idx_usr_api_key_user_id ON public.usr_api_key USING btree (user_id);
+
+-- External Identity --------------------------------------------------------------------------------------------------------------------------------
+-- DROP SEQUENCE IF EXISTS public.seq_usr_external_identity_id;
+CREATE SEQUENCE if not exists public.seq_usr_external_identity_id INCREMENT BY 1 MINVALUE 1 MAXVALUE 9223372036854775807 START 1 CACHE 1 NO CYCLE;
+
+-- DROP TABLE public.usr_external_identity;
+CREATE TABLE if not exists public.usr_external_identity (
+    id bigint PRIMARY KEY DEFAULT nextval('seq_usr_external_identity_id':: regclass) NOT NULL,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    user_id bigint NOT NULL,
+    provider varchar(50) NOT NULL,
+    subject varchar(255) NOT NULL,
+    email varchar(255) NULL,
+    CONSTRAINT fk_usr_external_identity_user FOREIGN KEY (user_id) REFERENCES public.usr_user (id) ON DELETE CASCADE,
+    CONSTRAINT uni_usr_external_identity_subject UNIQUE (provider, subject)
+);
+
+CREATE INDEX if not exists idx_usr_external_identity_user_id ON public.usr_external_identity USING btree (user_id);
+
+-- Audit --------------------------------------------------------------------------------------------------------------------------------------------
+-- DROP SEQUENCE IF EXISTS public.seq_usr_audit_id;
+CREATE SEQUENCE if not exists public.seq_usr_audit_id INCREMENT BY 1 MINVALUE 1 MAXVALUE 9223372036854775807 START 1 CACHE 1 NO CYCLE;
+
+-- Records outlive the users and entities they refer to, so they hold no foreign keys
+-- DROP TABLE public.usr_audit;
+CREATE TABLE if not exists public.usr_audit (
+    id bigint PRIMARY KEY DEFAULT nextval('seq_usr_audit_id':: regclass) NOT NULL,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    organization_id bigint NULL,
+    actor_id bigint NULL,
+    impersonator_id bigint NULL,
+    action varchar(50) NOT NULL,
+    entity_type varchar(50) NOT NULL,
+    entity_id varchar(100) NOT NULL,
+    changes jsonb DEFAULT '{}' NOT NULL,
+    request_id varchar(100) NULL,
+    ip varchar(45) NULL
+);
+
+CREATE INDEX if not exists idx_usr_audit_entity ON public.usr_audit USING btree (entity_type, entity_id);
+CREATE INDEX if not exists idx_usr_audit_actor_id ON public.usr_audit USING btree (actor_id);
+CREATE INDEX if not exists idx_usr_audit_organization_id ON public.usr_audit USING btree (organization_id);
+CREATE INDEX if not exists idx_usr_audit_request_id ON public.usr_audit USING btree (request_id);
+CREATE INDEX if not exists idx_usr_audit_created_at ON public.usr_audit USING btree (created_at);
+
+-- The audit log is append-only
+CREATE OR REPLACE FUNCTION public.usr_audit_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'usr_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
 
-ALTER SEQUENCE public.seq_usr_user_id RESTART WITH 10;
\ No newline at end of file
+DROP TRIGGER IF EXISTS trg_usr_audit_append_only ON public.usr_audit;
+CREATE TRIGGER trg_usr_audit_append_only BEFORE UPDATE OR DELETE ON public.usr_audit
+    FOR EACH ROW EXECUTE FUNCTION public.usr_audit_append_only();
@@ -53,6 +53,10 @@ func initLogger(cfg *config.Environment) *loggerx.Logger {
 // @in							header
 // @name						Authorization
 // @description 				Type "Bearer" followed by a space and the JWT token.
+// @securityDefinitions.apiKey	APIKey
+// @in							header
+// @name						X-API-Key
+// @description 				API key created at /auth/keys, limited to its scopes.
 func main() {
 	// Load configuration
 	cfg := config.MustLoad()
@@ -99,13 +103,13 @@ func main() {
 	// Create REST server using the Application
 	server := rest.NewServer(
 		rest.Config{
-			Port:              cfg.Port,
-			EnablePrefork:     cfg.EnablePrefork,
-			EnableLogger:      cfg.EnableLogger,
-			EnableSwagger:     cfg.EnableSwagger,
-			Version:           cfg.Version,
-			AccessPrivateKey:  cfg.AccessPrivateKey,
-			RefreshPrivateKey: cfg.RefreshPrivateKey,
+			Port:          cfg.Port,
+			EnablePrefork: cfg.EnablePrefork,
+			EnableLogger:  cfg.EnableLogger,
+			EnableSwagger: cfg.EnableSwagger,
+			Version:       cfg.Version,
+			AccessKeys:    container.AccessKeys,
+			RefreshKeys:   container.RefreshKeys,
 		},
 		application,
 		log,
@@ -140,6 +144,9 @@ func handleShutdown(log *loggerx.Logger, server *rest.Server, container *di.Cont
 	// Shutdown server
 	_ = server.Shutdown()
 
+	// Flush pending deliveries
+	_ = container.Close()
+
 	// Close database connection
 	if container.DB != nil {
 		if sqlDB, err := container.DB.DB(); err == nil {
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -34,10 +35,62 @@ type Environment struct {
 	EnablePrefork bool `env:"API_ENABLE_PREFORK" default:"1"`
 
 	// JWT
-	AccessPrivateKey  *rsa.PrivateKey `env:"ACCESS_TOKEN" default:"new"`
-	AccessExpiration  time.Duration   `env:"ACCESS_TOKEN_EXPIRE" default:"15m"`
-	RefreshPrivateKey *rsa.PrivateKey `env:"RFRESH_TOKEN" default:"new"`
-	RefreshExpiration time.Duration   `env:"RFRESH_TOKEN_EXPIRE" default:"60m"`
+	AccessPrivateKey   *rsa.PrivateKey   `env:"ACCESS_TOKEN" default:"new"`
+	AccessRetiredKeys  []*rsa.PrivateKey `env:"ACCESS_TOKEN_RETIRED" sep:","`
+	AccessExpiration   time.Duration     `env:"ACCESS_TOKEN_EXPIRE" default:"15m"`
+	RefreshPrivateKey  *rsa.PrivateKey   `env:"RFRESH_TOKEN" default:"new"`
+	RefreshRetiredKeys []*rsa.PrivateKey `env:"RFRESH_TOKEN_RETIRED" sep:","`
+	RefreshExpiration  time.Duration     `env:"RFRESH_TOKEN_EXPIRE" default:"60m"`
+	TokenIssuer        string            `env:"JWT_ISSUER" default:"go-api"`
+	TokenAudience      []string          `env:"JWT_AUDIENCE" default:"go-api" sep:","`
+	TokenLeeway        time.Duration     `env:"JWT_LEEWAY" default:"30s"`
+
+	// Account
+	PasswordResetExpiration      time.Duration `env:"PASSWORD_RESET_EXPIRE" default:"30m"`
+	InvitationExpiration         time.Duration `env:"INVITATION_EXPIRE" default:"72h"`
+	InvitationURL                string        `env:"INVITATION_URL" default:""`
+	EmailVerificationExpiration  time.Duration `env:"EMAIL_VERIFICATION_EXPIRE" default:"24h"`
+	TwoFactorChallengeExpiration time.Duration `env:"TWO_FACTOR_CHALLENGE_EXPIRE" default:"5m"`
+	ImpersonationExpiration      time.Duration `env:"IMPERSONATION_EXPIRE" default:"15m"`
+	SessionActivityFlush         time.Duration `env:"SESSION_ACTIVITY_FLUSH" default:"30s"`
+	DeletedRetention             time.Duration `env:"DELETED_RETENTION" default:"720h"`
+	PurgeInterval                time.Duration `env:"PURGE_INTERVAL" default:"1h"`
+	ImportMaxRows                int           `env:"IMPORT_MAX_ROWS" default:"1000"`
+
+	// Password policy, which profiles can override
+	PasswordMinLength     int  `env:"PASSWORD_MIN_LENGTH" default:"8"`
+	PasswordRequireUpper  bool `env:"PASSWORD_REQUIRE_UPPER" default:"0"`
+	PasswordRequireLower  bool `env:"PASSWORD_REQUIRE_LOWER" default:"0"`
+	PasswordRequireDigit  bool `env:"PASSWORD_REQUIRE_DIGIT" default:"0"`
+	PasswordRequireSymbol bool `env:"PASSWORD_REQUIRE_SYMBOL" default:"0"`
+	PasswordRejectCommon  bool `env:"PASSWORD_REJECT_COMMON" default:"1"`
+	PasswordRejectSimilar bool `env:"PASSWORD_REJECT_SIMILAR" default:"1"`
+	PasswordHistory       int  `env:"PASSWORD_HISTORY" default:"0"`
+	PasswordMaxAgeDays    int  `env:"PASSWORD_MAX_AGE_DAYS" default:"0"`
+
+	// Password hashing; hashes of the other algorithm or other parameters are upgraded on login
+	PasswordHash      string `env:"PASSWORD_HASH" default:"argon2id"`
+	Argon2Memory      uint32 `env:"ARGON2_MEMORY" default:"19456"`
+	Argon2Iterations  uint32 `env:"ARGON2_ITERATIONS" default:"2"`
+	Argon2Parallelism uint8  `env:"ARGON2_PARALLELISM" default:"1"`
+	BcryptCost        int    `env:"BCRYPT_COST" default:"10"`
+
+	// Login throttling
+	LoginMaxFailures      int           `env:"LOGIN_MAX_FAILURES" default:"5"`
+	LoginMaxFailuresPerIP int           `env:"LOGIN_MAX_FAILURES_IP" default:"20"`
+	LoginBackoff          time.Duration `env:"LOGIN_BACKOFF" default:"1s"`
+	LoginLockout          time.Duration `env:"LOGIN_LOCKOUT" default:"15m"`
+	LoginCaseInsensitive  bool          `env:"LOGIN_CASE_INSENSITIVE" default:"1"`
+
+	// OpenID Connect
+	OIDCProviderNames   []string                 `env:"OIDC_PROVIDERS" sep:","`
+	OIDCLoginExpiration time.Duration            `env:"OIDC_LOGIN_EXPIRE" default:"10m"`
+	OIDCProviders       map[string]*OIDCProvider // Loaded from OIDC_<NAME>_* for every name of OIDC_PROVIDERS
+
+	// OAuth 2.0 / OpenID Connect provider
+	OAuthIssuer         string        `env:"OAUTH_ISSUER" default:"http://localhost:${API_PORT}"`
+	OAuthLoginURL       string        `env:"OAUTH_LOGIN_URL" default:"http://localhost:3000/oauth/login"`
+	OAuthCodeExpiration time.Duration `env:"OAUTH_CODE_EXPIRE" default:"1m"`
 
 	// Database
 	PGHost     string `env:"POSTGRES_HOST" default:"postgres"`
@@ -63,6 +116,30 @@ type Environment struct {
 	RedisPort int    `env:"REDIS_PORT" default:"6379"`
 	RedisPass string `env:"REDIS_PASS" default:""`
 	RedisDB   int    `env:"REDIS_DB" default:"0"`
+
+	// Mail
+	MailDriver   string `env:"MAIL_DRIVER" default:"file"`
+	MailHost     string `env:"MAIL_HOST" default:"localhost"`
+	MailPort     int    `env:"MAIL_PORT" default:"587"`
+	MailUser     string `env:"MAIL_USER" default:""`
+	MailPassword string `env:"MAIL_PASS" default:""`
+	MailFrom     string `env:"MAIL_FROM" default:"no-reply@localhost"`
+	MailDir      string `env:"MAIL_DIR" default:"/tmp/mail"`
+	MailLanguage string `env:"MAIL_LANGUAGE" default:"en-US"`
+	MailRetries  int    `env:"MAIL_RETRIES" default:"5"`
+}
+
+// OIDCProvider holds the registration of the API as a client of an OpenID Connect provider
+type OIDCProvider struct {
+	Issuer         string   `env:"ISSUER" required:"true"`
+	ClientID       string   `env:"CLIENT_ID" required:"true"`
+	ClientSecret   string   `env:"CLIENT_SECRET"`
+	RedirectURL    string   `env:"REDIRECT_URL" required:"true"`
+	Scopes         []string `env:"SCOPES" default:"openid,email,profile" sep:","`
+	DefaultProfile uint     `env:"DEFAULT_PROFILE" default:"0"`
+	UsernameClaim  string   `env:"USERNAME_CLAIM" default:"preferred_username"`
+	NameClaim      string   `env:"NAME_CLAIM" default:"name"`
+	EmailClaim     string   `env:"EMAIL_CLAIM" default:"email"`
 }
 
 func init() {
@@ -110,6 +187,17 @@ func load() (*Environment, error) {
 		os.Exit(1)
 	}
 
+	env.OIDCProviders = make(map[string]*OIDCProvider, len(env.OIDCProviderNames))
+	for _, name := range env.OIDCProviderNames {
+		provider := &OIDCProvider{}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		if err := envx.LoadWithPrefix(provider, prefix); err != nil {
+			fmt.Printf("Failed to parse OIDC provider %q: %v\n", name, err)
+			os.Exit(1)
+		}
+		env.OIDCProviders[name] = provider
+	}
+
 	time.Local = env.Timezone
 
 	return env, nil
@@ -24,9 +24,60 @@ API_ACCEPT_SKIP_AUTH='1'                        # API accept skip auth header
 
 ACCESS_TOKEN_EXPIRE='50m'                       # Access token expiration (m=min, s=seg, h=hour, default=50m)
 RFRESH_TOKEN_EXPIRE='3h'                        # Refresh token expiration (m=min, s=seg, h=hour, default=3h)
+JWT_ISSUER='go-api'                             # Issuer (iss) of every token
+JWT_AUDIENCE='go-api'                           # Audiences (aud) of access tokens, comma separated, the first one identifies this API
+JWT_LEEWAY='30s'                                # Clock skew tolerated when validating tokens (default=30s)
+PASSWORD_RESET_EXPIRE='30m'                     # Password reset token expiration (m=min, s=seg, h=hour, default=30m)
+INVITATION_EXPIRE='72h'                         # Invitation expiration (m=min, s=seg, h=hour, default=72h)
+INVITATION_URL=''                               # Page accepting invitations, linked in invitation emails with a "token" query parameter
+EMAIL_VERIFICATION_EXPIRE='24h'                 # Email verification token expiration (m=min, s=seg, h=hour, default=24h)
+TWO_FACTOR_CHALLENGE_EXPIRE='5m'                # Time to enter the 2FA code after the password (m=min, s=seg, h=hour, default=5m)
+IMPERSONATION_EXPIRE='15m'                      # Lifetime of tokens of root users acting as another user (m=min, s=seg, h=hour, default=15m)
+SESSION_ACTIVITY_FLUSH='30s'                    # How often the last activity of sessions is saved, in a single write (default=30s)
+DELETED_RETENTION='720h'                        # Time deleted users and profiles can be restored before being purged (default=720h)
+PURGE_INTERVAL='1h'                             # How often users and profiles past the retention are purged (default=1h)
+IMPORT_MAX_ROWS='1000'                          # Maximum rows of a user import, headers excluded, 0 for no limit (default=1000)
+
+PASSWORD_MIN_LENGTH='8'                         # Minimum password length, never below 6
+PASSWORD_REQUIRE_UPPER='0'                      # Passwords must have an uppercase letter
+PASSWORD_REQUIRE_LOWER='0'                      # Passwords must have a lowercase letter
+PASSWORD_REQUIRE_DIGIT='0'                      # Passwords must have a digit
+PASSWORD_REQUIRE_SYMBOL='0'                     # Passwords must have a symbol
+PASSWORD_REJECT_COMMON='1'                      # Reject common and breached passwords
+PASSWORD_REJECT_SIMILAR='1'                     # Reject passwords resembling the username or email
+PASSWORD_HISTORY='0'                            # Previous passwords that cannot be reused
+PASSWORD_MAX_AGE_DAYS='0'                       # Days before a password must be changed at login (0=never)
+
+PASSWORD_HASH='argon2id'                        # Password hashing algorithm (argon2id, bcrypt), older hashes are upgraded on login
+ARGON2_MEMORY='19456'                           # Argon2id memory in KiB (default=19456)
+ARGON2_ITERATIONS='2'                           # Argon2id iterations (default=2)
+ARGON2_PARALLELISM='1'                          # Argon2id parallelism (default=1)
+BCRYPT_COST='10'                                # Bcrypt cost (default=10)
+
+LOGIN_MAX_FAILURES='5'                          # Failed logins per account before a lockout
+LOGIN_MAX_FAILURES_IP='20'                      # Failed logins per IP before a lockout
+LOGIN_BACKOFF='1s'                              # Wait after the first failed login, doubled on each failure (default=1s)
+LOGIN_LOCKOUT='15m'                             # Lockout duration, also the time failures are remembered (default=15m)
+LOGIN_CASE_INSENSITIVE='1'                      # Match the username or email of logins regardless of case
+
+OIDC_PROVIDERS=''                               # OpenID Connect providers users can log in with, comma separated (e.g. corp)
+OIDC_LOGIN_EXPIRE='10m'                         # Time to log in at a provider (m=min, s=seg, h=hour, default=10m)
+# OIDC_CORP_ISSUER='https://idp.example.com'    # Issuer of the provider named corp
+# OIDC_CORP_CLIENT_ID='go-api'                  # Client ID registered at the provider
+# OIDC_CORP_CLIENT_SECRET=''                    # Client secret registered at the provider - PRIVATE TOKEN
+# OIDC_CORP_REDIRECT_URL='http://localhost:9999/auth/oidc/corp/callback' # Redirect URL registered at the provider
+# OIDC_CORP_SCOPES='openid,email,profile'       # Requested scopes
+# OIDC_CORP_DEFAULT_PROFILE='0'                 # Profile of users created on their first login, 0 only lets existing users in
+# OIDC_CORP_USERNAME_CLAIM='preferred_username' # Claims mapped to the user (also NAME_CLAIM and EMAIL_CLAIM)
+
+OAUTH_ISSUER='http://localhost:9999'            # Public URL of the API, issuer of the ID tokens it signs
+OAUTH_LOGIN_URL='http://localhost:3000/oauth/login' # Page where users log in to authorize OAuth clients
+OAUTH_CODE_EXPIRE='1m'                          # Time to redeem an authorization code (m=min, s=seg, h=hour, default=1m)
 
 ACCESS_TOKEN='${access_token}'                  # Token to encode access token - PRIVATE TOKEN
 RFRESH_TOKEN='${refresh_token}'                 # Token to encode refresh token - PRIVATE TOKEN
+ACCESS_TOKEN_RETIRED=''                         # Previous access tokens, comma separated, still accepted after a rotation - PRIVATE TOKEN
+RFRESH_TOKEN_RETIRED=''                         # Previous refresh tokens, comma separated, still accepted after a rotation - PRIVATE TOKEN
 
 POSTGRES_HOST='postgres'                        # Postgres Container HOST
 POSTGRES_PORT='5432'                            # Postgres Container PORT
@@ -39,4 +90,14 @@ MINIO_API_PORT='9004'                           # Minio API PORT
 MINIO_WEB_PORT='9005'                           # Minio WEB PORT
 MINIO_USER='minio'                              # Minio USER
 MINIO_PASS='miniopass'                          # Minio PASS
-MINIO_BUCKET_FILES='api'                        # Minio BUCKET" >.env
+MINIO_BUCKET_FILES='api'                        # Minio BUCKET
+
+MAIL_DRIVER='file'                              # Mail driver (smtp, file or memory)
+MAIL_HOST='localhost'                           # SMTP HOST
+MAIL_PORT='587'                                 # SMTP PORT
+MAIL_USER=''                                    # SMTP USER
+MAIL_PASS=''                                    # SMTP PASS
+MAIL_FROM='no-reply@localhost'                  # Mail sender address
+MAIL_DIR='/tmp/mail'                            # Directory for the file mail driver
+MAIL_LANGUAGE='en-US'                           # Mail language (en-US or pt-BR)
+MAIL_RETRIES='5'                                # Mail delivery attempts" >.env
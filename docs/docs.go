@@ -42,11 +42,187 @@ const docTemplate = `{
                 }
             }
         },
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Public keys verifying access tokens, selected by the token's kid header. Retired keys are listed until every token they signed expires.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "JSON Web Key Set",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_jwtx.JWKS"
+                        }
+                    }
+                }
+            }
+        },
+        "/.well-known/openid-configuration": {
+            "get": {
+                "description": "Metadata of the OAuth 2.0 / OpenID Connect provider: its endpoints and supported features",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth"
+                ],
+                "summary": "OpenID Connect discovery",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_oidc.Metadata"
+                        }
+                    }
+                }
+            }
+        },
+        "/audit": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Get the audit log of the changes made to users, profiles and sessions, most recent first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Get audit records",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "user",
+                            "profile",
+                            "session"
+                        ],
+                        "type": "string",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.PaginatedOutput-github_com_raulaguila_go-api_internal_core_dto_AuditOutput"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/auth": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "APIKey": []
                     }
                 ],
                 "description": "User authenticated",
@@ -159,7 +335,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "User authentication",
+                "description": "Authenticate with a username or email and a password. Unknown users get the same answer as wrong passwords.",
                 "consumes": [
                     "application/json"
                 ],
@@ -194,7 +370,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Tokens, or a challenge token when 2FA or a password change is required",
                         "schema": {
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.AuthOutput"
                         }
@@ -205,49 +381,27 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "429": {
+                        "description": "Too many failed attempts, see the Retry-After header",
                         "schema": {
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
-                    }
-                }
-            }
-        },
-        "/health": {
-            "get": {
-                "description": "Returns detailed health status including database and storage checks",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Health"
-                ],
-                "summary": "Detailed Health Check",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/internal_adapter_driver_rest_handler.HealthStatus"
-                        }
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_adapter_driver_rest_handler.HealthStatus"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
                     }
                 }
-            }
-        },
-        "/profile": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get profiles",
+                "description": "Revoke the current session",
                 "consumes": [
                     "application/json"
                 ],
@@ -255,19 +409,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Auth"
                 ],
-                "summary": "Get profiles",
+                "summary": "User logout",
                 "parameters": [
                     {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
+                        "type": "string",
+                        "description": "User token",
+                        "name": "Authorization",
                         "in": "header"
                     },
                     {
@@ -280,53 +429,19 @@ const docTemplate = `{
                         "description": "Request language",
                         "name": "Accept-Language",
                         "in": "header"
-                    },
-                    {
-                        "type": "integer",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "name": "list_root",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "name": "with_permissions",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.PaginatedOutput-github_com_raulaguila_go-api_internal_core_dto_ProfileOutput"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
                     },
                     "500": {
@@ -336,14 +451,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/2fa": {
             "post": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Insert profile",
+                "description": "Complete a login that requires a second factor, using a TOTP or recovery code",
                 "consumes": [
                     "application/json"
                 ],
@@ -351,21 +463,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Auth"
                 ],
-                "summary": "Insert profile",
+                "summary": "Two-factor authentication",
                 "parameters": [
-                    {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
-                        "in": "header"
-                    },
                     {
                         "enum": [
                             "en-US",
@@ -378,20 +479,20 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "Profile model",
-                        "name": "profile",
+                        "description": "Challenge token and code",
+                        "name": "code",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ProfileInput"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.TwoFactorInput"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ProfileOutput"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.AuthOutput"
                         }
                     },
                     "400": {
@@ -400,8 +501,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, see the Retry-After header",
                         "schema": {
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
@@ -420,7 +527,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Delete profiles by ID",
+                "description": "Turn 2FA off, confirming with a TOTP or recovery code",
                 "consumes": [
                     "application/json"
                 ],
@@ -428,19 +535,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Auth"
                 ],
-                "summary": "Delete profiles by ID",
+                "summary": "Disable 2FA",
                 "parameters": [
                     {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
+                        "type": "string",
+                        "description": "User token",
+                        "name": "Authorization",
                         "in": "header"
                     },
                     {
@@ -455,24 +557,36 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "Profiles ID",
-                        "name": "ids",
+                        "description": "TOTP or recovery code",
+                        "name": "code",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.IDsInput"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.TwoFactorCodeInput"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
@@ -486,14 +600,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/profile/list": {
-            "get": {
+        "/auth/2fa/setup": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "List profiles",
+                "description": "Activate 2FA with a code of the authenticator app and get the recovery codes",
                 "consumes": [
                     "application/json"
                 ],
@@ -501,19 +615,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Auth"
                 ],
-                "summary": "List profiles",
+                "summary": "Confirm 2FA enrollment",
                 "parameters": [
                     {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
+                        "type": "string",
+                        "description": "User token",
+                        "name": "Authorization",
                         "in": "header"
                     },
                     {
@@ -528,54 +637,32 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "type": "integer",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "name": "list_root",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "name": "with_permissions",
-                        "in": "query"
+                        "description": "TOTP code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.TwoFactorCodeInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ItemOutput"
-                            }
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.RecoveryCodesOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
                     },
                     "500": {
@@ -585,16 +672,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/profile/{id}": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update profile by ID",
+                "description": "Generate a TOTP secret to be registered in an authenticator app",
                 "consumes": [
                     "application/json"
                 ],
@@ -602,19 +687,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Auth"
                 ],
-                "summary": "Update profile by ID",
+                "summary": "Start 2FA enrollment",
                 "parameters": [
                     {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
+                        "type": "string",
+                        "description": "User token",
+                        "name": "Authorization",
                         "in": "header"
                     },
                     {
@@ -627,39 +707,23 @@ const docTemplate = `{
                         "description": "Request language",
                         "name": "Accept-Language",
                         "in": "header"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Profile ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Profile model",
-                        "name": "profile",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ProfileInput"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ProfileOutput"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.TwoFactorSetupOutput"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
@@ -673,14 +737,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
-            "get": {
+        "/auth/all": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get all users",
+                "description": "Revoke every session of the authenticated user",
                 "consumes": [
                     "application/json"
                 ],
@@ -688,19 +752,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Auth"
                 ],
-                "summary": "Get users",
+                "summary": "User logout from all devices",
                 "parameters": [
                     {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
+                        "type": "string",
+                        "description": "User token",
+                        "name": "Authorization",
                         "in": "header"
                     },
                     {
@@ -713,53 +772,19 @@ const docTemplate = `{
                         "description": "Request language",
                         "name": "Accept-Language",
                         "in": "header"
-                    },
-                    {
-                        "type": "integer",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "name": "profile_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "name": "status",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.PaginatedOutput-github_com_raulaguila_go-api_internal_core_dto_UserOutput"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
                     },
                     "500": {
@@ -769,14 +794,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/impersonate/{id}": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Insert user",
+                "description": "Get a short-lived access token acting as a user, with the user's permissions. Only root users can impersonate, and only read requests are accepted with the token.",
                 "consumes": [
                     "application/json"
                 ],
@@ -784,19 +811,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Auth"
                 ],
-                "summary": "Insert user",
+                "summary": "Impersonate user",
                 "parameters": [
                     {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
+                        "type": "string",
+                        "description": "User token",
+                        "name": "Authorization",
                         "in": "header"
                     },
                     {
@@ -811,20 +833,18 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "User model",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserInput"
-                        }
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Access token, without refresh token",
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserOutput"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.AuthOutput"
                         }
                     },
                     "400": {
@@ -833,8 +853,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
@@ -846,14 +878,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/keys": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete user by ID",
+                "description": "Get the API keys of the authenticated user",
                 "consumes": [
                     "application/json"
                 ],
@@ -861,21 +895,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Auth"
                 ],
-                "summary": "Delete user by ID",
+                "summary": "Get API keys",
                 "parameters": [
-                    {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
-                        "in": "header"
-                    },
                     {
                         "enum": [
                             "en-US",
@@ -886,23 +909,26 @@ const docTemplate = `{
                         "description": "Request language",
                         "name": "Accept-Language",
                         "in": "header"
-                    },
-                    {
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.IDsInput"
-                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.APIKeyOutput"
+                            }
+                        }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
@@ -914,16 +940,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/user/pass": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Set user password by ID",
+                "description": "Create an API key for the authenticated user, limited to scopes granted by the user's profile. The key is only returned once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -931,21 +955,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Auth"
                 ],
-                "summary": "Set user password by ID",
+                "summary": "Create API key",
                 "parameters": [
-                    {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
-                        "in": "header"
-                    },
                     {
                         "enum": [
                             "en-US",
@@ -958,29 +971,36 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "type": "string",
-                        "format": "email",
-                        "description": "User email",
-                        "name": "email",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "description": "Password model",
-                        "name": "password",
+                        "description": "API key model",
+                        "name": "key",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.PasswordInput"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.APIKeyInput"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.APIKeyCreatedOutput"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
                         }
@@ -992,14 +1012,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/keys/{id}": {
             "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Reset user password by ID",
+                "description": "Revoke an API key of the authenticated user",
                 "consumes": [
                     "application/json"
                 ],
@@ -1007,21 +1029,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Auth"
                 ],
-                "summary": "Reset user password by ID",
+                "summary": "Revoke API key",
                 "parameters": [
-                    {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
-                        "in": "header"
-                    },
                     {
                         "enum": [
                             "en-US",
@@ -1034,10 +1045,10 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "type": "string",
-                        "description": "User email",
-                        "name": "email",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -1045,6 +1056,24 @@ const docTemplate = `{
                     "200": {
                         "description": "OK"
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1060,14 +1089,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/{id}": {
-            "put": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
+        "/auth/oidc": {
+            "get": {
+                "description": "List the identity providers users can log in with",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Update user by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "External identity providers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}": {
+            "get": {
+                "description": "Redirect to the login page of an identity provider, which redirects back to the callback",
                 "consumes": [
                     "application/json"
                 ],
@@ -1075,21 +1125,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Auth"
                 ],
-                "summary": "Update user by ID",
+                "summary": "External login",
                 "parameters": [
-                    {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
-                        "in": "header"
-                    },
                     {
                         "enum": [
                             "en-US",
@@ -1102,34 +1141,22 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Identity provider",
+                        "name": "provider",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "User model",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserInput"
-                        }
+                        "type": "boolean",
+                        "description": "Expire token",
+                        "name": "expire",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserOutput"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
-                        }
+                    "302": {
+                        "description": "Found"
                     },
                     "404": {
                         "description": "Not Found",
@@ -1145,173 +1172,4839 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
+        },
+        "/auth/oidc/{provider}/callback": {
+            "get": {
+                "description": "Complete a login when the identity provider redirects back, linking or creating the user on the first login",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "External login callback",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identity provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "State",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tokens, or a challenge token when 2FA is required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.AuthOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password": {
+            "put": {
+                "description": "Replace an expired password using the challenge token returned by the login, then log in again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Change expired password",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Challenge token and new password",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ExpiredPasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the devices the authenticated user is signed in on, most recently active first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get sessions",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.SessionOutput"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sign the authenticated user out of a device",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Revoke session",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Returns detailed health status including database and storage checks",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Detailed Health Check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapter_driver_rest_handler.HealthStatus"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapter_driver_rest_handler.HealthStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/authorize": {
+            "get": {
+                "description": "Start an OAuth 2.0 authorization code flow: redirect to the login page, which calls the authorization endpoint with POST once the user logged in. Requests of unknown clients or redirect URIs are refused, other errors are redirected to the client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth"
+                ],
+                "summary": "Authorization endpoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "client_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "code_challenge",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "S256",
+                        "name": "code_challenge_method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "nonce",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "redirect_uri",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "code",
+                        "name": "response_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "openid profile",
+                        "description": "Space separated",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_oidc.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Authorize a client on behalf of the authenticated user and return where to redirect the browser, with an authorization code or an error",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth"
+                ],
+                "summary": "Authorize client",
+                "parameters": [
+                    {
+                        "description": "Authorization request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.AuthorizeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.AuthorizeOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_oidc.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/clients": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Get the registered OAuth clients",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth"
+                ],
+                "summary": "Get OAuth clients",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.OAuthClientOutput"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Register an OAuth client. Confidential clients get a secret, which is only returned once; public clients must use PKCE.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth"
+                ],
+                "summary": "Create OAuth client",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "OAuth client model",
+                        "name": "client",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.OAuthClientInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.OAuthClientCreatedOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/clients/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Delete an OAuth client, ending the sessions of its users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth"
+                ],
+                "summary": "Delete OAuth client",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "OAuth client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/revoke": {
+            "post": {
+                "description": "Revoke an access or refresh token issued to the client, ending its session. Unknown tokens are ignored.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth"
+                ],
+                "summary": "Revocation endpoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token",
+                        "name": "token",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "access_token",
+                            "refresh_token"
+                        ],
+                        "type": "string",
+                        "description": "Token type",
+                        "name": "token_type_hint",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client ID",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client secret",
+                        "name": "client_secret",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_oidc.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_oidc.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/token": {
+            "post": {
+                "description": "Exchange an authorization code, a refresh token or the client's credentials for tokens. Clients authenticate with HTTP Basic or the client_id and client_secret parameters.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth"
+                ],
+                "summary": "Token endpoint",
+                "parameters": [
+                    {
+                        "enum": [
+                            "authorization_code",
+                            "refresh_token",
+                            "client_credentials"
+                        ],
+                        "type": "string",
+                        "description": "Grant type",
+                        "name": "grant_type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Redirect URI of the authorization request",
+                        "name": "redirect_uri",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "PKCE code verifier",
+                        "name": "code_verifier",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Refresh token",
+                        "name": "refresh_token",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scopes of client credentials tokens",
+                        "name": "scope",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client ID",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client secret",
+                        "name": "client_secret",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_oidc.Tokens"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_oidc.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_oidc.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/userinfo": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Claims about the user of a token issued with the openid scope",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth"
+                ],
+                "summary": "UserInfo endpoint",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserInfoOutput"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_oidc.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_oidc.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Get every organization hosted by the deployment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Get organizations",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.OrganizationOutput"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Insert an organization, a new tenant",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Insert organization",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Organization model",
+                        "name": "organization",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.OrganizationInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.OrganizationOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Get organization by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Get organization by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.OrganizationOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Update organization by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Update organization by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Organization model",
+                        "name": "organization",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.OrganizationInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.OrganizationOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Delete an organization, which must have no users or profiles left",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Delete organization by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Get profiles",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Get profiles",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "only",
+                            "include"
+                        ],
+                        "type": "string",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "list_root",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only narrows the profiles seen by super-admins",
+                        "name": "organization_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "with_permissions",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.PaginatedOutput-github_com_raulaguila_go-api_internal_core_dto_ProfileOutput"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Insert profile",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Insert profile",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Profile model",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ProfileInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ProfileOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Delete profiles by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Delete profiles by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Profiles ID",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.IDsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/list": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "List profiles",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "List profiles",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "only",
+                            "include"
+                        ],
+                        "type": "string",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "list_root",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only narrows the profiles seen by super-admins",
+                        "name": "organization_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "with_permissions",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ItemOutput"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Take deleted profiles out of the trash",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Restore profiles by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Profiles ID",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.IDsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Get profile by ID, with its version in the ETag header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Get profile by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ProfileOutput"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Profile version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Update profile by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Update profile by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the profile read, refusing the update if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Profile model",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ProfileInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ProfileOutput"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Profile version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Partially update profile by ID with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902) of its document",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Patch profile by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the profile read, refusing the update if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch of the profile document",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ProfileDocument"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ProfileOutput"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Profile version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Get all users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get users",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "only",
+                            "include"
+                        ],
+                        "type": "string",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only narrows the users seen by super-admins",
+                        "name": "organization_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "profile_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.PaginatedOutput-github_com_raulaguila_go-api_internal_core_dto_UserOutput"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Insert user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Insert user",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "User model",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Delete user by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Delete user by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.IDsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/email": {
+            "put": {
+                "description": "Confirm an email with a verification token, applying a pending email change",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Verify email",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Verification token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.EmailVerificationInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Send a single-use verification token to the email awaiting verification of the account, if any",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Request email verification",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Account email",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.EmailInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/import": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Create users from the rows of a CSV or XLSX file, whose first row holds the column headers, and send them the welcome message to set their password. Invalid rows and rows of existing users are reported instead; a dry run only reports them. Files with more rows than IMPORT_MAX_ROWS are refused.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Import users",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV or XLSX file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "name",
+                        "description": "Header of the names",
+                        "name": "name_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": "username",
+                        "description": "Header of the usernames",
+                        "name": "username_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": "email",
+                        "description": "Header of the emails",
+                        "name": "email_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": "profile_id",
+                        "description": "Header of the profile IDs",
+                        "name": "profile_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Profile of the users whose row names none",
+                        "name": "profile_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the rows",
+                        "name": "dry_run",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Create pending users and send them invitations",
+                        "name": "invite",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserImportOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/invite": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Get the invitations not accepted yet, the ones expiring first first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get invitations",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.InvitationOutput"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Accept an invitation by setting the first password with the invitation token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Accept invitation",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Password model",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.PasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Create a pending user and email an expiring invitation, accepted by setting the first password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Invite user",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "User model",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/invite/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Email a new invitation to a pending user, invalidating the previous one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Resend invitation",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Withdraw the invitation of a pending user, deleting the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Revoke invitation",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/pass": {
+            "put": {
+                "description": "Set user password using a password reset token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Set user password",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Password model",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.PasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Send a single-use password reset token to the user, if the account exists",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Request password reset",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Account email",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.PasswordResetInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Send a single-use password reset token to the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Reset user password",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User email",
+                        "name": "email",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Take deleted users out of the trash; users whose profile is deleted are restored after it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Restore users by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.IDsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Get user by ID, with its version in the ETag header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserOutput"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "User version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Update user by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update user by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the user read, refusing the update if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User model",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserOutput"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "User version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Partially update user by ID with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902) of its document",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Patch user by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the user read, refusing the update if it changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch of the user document",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserDocument"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserOutput"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "User version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/lock": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Clear the failed logins of a user, lifting a lockout",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Unlock user by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Get the devices the user is signed in on, most recently active first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user sessions by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.SessionOutput"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Sign the user out of every device",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Revoke user sessions by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/sessions/{session}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Sign the user out of a single device",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Revoke user session by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            true,
+                            false
+                        ],
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Skip auth",
+                        "name": "X-Skip-Auth",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "session",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
         "github_com_raulaguila_go-api_internal_adapter_driver_rest_presenter.Response": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer",
-                    "example": 400
+                "code": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "message": {
+                    "type": "string",
+                    "example": "status bad request"
+                },
+                "object": {}
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.APIKeyCreatedOutput": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.APIKeyInput": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "deploy script"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "users:view"
+                    ]
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.APIKeyOutput": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.AuditChangeOutput": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {}
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.AuditOutput": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "integer"
+                },
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.AuditChangeOutput"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "entity_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "impersonator_id": {
+                    "description": "Set when the actor was impersonated",
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.AuthOutput": {
+            "type": "object",
+            "properties": {
+                "accesstoken": {
+                    "type": "string"
+                },
+                "challenge_token": {
+                    "type": "string"
+                },
+                "password_change_required": {
+                    "type": "boolean"
+                },
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "refreshtoken": {
+                    "type": "string"
+                },
+                "two_factor_required": {
+                    "type": "boolean"
+                },
+                "two_factor_setup": {
+                    "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.TwoFactorSetupOutput"
+                },
+                "user": {
+                    "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserOutput"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.AuthorizeInput": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "code_challenge": {
+                    "type": "string"
+                },
+                "code_challenge_method": {
+                    "type": "string",
+                    "example": "S256"
+                },
+                "nonce": {
+                    "type": "string"
+                },
+                "redirect_uri": {
+                    "type": "string"
+                },
+                "response_type": {
+                    "type": "string",
+                    "example": "code"
+                },
+                "scope": {
+                    "description": "Space separated",
+                    "type": "string",
+                    "example": "openid profile"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.AuthorizeOutput": {
+            "type": "object",
+            "properties": {
+                "redirect_uri": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.EmailInput": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.EmailVerificationInput": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.ExpiredPasswordInput": {
+            "type": "object",
+            "required": [
+                "challenge_token",
+                "password",
+                "password_confirm"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "password_confirm": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.IDsInput": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.InvitationOutput": {
+            "type": "object",
+            "properties": {
+                "expired": {
+                    "description": "Expired invitations must be resent before they can be accepted",
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "invited_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserOutput"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.ItemOutput": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.LoginInput": {
+            "type": "object",
+            "required": [
+                "login",
+                "password"
+            ],
+            "properties": {
+                "expiration": {
+                    "type": "boolean"
+                },
+                "login": {
+                    "description": "Username or email",
+                    "type": "string",
+                    "example": "admin"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.OAuthClientCreatedOutput": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "confidential": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "grant_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "redirect_uris": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.OAuthClientInput": {
+            "type": "object",
+            "required": [
+                "grant_types",
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "confidential": {
+                    "description": "Whether the client can keep a secret, e.g. a server-side app",
+                    "type": "boolean"
                 },
-                "message": {
+                "grant_types": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "authorization_code",
+                        "refresh_token"
+                    ]
+                },
+                "name": {
                     "type": "string",
-                    "example": "status bad request"
+                    "example": "reports"
                 },
-                "object": {}
+                "redirect_uris": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "https://reports.example.com/callback"
+                    ]
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "openid",
+                        "profile",
+                        "email",
+                        "users:read"
+                    ]
+                }
             }
         },
-        "github_com_raulaguila_go-api_internal_core_dto.AuthOutput": {
+        "github_com_raulaguila_go-api_internal_core_dto.OAuthClientOutput": {
             "type": "object",
             "properties": {
-                "accesstoken": {
+                "client_id": {
                     "type": "string"
                 },
-                "refreshtoken": {
+                "confidential": {
+                    "type": "boolean"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserOutput"
+                "grant_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "redirect_uris": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "github_com_raulaguila_go-api_internal_core_dto.IDsInput": {
+        "github_com_raulaguila_go-api_internal_core_dto.OrganizationInput": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "ACME"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.OrganizationOutput": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.PaginatedOutput-github_com_raulaguila_go-api_internal_core_dto_AuditOutput": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.AuditOutput"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.PaginationOutput"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.PaginatedOutput-github_com_raulaguila_go-api_internal_core_dto_ProfileOutput": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ProfileOutput"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.PaginationOutput"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.PaginatedOutput-github_com_raulaguila_go-api_internal_core_dto_UserOutput": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserOutput"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.PaginationOutput"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.PaginationOutput": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.PasswordInput": {
             "type": "object",
             "required": [
-                "ids"
+                "password",
+                "password_confirm",
+                "token"
             ],
             "properties": {
-                "ids": {
+                "password": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "minLength": 6
+                },
+                "password_confirm": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.PasswordResetInput": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.ProfileDocument": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "password_policy": {
+                    "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_passwd.Policy"
+                },
+                "permissions": {
                     "type": "array",
-                    "minItems": 1,
                     "items": {
-                        "type": "integer"
+                        "type": "string"
                     }
+                },
+                "require_2fa": {
+                    "type": "boolean"
+                },
+                "require_verified_email": {
+                    "type": "boolean"
                 }
             }
         },
-        "github_com_raulaguila_go-api_internal_core_dto.ItemOutput": {
+        "github_com_raulaguila_go-api_internal_core_dto.ProfileInput": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 4
+                },
+                "organization_id": {
+                    "description": "Organization of a new profile, chosen by super-admins only",
+                    "type": "integer"
+                },
+                "password_policy": {
+                    "description": "Overrides the environment's policy for the profile's users",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_passwd.Policy"
+                        }
+                    ]
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "require_2fa": {
+                    "type": "boolean"
+                },
+                "require_verified_email": {
+                    "description": "Users must verify their email before logging in",
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.ProfileOutput": {
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "description": "Only set for profiles in the trash",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "password_policy": {
+                    "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_passwd.Policy"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "require_2fa": {
+                    "type": "boolean"
+                },
+                "require_verified_email": {
+                    "type": "boolean"
+                },
+                "version": {
+                    "description": "Also sent as the ETag header",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.RecoveryCodesOutput": {
             "type": "object",
             "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_core_dto.SessionOutput": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "description": "OAuth client the session was opened for",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "description": "Session of the request",
+                    "type": "boolean"
+                },
+                "device": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
                 "id": {
+                    "type": "string"
+                },
+                "impersonated_by": {
+                    "description": "Actor of an impersonation session",
                     "type": "integer"
                 },
-                "name": {
+                "ip": {
+                    "type": "string"
+                },
+                "last_active_at": {
+                    "type": "string"
+                },
+                "user_agent": {
                     "type": "string"
                 }
             }
         },
-        "github_com_raulaguila_go-api_internal_core_dto.LoginInput": {
+        "github_com_raulaguila_go-api_internal_core_dto.TwoFactorCodeInput": {
             "type": "object",
             "required": [
-                "login",
-                "password"
+                "code"
             ],
             "properties": {
-                "expiration": {
-                    "type": "boolean"
-                },
-                "login": {
-                    "type": "string"
-                },
-                "password": {
+                "code": {
                     "type": "string"
                 }
             }
         },
-        "github_com_raulaguila_go-api_internal_core_dto.PaginatedOutput-github_com_raulaguila_go-api_internal_core_dto_ProfileOutput": {
+        "github_com_raulaguila_go-api_internal_core_dto.TwoFactorInput": {
             "type": "object",
+            "required": [
+                "challenge_token",
+                "code"
+            ],
             "properties": {
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ProfileOutput"
-                    }
+                "challenge_token": {
+                    "type": "string"
                 },
-                "pagination": {
-                    "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.PaginationOutput"
+                "code": {
+                    "description": "TOTP or recovery code",
+                    "type": "string"
                 }
             }
         },
-        "github_com_raulaguila_go-api_internal_core_dto.PaginatedOutput-github_com_raulaguila_go-api_internal_core_dto_UserOutput": {
+        "github_com_raulaguila_go-api_internal_core_dto.TwoFactorSetupOutput": {
             "type": "object",
             "properties": {
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserOutput"
-                    }
+                "qr_code": {
+                    "description": "PNG image of the URI, as a data URI",
+                    "type": "string"
                 },
-                "pagination": {
-                    "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.PaginationOutput"
+                "secret": {
+                    "type": "string"
+                },
+                "uri": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_raulaguila_go-api_internal_core_dto.PaginationOutput": {
+        "github_com_raulaguila_go-api_internal_core_dto.UserDocument": {
             "type": "object",
             "properties": {
-                "limit": {
-                    "type": "integer"
+                "email": {
+                    "description": "Changes are pending until the user confirms the new address",
+                    "type": "string"
                 },
-                "page": {
-                    "type": "integer"
+                "name": {
+                    "type": "string"
                 },
-                "total_items": {
+                "profile_id": {
                     "type": "integer"
                 },
-                "total_pages": {
-                    "type": "integer"
+                "status": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_raulaguila_go-api_internal_core_dto.PasswordInput": {
+        "github_com_raulaguila_go-api_internal_core_dto.UserImportOutput": {
             "type": "object",
-            "required": [
-                "password",
-                "password_confirm"
-            ],
             "properties": {
-                "password": {
-                    "type": "string",
-                    "maxLength": 128,
-                    "minLength": 6
+                "created": {
+                    "description": "Users created, or that would be in a dry run",
+                    "type": "integer"
                 },
-                "password_confirm": {
-                    "type": "string"
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "description": "Rows that are invalid",
+                    "type": "integer"
+                },
+                "rows": {
+                    "description": "Skipped and failed rows, with the reasons why",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserImportRowOutput"
+                    }
+                },
+                "skipped": {
+                    "description": "Rows of users that already exist",
+                    "type": "integer"
                 }
             }
         },
-        "github_com_raulaguila_go-api_internal_core_dto.ProfileInput": {
+        "github_com_raulaguila_go-api_internal_core_dto.UserImportRowOutput": {
             "type": "object",
             "properties": {
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 4
-                },
-                "permissions": {
+                "errors": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
+                },
+                "row": {
+                    "description": "Line of the row in the spreadsheet, the headers being line 1",
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "boolean"
                 }
             }
         },
-        "github_com_raulaguila_go-api_internal_core_dto.ProfileOutput": {
+        "github_com_raulaguila_go-api_internal_core_dto.UserInfoOutput": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "type": "boolean"
                 },
                 "name": {
                     "type": "string"
                 },
-                "permissions": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "preferred_username": {
+                    "type": "string"
+                },
+                "sub": {
+                    "type": "string"
                 }
             }
         },
@@ -1327,6 +6020,7 @@ const docTemplate = `{
                     "minLength": 5
                 },
                 "profile_id": {
+                    "description": "Users join the organization of their profile",
                     "type": "integer",
                     "minimum": 1
                 },
@@ -1346,23 +6040,233 @@ const docTemplate = `{
                 "corp_id": {
                     "type": "string"
                 },
+                "deleted_at": {
+                    "description": "Only set for users in the trash",
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
+                "email_verified_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "impersonated_by": {
+                    "description": "Actor of an impersonation, who sees the API as the user",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.UserOutput"
+                        }
+                    ]
+                },
                 "name": {
                     "type": "string"
                 },
                 "new": {
                     "type": "boolean"
                 },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "pending": {
+                    "description": "Invited and not accepted yet",
+                    "type": "boolean"
+                },
+                "pending_email": {
+                    "description": "Applied once the user confirms it",
+                    "type": "string"
+                },
                 "profile": {
                     "$ref": "#/definitions/github_com_raulaguila_go-api_internal_core_dto.ProfileOutput"
                 },
                 "status": {
                     "type": "boolean"
+                },
+                "version": {
+                    "description": "Also sent as the ETag header",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_pkg_jwtx.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string",
+                    "example": "RS256"
+                },
+                "e": {
+                    "type": "string",
+                    "example": "AQAB"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string",
+                    "example": "RSA"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string",
+                    "example": "sig"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_pkg_jwtx.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_jwtx.JWK"
+                    }
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_pkg_oidc.Error": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "error_description": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_pkg_oidc.Metadata": {
+            "type": "object",
+            "properties": {
+                "authorization_endpoint": {
+                    "type": "string"
+                },
+                "claims_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "code_challenge_methods_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "grant_types_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id_token_signing_alg_values_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "jwks_uri": {
+                    "type": "string"
+                },
+                "response_types_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "revocation_endpoint": {
+                    "type": "string"
+                },
+                "scopes_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "subject_types_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token_endpoint": {
+                    "type": "string"
+                },
+                "token_endpoint_auth_methods_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "userinfo_endpoint": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_pkg_oidc.Tokens": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "id_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_pkg_passwd.Policy": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "description": "Previous passwords that cannot be reused",
+                    "type": "integer"
+                },
+                "max_age_days": {
+                    "description": "Days before a password must be changed, zero never expires",
+                    "type": "integer"
+                },
+                "min_length": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "reject_common": {
+                    "description": "Reject passwords of the embedded denylist",
+                    "type": "boolean"
+                },
+                "reject_similar": {
+                    "description": "Reject passwords containing the username or email, or contained in them",
+                    "type": "boolean"
+                },
+                "require_digit": {
+                    "type": "boolean"
+                },
+                "require_lower": {
+                    "type": "boolean"
+                },
+                "require_symbol": {
+                    "type": "boolean"
+                },
+                "require_upper": {
+                    "type": "boolean"
                 }
             }
         },
@@ -1405,6 +6309,12 @@ const docTemplate = `{
         }
     },
     "securityDefinitions": {
+        "APIKey": {
+            "description": "API key created at /auth/keys, limited to its scopes.",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
         "Bearer": {
             "description": "Type \"Bearer\" followed by a space and the JWT token.",
             "type": "apiKey",
@@ -0,0 +1,70 @@
+// Package identity provides implementations of the IdentityProvider output port.
+package identity
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/pkg/oidc"
+)
+
+// ClaimMapping names the ID token claims holding the user's attributes
+type ClaimMapping struct {
+	Username string
+	Name     string
+	Email    string
+}
+
+// DefaultClaimMapping maps the standard OpenID Connect claims
+var DefaultClaimMapping = ClaimMapping{
+	Username: "preferred_username",
+	Name:     "name",
+	Email:    "email",
+}
+
+// oidcProvider implements the IdentityProvider interface for an OpenID Connect provider
+type oidcProvider struct {
+	client *oidc.Client
+	claims ClaimMapping
+}
+
+// NewOIDCProvider creates a new IdentityProvider logging users in with OpenID Connect.
+// Empty names of the claim mapping fall back to the standard claims.
+func NewOIDCProvider(client *oidc.Client, claims ClaimMapping) output.IdentityProvider {
+	if claims.Username == "" {
+		claims.Username = DefaultClaimMapping.Username
+	}
+	if claims.Name == "" {
+		claims.Name = DefaultClaimMapping.Name
+	}
+	if claims.Email == "" {
+		claims.Email = DefaultClaimMapping.Email
+	}
+	return &oidcProvider{client: client, claims: claims}
+}
+
+// AuthCodeURL returns the URL of the provider's login page for a new login
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	return p.client.AuthCodeURL(ctx, state, nonce, codeVerifier)
+}
+
+// Exchange redeems the authorization code and returns the claims of the verified ID token
+func (p *oidcProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*output.ExternalClaims, error) {
+	tokens, err := p.client.Exchange(ctx, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := p.client.VerifyIDToken(ctx, tokens.IDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output.ExternalClaims{
+		Subject:       claims.Subject,
+		Email:         claims.String(p.claims.Email),
+		EmailVerified: claims.Bool("email_verified"),
+		Name:          claims.String(p.claims.Name),
+		Username:      claims.String(p.claims.Username),
+	}, nil
+}
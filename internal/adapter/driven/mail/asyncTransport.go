@@ -0,0 +1,172 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raulaguila/go-api/pkg/loggerx"
+)
+
+var (
+	// ErrQueueFull is returned when the delivery queue cannot take more messages
+	ErrQueueFull = errors.New("mail queue is full")
+	// ErrClosed is returned when delivering after Close
+	ErrClosed = errors.New("mail transport is closed")
+)
+
+// AsyncOption configures an AsyncTransport
+type AsyncOption func(*AsyncTransport)
+
+// WithQueueSize sets how many messages can wait for delivery
+func WithQueueSize(size int) AsyncOption {
+	return func(t *AsyncTransport) {
+		if size > 0 {
+			t.queueSize = size
+		}
+	}
+}
+
+// WithWorkers sets the number of concurrent deliveries
+func WithWorkers(n int) AsyncOption {
+	return func(t *AsyncTransport) {
+		if n > 0 {
+			t.workers = n
+		}
+	}
+}
+
+// WithRetries sets how many times a failed delivery is attempted
+func WithRetries(n int) AsyncOption {
+	return func(t *AsyncTransport) {
+		if n > 0 {
+			t.attempts = n
+		}
+	}
+}
+
+// WithBackoff sets the wait before the first retry, doubled on each new attempt
+func WithBackoff(d time.Duration) AsyncOption {
+	return func(t *AsyncTransport) {
+		if d > 0 {
+			t.backoff = d
+		}
+	}
+}
+
+// AsyncTransport queues messages and delivers them in background workers,
+// retrying failed deliveries with exponential backoff. Deliver never blocks.
+type AsyncTransport struct {
+	next      Transport
+	log       *loggerx.Logger
+	queue     chan *Message
+	queueSize int
+	workers   int
+	attempts  int
+	backoff   time.Duration
+	mu        sync.RWMutex
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// NewAsyncTransport creates a new AsyncTransport delivering through next
+func NewAsyncTransport(next Transport, log *loggerx.Logger, opts ...AsyncOption) *AsyncTransport {
+	t := &AsyncTransport{
+		next:      next,
+		log:       log,
+		queueSize: 100,
+		workers:   2,
+		attempts:  5,
+		backoff:   time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	t.queue = make(chan *Message, t.queueSize)
+	for range t.workers {
+		t.wg.Add(1)
+		go t.worker()
+	}
+
+	return t
+}
+
+// Deliver queues the message for delivery
+func (t *AsyncTransport) Deliver(_ context.Context, msg *Message) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.closed {
+		return ErrClosed
+	}
+
+	select {
+	case t.queue <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting messages and waits for the queued ones to be delivered
+func (t *AsyncTransport) Close() error {
+	t.mu.Lock()
+	if !t.closed {
+		t.closed = true
+		close(t.queue)
+	}
+	t.mu.Unlock()
+
+	t.wg.Wait()
+	return nil
+}
+
+// worker delivers queued messages until the queue is closed
+func (t *AsyncTransport) worker() {
+	defer t.wg.Done()
+
+	for msg := range t.queue {
+		t.deliver(msg)
+	}
+}
+
+// deliver attempts to deliver a message, waiting between failed attempts
+func (t *AsyncTransport) deliver(msg *Message) {
+	wait := t.backoff
+	for attempt := 1; ; attempt++ {
+		// The request context is gone by now, deliveries run on their own
+		err := t.next.Deliver(context.Background(), msg)
+		if err == nil {
+			return
+		}
+
+		if attempt >= t.attempts {
+			if t.log != nil {
+				t.log.Error("Mail delivery failed",
+					slog.String("to", strings.Join(msg.To, ", ")),
+					slog.String("subject", msg.Subject),
+					slog.Int("attempts", attempt),
+					slog.String("error", err.Error()),
+				)
+			}
+			return
+		}
+
+		if t.log != nil {
+			t.log.Warn("Mail delivery failed, retrying",
+				slog.String("to", strings.Join(msg.To, ", ")),
+				slog.Int("attempt", attempt),
+				slog.Duration("retry_in", wait),
+				slog.String("error", err.Error()),
+			)
+		}
+
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
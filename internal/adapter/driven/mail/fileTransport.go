@@ -0,0 +1,35 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fileTransport writes messages as .eml files, for development
+type fileTransport struct {
+	dir string
+}
+
+// NewFileTransport creates a new Transport that writes each message to dir
+func NewFileTransport(dir string) (Transport, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &fileTransport{dir: dir}, nil
+}
+
+// Deliver writes the message to a new file
+func (t *fileTransport) Deliver(_ context.Context, msg *Message) error {
+	body, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405"), uuid.NewString())
+	return os.WriteFile(filepath.Join(t.dir, name), body, 0o640)
+}
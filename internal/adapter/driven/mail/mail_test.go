@@ -0,0 +1,131 @@
+package mail_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/config"
+	"github.com/raulaguila/go-api/internal/adapter/driven/mail"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// flakyTransport fails the first deliveries before delegating
+type flakyTransport struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	next     mail.Transport
+}
+
+func (t *flakyTransport) Deliver(ctx context.Context, msg *mail.Message) error {
+	t.mu.Lock()
+	t.calls++
+	fail := t.calls <= t.failures
+	t.mu.Unlock()
+
+	if fail {
+		return errors.New("connection refused")
+	}
+	return t.next.Deliver(ctx, msg)
+}
+
+func TestRenderer_Localized(t *testing.T) {
+	renderer, err := mail.NewRenderer(config.Locales, "en-US")
+	require.NoError(t, err)
+
+	data := map[string]any{"App": "API", "Name": "John", "Username": "john", "Token": "secret-token", "ExpiresAt": "2030-01-01 00:00 UTC"}
+
+	en, err := renderer.Render("passwordReset", "", data)
+	require.NoError(t, err)
+	assert.Equal(t, "Password reset", en.Subject)
+	assert.Contains(t, en.Text, "Hello John,")
+	assert.Contains(t, en.Text, "secret-token")
+	assert.Contains(t, en.HTML, "<html>")
+
+	pt, err := renderer.Render("passwordReset", "pt-BR", data)
+	require.NoError(t, err)
+	assert.Equal(t, "Redefinição de senha", pt.Subject)
+	assert.Contains(t, pt.Text, "Olá John,")
+}
+
+func TestRenderer_EscapesHTML(t *testing.T) {
+	renderer, err := mail.NewRenderer(config.Locales, "en-US")
+	require.NoError(t, err)
+
+	out, err := renderer.Render("welcome", "", map[string]any{"Name": "<script>", "Token": "<b>"})
+	require.NoError(t, err)
+	assert.NotContains(t, out.HTML, "<script>")
+	assert.NotContains(t, out.HTML, "<b>")
+}
+
+func TestRenderer_InvitationLink(t *testing.T) {
+	renderer, err := mail.NewRenderer(config.Locales, "en-US")
+	require.NoError(t, err)
+
+	data := map[string]any{"App": "API", "Name": "John", "Username": "john", "Token": "secret-token", "ExpiresAt": "2030-01-01 00:00 UTC"}
+
+	out, err := renderer.Render("invitation", "", data)
+	require.NoError(t, err)
+	assert.Equal(t, "Invitation to API", out.Subject)
+	assert.Contains(t, out.Text, "secret-token", "invitations carry the bare token without a link")
+
+	data["Link"] = "https://app.example.com/invite?token=secret-token"
+	out, err = renderer.Render("invitation", "", data)
+	require.NoError(t, err)
+	assert.Contains(t, out.Text, "https://app.example.com/invite?token=secret-token")
+	assert.Contains(t, out.HTML, `href="https://app.example.com/invite?token=secret-token"`)
+}
+
+func TestMailer_SendsMultipartMessage(t *testing.T) {
+	transport := mail.NewMemoryTransport()
+	mailer := mail.NewMailer(transport, mail.MustNewRenderer(config.Locales, "en-US"), "no-reply@example.com")
+
+	err := mailer.Send(context.Background(), &output.MailMessage{
+		To:       []string{"john@example.com"},
+		Template: "accountDisabled",
+		Data:     map[string]any{"App": "API", "Name": "John"},
+	})
+	require.NoError(t, err)
+
+	messages := transport.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "no-reply@example.com", messages[0].From)
+	assert.Equal(t, "Your account was disabled", messages[0].Subject)
+
+	raw, err := messages[0].Bytes()
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "multipart/alternative")
+	assert.Equal(t, 2, strings.Count(string(raw), "Content-Transfer-Encoding: quoted-printable"))
+}
+
+func TestAsyncTransport_RetriesFailedDeliveries(t *testing.T) {
+	memory := mail.NewMemoryTransport()
+	flaky := &flakyTransport{failures: 2, next: memory}
+	async := mail.NewAsyncTransport(flaky, nil, mail.WithRetries(3), mail.WithBackoff(time.Millisecond))
+
+	require.NoError(t, async.Deliver(context.Background(), &mail.Message{To: []string{"john@example.com"}}))
+	require.NoError(t, async.Close())
+
+	assert.Equal(t, 3, flaky.calls)
+	assert.Len(t, memory.Messages(), 1)
+	assert.ErrorIs(t, async.Deliver(context.Background(), &mail.Message{}), mail.ErrClosed)
+}
+
+func TestAsyncTransport_GivesUpAfterRetries(t *testing.T) {
+	memory := mail.NewMemoryTransport()
+	flaky := &flakyTransport{failures: 10, next: memory}
+	async := mail.NewAsyncTransport(flaky, nil, mail.WithRetries(2), mail.WithBackoff(time.Millisecond))
+
+	require.NoError(t, async.Deliver(context.Background(), &mail.Message{To: []string{"john@example.com"}}))
+	require.NoError(t, async.Close())
+
+	assert.Equal(t, 2, flaky.calls)
+	assert.Empty(t, memory.Messages())
+}
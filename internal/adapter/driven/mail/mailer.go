@@ -0,0 +1,39 @@
+package mail
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// mailer implements the Mailer interface
+type mailer struct {
+	transport Transport
+	renderer  *Renderer
+	from      string
+}
+
+// NewMailer creates a new Mailer that renders messages and hands them to transport
+func NewMailer(transport Transport, renderer *Renderer, from string) output.Mailer {
+	return &mailer{
+		transport: transport,
+		renderer:  renderer,
+		from:      from,
+	}
+}
+
+// Send renders the message template and delivers it
+func (m *mailer) Send(ctx context.Context, msg *output.MailMessage) error {
+	rendered, err := m.renderer.Render(msg.Template, msg.Language, msg.Data)
+	if err != nil {
+		return err
+	}
+
+	return m.transport.Deliver(ctx, &Message{
+		From:    m.from,
+		To:      msg.To,
+		Subject: rendered.Subject,
+		Text:    rendered.Text,
+		HTML:    rendered.HTML,
+	})
+}
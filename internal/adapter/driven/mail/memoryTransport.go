@@ -0,0 +1,42 @@
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryTransport keeps delivered messages in memory, for tests
+type MemoryTransport struct {
+	mu       sync.RWMutex
+	messages []Message
+}
+
+// NewMemoryTransport creates a new MemoryTransport
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{}
+}
+
+// Deliver stores the message
+func (t *MemoryTransport) Deliver(_ context.Context, msg *Message) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.messages = append(t.messages, *msg)
+	return nil
+}
+
+// Messages returns a copy of the delivered messages
+func (t *MemoryTransport) Messages() []Message {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return append([]Message(nil), t.messages...)
+}
+
+// Reset drops every delivered message
+func (t *MemoryTransport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.messages = nil
+}
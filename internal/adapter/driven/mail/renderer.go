@@ -0,0 +1,129 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed templates/*
+var templatesFS embed.FS
+
+// Rendered holds the localized parts of a message
+type Rendered struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Renderer renders mail templates localized with the application locale files.
+// Each template has a text and an HTML version, and its subject is the locale
+// message "mail<Template>Subject".
+type Renderer struct {
+	bundle          *i18n.Bundle
+	defaultLanguage string
+	text            *texttemplate.Template
+	html            *htmltemplate.Template
+}
+
+// NewRenderer creates a Renderer loading every *.yaml locale file found in locales
+func NewRenderer(locales fs.FS, defaultLanguage string) (*Renderer, error) {
+	tag, err := language.Parse(defaultLanguage)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default language: %w", err)
+	}
+
+	bundle := i18n.NewBundle(tag)
+	bundle.RegisterUnmarshalFunc("yaml", yaml.Unmarshal)
+
+	files, err := fs.Glob(locales, "locales/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if _, err := bundle.LoadMessageFileFS(locales, file); err != nil {
+			return nil, fmt.Errorf("failed to load locale %s: %w", file, err)
+		}
+	}
+
+	// Placeholder functions, replaced by the localizer of each render
+	funcs := map[string]any{"t": func(string, ...any) string { return "" }}
+
+	text, err := texttemplate.New("").Funcs(funcs).ParseFS(templatesFS, "templates/*.txt")
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := htmltemplate.New("").Funcs(funcs).ParseFS(templatesFS, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Renderer{
+		bundle:          bundle,
+		defaultLanguage: tag.String(),
+		text:            text,
+		html:            html,
+	}, nil
+}
+
+// MustNewRenderer creates a Renderer and panics on error
+func MustNewRenderer(locales fs.FS, defaultLanguage string) *Renderer {
+	r, err := NewRenderer(locales, defaultLanguage)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Render renders the named template in the given language
+func (r *Renderer) Render(name, lang string, data map[string]any) (*Rendered, error) {
+	if name == "" {
+		return nil, fmt.Errorf("mail template name is required")
+	}
+
+	localizer := i18n.NewLocalizer(r.bundle, lang, r.defaultLanguage)
+	localize := func(id string, _ ...any) string {
+		msg, err := localizer.Localize(&i18n.LocalizeConfig{MessageID: id, TemplateData: data})
+		if err != nil {
+			return id
+		}
+		return msg
+	}
+
+	subject, err := localizer.Localize(&i18n.LocalizeConfig{
+		MessageID:    "mail" + strings.ToUpper(name[:1]) + name[1:] + "Subject",
+		TemplateData: data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := r.text.Clone()
+	if err != nil {
+		return nil, err
+	}
+	var textBuf bytes.Buffer
+	if err := text.Funcs(map[string]any{"t": localize}).ExecuteTemplate(&textBuf, name+".txt", data); err != nil {
+		return nil, err
+	}
+
+	html, err := r.html.Clone()
+	if err != nil {
+		return nil, err
+	}
+	var htmlBuf bytes.Buffer
+	if err := html.Funcs(map[string]any{"t": localize}).ExecuteTemplate(&htmlBuf, name+".html", data); err != nil {
+		return nil, err
+	}
+
+	return &Rendered{Subject: subject, Text: textBuf.String(), HTML: htmlBuf.String()}, nil
+}
@@ -0,0 +1,43 @@
+package mail
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"strconv"
+)
+
+// SMTPConfig holds SMTP server configuration
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+}
+
+// smtpTransport delivers messages through an SMTP server
+type smtpTransport struct {
+	cfg SMTPConfig
+}
+
+// NewSMTPTransport creates a new Transport backed by an SMTP server.
+// STARTTLS is used whenever the server supports it.
+func NewSMTPTransport(cfg SMTPConfig) Transport {
+	return &smtpTransport{cfg: cfg}
+}
+
+// Deliver sends a message through the SMTP server
+func (t *smtpTransport) Deliver(_ context.Context, msg *Message) error {
+	body, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if t.cfg.User != "" {
+		auth = smtp.PlainAuth("", t.cfg.User, t.cfg.Password, t.cfg.Host)
+	}
+
+	addr := net.JoinHostPort(t.cfg.Host, strconv.Itoa(t.cfg.Port))
+	return smtp.SendMail(addr, auth, msg.From, msg.To, body)
+}
@@ -0,0 +1,113 @@
+// Package notification provides implementations of the Notifier output port.
+package notification
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// Mail templates, see internal/adapter/driven/mail/templates
+const (
+	templateWelcome         = "welcome"
+	templateInvitation      = "invitation"
+	templatePasswordReset   = "passwordReset"
+	templateAccountDisabled = "accountDisabled"
+	templateEmailVerify     = "emailVerification"
+	templateEmailChange     = "emailChangeRequested"
+)
+
+// expirationLayout formats token expirations in messages
+const expirationLayout = "2006-01-02 15:04 MST"
+
+// mailNotifier implements the Notifier interface by sending emails
+type mailNotifier struct {
+	mailer        output.Mailer
+	appName       string
+	invitationURL string
+}
+
+// NewMailNotifier creates a new Notifier that sends emails through mailer. Invitations link to
+// invitationURL with the token in the "token" query parameter, or carry the bare token if it is empty.
+func NewMailNotifier(mailer output.Mailer, appName, invitationURL string) output.Notifier {
+	return &mailNotifier{
+		mailer:        mailer,
+		appName:       appName,
+		invitationURL: invitationURL,
+	}
+}
+
+// SendWelcome sends the account creation email
+func (n *mailNotifier) SendWelcome(ctx context.Context, user *entity.User, token string, expiresAt time.Time) error {
+	return n.send(ctx, user, templateWelcome, map[string]any{
+		"Token":     token,
+		"ExpiresAt": expiresAt.Format(expirationLayout),
+	})
+}
+
+// SendInvitation sends the invitation email, linking to the page that accepts it
+func (n *mailNotifier) SendInvitation(ctx context.Context, user *entity.User, token string, expiresAt time.Time) error {
+	data := map[string]any{
+		"Token":     token,
+		"ExpiresAt": expiresAt.Format(expirationLayout),
+	}
+	if link, err := url.Parse(n.invitationURL); err == nil && n.invitationURL != "" {
+		query := link.Query()
+		query.Set("token", token)
+		link.RawQuery = query.Encode()
+		data["Link"] = link.String()
+	}
+	return n.send(ctx, user, templateInvitation, data)
+}
+
+// SendPasswordReset sends the password reset email
+func (n *mailNotifier) SendPasswordReset(ctx context.Context, user *entity.User, token string, expiresAt time.Time) error {
+	return n.send(ctx, user, templatePasswordReset, map[string]any{
+		"Token":     token,
+		"ExpiresAt": expiresAt.Format(expirationLayout),
+	})
+}
+
+// SendEmailVerification sends the email verification email to the address being verified
+func (n *mailNotifier) SendEmailVerification(ctx context.Context, user *entity.User, email, token string, expiresAt time.Time) error {
+	return n.sendTo(ctx, email, user, templateEmailVerify, map[string]any{
+		"Token":     token,
+		"ExpiresAt": expiresAt.Format(expirationLayout),
+	})
+}
+
+// SendEmailChangeRequested sends the email change warning to the current address
+func (n *mailNotifier) SendEmailChangeRequested(ctx context.Context, user *entity.User, newEmail string) error {
+	return n.send(ctx, user, templateEmailChange, map[string]any{
+		"NewEmail": newEmail,
+	})
+}
+
+// SendAccountDisabled sends the account disabled email
+func (n *mailNotifier) SendAccountDisabled(ctx context.Context, user *entity.User) error {
+	return n.send(ctx, user, templateAccountDisabled, nil)
+}
+
+// send fills the data common to every template and sends the email to the user's current address
+func (n *mailNotifier) send(ctx context.Context, user *entity.User, template string, data map[string]any) error {
+	return n.sendTo(ctx, user.Email, user, template, data)
+}
+
+// sendTo fills the data common to every template and sends the email to an address of the user
+func (n *mailNotifier) sendTo(ctx context.Context, to string, user *entity.User, template string, data map[string]any) error {
+	if data == nil {
+		data = make(map[string]any)
+	}
+	data["App"] = n.appName
+	data["Name"] = user.Name
+	data["Username"] = user.Username
+
+	return n.mailer.Send(ctx, &output.MailMessage{
+		To:       []string{to},
+		Template: template,
+		Data:     data,
+	})
+}
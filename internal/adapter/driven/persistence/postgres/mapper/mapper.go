@@ -1,8 +1,14 @@
 package mapper
 
 import (
+	"time"
+
+	"gorm.io/gorm"
+
 	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/model"
 	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/pkg/passwd"
+	"github.com/raulaguila/go-api/pkg/utils"
 )
 
 // MapSlice is a generic helper to map slices
@@ -14,17 +20,39 @@ func MapSlice[T any, U any](items []T, fn func(T) U) []U {
 	return result
 }
 
+// deletedAtToModel converts the deletion time of an entity to a soft delete column
+func deletedAtToModel(t *time.Time) gorm.DeletedAt {
+	if t == nil {
+		return gorm.DeletedAt{}
+	}
+	return gorm.DeletedAt{Time: *t, Valid: true}
+}
+
+// deletedAtToEntity converts a soft delete column to the deletion time of an entity
+func deletedAtToEntity(d gorm.DeletedAt) *time.Time {
+	if !d.Valid {
+		return nil
+	}
+	return &d.Time
+}
+
 // ProfileToModel converts a Profile entity to a ProfileModel
 func ProfileToModel(e *entity.Profile) *model.ProfileModel {
 	if e == nil {
 		return nil
 	}
 	return &model.ProfileModel{
-		ID:          e.ID,
-		Name:        e.Name,
-		Permissions: e.Permissions,
-		CreatedAt:   e.CreatedAt,
-		UpdatedAt:   e.UpdatedAt,
+		ID:                   e.ID,
+		OrganizationID:       e.OrganizationID,
+		Name:                 e.Name,
+		Permissions:          e.Permissions,
+		RequireTwoFactor:     e.RequireTwoFactor,
+		RequireVerifiedEmail: e.RequireVerifiedEmail,
+		PasswordPolicy:       (*model.PasswordPolicy)(e.PasswordPolicy),
+		Version:              e.Version,
+		CreatedAt:            e.CreatedAt,
+		UpdatedAt:            e.UpdatedAt,
+		DeletedAt:            deletedAtToModel(e.DeletedAt),
 	}
 }
 
@@ -34,11 +62,17 @@ func ProfileToEntity(m *model.ProfileModel) *entity.Profile {
 		return nil
 	}
 	return &entity.Profile{
-		ID:          m.ID,
-		Name:        m.Name,
-		Permissions: m.Permissions,
-		CreatedAt:   m.CreatedAt,
-		UpdatedAt:   m.UpdatedAt,
+		ID:                   m.ID,
+		OrganizationID:       m.OrganizationID,
+		Name:                 m.Name,
+		Permissions:          m.Permissions,
+		RequireTwoFactor:     m.RequireTwoFactor,
+		RequireVerifiedEmail: m.RequireVerifiedEmail,
+		PasswordPolicy:       (*passwd.Policy)(m.PasswordPolicy),
+		Version:              m.Version,
+		CreatedAt:            m.CreatedAt,
+		UpdatedAt:            m.UpdatedAt,
+		DeletedAt:            deletedAtToEntity(m.DeletedAt),
 	}
 }
 
@@ -48,14 +82,20 @@ func AuthToModel(e *entity.Auth) *model.AuthModel {
 		return nil
 	}
 	return &model.AuthModel{
-		ID:        e.ID,
-		Status:    e.Status,
-		ProfileID: e.ProfileID,
-		Profile:   ProfileToModel(e.Profile),
-		Token:     e.Token,
-		Password:  e.Password,
-		CreatedAt: e.CreatedAt,
-		UpdatedAt: e.UpdatedAt,
+		ID:                e.ID,
+		Status:            e.Status,
+		Pending:           e.Pending,
+		ProfileID:         e.ProfileID,
+		Profile:           ProfileToModel(e.Profile),
+		Password:          e.Password,
+		PasswordChangedAt: e.PasswordChangedAt,
+		PasswordHistory:   e.PasswordHistory,
+		TOTPSecret:        e.TOTPSecret,
+		TOTPEnabled:       e.TOTPEnabled,
+		TOTPLastStep:      e.TOTPLastStep,
+		RecoveryCodes:     e.RecoveryCodes,
+		CreatedAt:         e.CreatedAt,
+		UpdatedAt:         e.UpdatedAt,
 	}
 }
 
@@ -65,14 +105,20 @@ func AuthToEntity(m *model.AuthModel) *entity.Auth {
 		return nil
 	}
 	return &entity.Auth{
-		ID:        m.ID,
-		Status:    m.Status,
-		ProfileID: m.ProfileID,
-		Profile:   ProfileToEntity(m.Profile),
-		Token:     m.Token,
-		Password:  m.Password,
-		CreatedAt: m.CreatedAt,
-		UpdatedAt: m.UpdatedAt,
+		ID:                m.ID,
+		Status:            m.Status,
+		Pending:           m.Pending,
+		ProfileID:         m.ProfileID,
+		Profile:           ProfileToEntity(m.Profile),
+		Password:          m.Password,
+		PasswordChangedAt: m.PasswordChangedAt,
+		PasswordHistory:   m.PasswordHistory,
+		TOTPSecret:        m.TOTPSecret,
+		TOTPEnabled:       m.TOTPEnabled,
+		TOTPLastStep:      m.TOTPLastStep,
+		RecoveryCodes:     m.RecoveryCodes,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
 	}
 }
 
@@ -82,34 +128,316 @@ func UserToModel(e *entity.User) *model.UserModel {
 		return nil
 	}
 	return &model.UserModel{
+		ID:              e.ID,
+		OrganizationID:  e.OrganizationID,
+		Name:            e.Name,
+		Username:        e.Username,
+		Email:           e.Email,
+		AuthID:          e.AuthID,
+		Auth:            AuthToModel(e.Auth),
+		EmailVerifiedAt: e.EmailVerifiedAt,
+		PendingEmail:    e.PendingEmail,
+		Version:         e.Version,
+		CreatedAt:       e.CreatedAt,
+		UpdatedAt:       e.UpdatedAt,
+		DeletedAt:       deletedAtToModel(e.DeletedAt),
+	}
+}
+
+// UserToEntity converts a UserModel to a User entity
+func UserToEntity(m *model.UserModel) *entity.User {
+	if m == nil {
+		return nil
+	}
+	return &entity.User{
+		ID:              m.ID,
+		OrganizationID:  m.OrganizationID,
+		Name:            m.Name,
+		Username:        m.Username,
+		Email:           m.Email,
+		AuthID:          m.AuthID,
+		Auth:            AuthToEntity(m.Auth),
+		EmailVerifiedAt: m.EmailVerifiedAt,
+		PendingEmail:    m.PendingEmail,
+		Version:         m.Version,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+		DeletedAt:       deletedAtToEntity(m.DeletedAt),
+	}
+}
+
+// SessionToModel converts a Session entity to a SessionModel
+func SessionToModel(e *entity.Session) *model.SessionModel {
+	if e == nil {
+		return nil
+	}
+	return &model.SessionModel{
+		ID:             e.ID,
+		UserID:         e.UserID,
+		RefreshTokenID: e.RefreshTokenID,
+		UserAgent:      e.UserAgent,
+		IP:             e.IP,
+		ClientID:       nullableString(e.ClientID),
+		Scopes:         e.Scopes,
+		ActorID:        e.ActorID,
+		LastActiveAt:   e.LastActiveAt,
+		ExpiresAt:      e.ExpiresAt,
+		RevokedAt:      e.RevokedAt,
+		CreatedAt:      e.CreatedAt,
+		UpdatedAt:      e.UpdatedAt,
+	}
+}
+
+// SessionToEntity converts a SessionModel to a Session entity
+func SessionToEntity(m *model.SessionModel) *entity.Session {
+	if m == nil {
+		return nil
+	}
+	return &entity.Session{
+		ID:             m.ID,
+		UserID:         m.UserID,
+		RefreshTokenID: m.RefreshTokenID,
+		UserAgent:      m.UserAgent,
+		IP:             m.IP,
+		ClientID:       utils.Deref(m.ClientID, ""),
+		Scopes:         m.Scopes,
+		ActorID:        m.ActorID,
+		LastActiveAt:   m.LastActiveAt,
+		ExpiresAt:      m.ExpiresAt,
+		RevokedAt:      m.RevokedAt,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+// UserTokenToModel converts a UserToken entity to a UserTokenModel
+func UserTokenToModel(e *entity.UserToken) *model.UserTokenModel {
+	if e == nil {
+		return nil
+	}
+	return &model.UserTokenModel{
 		ID:        e.ID,
-		Name:      e.Name,
-		Username:  e.Username,
+		UserID:    e.UserID,
+		Purpose:   string(e.Purpose),
+		TokenHash: e.TokenHash,
+		ExpiresAt: e.ExpiresAt,
+		UsedAt:    e.UsedAt,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+// UserTokenToEntity converts a UserTokenModel to a UserToken entity
+func UserTokenToEntity(m *model.UserTokenModel) *entity.UserToken {
+	if m == nil {
+		return nil
+	}
+	return &entity.UserToken{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		User:      UserToEntity(m.User),
+		Purpose:   entity.TokenPurpose(m.Purpose),
+		TokenHash: m.TokenHash,
+		ExpiresAt: m.ExpiresAt,
+		UsedAt:    m.UsedAt,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// LoginAttemptToModel converts a LoginAttempt entity to a LoginAttemptModel
+func LoginAttemptToModel(e *entity.LoginAttempt) *model.LoginAttemptModel {
+	if e == nil {
+		return nil
+	}
+	return &model.LoginAttemptModel{
+		ID:        e.ID,
+		UserID:    e.UserID,
+		Login:     e.Login,
+		IP:        e.IP,
+		UserAgent: e.UserAgent,
+		Success:   e.Success,
+		Reason:    string(e.Reason),
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+// APIKeyToModel converts an APIKey entity to an APIKeyModel
+func APIKeyToModel(e *entity.APIKey) *model.APIKeyModel {
+	if e == nil {
+		return nil
+	}
+	return &model.APIKeyModel{
+		ID:         e.ID,
+		UserID:     e.UserID,
+		Name:       e.Name,
+		Prefix:     e.Prefix,
+		KeyHash:    e.KeyHash,
+		Scopes:     e.Scopes,
+		ExpiresAt:  e.ExpiresAt,
+		LastUsedAt: e.LastUsedAt,
+		RevokedAt:  e.RevokedAt,
+		CreatedAt:  e.CreatedAt,
+	}
+}
+
+// APIKeyToEntity converts an APIKeyModel to an APIKey entity
+func APIKeyToEntity(m *model.APIKeyModel) *entity.APIKey {
+	if m == nil {
+		return nil
+	}
+	return &entity.APIKey{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		Name:       m.Name,
+		Prefix:     m.Prefix,
+		KeyHash:    m.KeyHash,
+		Scopes:     m.Scopes,
+		ExpiresAt:  m.ExpiresAt,
+		LastUsedAt: m.LastUsedAt,
+		RevokedAt:  m.RevokedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+// ExternalIdentityToModel converts an ExternalIdentity entity to an ExternalIdentityModel
+func ExternalIdentityToModel(e *entity.ExternalIdentity) *model.ExternalIdentityModel {
+	if e == nil {
+		return nil
+	}
+	return &model.ExternalIdentityModel{
+		ID:        e.ID,
+		UserID:    e.UserID,
+		Provider:  e.Provider,
+		Subject:   e.Subject,
 		Email:     e.Email,
-		AuthID:    e.AuthID,
-		Auth:      AuthToModel(e.Auth),
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+// ExternalIdentityToEntity converts an ExternalIdentityModel to an ExternalIdentity entity
+func ExternalIdentityToEntity(m *model.ExternalIdentityModel) *entity.ExternalIdentity {
+	if m == nil {
+		return nil
+	}
+	return &entity.ExternalIdentity{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		Provider:  m.Provider,
+		Subject:   m.Subject,
+		Email:     m.Email,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// OAuthClientToModel converts an OAuthClient entity to an OAuthClientModel
+func OAuthClientToModel(e *entity.OAuthClient) *model.OAuthClientModel {
+	if e == nil {
+		return nil
+	}
+	return &model.OAuthClientModel{
+		ID:           e.ID,
+		ClientID:     e.ClientID,
+		Name:         e.Name,
+		SecretHash:   nullableString(e.SecretHash),
+		RedirectURIs: e.RedirectURIs,
+		Scopes:       e.Scopes,
+		GrantTypes:   e.GrantTypes,
+		CreatedAt:    e.CreatedAt,
+		UpdatedAt:    e.UpdatedAt,
+	}
+}
+
+// OAuthClientToEntity converts an OAuthClientModel to an OAuthClient entity
+func OAuthClientToEntity(m *model.OAuthClientModel) *entity.OAuthClient {
+	if m == nil {
+		return nil
+	}
+	return &entity.OAuthClient{
+		ID:           m.ID,
+		ClientID:     m.ClientID,
+		Name:         m.Name,
+		SecretHash:   utils.Deref(m.SecretHash, ""),
+		RedirectURIs: m.RedirectURIs,
+		Scopes:       m.Scopes,
+		GrantTypes:   m.GrantTypes,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+// OrganizationToModel converts an Organization entity to an OrganizationModel
+func OrganizationToModel(e *entity.Organization) *model.OrganizationModel {
+	if e == nil {
+		return nil
+	}
+	return &model.OrganizationModel{
+		ID:        e.ID,
+		Name:      e.Name,
 		CreatedAt: e.CreatedAt,
 		UpdatedAt: e.UpdatedAt,
 	}
 }
 
-// UserToEntity converts a UserModel to a User entity
-func UserToEntity(m *model.UserModel) *entity.User {
+// OrganizationToEntity converts an OrganizationModel to an Organization entity
+func OrganizationToEntity(m *model.OrganizationModel) *entity.Organization {
 	if m == nil {
 		return nil
 	}
-	return &entity.User{
+	return &entity.Organization{
 		ID:        m.ID,
 		Name:      m.Name,
-		Username:  m.Username,
-		Email:     m.Email,
-		AuthID:    m.AuthID,
-		Auth:      AuthToEntity(m.Auth),
 		CreatedAt: m.CreatedAt,
 		UpdatedAt: m.UpdatedAt,
 	}
 }
 
+// AuditToModel converts an AuditRecord entity to an AuditModel
+func AuditToModel(e *entity.AuditRecord) *model.AuditModel {
+	if e == nil {
+		return nil
+	}
+	changes := make(model.AuditChanges, len(e.Changes))
+	for field, change := range e.Changes {
+		changes[field] = model.AuditChange{Before: change.Before, After: change.After}
+	}
+	return &model.AuditModel{
+		ID:             e.ID,
+		OrganizationID: e.OrganizationID,
+		ActorID:        e.ActorID,
+		ImpersonatorID: e.ImpersonatorID,
+		Action:         e.Action,
+		EntityType:     e.EntityType,
+		EntityID:       e.EntityID,
+		Changes:        changes,
+		RequestID:      e.RequestID,
+		IP:             e.IP,
+		CreatedAt:      e.CreatedAt,
+	}
+}
+
+// AuditToEntity converts an AuditModel to an AuditRecord entity
+func AuditToEntity(m *model.AuditModel) *entity.AuditRecord {
+	if m == nil {
+		return nil
+	}
+	changes := make(map[string]entity.AuditChange, len(m.Changes))
+	for field, change := range m.Changes {
+		changes[field] = entity.AuditChange{Before: change.Before, After: change.After}
+	}
+	return &entity.AuditRecord{
+		ID:             m.ID,
+		OrganizationID: m.OrganizationID,
+		ActorID:        m.ActorID,
+		ImpersonatorID: m.ImpersonatorID,
+		Action:         m.Action,
+		EntityType:     m.EntityType,
+		EntityID:       m.EntityID,
+		Changes:        changes,
+		RequestID:      m.RequestID,
+		IP:             m.IP,
+		CreatedAt:      m.CreatedAt,
+	}
+}
+
 // UsersToEntities converts a slice of UserModels to User entities
 func UsersToEntities(models []*model.UserModel) []*entity.User {
 	return MapSlice(models, UserToEntity)
@@ -120,6 +448,36 @@ func ProfilesToEntities(models []*model.ProfileModel) []*entity.Profile {
 	return MapSlice(models, ProfileToEntity)
 }
 
+// APIKeysToEntities converts a slice of APIKeyModels to APIKey entities
+func APIKeysToEntities(models []*model.APIKeyModel) []*entity.APIKey {
+	return MapSlice(models, APIKeyToEntity)
+}
+
+// SessionsToEntities converts a slice of SessionModels to Session entities
+func SessionsToEntities(models []*model.SessionModel) []*entity.Session {
+	return MapSlice(models, SessionToEntity)
+}
+
+// OAuthClientsToEntities converts a slice of OAuthClientModels to OAuthClient entities
+func OAuthClientsToEntities(models []*model.OAuthClientModel) []*entity.OAuthClient {
+	return MapSlice(models, OAuthClientToEntity)
+}
+
+// UserTokensToEntities converts a slice of UserTokenModels to UserToken entities
+func UserTokensToEntities(models []*model.UserTokenModel) []*entity.UserToken {
+	return MapSlice(models, UserTokenToEntity)
+}
+
+// OrganizationsToEntities converts a slice of OrganizationModels to Organization entities
+func OrganizationsToEntities(models []*model.OrganizationModel) []*entity.Organization {
+	return MapSlice(models, OrganizationToEntity)
+}
+
+// AuditsToEntities converts a slice of AuditModels to AuditRecord entities
+func AuditsToEntities(models []*model.AuditModel) []*entity.AuditRecord {
+	return MapSlice(models, AuditToEntity)
+}
+
 // UsersToModels converts a slice of User entities to UserModels
 func UsersToModels(entities []*entity.User) []*model.UserModel {
 	return MapSlice(entities, UserToModel)
@@ -129,3 +487,11 @@ func UsersToModels(entities []*entity.User) []*model.UserModel {
 func ProfilesToModels(entities []*entity.Profile) []*model.ProfileModel {
 	return MapSlice(entities, ProfileToModel)
 }
+
+// nullableString maps empty strings to NULL columns
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
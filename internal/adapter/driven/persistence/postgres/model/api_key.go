@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// APIKeyModel represents the database model for APIKey
+type APIKeyModel struct {
+	ID         uint           `gorm:"primarykey"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime"`
+	UserID     uint           `gorm:"column:user_id;type:bigint;not null;index;"`
+	User       *UserModel     `gorm:"constraint:OnDelete:CASCADE"`
+	Name       string         `gorm:"column:name;type:varchar(100);not null;"`
+	Prefix     string         `gorm:"column:prefix;type:varchar(12);not null;"`
+	KeyHash    string         `gorm:"column:key_hash;type:varchar(64);not null;uniqueIndex;"`
+	Scopes     pq.StringArray `gorm:"column:scopes;type:text[];not null;"`
+	ExpiresAt  *time.Time     `gorm:"column:expires_at;"`
+	LastUsedAt *time.Time     `gorm:"column:last_used_at;"`
+	RevokedAt  *time.Time     `gorm:"column:revoked_at;"`
+}
+
+// TableName returns the table name for APIKey
+func (APIKeyModel) TableName() string {
+	return "usr_api_key"
+}
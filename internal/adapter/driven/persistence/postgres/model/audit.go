@@ -0,0 +1,58 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AuditModel represents the database model for AuditRecord. Records are append-only and
+// outlive the users and entities they refer to, so they hold no foreign keys.
+type AuditModel struct {
+	ID             uint         `gorm:"primarykey"`
+	CreatedAt      time.Time    `gorm:"autoCreateTime;index"`
+	OrganizationID *uint        `gorm:"column:organization_id;type:bigint;index;"`
+	ActorID        *uint        `gorm:"column:actor_id;type:bigint;index;"`
+	ImpersonatorID *uint        `gorm:"column:impersonator_id;type:bigint;"`
+	Action         string       `gorm:"column:action;type:varchar(50);not null;"`
+	EntityType     string       `gorm:"column:entity_type;type:varchar(50);not null;index:idx_usr_audit_entity;"`
+	EntityID       string       `gorm:"column:entity_id;type:varchar(100);not null;index:idx_usr_audit_entity;"`
+	Changes        AuditChanges `gorm:"column:changes;type:jsonb;not null;"`
+	RequestID      string       `gorm:"column:request_id;type:varchar(100);index;"`
+	IP             string       `gorm:"column:ip;type:varchar(45);"`
+}
+
+// AuditChange stores the value of a field before and after an audited action
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditChanges stores the changed fields of an audit record as JSON
+type AuditChanges map[string]AuditChange
+
+// Value implements driver.Valuer
+func (c AuditChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner
+func (c *AuditChanges) Scan(value any) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return fmt.Errorf("unsupported audit changes type %T", value)
+	}
+}
+
+// TableName returns the table name for AuditRecord
+func (AuditModel) TableName() string {
+	return "usr_audit"
+}
@@ -2,6 +2,8 @@ package model
 
 import (
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // AuthModel represents the database model for Auth
@@ -10,10 +12,18 @@ type AuthModel struct {
 	CreatedAt time.Time     `gorm:"autoCreateTime"`
 	UpdatedAt time.Time     `gorm:"autoUpdateTime"`
 	Status    bool          `gorm:"column:status;type:bool;not null;"`
+	Pending   bool          `gorm:"column:pending;type:bool;not null;default:false;"`
 	ProfileID uint          `gorm:"column:profile_id;type:bigint;not null;index;"`
 	Profile   *ProfileModel `gorm:"foreignKey:ProfileID"`
-	Token     *string       `gorm:"column:token;type:varchar(255);unique;index"`
 	Password  *string       `gorm:"column:password;type:varchar(255);"`
+
+	PasswordChangedAt *time.Time     `gorm:"column:password_changed_at;type:timestamptz;"`
+	PasswordHistory   pq.StringArray `gorm:"column:password_history;type:text[];"`
+
+	TOTPSecret    *string        `gorm:"column:totp_secret;type:varchar(64);"`
+	TOTPEnabled   bool           `gorm:"column:totp_enabled;type:bool;not null;default:false;"`
+	TOTPLastStep  int64          `gorm:"column:totp_last_step;type:bigint;not null;default:0;"`
+	RecoveryCodes pq.StringArray `gorm:"column:recovery_codes;type:text[];"`
 }
 
 // TableName returns the table name for Auth
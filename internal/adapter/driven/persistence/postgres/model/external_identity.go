@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// ExternalIdentityModel represents the database model for ExternalIdentity
+type ExternalIdentityModel struct {
+	ID        uint       `gorm:"primarykey"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+	UserID    uint       `gorm:"column:user_id;type:bigint;not null;index;"`
+	User      *UserModel `gorm:"constraint:OnDelete:CASCADE"`
+	Provider  string     `gorm:"column:provider;type:varchar(50);not null;uniqueIndex:idx_usr_external_identity_subject;"`
+	Subject   string     `gorm:"column:subject;type:varchar(255);not null;uniqueIndex:idx_usr_external_identity_subject;"`
+	Email     string     `gorm:"column:email;type:varchar(255);"`
+}
+
+// TableName returns the table name for ExternalIdentity
+func (ExternalIdentityModel) TableName() string {
+	return "usr_external_identity"
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+)
+
+// LoginAttemptModel represents the database model for LoginAttempt
+type LoginAttemptModel struct {
+	ID        uint       `gorm:"primarykey"`
+	CreatedAt time.Time  `gorm:"autoCreateTime;index"`
+	UserID    *uint      `gorm:"column:user_id;type:bigint;index;"`
+	User      *UserModel `gorm:"constraint:OnDelete:SET NULL"`
+	Login     string     `gorm:"column:login;type:varchar(255);not null;"`
+	IP        string     `gorm:"column:ip;type:varchar(45);"`
+	UserAgent string     `gorm:"column:user_agent;type:varchar(255);"`
+	Success   bool       `gorm:"column:success;not null;"`
+	Reason    string     `gorm:"column:reason;type:varchar(50);"`
+}
+
+// TableName returns the table name for LoginAttempt
+func (LoginAttemptModel) TableName() string {
+	return "usr_login_attempt"
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// OAuthClientModel represents the database model for OAuthClient
+type OAuthClientModel struct {
+	ID           uint           `gorm:"primarykey"`
+	CreatedAt    time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime"`
+	ClientID     string         `gorm:"column:client_id;type:varchar(36);not null;uniqueIndex;"`
+	Name         string         `gorm:"column:name;type:varchar(100);not null;"`
+	SecretHash   *string        `gorm:"column:secret_hash;type:varchar(64);"`
+	RedirectURIs pq.StringArray `gorm:"column:redirect_uris;type:text[];not null;"`
+	Scopes       pq.StringArray `gorm:"column:scopes;type:text[];not null;"`
+	GrantTypes   pq.StringArray `gorm:"column:grant_types;type:text[];not null;"`
+}
+
+// TableName returns the table name for OAuthClient
+func (OAuthClientModel) TableName() string {
+	return "usr_oauth_client"
+}
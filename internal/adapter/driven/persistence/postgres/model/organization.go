@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// OrganizationModel represents the database model for Organization
+type OrganizationModel struct {
+	ID        uint      `gorm:"primarykey"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	Name      string    `gorm:"column:name;type:varchar(100);unique;not null;"`
+}
+
+// TableName returns the table name for Organization
+func (OrganizationModel) TableName() string {
+	return "usr_organization"
+}
@@ -1,18 +1,51 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/pkg/passwd"
 )
 
 // ProfileModel represents the database model for Profile
 type ProfileModel struct {
-	ID          uint           `gorm:"primarykey"`
-	CreatedAt   time.Time      `gorm:"autoCreateTime"`
-	UpdatedAt   time.Time      `gorm:"autoUpdateTime"`
-	Name        string         `gorm:"column:name;type:varchar(100);unique;not null;"`
-	Permissions pq.StringArray `gorm:"column:permissions;type:text[];not null;"`
+	ID             uint           `gorm:"primarykey"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt      gorm.DeletedAt `gorm:"column:deleted_at;index;"`
+	OrganizationID *uint          `gorm:"column:organization_id;type:bigint;uniqueIndex:uni_usr_profile,where:deleted_at IS NULL;"`
+	Name           string         `gorm:"column:name;type:varchar(100);uniqueIndex:uni_usr_profile,where:deleted_at IS NULL;not null;"`
+	Permissions    pq.StringArray `gorm:"column:permissions;type:text[];not null;"`
+	Version        uint           `gorm:"column:version;not null;default:1;"`
+
+	RequireTwoFactor     bool            `gorm:"column:require_2fa;type:bool;not null;default:false;"`
+	RequireVerifiedEmail bool            `gorm:"column:require_verified_email;type:bool;not null;default:false;"`
+	PasswordPolicy       *PasswordPolicy `gorm:"column:password_policy;type:jsonb;"`
+}
+
+// PasswordPolicy stores a password policy as JSON
+type PasswordPolicy passwd.Policy
+
+// Value implements driver.Valuer
+func (p PasswordPolicy) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan implements sql.Scanner
+func (p *PasswordPolicy) Scan(value any) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return fmt.Errorf("unsupported password policy type %T", value)
+	}
 }
 
 // TableName returns the table name for Profile
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// SessionModel represents the database model for Session
+type SessionModel struct {
+	ID             string         `gorm:"primarykey;type:varchar(36)"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime"`
+	UserID         uint           `gorm:"column:user_id;type:bigint;not null;index;"`
+	User           *UserModel     `gorm:"constraint:OnDelete:CASCADE"`
+	RefreshTokenID string         `gorm:"column:refresh_token_id;type:varchar(36);not null;"`
+	UserAgent      string         `gorm:"column:user_agent;type:varchar(255);"`
+	IP             string         `gorm:"column:ip;type:varchar(45);"`
+	ClientID       *string        `gorm:"column:client_id;type:varchar(36);"`
+	Scopes         pq.StringArray `gorm:"column:scopes;type:text[];"`
+	ActorID        *uint          `gorm:"column:actor_id;type:bigint;"`
+	LastActiveAt   *time.Time     `gorm:"column:last_active_at;"`
+	ExpiresAt      *time.Time     `gorm:"column:expires_at;"`
+	RevokedAt      *time.Time     `gorm:"column:revoked_at;"`
+}
+
+// TableName returns the table name for Session
+func (SessionModel) TableName() string {
+	return "usr_session"
+}
@@ -8,22 +8,23 @@ import (
 
 // UserModel represents the database model for User
 type UserModel struct {
-	ID        uint       `gorm:"primarykey"`
-	CreatedAt time.Time  `gorm:"autoCreateTime"`
-	UpdatedAt time.Time  `gorm:"autoUpdateTime"`
-	Name      string     `gorm:"column:name;"`
-	Username  string     `gorm:"column:username;"`
-	Email     string     `gorm:"column:mail;"`
-	AuthID    uint       `gorm:"column:auth_id;"`
-	Auth      *AuthModel `gorm:"constraint:OnDelete:CASCADE"`
+	ID             uint           `gorm:"primarykey"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt      gorm.DeletedAt `gorm:"column:deleted_at;index;"`
+	OrganizationID *uint          `gorm:"column:organization_id;type:bigint;index;"`
+	Name           string         `gorm:"column:name;"`
+	Username       string         `gorm:"column:username;"`
+	Email          string         `gorm:"column:mail;"`
+	AuthID         uint           `gorm:"column:auth_id;"`
+	Version        uint           `gorm:"column:version;not null;default:1;"`
+	Auth           *AuthModel     `gorm:"constraint:OnDelete:CASCADE"`
+
+	EmailVerifiedAt *time.Time `gorm:"column:email_verified_at;type:timestamptz;"`
+	PendingEmail    *string    `gorm:"column:pending_email;type:varchar(255);"`
 }
 
 // TableName returns the table name for User
 func (UserModel) TableName() string {
 	return "usr_user"
 }
-
-// AfterDelete hook to delete associated Auth
-func (u *UserModel) AfterDelete(tx *gorm.DB) error {
-	return tx.Delete(&AuthModel{}, u.AuthID).Error
-}
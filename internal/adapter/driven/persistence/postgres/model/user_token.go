@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+)
+
+// UserTokenModel represents the database model for UserToken
+type UserTokenModel struct {
+	ID        uint       `gorm:"primarykey"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+	UserID    uint       `gorm:"column:user_id;type:bigint;not null;index;"`
+	User      *UserModel `gorm:"constraint:OnDelete:CASCADE"`
+	Purpose   string     `gorm:"column:purpose;type:varchar(50);not null;"`
+	TokenHash string     `gorm:"column:token_hash;type:varchar(64);not null;uniqueIndex;"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null;"`
+	UsedAt    *time.Time `gorm:"column:used_at;"`
+}
+
+// TableName returns the table name for UserToken
+func (UserTokenModel) TableName() string {
+	return "usr_token"
+}
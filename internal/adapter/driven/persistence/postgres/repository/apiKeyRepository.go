@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/mapper"
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/model"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// apiKeyRepository implements the APIKeyRepository interface
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository instance
+func NewAPIKeyRepository(db *gorm.DB) output.APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// FindByHash returns a key by the hash of its secret
+func (r *apiKeyRepository) FindByHash(ctx context.Context, hash string) (*entity.APIKey, error) {
+	var m model.APIKeyModel
+	if err := conn(ctx, r.db).First(&m, "key_hash = ?", hash).Error; err != nil {
+		return nil, err
+	}
+	return mapper.APIKeyToEntity(&m), nil
+}
+
+// FindByUser returns the keys of a user that were not revoked
+func (r *apiKeyRepository) FindByUser(ctx context.Context, userID uint) ([]*entity.APIKey, error) {
+	var models []*model.APIKeyModel
+	if err := conn(ctx, r.db).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return mapper.APIKeysToEntities(models), nil
+}
+
+// Create creates a new key
+func (r *apiKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	m := mapper.APIKeyToModel(key)
+	if err := conn(ctx, r.db).Create(m).Error; err != nil {
+		return err
+	}
+	key.ID = m.ID
+	key.CreatedAt = m.CreatedAt
+	return nil
+}
+
+// Revoke revokes a key of a user, failing if the user has no such active key
+func (r *apiKeyRepository) Revoke(ctx context.Context, userID, id uint) error {
+	result := conn(ctx, r.db).Model(&model.APIKeyModel{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Touch records when a key was last used
+func (r *apiKeyRepository) Touch(ctx context.Context, id uint, usedAt time.Time) error {
+	return conn(ctx, r.db).Model(&model.APIKeyModel{}).
+		Where("id = ?", id).
+		Update("last_used_at", usedAt).Error
+}
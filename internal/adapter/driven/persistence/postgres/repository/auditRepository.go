@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/mapper"
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/model"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// auditRepository implements the AuditRepository interface
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new AuditRepository instance
+func NewAuditRepository(db *gorm.DB) output.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// applyFilter applies filters to the query
+func (r *auditRepository) applyFilter(ctx context.Context, filter *dto.AuditFilter) *gorm.DB {
+	query := scopeOrganization(ctx, conn(ctx, r.db).Model(&model.AuditModel{}), "organization_id")
+
+	if filter != nil {
+		if filter.ID != nil {
+			query = query.Where("id = ?", *filter.ID)
+		}
+		if filter.ActorID != nil {
+			query = query.Where("(actor_id = ? OR impersonator_id = ?)", *filter.ActorID, *filter.ActorID)
+		}
+		if filter.Action != "" {
+			query = query.Where("action = ?", filter.Action)
+		}
+		if filter.EntityType != "" {
+			query = query.Where("entity_type = ?", filter.EntityType)
+		}
+		if filter.EntityID != "" {
+			query = query.Where("entity_id = ?", filter.EntityID)
+		}
+		if filter.RequestID != "" {
+			query = query.Where("request_id = ?", filter.RequestID)
+		}
+		if filter.Search != "" {
+			search := "%" + strings.ToLower(filter.Search) + "%"
+			query = query.Where("(LOWER(action) LIKE ? OR LOWER(entity_id) LIKE ? OR LOWER(changes::text) LIKE ?)", search, search, search)
+		}
+	}
+
+	return query
+}
+
+// Create appends a record to the audit log
+func (r *auditRepository) Create(ctx context.Context, record *entity.AuditRecord) error {
+	m := mapper.AuditToModel(record)
+	if err := conn(ctx, r.db).Create(m).Error; err != nil {
+		return err
+	}
+	record.ID = m.ID
+	return nil
+}
+
+// Count returns the number of records matching the filter
+func (r *auditRepository) Count(ctx context.Context, filter *dto.AuditFilter) (int64, error) {
+	var count int64
+	err := r.applyFilter(ctx, filter).Count(&count).Error
+	return count, err
+}
+
+// FindAll returns the records matching the filter, most recent first unless the filter
+// orders them ascending
+func (r *auditRepository) FindAll(ctx context.Context, filter *dto.AuditFilter) ([]*entity.AuditRecord, error) {
+	query := r.applyFilter(ctx, filter)
+
+	order := "id DESC"
+	if filter != nil {
+		if strings.EqualFold(filter.Order, "asc") {
+			order = "id ASC"
+		}
+		if ok, offset, limit := filter.ApplyPagination(); ok {
+			query = query.Offset(offset).Limit(limit)
+		}
+	}
+
+	var models []*model.AuditModel
+	if err := query.Order(order).Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return mapper.AuditsToEntities(models), nil
+}
@@ -0,0 +1,84 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres"
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/model"
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/repository"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/pkg/tenant"
+)
+
+func TestAuditRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	container, connStr, err := setupPostgresContainer(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	}()
+
+	db := postgres.MustConnect(&postgres.Config{Dsn: connStr})
+	require.NoError(t, db.AutoMigrate(&model.AuditModel{}))
+
+	repo := repository.NewAuditRepository(db)
+	acme, globex := uint(1), uint(2)
+
+	before := entity.AuditState{"name": "John Doe", "status": true}
+	after := entity.AuditState{"name": "John Smith", "status": true}
+	update := entity.NewAuditRecord(entity.AuditActionUpdate, entity.AuditEntityUser, "7", &acme, before, after)
+	update.RequestID = "req-1"
+	require.NoError(t, repo.Create(ctx, update))
+	require.NoError(t, repo.Create(ctx, entity.NewAuditRecord(entity.AuditActionDelete, entity.AuditEntityUser, "7", &acme, after, nil)))
+	require.NoError(t, repo.Create(ctx, entity.NewAuditRecord(entity.AuditActionCreate, entity.AuditEntityProfile, "3", &globex, nil, nil)))
+
+	t.Run("Most recent first", func(t *testing.T) {
+		records, err := repo.FindAll(ctx, &dto.AuditFilter{EntityType: entity.AuditEntityUser, EntityID: "7"})
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, entity.AuditActionDelete, records[0].Action)
+		assert.Equal(t, map[string]entity.AuditChange{"name": {Before: "John Doe", After: "John Smith"}}, records[1].Changes)
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		records, err := repo.FindAll(ctx, &dto.AuditFilter{RequestID: "req-1"})
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, update.ID, records[0].ID)
+
+		count, err := repo.Count(ctx, &dto.AuditFilter{Action: entity.AuditActionCreate})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Scoped to the organization", func(t *testing.T) {
+		count, err := repo.Count(tenant.WithOrganization(ctx, &globex), &dto.AuditFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Rolled back with its transaction", func(t *testing.T) {
+		failed := errors.New("change failed")
+		err := repository.NewTransactor(db).Transaction(ctx, func(ctx context.Context) error {
+			require.NoError(t, repo.Create(ctx, entity.NewAuditRecord(entity.AuditActionRestore, entity.AuditEntityUser, "8", &acme, nil, nil)))
+			return failed
+		})
+		assert.ErrorIs(t, err, failed)
+
+		count, err := repo.Count(ctx, &dto.AuditFilter{EntityID: "8"})
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
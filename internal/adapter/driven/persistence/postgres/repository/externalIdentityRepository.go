@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/mapper"
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/model"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// externalIdentityRepository implements the ExternalIdentityRepository interface
+type externalIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewExternalIdentityRepository creates a new ExternalIdentityRepository instance
+func NewExternalIdentityRepository(db *gorm.DB) output.ExternalIdentityRepository {
+	return &externalIdentityRepository{db: db}
+}
+
+// FindBySubject returns the identity of an account at a provider
+func (r *externalIdentityRepository) FindBySubject(ctx context.Context, provider, subject string) (*entity.ExternalIdentity, error) {
+	var m model.ExternalIdentityModel
+	if err := conn(ctx, r.db).First(&m, "provider = ? AND subject = ?", provider, subject).Error; err != nil {
+		return nil, err
+	}
+	return mapper.ExternalIdentityToEntity(&m), nil
+}
+
+// Create links a new identity to a user
+func (r *externalIdentityRepository) Create(ctx context.Context, identity *entity.ExternalIdentity) error {
+	m := mapper.ExternalIdentityToModel(identity)
+	if err := conn(ctx, r.db).Create(m).Error; err != nil {
+		return err
+	}
+	identity.ID = m.ID
+	identity.CreatedAt = m.CreatedAt
+	return nil
+}
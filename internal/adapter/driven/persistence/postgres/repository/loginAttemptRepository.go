@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/mapper"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// loginAttemptRepository implements the LoginAttemptRepository interface
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository creates a new LoginAttemptRepository instance
+func NewLoginAttemptRepository(db *gorm.DB) output.LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+// Create records a login attempt
+func (r *loginAttemptRepository) Create(ctx context.Context, attempt *entity.LoginAttempt) error {
+	m := mapper.LoginAttemptToModel(attempt)
+	if err := conn(ctx, r.db).Create(m).Error; err != nil {
+		return err
+	}
+	attempt.ID = m.ID
+	return nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/mapper"
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/model"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// oauthClientRepository implements the OAuthClientRepository interface
+type oauthClientRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthClientRepository creates a new OAuthClientRepository instance
+func NewOAuthClientRepository(db *gorm.DB) output.OAuthClientRepository {
+	return &oauthClientRepository{db: db}
+}
+
+// FindAll returns every registered client
+func (r *oauthClientRepository) FindAll(ctx context.Context) ([]*entity.OAuthClient, error) {
+	var models []*model.OAuthClientModel
+	if err := conn(ctx, r.db).Order("name").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return mapper.OAuthClientsToEntities(models), nil
+}
+
+// FindByClientID returns a client by its public client ID
+func (r *oauthClientRepository) FindByClientID(ctx context.Context, clientID string) (*entity.OAuthClient, error) {
+	var m model.OAuthClientModel
+	if err := conn(ctx, r.db).First(&m, "client_id = ?", clientID).Error; err != nil {
+		return nil, err
+	}
+	return mapper.OAuthClientToEntity(&m), nil
+}
+
+// Create registers a new client
+func (r *oauthClientRepository) Create(ctx context.Context, client *entity.OAuthClient) error {
+	m := mapper.OAuthClientToModel(client)
+	if err := conn(ctx, r.db).Create(m).Error; err != nil {
+		return err
+	}
+	client.ID = m.ID
+	client.CreatedAt = m.CreatedAt
+	client.UpdatedAt = m.UpdatedAt
+	return nil
+}
+
+// Delete removes a client; the sessions opened for it are removed by the database
+func (r *oauthClientRepository) Delete(ctx context.Context, id uint) error {
+	result := conn(ctx, r.db).Delete(&model.OAuthClientModel{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/mapper"
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/model"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// organizationRepository implements the OrganizationRepository interface
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+// NewOrganizationRepository creates a new OrganizationRepository instance
+func NewOrganizationRepository(db *gorm.DB) output.OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+// scoped returns a query on the organization ctx is scoped to, the only one its users can see
+func (r *organizationRepository) scoped(ctx context.Context) *gorm.DB {
+	return scopeOrganization(ctx, conn(ctx, r.db), "id")
+}
+
+// FindAll returns every organization
+func (r *organizationRepository) FindAll(ctx context.Context) ([]*entity.Organization, error) {
+	var models []*model.OrganizationModel
+	if err := r.scoped(ctx).Order("name").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return mapper.OrganizationsToEntities(models), nil
+}
+
+// FindByID returns an organization by its ID
+func (r *organizationRepository) FindByID(ctx context.Context, id uint) (*entity.Organization, error) {
+	var m model.OrganizationModel
+	if err := r.scoped(ctx).First(&m, id).Error; err != nil {
+		return nil, err
+	}
+	return mapper.OrganizationToEntity(&m), nil
+}
+
+// Create creates a new organization
+func (r *organizationRepository) Create(ctx context.Context, organization *entity.Organization) error {
+	m := mapper.OrganizationToModel(organization)
+	if err := conn(ctx, r.db).Create(m).Error; err != nil {
+		return err
+	}
+	organization.ID = m.ID
+	organization.CreatedAt = m.CreatedAt
+	organization.UpdatedAt = m.UpdatedAt
+	return nil
+}
+
+// Update updates an existing organization
+func (r *organizationRepository) Update(ctx context.Context, organization *entity.Organization) error {
+	m := mapper.OrganizationToModel(organization)
+	return r.scoped(ctx).Model(m).Updates(map[string]any{
+		"name": m.Name,
+	}).Error
+}
+
+// Delete removes an organization; the database refuses to remove organizations with users or profiles
+func (r *organizationRepository) Delete(ctx context.Context, id uint) error {
+	result := r.scoped(ctx).Delete(&model.OrganizationModel{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
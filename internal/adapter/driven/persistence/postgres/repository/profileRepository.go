@@ -6,6 +6,7 @@ import (
 	"os"
 	"slices"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/raulaguila/go-api/internal/core/domain/entity"
 	"github.com/raulaguila/go-api/internal/core/dto"
 	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/pkg/tenant"
 )
 
 // profileRepository implements the ProfileRepository interface
@@ -28,9 +30,13 @@ func NewProfileRepository(db *gorm.DB) output.ProfileRepository {
 
 // applyFilter applies filters to the query
 func (r *profileRepository) applyFilter(ctx context.Context, filter *dto.ProfileFilter) *gorm.DB {
-	query := r.db.WithContext(ctx)
+	query := r.scoped(ctx)
 
 	if filter != nil {
+		if filter.OrganizationID != nil {
+			query = query.Where("organization_id = ?", *filter.OrganizationID)
+		}
+
 		if filter.ID != nil {
 			query = query.Where("id = ?", *filter.ID)
 		}
@@ -44,6 +50,8 @@ func (r *profileRepository) applyFilter(ctx context.Context, filter *dto.Profile
 			query = query.Omit("permissions")
 		}
 
+		query = scopeDeleted(query, filter.Deleted, "deleted_at")
+
 		if !filter.ListRoot {
 			query = query.Where("name != ?", "ROOT")
 		}
@@ -54,6 +62,11 @@ func (r *profileRepository) applyFilter(ctx context.Context, filter *dto.Profile
 	return query.Group("id")
 }
 
+// scoped returns a query on the profiles of the organization ctx is scoped to
+func (r *profileRepository) scoped(ctx context.Context) *gorm.DB {
+	return scopeOrganization(ctx, conn(ctx, r.db), "organization_id")
+}
+
 // applyOrder applies ordering to the query
 func (r *profileRepository) applyOrder(query *gorm.DB, filter *dto.ProfileFilter) *gorm.DB {
 	sort := filter.Sort
@@ -97,7 +110,7 @@ func (r *profileRepository) FindAll(ctx context.Context, filter *dto.ProfileFilt
 // FindByID returns a profile by its ID
 func (r *profileRepository) FindByID(ctx context.Context, id uint) (*entity.Profile, error) {
 	var m model.ProfileModel
-	if err := r.db.WithContext(ctx).First(&m, id).Error; err != nil {
+	if err := r.scoped(ctx).First(&m, id).Error; err != nil {
 		return nil, err
 	}
 	return mapper.ProfileToEntity(&m), nil
@@ -106,43 +119,95 @@ func (r *profileRepository) FindByID(ctx context.Context, id uint) (*entity.Prof
 // FindByName returns a profile by its name
 func (r *profileRepository) FindByName(ctx context.Context, name string) (*entity.Profile, error) {
 	var m model.ProfileModel
-	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&m).Error; err != nil {
+	if err := r.scoped(ctx).Where("name = ?", name).First(&m).Error; err != nil {
 		return nil, err
 	}
 	return mapper.ProfileToEntity(&m), nil
 }
 
-// Create creates a new profile
+// Create creates a new profile, in the organization ctx is scoped to if any
 func (r *profileRepository) Create(ctx context.Context, profile *entity.Profile) error {
+	profile.OrganizationID = tenant.Resolve(ctx, profile.OrganizationID)
 	m := mapper.ProfileToModel(profile)
-	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+	if err := conn(ctx, r.db).Create(m).Error; err != nil {
 		return err
 	}
 	profile.ID = m.ID
+	profile.Version = m.Version
 	profile.CreatedAt = m.CreatedAt
 	profile.UpdatedAt = m.UpdatedAt
 	return nil
 }
 
-// Update updates an existing profile
+// Update updates an existing profile of the organization ctx is scoped to, provided it is still
+// at the version read: a profile updated by someone else in the meantime is refused with output.ErrStaleVersion
 func (r *profileRepository) Update(ctx context.Context, profile *entity.Profile) error {
+	if !tenant.Allows(ctx, profile.OrganizationID) {
+		return gorm.ErrRecordNotFound
+	}
 	m := mapper.ProfileToModel(profile)
-	return r.db.WithContext(ctx).Model(m).Updates(map[string]any{
-		"name":        m.Name,
-		"permissions": m.Permissions,
-	}).Error
+	result := conn(ctx, r.db).Model(&model.ProfileModel{}).Where("id = ? AND version = ?", m.ID, m.Version).Updates(map[string]any{
+		"name":                   m.Name,
+		"permissions":            m.Permissions,
+		"require_2fa":            m.RequireTwoFactor,
+		"require_verified_email": m.RequireVerifiedEmail,
+		"password_policy":        m.PasswordPolicy,
+		"version":                gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return output.ErrStaleVersion
+	}
+	profile.Version++
+	return nil
 }
 
-// Delete deletes profiles by their IDs
+// InUse checks if any of the profiles is granted to users not in the trash
+func (r *profileRepository) InUse(ctx context.Context, ids []uint) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.AuthModel{}).
+		Joins("JOIN usr_user ON usr_user.auth_id = usr_auth.id AND usr_user.deleted_at IS NULL").
+		Where("usr_auth.profile_id IN ?", ids).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Delete moves profiles of the organization ctx is scoped to to the trash by their IDs
 func (r *profileRepository) Delete(ctx context.Context, ids []uint) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		result := tx.Delete(&model.ProfileModel{}, ids)
-		if result.Error != nil {
-			return result.Error
-		}
-		if result.RowsAffected == 0 {
-			return gorm.ErrRecordNotFound
-		}
-		return nil
-	})
+	result := r.scoped(ctx).Delete(&model.ProfileModel{}, ids)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Restore takes profiles of the organization ctx is scoped to out of the trash by their IDs
+func (r *profileRepository) Restore(ctx context.Context, ids []uint) error {
+	result := r.scoped(ctx).Unscoped().Model(&model.ProfileModel{}).
+		Where("id IN ? AND deleted_at IS NOT NULL", ids).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge permanently deletes the profiles of the organization ctx is scoped to moved to the trash
+// before a time and returns how many were deleted. Profiles still granted to users in the trash wait for them to be purged.
+func (r *profileRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	// Grants of any organization keep a profile
+	granted := conn(ctx, r.db).Model(&model.AuthModel{}).Select("profile_id")
+
+	result := r.scoped(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND id NOT IN (?)", before, granted).
+		Delete(&model.ProfileModel{})
+	return result.RowsAffected, result.Error
 }
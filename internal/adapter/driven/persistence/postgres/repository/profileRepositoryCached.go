@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"time"
 
+	"gorm.io/gorm"
+
 	"github.com/raulaguila/go-api/internal/adapter/driven/storage/redis"
 	"github.com/raulaguila/go-api/internal/core/domain/entity"
 	"github.com/raulaguila/go-api/internal/core/dto"
 	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/pkg/tenant"
 )
 
 const (
@@ -45,6 +48,10 @@ func (r *CachedProfileRepository) FindByID(ctx context.Context, id uint) (*entit
 	if err == nil {
 		var profile entity.Profile
 		if err := json.Unmarshal([]byte(val), &profile); err == nil {
+			// Cached profiles are shared by every organization
+			if !tenant.Allows(ctx, profile.OrganizationID) {
+				return nil, gorm.ErrRecordNotFound
+			}
 			return &profile, nil
 		}
 	}
@@ -55,6 +62,11 @@ func (r *CachedProfileRepository) FindByID(ctx context.Context, id uint) (*entit
 		return nil, err
 	}
 
+	// Profiles read within a transaction may be rolled back
+	if inTransaction(ctx) {
+		return profile, nil
+	}
+
 	// Set cache asynchronously to not block response
 	go func() {
 		if data, err := json.Marshal(profile); err == nil {
@@ -72,12 +84,10 @@ func (r *CachedProfileRepository) Create(ctx context.Context, profile *entity.Pr
 }
 
 func (r *CachedProfileRepository) Update(ctx context.Context, profile *entity.Profile) error {
-	if err := r.delegate.Update(ctx, profile); err != nil {
-		return err
-	}
-	// Invalidate cache
+	err := r.delegate.Update(ctx, profile)
+	// Invalidate cache, also when the update was refused: a stale version means a stale entry
 	_ = r.redis.GetClient().Del(ctx, r.cacheKey(profile.ID)).Err()
-	return nil
+	return err
 }
 
 func (r *CachedProfileRepository) Delete(ctx context.Context, ids []uint) error {
@@ -95,6 +105,16 @@ func (r *CachedProfileRepository) Delete(ctx context.Context, ids []uint) error
 	return nil
 }
 
+// Restore and Purge only touch profiles in the trash, evicted by Delete
+
+func (r *CachedProfileRepository) Restore(ctx context.Context, ids []uint) error {
+	return r.delegate.Restore(ctx, ids)
+}
+
+func (r *CachedProfileRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return r.delegate.Purge(ctx, before)
+}
+
 // Read-only methods without caching (for now) or complex query caching strategy needed
 
 func (r *CachedProfileRepository) FindAll(ctx context.Context, filter *dto.ProfileFilter) ([]*entity.Profile, error) {
@@ -108,3 +128,7 @@ func (r *CachedProfileRepository) FindByName(ctx context.Context, name string) (
 func (r *CachedProfileRepository) Count(ctx context.Context, filter *dto.ProfileFilter) (int64, error) {
 	return r.delegate.Count(ctx, filter)
 }
+
+func (r *CachedProfileRepository) InUse(ctx context.Context, ids []uint) (bool, error) {
+	return r.delegate.InUse(ctx, ids)
+}
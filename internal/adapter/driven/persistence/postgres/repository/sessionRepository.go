@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/mapper"
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/model"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// sessionRepository implements the SessionRepository interface
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new SessionRepository instance
+func NewSessionRepository(db *gorm.DB) output.SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// FindByID returns a session by its ID
+func (r *sessionRepository) FindByID(ctx context.Context, id string) (*entity.Session, error) {
+	var m model.SessionModel
+	if err := conn(ctx, r.db).First(&m, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return mapper.SessionToEntity(&m), nil
+}
+
+// FindActiveByUser returns the sessions of a user that are neither revoked nor expired, most recently active first
+func (r *sessionRepository) FindActiveByUser(ctx context.Context, userID uint) ([]*entity.Session, error) {
+	var models []*model.SessionModel
+	if err := conn(ctx, r.db).
+		Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, time.Now()).
+		Order("last_active_at DESC NULLS LAST, created_at DESC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return mapper.SessionsToEntities(models), nil
+}
+
+// Create creates a new session
+func (r *sessionRepository) Create(ctx context.Context, session *entity.Session) error {
+	m := mapper.SessionToModel(session)
+	if err := conn(ctx, r.db).Create(m).Error; err != nil {
+		return err
+	}
+	session.CreatedAt = m.CreatedAt
+	session.UpdatedAt = m.UpdatedAt
+	return nil
+}
+
+// Rotate stores the new refresh token ID only if the stored one still matches previousTokenID
+func (r *sessionRepository) Rotate(ctx context.Context, session *entity.Session, previousTokenID string) error {
+	result := conn(ctx, r.db).Model(&model.SessionModel{}).
+		Where("id = ? AND refresh_token_id = ? AND revoked_at IS NULL", session.ID, previousTokenID).
+		Updates(map[string]any{
+			"refresh_token_id": session.RefreshTokenID,
+			"expires_at":       session.ExpiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Revoke revokes a session
+func (r *sessionRepository) Revoke(ctx context.Context, id string) error {
+	return conn(ctx, r.db).Model(&model.SessionModel{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// SaveActivity stores the last activity of sessions in a single transaction
+func (r *sessionRepository) SaveActivity(ctx context.Context, activity map[string]time.Time) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for id, at := range activity {
+			// UpdateColumn keeps updated_at, which tracks changes to the session itself
+			err := tx.Model(&model.SessionModel{}).
+				Where("id = ? AND (last_active_at IS NULL OR last_active_at < ?)", id, at).
+				UpdateColumn("last_active_at", at).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RevokeByUser revokes all active sessions of a user
+func (r *sessionRepository) RevokeByUser(ctx context.Context, userID uint) error {
+	return conn(ctx, r.db).Model(&model.SessionModel{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/core/dto"
+)
+
+// scopeDeleted applies the deleted filter to query: records in the trash, whose column is
+// set, are left out unless deleted asks for them only or along with the others
+func scopeDeleted(query *gorm.DB, deleted, column string) *gorm.DB {
+	switch deleted {
+	case dto.DeletedOnly:
+		return query.Unscoped().Where(column + " IS NOT NULL")
+	case dto.DeletedInclude:
+		return query.Unscoped()
+	default:
+		return query
+	}
+}
@@ -0,0 +1,114 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres"
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/model"
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/repository"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/pkg/tenant"
+)
+
+func TestSoftDelete_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	container, connStr, err := setupPostgresContainer(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	}()
+
+	db := postgres.MustConnect(&postgres.Config{Dsn: connStr})
+	require.NoError(t, db.AutoMigrate(&model.OrganizationModel{}, &model.ProfileModel{}, &model.AuthModel{}, &model.UserModel{}))
+
+	profiles, users := repository.NewProfileRepository(db), repository.NewUserRepository(db)
+
+	profile := entity.NewProfile("EDITOR", []string{entity.PermissionUsersRead})
+	require.NoError(t, profiles.Create(ctx, profile))
+	auth, _ := entity.NewAuth(profile.ID, true)
+	user, err := entity.NewUser("John Doe", "john", "john@example.com", auth)
+	require.NoError(t, err)
+	require.NoError(t, users.Create(ctx, user))
+
+	countUsers := func(deleted string) int64 {
+		count, err := users.Count(ctx, &dto.UserFilter{Deleted: deleted})
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("Delete", func(t *testing.T) {
+		inUse, err := profiles.InUse(ctx, []uint{profile.ID})
+		require.NoError(t, err)
+		assert.True(t, inUse)
+
+		require.NoError(t, users.Delete(ctx, []uint{user.ID}))
+		_, err = users.FindByID(ctx, user.ID)
+		assert.Error(t, err)
+
+		assert.Zero(t, countUsers(""))
+		assert.EqualValues(t, 1, countUsers(dto.DeletedOnly))
+		assert.EqualValues(t, 1, countUsers(dto.DeletedInclude))
+
+		inUse, err = profiles.InUse(ctx, []uint{profile.ID})
+		require.NoError(t, err)
+		assert.False(t, inUse, "users in the trash do not hold their profile")
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		require.NoError(t, profiles.Delete(ctx, []uint{profile.ID}))
+		assert.Error(t, users.Restore(ctx, []uint{user.ID}), "users wait for their profile to be restored")
+
+		require.NoError(t, profiles.Restore(ctx, []uint{profile.ID}))
+		require.NoError(t, users.Restore(ctx, []uint{user.ID}))
+		found, err := users.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Nil(t, found.DeletedAt)
+		assert.Equal(t, profile.ID, found.Auth.Profile.ID)
+
+		assert.Error(t, users.Restore(ctx, []uint{user.ID}), "only users in the trash are restored")
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		require.NoError(t, users.Delete(ctx, []uint{user.ID}))
+		require.NoError(t, profiles.Delete(ctx, []uint{profile.ID}))
+
+		purged, err := users.Purge(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, purged, "users within the retention period are kept")
+
+		otherOrganization := uint(99)
+		other := tenant.WithOrganization(ctx, &otherOrganization)
+		purged, err = users.Purge(other, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Zero(t, purged, "users of other organizations are kept")
+
+		purged, err = profiles.Purge(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Zero(t, purged, "profiles granted to users in the trash wait for them")
+
+		purged, err = users.Purge(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, purged)
+		assert.Zero(t, countUsers(dto.DeletedInclude))
+
+		var auths int64
+		require.NoError(t, db.Model(&model.AuthModel{}).Where("id = ?", user.AuthID).Count(&auths).Error)
+		assert.Zero(t, auths)
+
+		purged, err = profiles.Purge(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, purged)
+	})
+}
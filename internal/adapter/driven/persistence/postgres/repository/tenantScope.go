@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/pkg/tenant"
+)
+
+// scopeOrganization limits query to the records of the organization ctx is scoped to,
+// through column, and leaves queries of unscoped contexts as they are
+func scopeOrganization(ctx context.Context, query *gorm.DB, column string) *gorm.DB {
+	if id, ok := tenant.Organization(ctx); ok {
+		return query.Where(column+" = ?", id)
+	}
+	return query
+}
@@ -0,0 +1,95 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres"
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/model"
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/repository"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/pkg/tenant"
+)
+
+func TestTenantIsolation_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	container, connStr, err := setupPostgresContainer(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	}()
+
+	db := postgres.MustConnect(&postgres.Config{Dsn: connStr})
+	require.NoError(t, db.AutoMigrate(&model.OrganizationModel{}, &model.ProfileModel{}, &model.AuthModel{}, &model.UserModel{}))
+
+	acme, globex := &model.OrganizationModel{Name: "Acme"}, &model.OrganizationModel{Name: "Globex"}
+	require.NoError(t, db.Create(acme).Error)
+	require.NoError(t, db.Create(globex).Error)
+
+	acmeCtx := tenant.WithOrganization(ctx, &acme.ID)
+	globexCtx := tenant.WithOrganization(ctx, &globex.ID)
+
+	profiles, users := repository.NewProfileRepository(db), repository.NewUserRepository(db)
+
+	// Both organizations may name their profiles alike
+	acmeProfile := entity.NewProfile("ADMIN", []string{entity.PermissionUsersRead})
+	require.NoError(t, profiles.Create(acmeCtx, acmeProfile))
+	globexProfile := entity.NewProfile("ADMIN", []string{entity.PermissionUsersRead})
+	require.NoError(t, profiles.Create(globexCtx, globexProfile))
+	assert.Equal(t, &acme.ID, acmeProfile.OrganizationID)
+	assert.Equal(t, &globex.ID, globexProfile.OrganizationID)
+
+	auth, _ := entity.NewAuth(globexProfile.ID, true)
+	globexUser, err := entity.NewUser("Hank Scorpio", "hank", "hank@globex.com", auth)
+	require.NoError(t, err)
+	require.NoError(t, users.Create(ctx, globexUser))
+	assert.Equal(t, &globex.ID, globexUser.OrganizationID, "users join the organization of their profile")
+
+	t.Run("Profiles", func(t *testing.T) {
+		_, err := profiles.FindByID(acmeCtx, globexProfile.ID)
+		assert.Error(t, err)
+
+		found, err := profiles.FindAll(acmeCtx, &dto.ProfileFilter{})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, acmeProfile.ID, found[0].ID)
+
+		assert.Error(t, profiles.Update(acmeCtx, globexProfile))
+
+		require.NoError(t, profiles.Delete(acmeCtx, []uint{globexProfile.ID}))
+		_, err = profiles.FindByID(globexCtx, globexProfile.ID)
+		assert.NoError(t, err, "profiles of other organizations cannot be deleted")
+	})
+
+	t.Run("Users", func(t *testing.T) {
+		_, err := users.FindByID(acmeCtx, globexUser.ID)
+		assert.Error(t, err)
+		_, err = users.FindByEmail(acmeCtx, globexUser.Email)
+		assert.Error(t, err)
+
+		count, err := users.Count(acmeCtx, &dto.UserFilter{})
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		auth, _ := entity.NewAuth(globexProfile.ID, true)
+		intruder, _ := entity.NewUser("Wile E. Coyote", "wile", "wile@acme.com", auth)
+		assert.Error(t, users.Create(acmeCtx, intruder), "profiles of other organizations cannot be granted")
+
+		assert.Error(t, users.Update(acmeCtx, globexUser))
+
+		require.NoError(t, users.Delete(acmeCtx, []uint{globexUser.ID}))
+		found, err := users.FindByID(ctx, globexUser.ID)
+		require.NoError(t, err, "users of other organizations cannot be deleted")
+		assert.Equal(t, globexUser.Email, found.Email)
+	})
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// txKey is the context key of the transaction a context runs in
+type txKey struct{}
+
+// transactor implements the Transactor interface
+type transactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor creates a new Transactor instance
+func NewTransactor(db *gorm.DB) output.Transactor {
+	return &transactor{db: db}
+}
+
+// Transaction runs fn in a transaction, nested in the one ctx runs in if any
+func (t *transactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the connection of the transaction ctx runs in, or db outside of transactions
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// inTransaction reports whether ctx runs in a transaction, whose changes may not be committed yet
+func inTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return ok
+}
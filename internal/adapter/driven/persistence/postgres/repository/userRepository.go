@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -14,12 +16,16 @@ import (
 	"github.com/raulaguila/go-api/internal/core/domain/entity"
 	"github.com/raulaguila/go-api/internal/core/dto"
 	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/pkg/pgerror"
+	"github.com/raulaguila/go-api/pkg/tenant"
 )
 
 const (
 	userTable    = "usr_user"
 	authTable    = "usr_auth"
 	profileTable = "usr_profile"
+
+	userBatchSize = 500 // Users inserted, or looked up, per statement by CreateBatch and FindRegistered
 )
 
 // userRepository implements the UserRepository interface
@@ -34,9 +40,13 @@ func NewUserRepository(db *gorm.DB) output.UserRepository {
 
 // applyFilter applies filters to the query
 func (r *userRepository) applyFilter(ctx context.Context, filter *dto.UserFilter) *gorm.DB {
-	query := r.db.WithContext(ctx)
+	query := scopeOrganization(ctx, conn(ctx, r.db), userTable+".organization_id")
 
 	if filter != nil {
+		if filter.OrganizationID != nil {
+			query = query.Where(userTable+".organization_id = ?", *filter.OrganizationID)
+		}
+
 		if filter.ID != nil {
 			query = query.Where(userTable+".id = ?", *filter.ID)
 		}
@@ -49,6 +59,8 @@ func (r *userRepository) applyFilter(ctx context.Context, filter *dto.UserFilter
 			query = query.Where(authTable+".profile_id = ?", filter.ProfileID)
 		}
 
+		query = scopeDeleted(query, filter.Deleted, userTable+".deleted_at")
+
 		query = query.Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.auth_id", authTable, authTable, userTable))
 		query = query.Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.profile_id", profileTable, profileTable, authTable))
 
@@ -118,10 +130,15 @@ func (r *userRepository) FindAll(ctx context.Context, filter *dto.UserFilter) ([
 	return mapper.UsersToEntities(models), nil
 }
 
+// scoped returns a query on the users of the organization ctx is scoped to
+func (r *userRepository) scoped(ctx context.Context) *gorm.DB {
+	return scopeOrganization(ctx, conn(ctx, r.db), "organization_id")
+}
+
 // FindByID returns a user by its ID
 func (r *userRepository) FindByID(ctx context.Context, id uint) (*entity.User, error) {
 	var m model.UserModel
-	if err := r.db.WithContext(ctx).Preload("Auth.Profile").First(&m, id).Error; err != nil {
+	if err := r.scoped(ctx).Preload("Auth.Profile").First(&m, id).Error; err != nil {
 		return nil, err
 	}
 	return mapper.UserToEntity(&m), nil
@@ -130,7 +147,7 @@ func (r *userRepository) FindByID(ctx context.Context, id uint) (*entity.User, e
 // FindByUsername returns a user by its username
 func (r *userRepository) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
 	var m model.UserModel
-	if err := r.db.WithContext(ctx).Preload("Auth.Profile").Where("username = ?", username).First(&m).Error; err != nil {
+	if err := r.scoped(ctx).Preload("Auth.Profile").Where("username = ?", username).First(&m).Error; err != nil {
 		return nil, err
 	}
 	return mapper.UserToEntity(&m), nil
@@ -139,85 +156,282 @@ func (r *userRepository) FindByUsername(ctx context.Context, username string) (*
 // FindByEmail returns a user by its email
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var m model.UserModel
-	if err := r.db.WithContext(ctx).Preload("Auth.Profile").Where("mail = ?", email).First(&m).Error; err != nil {
+	if err := r.scoped(ctx).Preload("Auth.Profile").Where("mail = ?", email).First(&m).Error; err != nil {
 		return nil, err
 	}
 	return mapper.UserToEntity(&m), nil
 }
 
-// FindByToken returns a user by its authentication token
-func (r *userRepository) FindByToken(ctx context.Context, token string) (*entity.User, error) {
+// FindByLogin returns the user whose username or email is login. A username wins over
+// another user's email, so that nobody can take over a login by choosing that email.
+func (r *userRepository) FindByLogin(ctx context.Context, login string, caseInsensitive bool) (*entity.User, error) {
+	matches := func(column string) string {
+		if caseInsensitive {
+			return "LOWER(" + column + ") = LOWER(?)"
+		}
+		return column + " = ?"
+	}
+
 	var m model.UserModel
-	if err := r.db.WithContext(ctx).
-		Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.auth_id", authTable, authTable, userTable)).
-		Preload("Auth.Profile").
-		First(&m, authTable+".token = ?", token).Error; err != nil {
+	if err := r.scoped(ctx).Preload("Auth.Profile").
+		Where(matches("username")+" OR "+matches("mail"), login, login).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "CASE WHEN " + matches("username") + " THEN 0 ELSE 1 END", Vars: []any{login}}}).
+		Take(&m).Error; err != nil {
 		return nil, err
 	}
 	return mapper.UserToEntity(&m), nil
 }
 
-// Create creates a new user
+// FindRegistered returns which of the emails and usernames users of any organization registered,
+// compared case-insensitively; the keys are lowercased. Emails and usernames are unique across
+// organizations, so the lookup is not scoped to the organization of ctx.
+func (r *userRepository) FindRegistered(ctx context.Context, emails, usernames []string) (map[string]bool, map[string]bool, error) {
+	lower := func(values []string) []string {
+		lowered := make([]string, len(values))
+		for i, value := range values {
+			lowered[i] = strings.ToLower(value)
+		}
+		return lowered
+	}
+	chunk := func(values []string, start int) []string {
+		return values[min(start, len(values)):min(start+userBatchSize, len(values))]
+	}
+	emails, usernames = lower(emails), lower(usernames)
+
+	registeredEmails, registeredUsernames := map[string]bool{}, map[string]bool{}
+	for start := 0; start < max(len(emails), len(usernames)); start += userBatchSize {
+		var rows []struct{ Mail, Username string }
+		if err := conn(ctx, r.db).Model(&model.UserModel{}).
+			Select("LOWER(mail) AS mail", "LOWER(username) AS username").
+			Where("LOWER(mail) IN ? OR LOWER(username) IN ?", chunk(emails, start), chunk(usernames, start)).
+			Find(&rows).Error; err != nil {
+			return nil, nil, err
+		}
+		for _, row := range rows {
+			registeredEmails[row.Mail] = true
+			registeredUsernames[row.Username] = true
+		}
+	}
+
+	// Users found by one of their keys may have the other one registered too, but not requested
+	for email := range registeredEmails {
+		if !slices.Contains(emails, email) {
+			delete(registeredEmails, email)
+		}
+	}
+	for username := range registeredUsernames {
+		if !slices.Contains(usernames, username) {
+			delete(registeredUsernames, username)
+		}
+	}
+	return registeredEmails, registeredUsernames, nil
+}
+
+// Create creates a new user in the organization of its profile, which must be visible within ctx
 func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
 	m := mapper.UserToModel(user)
-	if err := r.db.Session(&gorm.Session{FullSaveAssociations: true}).WithContext(ctx).Create(m).Error; err != nil {
+	if m.Auth != nil {
+		var profile model.ProfileModel
+		if err := scopeOrganization(ctx, conn(ctx, r.db), "organization_id").Select("id", "organization_id").First(&profile, m.Auth.ProfileID).Error; err != nil {
+			return err
+		}
+		m.OrganizationID = profile.OrganizationID
+	}
+
+	if err := conn(ctx, r.db).Session(&gorm.Session{FullSaveAssociations: true}).Create(m).Error; err != nil {
 		return err
 	}
 	user.ID = m.ID
+	user.OrganizationID = m.OrganizationID
 	user.AuthID = m.AuthID
 	if m.Auth != nil {
 		user.Auth.ID = m.Auth.ID
 	}
+	user.Version = m.Version
 	user.CreatedAt = m.CreatedAt
 	user.UpdatedAt = m.UpdatedAt
 	return nil
 }
 
-// Update updates an existing user
-func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
-	m := mapper.UserToModel(user)
-
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Update Auth first
+// CreateBatch creates users in one transaction, inserting them in chunks. Like Create, it places
+// every user in the organization of its profile, which must be visible within ctx. Users whose
+// email or username was registered since they were checked are skipped and their indexes returned:
+// the chunk holding them is inserted again one user at a time.
+func (r *userRepository) CreateBatch(ctx context.Context, users []*entity.User) ([]int, error) {
+	models := make([]*model.UserModel, len(users))
+	organizations := map[uint]*uint{}
+	for i, user := range users {
+		m := mapper.UserToModel(user)
 		if m.Auth != nil {
-			if err := tx.Model(m.Auth).Updates(map[string]any{
-				"status":     m.Auth.Status,
-				"profile_id": m.Auth.ProfileID,
-				"token":      m.Auth.Token,
-				"password":   m.Auth.Password,
-			}).Error; err != nil {
-				return err
+			organizationID, ok := organizations[m.Auth.ProfileID]
+			if !ok {
+				var profile model.ProfileModel
+				if err := scopeOrganization(ctx, conn(ctx, r.db), "organization_id").Select("id", "organization_id").First(&profile, m.Auth.ProfileID).Error; err != nil {
+					return nil, err
+				}
+				organizationID = profile.OrganizationID
+				organizations[m.Auth.ProfileID] = organizationID
+			}
+			m.OrganizationID = organizationID
+		}
+		models[i] = m
+	}
+
+	// Nested transactions are savepoints, rolling back only the users inserted with a duplicate
+	insert := func(tx *gorm.DB, models []*model.UserModel) (bool, error) {
+		err := tx.Transaction(func(tx *gorm.DB) error {
+			return tx.Session(&gorm.Session{FullSaveAssociations: true}).Create(models).Error
+		})
+		if errors.Is(pgerror.HandlerError(err), pgerror.ErrDuplicatedKey) {
+			// Keys returned by the rolled back inserts do not exist
+			for _, m := range models {
+				m.ID, m.AuthID = 0, 0
+				if m.Auth != nil {
+					m.Auth.ID = 0
+				}
 			}
+			return false, nil
 		}
+		return err == nil, err
+	}
 
-		// Update User
-		return tx.Model(m).Updates(map[string]any{
-			"name":     m.Name,
-			"username": m.Username,
-			"mail":     m.Email,
-			"auth_id":  m.AuthID,
-		}).Error
+	var skipped []int
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(models); start += userBatchSize {
+			chunk := models[start:min(start+userBatchSize, len(models))]
+			created, err := insert(tx, chunk)
+			if err != nil {
+				return err
+			}
+			if created {
+				continue
+			}
+			for i, m := range chunk {
+				created, err := insert(tx, []*model.UserModel{m})
+				if err != nil {
+					return err
+				}
+				if !created {
+					skipped = append(skipped, start+i)
+				}
+			}
+		}
+		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range models {
+		if slices.Contains(skipped, i) {
+			continue
+		}
+		users[i].ID = m.ID
+		users[i].OrganizationID = m.OrganizationID
+		users[i].AuthID = m.AuthID
+		if m.Auth != nil {
+			users[i].Auth.ID = m.Auth.ID
+		}
+		users[i].Version = m.Version
+		users[i].CreatedAt = m.CreatedAt
+		users[i].UpdatedAt = m.UpdatedAt
+	}
+	return skipped, nil
 }
 
-// Delete deletes users by their IDs
-func (r *userRepository) Delete(ctx context.Context, ids []uint) error {
-	var models []*model.UserModel
-	if err := r.db.WithContext(ctx).Find(&models, ids).Error; err != nil {
-		return err
-	}
-	if len(models) == 0 {
+// Update updates an existing user of the organization ctx is scoped to, provided it is still at
+// the version read: a user updated by someone else in the meantime is refused with output.ErrStaleVersion
+func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
+	if !tenant.Allows(ctx, user.OrganizationID) {
 		return gorm.ErrRecordNotFound
 	}
+	m := mapper.UserToModel(user)
 
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		result := tx.Select(clause.Associations).Where("id IN ?", ids).Delete(&model.UserModel{})
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Update User first, so that a stale version writes nothing
+		result := tx.Model(&model.UserModel{}).Where("id = ? AND version = ?", m.ID, m.Version).Updates(map[string]any{
+			"organization_id":   m.OrganizationID,
+			"name":              m.Name,
+			"username":          m.Username,
+			"mail":              m.Email,
+			"email_verified_at": m.EmailVerifiedAt,
+			"pending_email":     m.PendingEmail,
+			"auth_id":           m.AuthID,
+			"version":           gorm.Expr("version + 1"),
+		})
 		if result.Error != nil {
 			return result.Error
 		}
 		if result.RowsAffected == 0 {
-			return gorm.ErrRecordNotFound
+			return output.ErrStaleVersion
+		}
+
+		// Update Auth
+		if m.Auth != nil {
+			if err := tx.Model(m.Auth).Updates(map[string]any{
+				"status":              m.Auth.Status,
+				"pending":             m.Auth.Pending,
+				"profile_id":          m.Auth.ProfileID,
+				"password":            m.Auth.Password,
+				"password_changed_at": m.Auth.PasswordChangedAt,
+				"password_history":    m.Auth.PasswordHistory,
+				"totp_secret":         m.Auth.TOTPSecret,
+				"totp_enabled":        m.Auth.TOTPEnabled,
+				"totp_last_step":      m.Auth.TOTPLastStep,
+				"recovery_codes":      m.Auth.RecoveryCodes,
+			}).Error; err != nil {
+				return err
+			}
 		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+	user.Version++
+	return nil
+}
+
+// Delete moves users of the organization ctx is scoped to to the trash by their IDs
+func (r *userRepository) Delete(ctx context.Context, ids []uint) error {
+	result := r.scoped(ctx).Where("id IN ?", ids).Delete(&model.UserModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Restore takes users of the organization ctx is scoped to out of the trash by their IDs.
+// Users whose profile is in the trash stay there until the profile is restored.
+func (r *userRepository) Restore(ctx context.Context, ids []uint) error {
+	liveProfile := scopeOrganization(ctx, conn(ctx, r.db), profileTable+".organization_id").
+		Table(authTable).
+		Select(authTable + ".id").
+		Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.profile_id", profileTable, profileTable, authTable)).
+		Where(profileTable + ".deleted_at IS NULL")
+
+	result := r.scoped(ctx).Unscoped().Model(&model.UserModel{}).
+		Where("id IN ? AND deleted_at IS NOT NULL AND auth_id IN (?)", ids, liveProfile).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge permanently deletes the users of the organization ctx is scoped to moved to the trash
+// before a time, with everything that belongs to them, and returns how many were deleted
+func (r *userRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	expired := r.scoped(ctx).Unscoped().Model(&model.UserModel{}).
+		Select("auth_id").
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before)
+
+	// Users are deleted along with their auth, and their sessions, tokens and keys along with them
+	result := conn(ctx, r.db).Where("id IN (?)", expired).Delete(&model.AuthModel{})
+	return result.RowsAffected, result.Error
 }
@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"time"
 
+	"gorm.io/gorm"
+
 	"github.com/raulaguila/go-api/internal/adapter/driven/storage/redis"
 	"github.com/raulaguila/go-api/internal/core/domain/entity"
 	"github.com/raulaguila/go-api/internal/core/dto"
 	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/pkg/tenant"
 )
 
 const (
@@ -44,10 +47,6 @@ func (r *CachedUserRepository) keyByUsername(username string) string {
 	return fmt.Sprintf("%susername:%s", userCacheKeyPrefix, username)
 }
 
-func (r *CachedUserRepository) keyByToken(token string) string {
-	return fmt.Sprintf("%stoken:%s", userCacheKeyPrefix, token)
-}
-
 // Generic get method to handle cache logic
 func (r *CachedUserRepository) getCached(ctx context.Context, key string, fetcher func() (*entity.User, error)) (*entity.User, error) {
 	client := r.redis.GetClient()
@@ -57,6 +56,10 @@ func (r *CachedUserRepository) getCached(ctx context.Context, key string, fetche
 	if err == nil {
 		var user entity.User
 		if err := json.Unmarshal([]byte(val), &user); err == nil {
+			// Cached users are shared by every organization
+			if !tenant.Allows(ctx, user.OrganizationID) {
+				return nil, gorm.ErrRecordNotFound
+			}
 			return &user, nil
 		}
 	}
@@ -67,6 +70,11 @@ func (r *CachedUserRepository) getCached(ctx context.Context, key string, fetche
 		return nil, err
 	}
 
+	// Users read within a transaction may be rolled back
+	if inTransaction(ctx) {
+		return user, nil
+	}
+
 	// Set cache async
 	go func() {
 		if data, err := json.Marshal(user); err == nil {
@@ -98,11 +106,14 @@ func (r *CachedUserRepository) FindByUsername(ctx context.Context, username stri
 	})
 }
 
-// FindByToken with caching
-func (r *CachedUserRepository) FindByToken(ctx context.Context, token string) (*entity.User, error) {
-	return r.getCached(ctx, r.keyByToken(token), func() (*entity.User, error) {
-		return r.delegate.FindByToken(ctx, token)
-	})
+// FindByLogin is not cached: a login may name a user by two keys, in any case
+func (r *CachedUserRepository) FindByLogin(ctx context.Context, login string, caseInsensitive bool) (*entity.User, error) {
+	return r.delegate.FindByLogin(ctx, login, caseInsensitive)
+}
+
+// FindRegistered is not cached: it looks up many users by two keys, in any case
+func (r *CachedUserRepository) FindRegistered(ctx context.Context, emails, usernames []string) (map[string]bool, map[string]bool, error) {
+	return r.delegate.FindRegistered(ctx, emails, usernames)
 }
 
 // Pass-through methods that invalidate cache
@@ -111,41 +122,60 @@ func (r *CachedUserRepository) Create(ctx context.Context, user *entity.User) er
 	return r.delegate.Create(ctx, user)
 }
 
+func (r *CachedUserRepository) CreateBatch(ctx context.Context, users []*entity.User) ([]int, error) {
+	return r.delegate.CreateBatch(ctx, users)
+}
+
 func (r *CachedUserRepository) Update(ctx context.Context, user *entity.User) error {
-	if err := r.delegate.Update(ctx, user); err != nil {
-		return err
-	}
+	err := r.delegate.Update(ctx, user)
 
-	// Invalidate all potential keys for this user
-	client := r.redis.GetClient()
-	pipe := client.Pipeline()
-	pipe.Del(ctx, r.keyByID(user.ID))
-	pipe.Del(ctx, r.keyByEmail(user.Email))
-	pipe.Del(ctx, r.keyByUsername(user.Username))
-	if user.Auth != nil && user.Auth.Token != nil {
-		pipe.Del(ctx, r.keyByToken(*user.Auth.Token))
-	}
-	_, _ = pipe.Exec(ctx)
+	// Invalidate all potential keys for this user, also when the update was refused:
+	// a stale version means a stale entry
+	r.evict(ctx, user)
 
-	return nil
+	return err
 }
 
 func (r *CachedUserRepository) Delete(ctx context.Context, ids []uint) error {
+	// The email and username keys of the users are only known before they are trashed
+	users := make([]*entity.User, 0, len(ids))
+	for _, id := range ids {
+		if user, err := r.delegate.FindByID(ctx, id); err == nil {
+			users = append(users, user)
+		}
+	}
+
 	if err := r.delegate.Delete(ctx, ids); err != nil {
 		return err
 	}
+	r.evict(ctx, users...)
+	return nil
+}
 
-	// We only have IDs here, so we invalidate ID keys.
-	// Note: Ideally we should invalidate Email/Username keys too, but we don't have them here without fetching.
-	// For now, TTL handles eventual consistency, or we could fetch before delete if strict consistency is required.
-	keys := make([]string, len(ids))
-	for i, id := range ids {
-		keys[i] = r.keyByID(id)
+// evict invalidates all potential keys of users
+func (r *CachedUserRepository) evict(ctx context.Context, users ...*entity.User) {
+	if len(users) == 0 {
+		return
 	}
-	if len(keys) > 0 {
-		_ = r.redis.GetClient().Del(ctx, keys...).Err()
+
+	pipe := r.redis.GetClient().Pipeline()
+	for _, user := range users {
+		pipe.Del(ctx, r.keyByID(user.ID))
+		pipe.Del(ctx, r.keyByEmail(user.Email))
+		pipe.Del(ctx, r.keyByUsername(user.Username))
 	}
-	return nil
+	_, _ = pipe.Exec(ctx)
+}
+
+// Restore and Purge only touch users in the trash. The delegate never finds those, so they are
+// only cached by reads that raced with Delete, until userCacheTTL expires them.
+
+func (r *CachedUserRepository) Restore(ctx context.Context, ids []uint) error {
+	return r.delegate.Restore(ctx, ids)
+}
+
+func (r *CachedUserRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return r.delegate.Purge(ctx, before)
 }
 
 // Read-only pass-through
@@ -9,6 +9,8 @@ import (
 	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/model"
 	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/repository"
 	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/pkg/tenant"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
@@ -62,8 +64,11 @@ func TestUserRepository_Integration(t *testing.T) {
 	db := postgres.MustConnect(&postgres.Config{Dsn: connStr})
 
 	// Migrate schema using Models
-	err = db.AutoMigrate(&model.UserModel{}, &model.AuthModel{}, &model.ProfileModel{})
+	err = db.AutoMigrate(&model.OrganizationModel{}, &model.UserModel{}, &model.AuthModel{}, &model.ProfileModel{})
 	require.NoError(t, err)
+	// Unique indexes of build/SQL, which the models do not declare
+	require.NoError(t, db.Exec("CREATE UNIQUE INDEX uni_usr_user ON usr_user (mail) WHERE deleted_at IS NULL").Error)
+	require.NoError(t, db.Exec("CREATE UNIQUE INDEX uni_usr_user_username ON usr_user (username) WHERE deleted_at IS NULL").Error)
 
 	// Seed required data (Profiles) using Model
 	profile := &model.ProfileModel{Name: "Admin", Permissions: []string{"all"}}
@@ -87,4 +92,86 @@ func TestUserRepository_Integration(t *testing.T) {
 		assert.Equal(t, users.Email, found.Email)
 		assert.Equal(t, users.Name, found.Name)
 	})
+
+	t.Run("Find User by Login", func(t *testing.T) {
+		auth, _ := entity.NewAuth(profile.ID, true)
+		user, _ := entity.NewUser("Jane Roe", "JaneRoe", "Jane@test.com", auth)
+		require.NoError(t, repo.Create(ctx, user))
+
+		found, err := repo.FindByLogin(ctx, "JaneRoe", false)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, found.ID)
+
+		found, err = repo.FindByLogin(ctx, "Jane@test.com", false)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, found.ID)
+
+		_, err = repo.FindByLogin(ctx, "janeroe", false)
+		assert.Error(t, err)
+
+		found, err = repo.FindByLogin(ctx, "jane@TEST.com", true)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, found.ID)
+	})
+	t.Run("Create Users in Batch", func(t *testing.T) {
+		var users []*entity.User
+		for _, login := range []string{"batchone", "batchtwo"} {
+			auth, _ := entity.NewAuth(profile.ID, true)
+			user, _ := entity.NewUser("Batch User", login, login+"@test.com", auth)
+			users = append(users, user)
+		}
+		skipped, err := repo.CreateBatch(ctx, users)
+		require.NoError(t, err)
+		assert.Empty(t, skipped)
+
+		for _, user := range users {
+			require.NotZero(t, user.ID)
+			found, err := repo.FindByID(ctx, user.ID)
+			require.NoError(t, err)
+			assert.Equal(t, user.Email, found.Email)
+			assert.Equal(t, profile.ID, found.Auth.ProfileID)
+		}
+
+		authThree, _ := entity.NewAuth(profile.ID, true)
+		three, _ := entity.NewUser("Batch User", "batchthree", "batchthree@test.com", authThree)
+		authTaken, _ := entity.NewAuth(profile.ID, true)
+		taken, _ := entity.NewUser("Batch User", "batchone", "other@test.com", authTaken)
+		skipped, err = repo.CreateBatch(ctx, []*entity.User{three, taken})
+		require.NoError(t, err)
+		assert.Equal(t, []int{1}, skipped, "users registered meanwhile are skipped")
+		assert.NotZero(t, three.ID)
+		assert.Zero(t, taken.ID)
+	})
+
+	t.Run("Find Registered Emails and Usernames", func(t *testing.T) {
+		otherOrganization := uint(99)
+		emails, usernames, err := repo.FindRegistered(tenant.WithOrganization(ctx, &otherOrganization),
+			[]string{"BATCHTWO@test.com", "nobody@test.com"}, []string{"BatchOne", "nobody"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{"batchtwo@test.com": true}, emails, "users of every organization are found, in any case")
+		assert.Equal(t, map[string]bool{"batchone": true}, usernames)
+	})
+
+	t.Run("Stale Update is Refused", func(t *testing.T) {
+		auth, _ := entity.NewAuth(profile.ID, true)
+		user, _ := entity.NewUser("Mary Major", "marymajor", "mary@test.com", auth)
+		require.NoError(t, repo.Create(ctx, user))
+
+		first, err := repo.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		second, err := repo.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+
+		first.UpdateName("Mary Minor")
+		require.NoError(t, repo.Update(ctx, first))
+		assert.Equal(t, uint(2), first.Version)
+
+		second.UpdateName("Mary Other")
+		assert.ErrorIs(t, repo.Update(ctx, second), output.ErrStaleVersion)
+
+		found, err := repo.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Mary Minor", found.Name)
+		assert.Equal(t, uint(2), found.Version)
+	})
 }
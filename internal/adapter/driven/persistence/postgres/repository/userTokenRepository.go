@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/mapper"
+	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/model"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// userTokenRepository implements the UserTokenRepository interface
+type userTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewUserTokenRepository creates a new UserTokenRepository instance
+func NewUserTokenRepository(db *gorm.DB) output.UserTokenRepository {
+	return &userTokenRepository{db: db}
+}
+
+// FindByHash returns a token by its purpose and hash
+func (r *userTokenRepository) FindByHash(ctx context.Context, purpose entity.TokenPurpose, hash string) (*entity.UserToken, error) {
+	var m model.UserTokenModel
+	if err := conn(ctx, r.db).First(&m, "purpose = ? AND token_hash = ?", string(purpose), hash).Error; err != nil {
+		return nil, err
+	}
+	return mapper.UserTokenToEntity(&m), nil
+}
+
+// FindUnused returns the unused tokens with the given purpose of the users of the organization
+// ctx is scoped to, with their users, the ones expiring first first
+func (r *userTokenRepository) FindUnused(ctx context.Context, purpose entity.TokenPurpose) ([]*entity.UserToken, error) {
+	query := conn(ctx, r.db).
+		Joins("JOIN usr_user ON usr_user.id = usr_token.user_id AND usr_user.deleted_at IS NULL").
+		Preload("User.Auth.Profile").
+		Where("usr_token.purpose = ? AND usr_token.used_at IS NULL", string(purpose))
+
+	var models []*model.UserTokenModel
+	if err := scopeOrganization(ctx, query, "usr_user.organization_id").Order("usr_token.expires_at").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return mapper.UserTokensToEntities(models), nil
+}
+
+// Create creates a new token
+func (r *userTokenRepository) Create(ctx context.Context, token *entity.UserToken) error {
+	m := mapper.UserTokenToModel(token)
+	if err := conn(ctx, r.db).Create(m).Error; err != nil {
+		return err
+	}
+	token.ID = m.ID
+	token.CreatedAt = m.CreatedAt
+	return nil
+}
+
+// Consume marks the token as used only if it was not used before
+func (r *userTokenRepository) Consume(ctx context.Context, token *entity.UserToken) error {
+	token.Use()
+	result := conn(ctx, r.db).Model(&model.UserTokenModel{}).
+		Where("id = ? AND used_at IS NULL", token.ID).
+		Update("used_at", token.UsedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteByUser deletes all tokens of a user with the given purpose
+func (r *userTokenRepository) DeleteByUser(ctx context.Context, userID uint, purpose entity.TokenPurpose) error {
+	return conn(ctx, r.db).
+		Where("user_id = ? AND purpose = ?", userID, string(purpose)).
+		Delete(&model.UserTokenModel{}).Error
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// authorizationCode is a stored code and when it expires
+type authorizationCode struct {
+	code      *entity.AuthorizationCode
+	expiresAt time.Time
+}
+
+// authorizationCodeStore implements the AuthorizationCodeStore interface in memory
+type authorizationCodeStore struct {
+	mu    sync.Mutex
+	codes map[string]authorizationCode
+}
+
+// NewAuthorizationCodeStore creates a new in-memory AuthorizationCodeStore
+func NewAuthorizationCodeStore() output.AuthorizationCodeStore {
+	return &authorizationCodeStore{codes: make(map[string]authorizationCode)}
+}
+
+// Save keeps a code for ttl
+func (s *authorizationCodeStore) Save(_ context.Context, code *entity.AuthorizationCode, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for value, stored := range s.codes {
+		if now.After(stored.expiresAt) {
+			delete(s.codes, value)
+		}
+	}
+
+	s.codes[code.Code] = authorizationCode{code: code, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// Take returns and forgets a code, or nil when there is none or it expired
+func (s *authorizationCodeStore) Take(_ context.Context, code string) (*entity.AuthorizationCode, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.codes[code]
+	if !ok {
+		return nil, nil
+	}
+	delete(s.codes, code)
+
+	if time.Now().After(stored.expiresAt) {
+		return nil, nil
+	}
+	return stored.code, nil
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// externalLogin is a stored login and when it expires
+type externalLogin struct {
+	login     *entity.ExternalLogin
+	expiresAt time.Time
+}
+
+// externalLoginStore implements the ExternalLoginStore interface in memory
+type externalLoginStore struct {
+	mu     sync.Mutex
+	logins map[string]externalLogin // state -> login
+}
+
+// NewExternalLoginStore creates a new in-memory ExternalLoginStore
+func NewExternalLoginStore() output.ExternalLoginStore {
+	return &externalLoginStore{logins: make(map[string]externalLogin)}
+}
+
+// Save keeps a login for ttl
+func (s *externalLoginStore) Save(_ context.Context, login *entity.ExternalLogin, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for state, stored := range s.logins {
+		if now.After(stored.expiresAt) {
+			delete(s.logins, state)
+		}
+	}
+
+	s.logins[login.State] = externalLogin{login: login, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// Take returns and forgets the login of a state, or nil when there is none or it expired
+func (s *externalLoginStore) Take(_ context.Context, state string) (*entity.ExternalLogin, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.logins[state]
+	if !ok {
+		return nil, nil
+	}
+	delete(s.logins, state)
+
+	if time.Now().After(stored.expiresAt) {
+		return nil, nil
+	}
+	return stored.login, nil
+}
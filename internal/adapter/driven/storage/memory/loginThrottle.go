@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// loginFailures is a failure counter with its expiration
+type loginFailures struct {
+	entity.LoginFailures
+	expiresAt time.Time
+}
+
+// loginThrottle implements the LoginThrottle interface in memory
+type loginThrottle struct {
+	mu       sync.Mutex
+	failures map[string]*loginFailures
+}
+
+// NewLoginThrottle creates a new in-memory LoginThrottle
+func NewLoginThrottle() output.LoginThrottle {
+	return &loginThrottle{failures: make(map[string]*loginFailures)}
+}
+
+// Failures returns the failures recorded for key, or nil if there are none
+func (t *loginThrottle) Failures(_ context.Context, key string) (*entity.LoginFailures, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f, ok := t.failures[key]
+	if !ok || time.Now().After(f.expiresAt) {
+		return nil, nil
+	}
+	failures := f.LoginFailures
+	return &failures, nil
+}
+
+// AddFailure records a failure for key, forgotten ttl after the last one, and returns the updated count
+func (t *loginThrottle) AddFailure(_ context.Context, key string, ttl time.Duration) (*entity.LoginFailures, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.purgeExpired(now)
+
+	f, ok := t.failures[key]
+	if !ok {
+		f = &loginFailures{}
+		t.failures[key] = f
+	}
+	f.Count++
+	f.LastFailure = now
+	f.expiresAt = now.Add(ttl)
+
+	failures := f.LoginFailures
+	return &failures, nil
+}
+
+// Reset clears the failures recorded for key
+func (t *loginThrottle) Reset(_ context.Context, key string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.failures, key)
+	return nil
+}
+
+// purgeExpired drops expired counters; must be called with the lock held
+func (t *loginThrottle) purgeExpired(now time.Time) {
+	for key, f := range t.failures {
+		if now.After(f.expiresAt) {
+			delete(t.failures, key)
+		}
+	}
+}
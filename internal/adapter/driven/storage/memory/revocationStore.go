@@ -0,0 +1,84 @@
+// Package memory provides in-process implementations of output ports,
+// used when no external store (e.g. Redis) is available.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// revocationStore implements the RevocationStore interface in memory
+type revocationStore struct {
+	mu       sync.RWMutex
+	sessions map[string]time.Time // session ID -> expiration (zero never expires)
+	users    map[uint]time.Time   // user ID -> revocation time
+}
+
+// NewRevocationStore creates a new in-memory RevocationStore
+func NewRevocationStore() output.RevocationStore {
+	return &revocationStore{
+		sessions: make(map[string]time.Time),
+		users:    make(map[uint]time.Time),
+	}
+}
+
+// RevokeSession marks a session as revoked for ttl (zero keeps it forever)
+func (s *revocationStore) RevokeSession(_ context.Context, sessionID string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.purgeExpired(now)
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = now.Add(ttl)
+	}
+	s.sessions[sessionID] = expiresAt
+	return nil
+}
+
+// IsSessionRevoked checks if a session was revoked
+func (s *revocationStore) IsSessionRevoked(_ context.Context, sessionID string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	expiresAt, ok := s.sessions[sessionID]
+	if !ok {
+		return false, nil
+	}
+	return expiresAt.IsZero() || time.Now().Before(expiresAt), nil
+}
+
+// RevokeUser marks every session of a user created until now as revoked
+func (s *revocationStore) RevokeUser(_ context.Context, userID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[userID] = time.Now()
+	return nil
+}
+
+// UserRevokedAt returns when the user's sessions were last revoked, or nil if never
+func (s *revocationStore) UserRevokedAt(_ context.Context, userID uint) (*time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	revokedAt, ok := s.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &revokedAt, nil
+}
+
+// purgeExpired drops expired session entries; must be called with the lock held
+func (s *revocationStore) purgeExpired(now time.Time) {
+	for id, expiresAt := range s.sessions {
+		if !expiresAt.IsZero() && now.After(expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/pkg/loggerx"
+)
+
+// SessionActivityBatcher implements the SessionActivityRecorder interface by collecting
+// the last activity of sessions in memory and saving it to the repository every interval,
+// in a single write. Activity recorded since the last flush is lost if the process crashes.
+type SessionActivityBatcher struct {
+	repo     output.SessionRepository
+	log      *loggerx.Logger
+	mu       sync.Mutex
+	pending  map[string]time.Time
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewSessionActivityBatcher creates a new SessionActivityBatcher flushing to repo every interval
+func NewSessionActivityBatcher(repo output.SessionRepository, interval time.Duration, log *loggerx.Logger) *SessionActivityBatcher {
+	b := &SessionActivityBatcher{
+		repo:    repo,
+		log:     log,
+		pending: make(map[string]time.Time),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go b.run(interval)
+	return b
+}
+
+// Record records that a session was active at a time; only the latest time of each session is kept
+func (b *SessionActivityBatcher) Record(sessionID string, at time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if at.After(b.pending[sessionID]) {
+		b.pending[sessionID] = at
+	}
+}
+
+// Flush saves the recorded activity, which is recorded again if saving fails
+func (b *SessionActivityBatcher) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	activity := b.pending
+	b.pending = make(map[string]time.Time)
+	b.mu.Unlock()
+
+	if len(activity) == 0 {
+		return nil
+	}
+
+	if err := b.repo.SaveActivity(ctx, activity); err != nil {
+		for id, at := range activity {
+			b.Record(id, at)
+		}
+		return err
+	}
+	return nil
+}
+
+// Close stops the periodic flushes and saves the activity recorded since the last one
+func (b *SessionActivityBatcher) Close() error {
+	b.stopOnce.Do(func() { close(b.stop) })
+	<-b.done
+	return b.Flush(context.Background())
+}
+
+// run flushes the recorded activity every interval until Close
+func (b *SessionActivityBatcher) run(interval time.Duration) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			if err := b.Flush(context.Background()); err != nil && b.log != nil {
+				b.log.Warn("Failed to save session activity", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/storage/memory"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// activityRepo implements output.SessionRepository, keeping only the saved activity
+type activityRepo struct {
+	output.SessionRepository
+	saves []map[string]time.Time
+	err   error
+}
+
+func (r *activityRepo) SaveActivity(_ context.Context, activity map[string]time.Time) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.saves = append(r.saves, activity)
+	return nil
+}
+
+func TestSessionActivityBatcher_SavesLatestActivityOnce(t *testing.T) {
+	repo := &activityRepo{}
+	batcher := memory.NewSessionActivityBatcher(repo, time.Hour, nil)
+	now := time.Now()
+
+	batcher.Record("a", now.Add(-time.Minute))
+	batcher.Record("a", now)
+	batcher.Record("a", now.Add(-2*time.Minute))
+	batcher.Record("b", now)
+
+	require.NoError(t, batcher.Close())
+	require.Len(t, repo.saves, 1, "activity is saved in a single write")
+	assert.Equal(t, map[string]time.Time{"a": now, "b": now}, repo.saves[0])
+}
+
+func TestSessionActivityBatcher_KeepsActivityWhenSaveFails(t *testing.T) {
+	repo := &activityRepo{err: errors.New("database unavailable")}
+	batcher := memory.NewSessionActivityBatcher(repo, time.Hour, nil)
+	now := time.Now()
+
+	batcher.Record("a", now)
+	assert.Error(t, batcher.Flush(context.Background()))
+
+	repo.err = nil
+	require.NoError(t, batcher.Close())
+	require.Len(t, repo.saves, 1)
+	assert.Equal(t, now, repo.saves[0]["a"])
+}
@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+const authorizationCodeKeyPrefix = "oauth:code:"
+
+// authorizationCodeStore implements the AuthorizationCodeStore interface on top of Redis
+type authorizationCodeStore struct {
+	client *redis.Client
+}
+
+// NewAuthorizationCodeStore creates a new Redis backed AuthorizationCodeStore
+func NewAuthorizationCodeStore(svc *Service) output.AuthorizationCodeStore {
+	return &authorizationCodeStore{client: svc.GetClient()}
+}
+
+// Save keeps a code for ttl
+func (s *authorizationCodeStore) Save(ctx context.Context, code *entity.AuthorizationCode, ttl time.Duration) error {
+	data, err := json.Marshal(code)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, authorizationCodeKeyPrefix+code.Code, data, ttl).Err()
+}
+
+// Take returns and forgets a code, or nil when there is none or it expired
+func (s *authorizationCodeStore) Take(ctx context.Context, code string) (*entity.AuthorizationCode, error) {
+	data, err := s.client.GetDel(ctx, authorizationCodeKeyPrefix+code).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &entity.AuthorizationCode{}
+	if err := json.Unmarshal(data, stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+const externalLoginKeyPrefix = "login:external:"
+
+// externalLoginStore implements the ExternalLoginStore interface on top of Redis
+type externalLoginStore struct {
+	client *redis.Client
+}
+
+// NewExternalLoginStore creates a new Redis backed ExternalLoginStore
+func NewExternalLoginStore(svc *Service) output.ExternalLoginStore {
+	return &externalLoginStore{client: svc.GetClient()}
+}
+
+// Save keeps a login for ttl
+func (s *externalLoginStore) Save(ctx context.Context, login *entity.ExternalLogin, ttl time.Duration) error {
+	data, err := json.Marshal(login)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, externalLoginKeyPrefix+login.State, data, ttl).Err()
+}
+
+// Take returns and forgets the login of a state, or nil when there is none or it expired
+func (s *externalLoginStore) Take(ctx context.Context, state string) (*entity.ExternalLogin, error) {
+	data, err := s.client.GetDel(ctx, externalLoginKeyPrefix+state).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	login := &entity.ExternalLogin{}
+	if err := json.Unmarshal(data, login); err != nil {
+		return nil, err
+	}
+	return login, nil
+}
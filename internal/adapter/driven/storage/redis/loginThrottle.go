@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+const (
+	loginFailuresKeyPrefix = "login:failures:"
+	loginFailuresCount     = "count"
+	loginFailuresLast      = "last"
+)
+
+// loginThrottle implements the LoginThrottle interface on top of Redis
+type loginThrottle struct {
+	client *redis.Client
+}
+
+// NewLoginThrottle creates a new Redis backed LoginThrottle
+func NewLoginThrottle(svc *Service) output.LoginThrottle {
+	return &loginThrottle{client: svc.GetClient()}
+}
+
+// Failures returns the failures recorded for key, or nil if there are none
+func (t *loginThrottle) Failures(ctx context.Context, key string) (*entity.LoginFailures, error) {
+	values, err := t.client.HGetAll(ctx, loginFailuresKeyPrefix+key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return parseLoginFailures(values[loginFailuresCount], values[loginFailuresLast])
+}
+
+// AddFailure records a failure for key, forgotten ttl after the last one, and returns the updated count
+func (t *loginThrottle) AddFailure(ctx context.Context, key string, ttl time.Duration) (*entity.LoginFailures, error) {
+	now := time.Now()
+	redisKey := loginFailuresKeyPrefix + key
+
+	var count *redis.IntCmd
+	_, err := t.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.HIncrBy(ctx, redisKey, loginFailuresCount, 1)
+		pipe.HSet(ctx, redisKey, loginFailuresLast, now.UnixNano())
+		pipe.Expire(ctx, redisKey, ttl)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.LoginFailures{Count: int(count.Val()), LastFailure: now}, nil
+}
+
+// Reset clears the failures recorded for key
+func (t *loginThrottle) Reset(ctx context.Context, key string) error {
+	return t.client.Del(ctx, loginFailuresKeyPrefix+key).Err()
+}
+
+// parseLoginFailures decodes the fields of a failures hash
+func parseLoginFailures(count, last string) (*entity.LoginFailures, error) {
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return nil, err
+	}
+	nanos, err := strconv.ParseInt(last, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.LoginFailures{Count: n, LastFailure: time.Unix(0, nanos)}, nil
+}
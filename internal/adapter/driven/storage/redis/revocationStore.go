@@ -0,0 +1,65 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+const (
+	revokedSessionKeyPrefix = "revoked:session:"
+	revokedUserKeyPrefix    = "revoked:user:"
+)
+
+// revocationStore implements the RevocationStore interface on top of Redis
+type revocationStore struct {
+	client *redis.Client
+}
+
+// NewRevocationStore creates a new Redis backed RevocationStore
+func NewRevocationStore(svc *Service) output.RevocationStore {
+	return &revocationStore{client: svc.GetClient()}
+}
+
+// RevokeSession marks a session as revoked for ttl (zero keeps it forever)
+func (s *revocationStore) RevokeSession(ctx context.Context, sessionID string, ttl time.Duration) error {
+	return s.client.Set(ctx, revokedSessionKeyPrefix+sessionID, 1, ttl).Err()
+}
+
+// IsSessionRevoked checks if a session was revoked
+func (s *revocationStore) IsSessionRevoked(ctx context.Context, sessionID string) (bool, error) {
+	count, err := s.client.Exists(ctx, revokedSessionKeyPrefix+sessionID).Result()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// RevokeUser marks every session of a user created until now as revoked
+func (s *revocationStore) RevokeUser(ctx context.Context, userID uint) error {
+	return s.client.Set(ctx, fmt.Sprintf("%s%d", revokedUserKeyPrefix, userID), time.Now().UnixNano(), 0).Err()
+}
+
+// UserRevokedAt returns when the user's sessions were last revoked, or nil if never
+func (s *revocationStore) UserRevokedAt(ctx context.Context, userID uint) (*time.Time, error) {
+	val, err := s.client.Get(ctx, fmt.Sprintf("%s%d", revokedUserKeyPrefix, userID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nanos, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	revokedAt := time.Unix(0, nanos)
+	return &revokedAt, nil
+}
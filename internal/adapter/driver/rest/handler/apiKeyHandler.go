@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"github.com/gofiber/contrib/fiberi18n/v2"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/middleware"
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/presenter"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/input"
+)
+
+// APIKeyHandler handles the API key endpoints of the authenticated user
+type APIKeyHandler struct {
+	useCase     input.APIKeyUseCase
+	handleError func(*fiber.Ctx, error) error
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler and registers routes
+func NewAPIKeyHandler(router fiber.Router, useCase input.APIKeyUseCase, accessAuth fiber.Handler) {
+	handler := &APIKeyHandler{
+		useCase: useCase,
+		handleError: middleware.NewErrorHandler(middleware.ErrorMapping{
+			"*": {
+				gorm.ErrRecordNotFound: {fiber.StatusNotFound, "apiKeyNotFound"},
+			},
+		}),
+	}
+
+	apiKeyInputDTO := middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyDTO,
+		OnLookup:   middleware.Body,
+		Model:      &dto.APIKeyInput{},
+	})
+
+	idParamDTO := middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyID,
+		OnLookup:   middleware.Params,
+		Model: &struct {
+			ID uint `params:"id"`
+		}{},
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return presenter.BadRequest(c, "invalidID")
+		},
+	})
+
+	// Keys are managed from interactive sessions only
+	router.Use(accessAuth, middleware.RequireSession())
+	router.Get("", handler.getAPIKeys)
+	router.Post("", apiKeyInputDTO, handler.createAPIKey)
+	router.Delete("/:id", idParamDTO, handler.revokeAPIKey)
+}
+
+// getAPIKeys godoc
+// @Summary      Get API keys
+// @Description  Get the API keys of the authenticated user
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Success      200  {array}   	dto.APIKeyOutput
+// @Failure      401,403,500  {object}  	presenter.Response
+// @Router       /auth/keys [get]
+// @Security	 Bearer
+func (h *APIKeyHandler) getAPIKeys(c *fiber.Ctx) error {
+	keys, err := h.useCase.GetAPIKeys(c.UserContext(), middleware.GetUserID(c))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(keys)
+}
+
+// createAPIKey godoc
+// @Summary      Create API key
+// @Description  Create an API key for the authenticated user, limited to scopes granted by the user's profile. The key is only returned once.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        key				body		dto.APIKeyInput		true	"API key model"
+// @Success      201  {object}  	dto.APIKeyCreatedOutput
+// @Failure      400,401,403,500  {object}  	presenter.Response
+// @Router       /auth/keys [post]
+// @Security	 Bearer
+func (h *APIKeyHandler) createAPIKey(c *fiber.Ctx) error {
+	keyDTO := GetLocal[dto.APIKeyInput](c, middleware.CtxKeyDTO)
+
+	key, err := h.useCase.CreateAPIKey(c.UserContext(), middleware.GetUserID(c), keyDTO)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(key)
+}
+
+// revokeAPIKey godoc
+// @Summary      Revoke API key
+// @Description  Revoke an API key of the authenticated user
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		uint				true	"API key ID"
+// @Success      200  {object}  	nil
+// @Failure      400,401,403,404,500  {object}  	presenter.Response
+// @Router       /auth/keys/{id} [delete]
+// @Security	 Bearer
+func (h *APIKeyHandler) revokeAPIKey(c *fiber.Ctx) error {
+	idStruct := GetLocal[struct {
+		ID uint `params:"id"`
+	}](c, middleware.CtxKeyID)
+
+	if err := h.useCase.RevokeAPIKey(c.UserContext(), middleware.GetUserID(c), idStruct.ID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "apiKeyRevoked"), nil)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/middleware"
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/presenter"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/input"
+)
+
+// AuditHandler handles the audit log endpoints
+type AuditHandler struct {
+	useCase     input.AuditUseCase
+	handleError func(*fiber.Ctx, error) error
+}
+
+// NewAuditHandler creates a new AuditHandler and registers routes
+func NewAuditHandler(router fiber.Router, useCase input.AuditUseCase, accessAuth fiber.Handler) {
+	handler := &AuditHandler{
+		useCase:     useCase,
+		handleError: middleware.NewErrorHandler(middleware.ErrorMapping{}),
+	}
+
+	auditFilterDTO := middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: localFilter,
+		OnLookup:   middleware.Query,
+		Model:      &dto.AuditFilter{},
+	})
+
+	router.Use(accessAuth)
+	router.Get("", middleware.RequirePermission(entity.PermissionAuditRead), auditFilterDTO, handler.getAuditRecords)
+}
+
+// getAuditRecords godoc
+// @Summary      Get audit records
+// @Description  Get the audit log of the changes made to users, profiles and sessions, most recent first
+// @Tags         Audit
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header	bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        pgfilter			query	dto.AuditFilter		false	"Audit Filter"
+// @Success      200  {object}   	dto.PaginatedOutput[dto.AuditOutput]
+// @Failure      401,403,500  {object}  	presenter.Response
+// @Router       /audit [get]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *AuditHandler) getAuditRecords(c *fiber.Ctx) error {
+	filter := c.Locals(localFilter).(*dto.AuditFilter)
+
+	response, err := h.useCase.GetAuditRecords(c.UserContext(), filter)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.Success(c, response)
+}
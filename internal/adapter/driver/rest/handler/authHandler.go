@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"time"
+
 	"github.com/gofiber/contrib/fiberi18n/v2"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -9,8 +11,13 @@ import (
 	"github.com/raulaguila/go-api/internal/adapter/driver/rest/presenter"
 	"github.com/raulaguila/go-api/internal/core/dto"
 	"github.com/raulaguila/go-api/internal/core/port/input"
+	"github.com/raulaguila/go-api/pkg/apperror"
 )
 
+// externalLoginCookie binds an external login to the browser that started it,
+// so a victim cannot be made to complete a login started by an attacker
+const externalLoginCookie = "oidc_state"
+
 // AuthHandler handles authentication endpoints
 type AuthHandler struct {
 	useCase     input.AuthUseCase
@@ -32,21 +39,59 @@ func NewAuthHandler(router fiber.Router, useCase input.AuthUseCase, accessAuth,
 		}),
 	}
 
+	// Credentials are only managed from sessions, never with an API key
+	requireSession := middleware.RequireSession()
+
 	router.Post("", handler.login)
 	router.Get("", accessAuth, handler.me)
 	router.Put("", refreshAuth, handler.refresh)
+	router.Delete("", accessAuth, requireSession, handler.logout)
+	router.Delete("/all", accessAuth, requireSession, handler.logoutAll)
+	router.Post("/impersonate/:id", accessAuth, requireSession, middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyID,
+		OnLookup:   middleware.Params,
+		Model: &struct {
+			ID uint `params:"id"`
+		}{},
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return presenter.BadRequest(c, "invalidID")
+		},
+	}), handler.impersonate)
+	router.Put("/password", middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyDTO,
+		OnLookup:   middleware.Body,
+		Model:      &dto.ExpiredPasswordInput{},
+	}), handler.changeExpiredPassword)
+
+	// Two-factor authentication
+	twoFactorCodeDTO := middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyDTO,
+		OnLookup:   middleware.Body,
+		Model:      &dto.TwoFactorCodeInput{},
+	})
+
+	// Login with external identity providers
+	router.Get("/oidc", handler.externalProviders)
+	router.Get("/oidc/:provider", handler.beginExternalLogin)
+	router.Get("/oidc/:provider/callback", handler.completeExternalLogin)
+
+	router.Post("/2fa", handler.verifyTwoFactor)
+	router.Post("/2fa/setup", accessAuth, requireSession, handler.setupTwoFactor)
+	router.Put("/2fa/setup", accessAuth, requireSession, twoFactorCodeDTO, handler.confirmTwoFactor)
+	router.Delete("/2fa", accessAuth, requireSession, twoFactorCodeDTO, handler.disableTwoFactor)
 }
 
 // login godoc
 // @Summary      User authentication
-// @Description  User authentication
+// @Description  Authenticate with a username or email and a password. Unknown users get the same answer as wrong passwords.
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
 // @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        credentials		body	dto.LoginInput	true	"Credentials model"
-// @Success      200  {object}  	dto.AuthOutput
+// @Success      200  {object}  	dto.AuthOutput	"Tokens, or a challenge token when 2FA or a password change is required"
 // @Failure      401  {object}  	presenter.Response
+// @Failure      429  {object}  	presenter.Response	"Too many failed attempts, see the Retry-After header"
 // @Failure      500  {object}  	presenter.Response
 // @Router       /auth [post]
 func (h *AuthHandler) login(c *fiber.Ctx) error {
@@ -54,8 +99,10 @@ func (h *AuthHandler) login(c *fiber.Ctx) error {
 	if err := c.BodyParser(credentials); err != nil {
 		return presenter.BadRequest(c, fiberi18n.MustLocalize(c, "invalidData"))
 	}
+	credentials.UserAgent = c.Get(fiber.HeaderUserAgent)
+	credentials.IP = c.IP()
 
-	authResponse, err := h.useCase.Login(c.Context(), credentials)
+	authResponse, err := h.useCase.Login(c.UserContext(), credentials)
 	if err != nil {
 		return h.handleError(c, err)
 	}
@@ -76,17 +123,22 @@ func (h *AuthHandler) login(c *fiber.Ctx) error {
 // @Failure      500  {object}  	presenter.Response
 // @Router       /auth [get]
 // @Security	 Bearer
+// @Security	 APIKey
 func (h *AuthHandler) me(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	if userID == 0 {
 		return presenter.Unauthorized(c, fiberi18n.MustLocalize(c, "unauthorized"))
 	}
 
-	user, err := h.useCase.Me(c.Context(), userID)
+	user, err := h.useCase.Me(c.UserContext(), userID)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
+	if actor := middleware.GetActor(c); actor != nil {
+		user.ImpersonatedBy = dto.EntityToUserOutput(actor)
+	}
+
 	return c.Status(fiber.StatusOK).JSON(user)
 }
 
@@ -104,16 +156,338 @@ func (h *AuthHandler) me(c *fiber.Ctx) error {
 // @Failure      500  {object}  	presenter.Response
 // @Router       /auth [put]
 func (h *AuthHandler) refresh(c *fiber.Ctx) error {
+	session := middleware.GetSession(c)
+	if session == nil {
+		return presenter.Unauthorized(c, fiberi18n.MustLocalize(c, "unauthorized"))
+	}
+
+	expire := c.Query("expire", "true") == "true"
+	authResponse, err := h.useCase.Refresh(c.UserContext(), session.ID, middleware.GetTokenID(c), expire)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(authResponse)
+}
+
+// logout godoc
+// @Summary      User logout
+// @Description  Revoke the current session
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization		header	string				false	"User token"
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Success      200  {object}  	presenter.Response
+// @Failure      401  {object}  	presenter.Response
+// @Failure      500  {object}  	presenter.Response
+// @Router       /auth [delete]
+// @Security	 Bearer
+func (h *AuthHandler) logout(c *fiber.Ctx) error {
+	session := middleware.GetSession(c)
+	if session == nil {
+		return presenter.Unauthorized(c, fiberi18n.MustLocalize(c, "unauthorized"))
+	}
+
+	if err := h.useCase.Logout(c.UserContext(), session.ID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "loggedOut"), nil)
+}
+
+// logoutAll godoc
+// @Summary      User logout from all devices
+// @Description  Revoke every session of the authenticated user
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization		header	string				false	"User token"
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Success      200  {object}  	presenter.Response
+// @Failure      401  {object}  	presenter.Response
+// @Failure      500  {object}  	presenter.Response
+// @Router       /auth/all [delete]
+// @Security	 Bearer
+func (h *AuthHandler) logoutAll(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	if userID == 0 {
 		return presenter.Unauthorized(c, fiberi18n.MustLocalize(c, "unauthorized"))
 	}
 
+	if err := h.useCase.LogoutAll(c.UserContext(), userID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "loggedOut"), nil)
+}
+
+// externalProviders godoc
+// @Summary      External identity providers
+// @Description  List the identity providers users can log in with
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}  	string
+// @Router       /auth/oidc [get]
+func (h *AuthHandler) externalProviders(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(h.useCase.ExternalProviders())
+}
+
+// beginExternalLogin godoc
+// @Summary      External login
+// @Description  Redirect to the login page of an identity provider, which redirects back to the callback
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        provider			path	string				true	"Identity provider"
+// @Param        expire				query	bool				false	"Expire token"
+// @Success      302
+// @Failure      404  {object}  	presenter.Response
+// @Failure      500  {object}  	presenter.Response
+// @Router       /auth/oidc/{provider} [get]
+func (h *AuthHandler) beginExternalLogin(c *fiber.Ctx) error {
 	expire := c.Query("expire", "true") == "true"
-	authResponse, err := h.useCase.Refresh(c.Context(), userID, expire)
+	login, err := h.useCase.BeginExternalLogin(c.UserContext(), c.Params("provider"), expire)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     externalLoginCookie,
+		Value:    login.State,
+		Path:     "/auth/oidc",
+		Secure:   c.Protocol() == "https",
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	return c.Redirect(login.URL, fiber.StatusFound)
+}
+
+// completeExternalLogin godoc
+// @Summary      External login callback
+// @Description  Complete a login when the identity provider redirects back, linking or creating the user on the first login
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        provider			path	string				true	"Identity provider"
+// @Param        code				query	string				true	"Authorization code"
+// @Param        state				query	string				true	"State"
+// @Success      200  {object}  	dto.AuthOutput	"Tokens, or a challenge token when 2FA is required"
+// @Failure      400,401,403,409  {object}  	presenter.Response
+// @Failure      500  {object}  	presenter.Response
+// @Router       /auth/oidc/{provider}/callback [get]
+func (h *AuthHandler) completeExternalLogin(c *fiber.Ctx) error {
+	state := c.Cookies(externalLoginCookie)
+	c.Cookie(&fiber.Cookie{
+		Name:     externalLoginCookie,
+		Path:     "/auth/oidc",
+		Expires:  time.Unix(0, 0),
+		HTTPOnly: true,
+	})
+
+	input := &dto.ExternalLoginInput{
+		Provider:  c.Params("provider"),
+		Code:      c.Query("code"),
+		State:     c.Query("state"),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IP:        c.IP(),
+	}
+
+	// The provider reports refused logins with an error parameter instead of a code
+	if c.Query("error") != "" || state == "" || state != input.State {
+		return h.handleError(c, apperror.ExternalLoginFailed(nil))
+	}
+
+	authResponse, err := h.useCase.CompleteExternalLogin(c.UserContext(), input)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(authResponse)
 }
+
+// impersonate godoc
+// @Summary      Impersonate user
+// @Description  Get a short-lived access token acting as a user, with the user's permissions. Only root users can impersonate, and only read requests are accepted with the token.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization		header	string				false	"User token"
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path	uint				true	"User ID"
+// @Success      200  {object}  	dto.AuthOutput	"Access token, without refresh token"
+// @Failure      400  {object}  	presenter.Response
+// @Failure      401  {object}  	presenter.Response
+// @Failure      403  {object}  	presenter.Response
+// @Failure      404  {object}  	presenter.Response
+// @Failure      500  {object}  	presenter.Response
+// @Router       /auth/impersonate/{id} [post]
+// @Security	 Bearer
+func (h *AuthHandler) impersonate(c *fiber.Ctx) error {
+	idStruct := GetLocal[struct {
+		ID uint `params:"id"`
+	}](c, middleware.CtxKeyID)
+
+	authResponse, err := h.useCase.Impersonate(c.UserContext(), &dto.ImpersonationInput{
+		ActorID:   middleware.GetUserID(c),
+		UserID:    idStruct.ID,
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IP:        c.IP(),
+	})
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(authResponse)
+}
+
+// changeExpiredPassword godoc
+// @Summary      Change expired password
+// @Description  Replace an expired password using the challenge token returned by the login, then log in again
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string						false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        password			body	dto.ExpiredPasswordInput	true	"Challenge token and new password"
+// @Success      200  {object}  	presenter.Response
+// @Failure      400  {object}  	presenter.Response
+// @Failure      401  {object}  	presenter.Response
+// @Failure      500  {object}  	presenter.Response
+// @Router       /auth/password [put]
+func (h *AuthHandler) changeExpiredPassword(c *fiber.Ctx) error {
+	input := GetLocal[dto.ExpiredPasswordInput](c, middleware.CtxKeyDTO)
+	if err := h.useCase.ChangeExpiredPassword(c.UserContext(), input); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "passSet"), nil)
+}
+
+// verifyTwoFactor godoc
+// @Summary      Two-factor authentication
+// @Description  Complete a login that requires a second factor, using a TOTP or recovery code
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        code				body	dto.TwoFactorInput	true	"Challenge token and code"
+// @Success      200  {object}  	dto.AuthOutput
+// @Failure      400  {object}  	presenter.Response
+// @Failure      401  {object}  	presenter.Response
+// @Failure      429  {object}  	presenter.Response	"Too many failed attempts, see the Retry-After header"
+// @Failure      500  {object}  	presenter.Response
+// @Router       /auth/2fa [post]
+func (h *AuthHandler) verifyTwoFactor(c *fiber.Ctx) error {
+	input := new(dto.TwoFactorInput)
+	if err := c.BodyParser(input); err != nil {
+		return presenter.BadRequest(c, fiberi18n.MustLocalize(c, "invalidData"))
+	}
+	input.UserAgent = c.Get(fiber.HeaderUserAgent)
+	input.IP = c.IP()
+
+	authResponse, err := h.useCase.VerifyTwoFactor(c.UserContext(), input)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(authResponse)
+}
+
+// setupTwoFactor godoc
+// @Summary      Start 2FA enrollment
+// @Description  Generate a TOTP secret to be registered in an authenticator app
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization		header	string				false	"User token"
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Success      200  {object}  	dto.TwoFactorSetupOutput
+// @Failure      401  {object}  	presenter.Response
+// @Failure      409  {object}  	presenter.Response
+// @Failure      500  {object}  	presenter.Response
+// @Router       /auth/2fa/setup [post]
+// @Security	 Bearer
+func (h *AuthHandler) setupTwoFactor(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return presenter.Unauthorized(c, fiberi18n.MustLocalize(c, "unauthorized"))
+	}
+
+	setup, err := h.useCase.SetupTwoFactor(c.UserContext(), userID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(setup)
+}
+
+// confirmTwoFactor godoc
+// @Summary      Confirm 2FA enrollment
+// @Description  Activate 2FA with a code of the authenticator app and get the recovery codes
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization		header	string					false	"User token"
+// @Param        Accept-Language	header	string					false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        code				body	dto.TwoFactorCodeInput	true	"TOTP code"
+// @Success      200  {object}  	dto.RecoveryCodesOutput
+// @Failure      400  {object}  	presenter.Response
+// @Failure      401  {object}  	presenter.Response
+// @Failure      500  {object}  	presenter.Response
+// @Router       /auth/2fa/setup [put]
+// @Security	 Bearer
+func (h *AuthHandler) confirmTwoFactor(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return presenter.Unauthorized(c, fiberi18n.MustLocalize(c, "unauthorized"))
+	}
+
+	input := GetLocal[dto.TwoFactorCodeInput](c, middleware.CtxKeyDTO)
+	if err := input.Validate(); err != nil {
+		return h.handleError(c, err)
+	}
+
+	codes, err := h.useCase.ConfirmTwoFactor(c.UserContext(), userID, input.Code)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(codes)
+}
+
+// disableTwoFactor godoc
+// @Summary      Disable 2FA
+// @Description  Turn 2FA off, confirming with a TOTP or recovery code
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization		header	string					false	"User token"
+// @Param        Accept-Language	header	string					false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        code				body	dto.TwoFactorCodeInput	true	"TOTP or recovery code"
+// @Success      200  {object}  	presenter.Response
+// @Failure      400  {object}  	presenter.Response
+// @Failure      401  {object}  	presenter.Response
+// @Failure      403  {object}  	presenter.Response
+// @Failure      500  {object}  	presenter.Response
+// @Router       /auth/2fa [delete]
+// @Security	 Bearer
+func (h *AuthHandler) disableTwoFactor(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return presenter.Unauthorized(c, fiberi18n.MustLocalize(c, "unauthorized"))
+	}
+
+	input := GetLocal[dto.TwoFactorCodeInput](c, middleware.CtxKeyDTO)
+	if err := input.Validate(); err != nil {
+		return h.handleError(c, err)
+	}
+
+	if err := h.useCase.DisableTwoFactor(c.UserContext(), userID, input.Code); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "twoFactorDisabled"), nil)
+}
@@ -1,12 +1,21 @@
 package handler
 
 import (
+	"bytes"
 	"net/url"
+	"strconv"
+	"strings"
 
+	"github.com/gofiber/contrib/fiberi18n/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/raulaguila/go-api/internal/adapter/driver/rest/middleware"
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/presenter"
+	"github.com/raulaguila/go-api/internal/core/dto"
 )
 
+// headerAcceptPatch lists the patch formats PATCH requests accept (RFC 5789)
+const headerAcceptPatch = "Accept-Patch"
+
 // GetLocal retrieves a value from Fiber locals safely cast to T.
 func GetLocal[T any](c *fiber.Ctx, key middleware.CtxKey) *T {
 	val := c.Locals(key)
@@ -20,3 +29,52 @@ func GetLocal[T any](c *fiber.Ctx, key middleware.CtxKey) *T {
 func GetQuery(c *fiber.Ctx, key string) (string, error) {
 	return url.QueryUnescape(c.Query(key, ""))
 }
+
+// SetETag sets the ETag header to the version of the resource a response carries.
+func SetETag(c *fiber.Ctx, version *uint) {
+	if version != nil {
+		c.Set(fiber.HeaderETag, strconv.Quote(strconv.FormatUint(uint64(*version), 10)))
+	}
+}
+
+// IfMatch returns the versions the If-Match header conditions a write on, nil when the header is
+// absent or "*"; the write proceeds if any of them is current. If-Match uses the strong comparison,
+// so weak tags never match, nor do tags that are not ours (RFC 9110 §13.1.1): ok is false when the
+// header lists no other tag.
+func IfMatch(c *fiber.Ctx) (versions []uint, ok bool) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" || header == "*" {
+		return nil, true
+	}
+
+	for member := range strings.SplitSeq(header, ",") {
+		member = strings.TrimSpace(member)
+		tag, err := strconv.Unquote(member)
+		if err != nil || !strings.HasPrefix(member, `"`) {
+			continue
+		}
+		if parsed, err := strconv.ParseUint(tag, 10, 0); err == nil {
+			versions = append(versions, uint(parsed))
+		}
+	}
+	return versions, versions != nil
+}
+
+// ParsePatch reads a partial update from the body, in the patch format its Content-Type names.
+// It returns nil for other media types, to be refused with UnsupportedPatch.
+func ParsePatch(c *fiber.Ctx) *dto.PatchInput {
+	mediaType, _, _ := strings.Cut(c.Get(fiber.HeaderContentType), ";")
+	format := dto.PatchFormat(strings.ToLower(strings.TrimSpace(mediaType)))
+	if format != dto.MergePatch && format != dto.JSONPatch {
+		return nil
+	}
+
+	// The body is only valid until the handler returns
+	return &dto.PatchInput{Format: format, Patch: bytes.Clone(c.Body())}
+}
+
+// UnsupportedPatch refuses a PATCH request in an unknown format, listing those accepted.
+func UnsupportedPatch(c *fiber.Ctx) error {
+	c.Set(headerAcceptPatch, string(dto.MergePatch)+", "+string(dto.JSONPatch))
+	return presenter.New(c, fiber.StatusUnsupportedMediaType, fiberi18n.MustLocalize(c, "unsupportedPatch"), nil)
+}
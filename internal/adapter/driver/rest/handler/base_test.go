@@ -0,0 +1,59 @@
+package handler_test
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/handler"
+)
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name, header string
+		versions     string // Empty for no version
+		ok           bool
+	}{
+		{"absent", "", "", true},
+		{"any", "*", "", true},
+		{"strong tag", `"3"`, "3", true},
+		{"several tags", `"3", "4"`, "3,4", true},
+		{"weak and strong tags", `W/"3", "4"`, "4", true},
+		{"weak tag", `W/"3"`, "", false},
+		{"unquoted", "3", "", false},
+		{"backquoted", "`3`", "", false},
+		{"foreign tag", `"abc"`, "", false},
+		{"weak tags", `W/"3", W/"4"`, "", false},
+	}
+
+	app := fiber.New()
+	app.Put("/", func(c *fiber.Ctx) error {
+		versions, ok := handler.IfMatch(c)
+		c.Set("X-Ok", strconv.FormatBool(ok))
+		tags := make([]string, len(versions))
+		for i, v := range versions {
+			tags[i] = strconv.FormatUint(uint64(v), 10)
+		}
+		c.Set("X-Versions", strings.Join(tags, ","))
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodPut, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(fiber.HeaderIfMatch, tt.header)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, strconv.FormatBool(tt.ok), resp.Header.Get("X-Ok"))
+			assert.Equal(t, tt.versions, resp.Header.Get("X-Versions"))
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"github.com/gofiber/contrib/fiberi18n/v2"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/middleware"
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/presenter"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/input"
+)
+
+// OAuthClientHandler handles the registration of the OAuth clients logging users in with the API
+type OAuthClientHandler struct {
+	useCase     input.OAuthUseCase
+	handleError func(*fiber.Ctx, error) error
+}
+
+// NewOAuthClientHandler creates a new OAuthClientHandler and registers routes
+func NewOAuthClientHandler(router fiber.Router, useCase input.OAuthUseCase, accessAuth fiber.Handler) {
+	handler := &OAuthClientHandler{
+		useCase: useCase,
+		handleError: middleware.NewErrorHandler(middleware.ErrorMapping{
+			"*": {
+				gorm.ErrRecordNotFound: {fiber.StatusNotFound, "oauthClientNotFound"},
+			},
+		}),
+	}
+
+	clientInputDTO := middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyDTO,
+		OnLookup:   middleware.Body,
+		Model:      &dto.OAuthClientInput{},
+	})
+
+	idParamDTO := middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyID,
+		OnLookup:   middleware.Params,
+		Model: &struct {
+			ID uint `params:"id"`
+		}{},
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return presenter.BadRequest(c, "invalidID")
+		},
+	})
+
+	canRead := middleware.RequirePermission(entity.PermissionClientsRead)
+	canWrite := middleware.RequirePermission(entity.PermissionClientsWrite)
+
+	// OAuth clients are shared by every organization, so only super-admins manage them
+	router.Use(accessAuth, middleware.RequireSuperAdmin())
+	router.Get("", canRead, handler.getClients)
+	router.Post("", canWrite, clientInputDTO, handler.createClient)
+	router.Delete("/:id", canWrite, idParamDTO, handler.deleteClient)
+}
+
+// getClients godoc
+// @Summary      Get OAuth clients
+// @Description  Get the registered OAuth clients
+// @Tags         OAuth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Success      200  {array}   	dto.OAuthClientOutput
+// @Failure      401,403,500  {object}  	presenter.Response
+// @Router       /oauth/clients [get]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *OAuthClientHandler) getClients(c *fiber.Ctx) error {
+	clients, err := h.useCase.GetClients(c.UserContext())
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(clients)
+}
+
+// createClient godoc
+// @Summary      Create OAuth client
+// @Description  Register an OAuth client. Confidential clients get a secret, which is only returned once; public clients must use PKCE.
+// @Tags         OAuth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string					false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        client				body		dto.OAuthClientInput	true	"OAuth client model"
+// @Success      201  {object}  	dto.OAuthClientCreatedOutput
+// @Failure      400,401,403,500  {object}  	presenter.Response
+// @Router       /oauth/clients [post]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *OAuthClientHandler) createClient(c *fiber.Ctx) error {
+	clientDTO := GetLocal[dto.OAuthClientInput](c, middleware.CtxKeyDTO)
+
+	client, err := h.useCase.CreateClient(c.UserContext(), clientDTO)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(client)
+}
+
+// deleteClient godoc
+// @Summary      Delete OAuth client
+// @Description  Delete an OAuth client, ending the sessions of its users
+// @Tags         OAuth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		uint				true	"OAuth client ID"
+// @Success      200  {object}  	nil
+// @Failure      400,401,403,404,500  {object}  	presenter.Response
+// @Router       /oauth/clients/{id} [delete]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *OAuthClientHandler) deleteClient(c *fiber.Ctx) error {
+	idStruct := GetLocal[struct {
+		ID uint `params:"id"`
+	}](c, middleware.CtxKeyID)
+
+	if err := h.useCase.DeleteClient(c.UserContext(), idStruct.ID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "oauthClientDeleted"), nil)
+}
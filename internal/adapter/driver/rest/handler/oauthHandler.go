@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/middleware"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/input"
+	"github.com/raulaguila/go-api/pkg/oidc"
+)
+
+// OAuthHandler handles the endpoints of the OAuth 2.0 / OpenID Connect provider,
+// which answer with the protocol's error format instead of the API's
+type OAuthHandler struct {
+	useCase     input.OAuthUseCase
+	handleError func(*fiber.Ctx, error) error
+}
+
+// NewOAuthHandler creates a new OAuthHandler and registers routes
+func NewOAuthHandler(router fiber.Router, useCase input.OAuthUseCase, accessAuth fiber.Handler) {
+	handler := &OAuthHandler{
+		useCase: useCase,
+		handleError: middleware.NewErrorHandler(middleware.ErrorMapping{
+			"*": {
+				gorm.ErrRecordNotFound: {fiber.StatusNotFound, "userNotFound"},
+			},
+		}),
+	}
+
+	authorizeDTO := middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyDTO,
+		OnLookup:   middleware.Body,
+		Model:      &dto.AuthorizeInput{},
+	})
+
+	router.Get("/authorize", handler.requestAuthorization)
+	router.Post("/authorize", accessAuth, middleware.RequireSession(), authorizeDTO, handler.authorize)
+	router.Post("/token", handler.token)
+	router.Get("/userinfo", accessAuth, handler.userInfo)
+	router.Post("/userinfo", accessAuth, handler.userInfo)
+	router.Post("/revoke", handler.revoke)
+}
+
+// requestAuthorization godoc
+// @Summary      Authorization endpoint
+// @Description  Start an OAuth 2.0 authorization code flow: redirect to the login page, which calls the authorization endpoint with POST once the user logged in. Requests of unknown clients or redirect URIs are refused, other errors are redirected to the client.
+// @Tags         OAuth
+// @Produce      json
+// @Param        request	query		dto.AuthorizeInput	true	"Authorization request"
+// @Success      302
+// @Failure      400  {object}  	oidc.Error
+// @Router       /oauth/authorize [get]
+func (h *OAuthHandler) requestAuthorization(c *fiber.Ctx) error {
+	request := &dto.AuthorizeInput{}
+	if err := c.QueryParser(request); err != nil {
+		return h.oauthError(c, &oidc.Error{Code: oidc.ErrorInvalidRequest})
+	}
+
+	authorization, err := h.useCase.RequestAuthorization(c.UserContext(), request)
+	if err != nil {
+		return h.oauthError(c, err)
+	}
+
+	return c.Redirect(authorization.RedirectURI, fiber.StatusFound)
+}
+
+// authorize godoc
+// @Summary      Authorize client
+// @Description  Authorize a client on behalf of the authenticated user and return where to redirect the browser, with an authorization code or an error
+// @Tags         OAuth
+// @Accept       json
+// @Produce      json
+// @Param        request	body		dto.AuthorizeInput	true	"Authorization request"
+// @Success      200  {object}  	dto.AuthorizeOutput
+// @Failure      400  {object}  	oidc.Error
+// @Router       /oauth/authorize [post]
+// @Security	 Bearer
+func (h *OAuthHandler) authorize(c *fiber.Ctx) error {
+	request := GetLocal[dto.AuthorizeInput](c, middleware.CtxKeyDTO)
+
+	// The ID token reports when the user logged in, which is when the session started
+	authTime := time.Now()
+	if session := middleware.GetSession(c); session != nil {
+		authTime = session.CreatedAt
+	}
+
+	authorization, err := h.useCase.Authorize(c.UserContext(), middleware.GetUserID(c), authTime, request)
+	if err != nil {
+		return h.oauthError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(authorization)
+}
+
+// token godoc
+// @Summary      Token endpoint
+// @Description  Exchange an authorization code, a refresh token or the client's credentials for tokens. Clients authenticate with HTTP Basic or the client_id and client_secret parameters.
+// @Tags         OAuth
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Param        grant_type		formData	string	true	"Grant type" enums(authorization_code,refresh_token,client_credentials)
+// @Param        code			formData	string	false	"Authorization code"
+// @Param        redirect_uri	formData	string	false	"Redirect URI of the authorization request"
+// @Param        code_verifier	formData	string	false	"PKCE code verifier"
+// @Param        refresh_token	formData	string	false	"Refresh token"
+// @Param        scope			formData	string	false	"Scopes of client credentials tokens"
+// @Param        client_id		formData	string	false	"Client ID"
+// @Param        client_secret	formData	string	false	"Client secret"
+// @Success      200  {object}  	oidc.Tokens
+// @Failure      400,401  {object}  	oidc.Error
+// @Router       /oauth/token [post]
+func (h *OAuthHandler) token(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	request := &dto.TokenInput{}
+	if err := c.BodyParser(request); err != nil {
+		return h.oauthError(c, &oidc.Error{Code: oidc.ErrorInvalidRequest})
+	}
+	if err := clientCredentials(c, &request.ClientID, &request.ClientSecret); err != nil {
+		return h.oauthError(c, err)
+	}
+	request.UserAgent = c.Get(fiber.HeaderUserAgent)
+	request.IP = c.IP()
+
+	tokens, err := h.useCase.Token(c.UserContext(), request)
+	if err != nil {
+		return h.oauthError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(tokens)
+}
+
+// userInfo godoc
+// @Summary      UserInfo endpoint
+// @Description  Claims about the user of a token issued with the openid scope
+// @Tags         OAuth
+// @Produce      json
+// @Success      200  {object}  	dto.UserInfoOutput
+// @Failure      401,403  {object}  	oidc.Error
+// @Router       /oauth/userinfo [get]
+// @Security	 Bearer
+func (h *OAuthHandler) userInfo(c *fiber.Ctx) error {
+	session := middleware.GetSession(c)
+	if session == nil {
+		return h.oauthError(c, &oidc.Error{Code: oidc.ErrorInvalidToken})
+	}
+
+	info, err := h.useCase.UserInfo(c.UserContext(), session.UserID, session.Scopes)
+	if err != nil {
+		return h.oauthError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(info)
+}
+
+// revoke godoc
+// @Summary      Revocation endpoint
+// @Description  Revoke an access or refresh token issued to the client, ending its session. Unknown tokens are ignored.
+// @Tags         OAuth
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Param        token				formData	string	true	"Token"
+// @Param        token_type_hint	formData	string	false	"Token type" enums(access_token,refresh_token)
+// @Param        client_id			formData	string	false	"Client ID"
+// @Param        client_secret		formData	string	false	"Client secret"
+// @Success      200
+// @Failure      400,401  {object}  	oidc.Error
+// @Router       /oauth/revoke [post]
+func (h *OAuthHandler) revoke(c *fiber.Ctx) error {
+	request := &dto.RevokeTokenInput{}
+	if err := c.BodyParser(request); err != nil {
+		return h.oauthError(c, &oidc.Error{Code: oidc.ErrorInvalidRequest})
+	}
+	if err := clientCredentials(c, &request.ClientID, &request.ClientSecret); err != nil {
+		return h.oauthError(c, err)
+	}
+
+	if err := h.useCase.Revoke(c.UserContext(), request); err != nil {
+		return h.oauthError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// oauthError writes a protocol error with the status RFC 6749 and RFC 6750 give it.
+// Other errors are left to the API's error handler.
+func (h *OAuthHandler) oauthError(c *fiber.Ctx, err error) error {
+	var oauthErr *oidc.Error
+	if !errors.As(err, &oauthErr) {
+		return h.handleError(c, err)
+	}
+
+	status := fiber.StatusBadRequest
+	switch oauthErr.Code {
+	case oidc.ErrorInvalidClient:
+		status = fiber.StatusUnauthorized
+		c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+	case oidc.ErrorInvalidToken:
+		status = fiber.StatusUnauthorized
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+	case oidc.ErrorInsufficientScope:
+		status = fiber.StatusForbidden
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="insufficient_scope", scope="openid"`)
+	}
+
+	return c.Status(status).JSON(oauthErr)
+}
+
+// clientCredentials reads the client's credentials from the Basic authorization header,
+// where both are form encoded (RFC 6749 section 2.3.1), if the request has one
+func clientCredentials(c *fiber.Ctx, clientID, clientSecret *string) error {
+	header := c.Get(fiber.HeaderAuthorization)
+	if header == "" {
+		return nil
+	}
+
+	invalid := &oidc.Error{Code: oidc.ErrorInvalidClient, Description: "malformed basic authorization"}
+	encoded, found := strings.CutPrefix(header, "Basic ")
+	if !found {
+		return invalid
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return invalid
+	}
+	id, secret, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return invalid
+	}
+
+	if *clientID, err = url.QueryUnescape(id); err != nil {
+		return invalid
+	}
+	if *clientSecret, err = url.QueryUnescape(secret); err != nil {
+		return invalid
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"github.com/gofiber/contrib/fiberi18n/v2"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/middleware"
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/presenter"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/input"
+	"github.com/raulaguila/go-api/pkg/pgerror"
+)
+
+// OrganizationHandler handles the organization endpoints, reserved to super-admins
+type OrganizationHandler struct {
+	useCase     input.OrganizationUseCase
+	handleError func(*fiber.Ctx, error) error
+}
+
+// NewOrganizationHandler creates a new OrganizationHandler and registers routes
+func NewOrganizationHandler(router fiber.Router, useCase input.OrganizationUseCase, accessAuth fiber.Handler) {
+	handler := &OrganizationHandler{
+		useCase: useCase,
+		handleError: middleware.NewErrorHandler(middleware.ErrorMapping{
+			fiber.MethodDelete: {
+				pgerror.ErrForeignKeyViolated: {fiber.StatusBadRequest, "organizationUsed"},
+			},
+			"*": {
+				pgerror.ErrDuplicatedKey: {fiber.StatusConflict, "organizationRegistered"},
+				gorm.ErrRecordNotFound:   {fiber.StatusNotFound, "organizationNotFound"},
+			},
+		}),
+	}
+
+	organizationInputDTO := middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyDTO,
+		OnLookup:   middleware.Body,
+		Model:      &dto.OrganizationInput{},
+	})
+
+	idParamDTO := middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyID,
+		OnLookup:   middleware.Params,
+		Model: &struct {
+			ID uint `params:"id"`
+		}{},
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return presenter.BadRequest(c, "invalidID")
+		},
+	})
+
+	canRead := middleware.RequirePermission(entity.PermissionOrganizationsRead)
+	canWrite := middleware.RequirePermission(entity.PermissionOrganizationsWrite)
+
+	router.Use(accessAuth, middleware.RequireSuperAdmin())
+	router.Get("", canRead, handler.getOrganizations)
+	router.Get("/:id", canRead, idParamDTO, handler.getOrganization)
+	router.Post("", canWrite, organizationInputDTO, handler.createOrganization)
+	router.Put("/:id", canWrite, idParamDTO, organizationInputDTO, handler.updateOrganization)
+	router.Delete("/:id", canWrite, idParamDTO, handler.deleteOrganization)
+}
+
+// getOrganizations godoc
+// @Summary      Get organizations
+// @Description  Get every organization hosted by the deployment
+// @Tags         Organization
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Success      200  {array}   	dto.OrganizationOutput
+// @Failure      401,403,500  {object}  	presenter.Response
+// @Router       /organization [get]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *OrganizationHandler) getOrganizations(c *fiber.Ctx) error {
+	organizations, err := h.useCase.GetOrganizations(c.UserContext())
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(organizations)
+}
+
+// getOrganization godoc
+// @Summary      Get organization by ID
+// @Description  Get organization by ID
+// @Tags         Organization
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		uint				true	"Organization ID"
+// @Success      200  {object}  	dto.OrganizationOutput
+// @Failure      400,401,403,404,500  {object}  	presenter.Response
+// @Router       /organization/{id} [get]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *OrganizationHandler) getOrganization(c *fiber.Ctx) error {
+	idStruct := GetLocal[struct {
+		ID uint `params:"id"`
+	}](c, middleware.CtxKeyID)
+
+	organization, err := h.useCase.GetOrganizationByID(c.UserContext(), idStruct.ID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(organization)
+}
+
+// createOrganization godoc
+// @Summary      Insert organization
+// @Description  Insert an organization, a new tenant
+// @Tags         Organization
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool					false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string					false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        organization		body		dto.OrganizationInput	true	"Organization model"
+// @Success      201  {object}  	dto.OrganizationOutput
+// @Failure      400,401,403,409,500  {object}  	presenter.Response
+// @Router       /organization [post]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *OrganizationHandler) createOrganization(c *fiber.Ctx) error {
+	organizationDTO := GetLocal[dto.OrganizationInput](c, middleware.CtxKeyDTO)
+
+	organization, err := h.useCase.CreateOrganization(c.UserContext(), organizationDTO)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.Created(c, fiberi18n.MustLocalize(c, "organizationCreated"), organization)
+}
+
+// updateOrganization godoc
+// @Summary      Update organization by ID
+// @Description  Update organization by ID
+// @Tags         Organization
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool					false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string					false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		uint					true	"Organization ID"
+// @Param        organization		body		dto.OrganizationInput	true	"Organization model"
+// @Success      200  {object}  	dto.OrganizationOutput
+// @Failure      400,401,403,404,409,500  {object}  	presenter.Response
+// @Router       /organization/{id} [put]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *OrganizationHandler) updateOrganization(c *fiber.Ctx) error {
+	idStruct := GetLocal[struct {
+		ID uint `params:"id"`
+	}](c, middleware.CtxKeyID)
+	organizationDTO := GetLocal[dto.OrganizationInput](c, middleware.CtxKeyDTO)
+
+	organization, err := h.useCase.UpdateOrganization(c.UserContext(), idStruct.ID, organizationDTO)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "organizationUpdated"), organization)
+}
+
+// deleteOrganization godoc
+// @Summary      Delete organization by ID
+// @Description  Delete an organization, which must have no users or profiles left
+// @Tags         Organization
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		uint				true	"Organization ID"
+// @Success      200  {object}  	nil
+// @Failure      400,401,403,404,500  {object}  	presenter.Response
+// @Router       /organization/{id} [delete]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *OrganizationHandler) deleteOrganization(c *fiber.Ctx) error {
+	idStruct := GetLocal[struct {
+		ID uint `params:"id"`
+	}](c, middleware.CtxKeyID)
+
+	if err := h.useCase.DeleteOrganization(c.UserContext(), idStruct.ID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "organizationDeleted"), nil)
+}
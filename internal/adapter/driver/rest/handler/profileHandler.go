@@ -10,6 +10,7 @@ import (
 	"github.com/raulaguila/go-api/internal/core/domain/entity"
 	"github.com/raulaguila/go-api/internal/core/dto"
 	"github.com/raulaguila/go-api/internal/core/port/input"
+	"github.com/raulaguila/go-api/pkg/apperror"
 	"github.com/raulaguila/go-api/pkg/pgerror"
 )
 
@@ -75,13 +76,19 @@ func NewProfileHandler(router fiber.Router, useCase input.ProfileUseCase, access
 		},
 	})
 
+	canRead := middleware.RequirePermission(entity.PermissionProfilesRead)
+	canWrite := middleware.RequirePermission(entity.PermissionProfilesWrite)
+
 	router.Use(accessAuth)
 
-	router.Get("", profileFilterDTO, handler.getProfiles)
-	router.Get("/list", profileFilterDTO, handler.listProfiles)
-	router.Post("", profileInputDTO, handler.createProfile)
-	router.Put("/:"+paramID, idParamDTO, profileInputDTO, handler.updateProfile)
-	router.Delete("", idsBodyDTO, handler.deleteProfiles)
+	router.Get("", canRead, profileFilterDTO, handler.getProfiles)
+	router.Get("/list", canRead, profileFilterDTO, handler.listProfiles)
+	router.Post("", canWrite, profileInputDTO, handler.createProfile)
+	router.Get("/:"+paramID, canRead, idParamDTO, handler.getProfile)
+	router.Put("/:"+paramID, canWrite, idParamDTO, profileInputDTO, handler.updateProfile)
+	router.Patch("/:"+paramID, canWrite, idParamDTO, handler.patchProfile)
+	router.Delete("", canWrite, idsBodyDTO, handler.deleteProfiles)
+	router.Post("/restore", canWrite, idsBodyDTO, handler.restoreProfiles)
 }
 
 // getProfiles godoc
@@ -94,14 +101,15 @@ func NewProfileHandler(router fiber.Router, useCase input.ProfileUseCase, access
 // @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        pgfilter			query	dto.ProfileFilter	false	"Profile Filter"
 // @Success      200  {object}   	dto.PaginatedOutput[dto.ProfileOutput]
-// @Failure      500  {object}  	presenter.Response
+// @Failure      403,500  {object}  	presenter.Response
 // @Router       /profile [get]
 // @Security	 Bearer
+// @Security	 APIKey
 func (h *ProfileHandler) getProfiles(c *fiber.Ctx) error {
 	filter := c.Locals(localFilter).(*dto.ProfileFilter)
 	filter.ListRoot = h.canListRoot(c)
 
-	response, err := h.useCase.GetProfiles(c.Context(), filter)
+	response, err := h.useCase.GetProfiles(c.UserContext(), filter)
 	if err != nil {
 		return h.handleError(c, err)
 	}
@@ -119,14 +127,15 @@ func (h *ProfileHandler) getProfiles(c *fiber.Ctx) error {
 // @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        pgfilter			query	dto.ProfileFilter	false	"Profile Filter"
 // @Success      200  {array}   	dto.ItemOutput
-// @Failure      500  {object}  	presenter.Response
+// @Failure      403,500  {object}  	presenter.Response
 // @Router       /profile/list [get]
 // @Security	 Bearer
+// @Security	 APIKey
 func (h *ProfileHandler) listProfiles(c *fiber.Ctx) error {
 	filter := c.Locals(localFilter).(*dto.ProfileFilter)
 	filter.ListRoot = h.canListRoot(c)
 
-	response, err := h.useCase.ListProfiles(c.Context(), filter)
+	response, err := h.useCase.ListProfiles(c.UserContext(), filter)
 	if err != nil {
 		return h.handleError(c, err)
 	}
@@ -134,6 +143,35 @@ func (h *ProfileHandler) listProfiles(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+// getProfile godoc
+// @Summary      Get profile by ID
+// @Description  Get profile by ID, with its version in the ETag header
+// @Tags         Profile
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header	bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path    uint				true	"Profile ID"
+// @Success      200  {object}  	dto.ProfileOutput
+// @Header       200  {string}  	ETag	"Profile version"
+// @Failure      400,403,404,500  {object}  	presenter.Response
+// @Router       /profile/{id} [get]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *ProfileHandler) getProfile(c *fiber.Ctx) error {
+	id := c.Locals(localID).(*struct {
+		ID uint `params:"id"`
+	})
+
+	profile, err := h.useCase.GetProfileByID(c.UserContext(), id.ID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	SetETag(c, profile.Version)
+	return c.Status(fiber.StatusOK).JSON(profile)
+}
+
 // createProfile godoc
 // @Summary      Insert profile
 // @Description  Insert profile
@@ -144,13 +182,14 @@ func (h *ProfileHandler) listProfiles(c *fiber.Ctx) error {
 // @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        profile			body	dto.ProfileInput	true	"Profile model"
 // @Success      201  {object}  	dto.ProfileOutput
-// @Failure      400,409,500  {object}  	presenter.Response
+// @Failure      400,403,409,500  {object}  	presenter.Response
 // @Router       /profile [post]
 // @Security	 Bearer
+// @Security	 APIKey
 func (h *ProfileHandler) createProfile(c *fiber.Ctx) error {
 	profileDTO := c.Locals(localDTO).(*dto.ProfileInput)
 
-	profile, err := h.useCase.CreateProfile(c.Context(), profileDTO)
+	profile, err := h.useCase.CreateProfile(c.UserContext(), profileDTO)
 	if err != nil {
 		return h.handleError(c, err)
 	}
@@ -166,23 +205,74 @@ func (h *ProfileHandler) createProfile(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        X-Skip-Auth		header	bool				false	"Skip auth" enums(true,false) default(true)
 // @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        If-Match			header	string				false	"ETag of the profile read, refusing the update if it changed since"
 // @Param        id					path    uint				true	"Profile ID"
 // @Param        profile			body	dto.ProfileInput 	true	"Profile model"
 // @Success      200  {object}  	dto.ProfileOutput
-// @Failure      400,404,500  {object}  	presenter.Response
+// @Header       200  {string}  	ETag	"Profile version"
+// @Failure      400,403,404,409,412,500  {object}  	presenter.Response
 // @Router       /profile/{id} [put]
 // @Security	 Bearer
+// @Security	 APIKey
 func (h *ProfileHandler) updateProfile(c *fiber.Ctx) error {
 	id := c.Locals(localID).(*struct {
 		ID uint `params:"id"`
 	})
 	profileDTO := c.Locals(localDTO).(*dto.ProfileInput)
 
-	profile, err := h.useCase.UpdateProfile(c.Context(), id.ID, profileDTO)
+	versions, ok := IfMatch(c)
+	if !ok {
+		return h.handleError(c, apperror.StaleVersion("profile"))
+	}
+	profileDTO.Versions = versions
+
+	profile, err := h.useCase.UpdateProfile(c.UserContext(), id.ID, profileDTO)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
+	SetETag(c, profile.Version)
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "profileUpdated"), profile)
+}
+
+// patchProfile godoc
+// @Summary      Patch profile by ID
+// @Description  Partially update profile by ID with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902) of its document
+// @Tags         Profile
+// @Accept       application/merge-patch+json,application/json-patch+json
+// @Produce      json
+// @Param        X-Skip-Auth		header	bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        If-Match			header	string				false	"ETag of the profile read, refusing the update if it changed since"
+// @Param        id					path    uint				true	"Profile ID"
+// @Param        patch				body	dto.ProfileDocument	true	"Patch of the profile document"
+// @Success      200  {object}  	dto.ProfileOutput
+// @Header       200  {string}  	ETag	"Profile version"
+// @Failure      400,403,404,409,412,415,500  {object}  	presenter.Response
+// @Router       /profile/{id} [patch]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *ProfileHandler) patchProfile(c *fiber.Ctx) error {
+	id := c.Locals(localID).(*struct {
+		ID uint `params:"id"`
+	})
+
+	patch := ParsePatch(c)
+	if patch == nil {
+		return UnsupportedPatch(c)
+	}
+	versions, ok := IfMatch(c)
+	if !ok {
+		return h.handleError(c, apperror.StaleVersion("profile"))
+	}
+	patch.Versions = versions
+
+	profile, err := h.useCase.PatchProfile(c.UserContext(), id.ID, patch)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	SetETag(c, profile.Version)
 	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "profileUpdated"), profile)
 }
 
@@ -196,19 +286,44 @@ func (h *ProfileHandler) updateProfile(c *fiber.Ctx) error {
 // @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        ids				body	dto.IDsInput   		true	"Profiles ID"
 // @Success      204  {object}  	presenter.Response
-// @Failure      404,500  {object}  	presenter.Response
+// @Failure      403,404,500  {object}  	presenter.Response
 // @Router       /profile [delete]
 // @Security	 Bearer
+// @Security	 APIKey
 func (h *ProfileHandler) deleteProfiles(c *fiber.Ctx) error {
 	toDelete := c.Locals(localID).(*dto.IDsInput)
 
-	if err := h.useCase.DeleteProfiles(c.Context(), toDelete.IDs); err != nil {
+	if err := h.useCase.DeleteProfiles(c.UserContext(), toDelete.IDs); err != nil {
 		return h.handleError(c, err)
 	}
 
 	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "profileDeleted"), nil)
 }
 
+// restoreProfiles godoc
+// @Summary      Restore profiles by ID
+// @Description  Take deleted profiles out of the trash
+// @Tags         Profile
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header	bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        ids				body	dto.IDsInput   		true	"Profiles ID"
+// @Success      200  {object}  	presenter.Response
+// @Failure      400,403,404,409,500  {object}  	presenter.Response
+// @Router       /profile/restore [post]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *ProfileHandler) restoreProfiles(c *fiber.Ctx) error {
+	toRestore := c.Locals(localID).(*dto.IDsInput)
+
+	if err := h.useCase.RestoreProfiles(c.UserContext(), toRestore.IDs); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "profileRestored"), nil)
+}
+
 // canListRoot checks if the current user can list root profile
 func (h *ProfileHandler) canListRoot(c *fiber.Ctx) bool {
 	if user, ok := c.Locals(middleware.LocalUser).(*entity.User); ok && user != nil && user.Auth != nil {
@@ -0,0 +1,71 @@
+package handler_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/contrib/fiberi18n/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	"github.com/raulaguila/go-api/config"
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/handler"
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/middleware"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output/outputtest"
+	"github.com/raulaguila/go-api/internal/core/usecase/profile"
+)
+
+func TestProfileHandler_Patch(t *testing.T) {
+	tests := []struct {
+		name, contentType, ifMatch, body string
+		status                           int
+		etag                             string
+	}{
+		{"merge patch", "application/merge-patch+json", `"1"`, `{"name":"HELPDESK"}`, fiber.StatusOK, `"2"`},
+		{"json patch", "application/json-patch+json; charset=utf-8", "", `[{"op":"add","path":"/permissions/-","value":"audit:read"}]`, fiber.StatusOK, `"2"`},
+		{"json body", fiber.MIMEApplicationJSON, "", `{"name":"HELPDESK"}`, fiber.StatusUnsupportedMediaType, ""},
+		{"stale If-Match", "application/merge-patch+json", `"3"`, `{"name":"HELPDESK"}`, fiber.StatusPreconditionFailed, ""},
+		{"If-Match list", "application/merge-patch+json", `"3", "1"`, `{"name":"HELPDESK"}`, fiber.StatusOK, `"2"`},
+		{"weak If-Match", "application/merge-patch+json", `W/"1"`, `{"name":"HELPDESK"}`, fiber.StatusPreconditionFailed, ""},
+		{"removed name", "application/merge-patch+json", "", `{"name":null}`, fiber.StatusBadRequest, ""},
+		{"failed test", "application/json-patch+json", "", `[{"op":"test","path":"/name","value":"ADMIN"}]`, fiber.StatusConflict, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := entity.NewProfile("SUPPORT", []string{entity.PermissionUsersRead})
+			p.ID = 3
+
+			app := fiber.New()
+			app.Use(fiberi18n.New(&fiberi18n.Config{
+				RootPath:        "./locales",
+				AcceptLanguages: []language.Tag{language.AmericanEnglish},
+				DefaultLanguage: language.AmericanEnglish,
+				Loader:          &fiberi18n.EmbedLoader{FS: config.Locales},
+			}))
+			skipAuth := func(c *fiber.Ctx) error {
+				c.Locals(middleware.LocalSkipAuth, true)
+				return c.Next()
+			}
+			handler.NewProfileHandler(app.Group("/profile"), profile.NewProfileUseCase(outputtest.NewProfileRepository(p), nil), skipAuth)
+
+			req := httptest.NewRequest(fiber.MethodPatch, "/profile/3", strings.NewReader(tt.body))
+			req.Header.Set(fiber.HeaderContentType, tt.contentType)
+			if tt.ifMatch != "" {
+				req.Header.Set(fiber.HeaderIfMatch, tt.ifMatch)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, tt.etag, resp.Header.Get(fiber.HeaderETag))
+			if tt.status == fiber.StatusUnsupportedMediaType {
+				assert.Equal(t, "application/merge-patch+json, application/json-patch+json", resp.Header.Get("Accept-Patch"))
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"github.com/gofiber/contrib/fiberi18n/v2"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/middleware"
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/presenter"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/input"
+)
+
+// SessionHandler handles the session endpoints of the authenticated user
+type SessionHandler struct {
+	useCase     input.UserUseCase
+	handleError func(*fiber.Ctx, error) error
+}
+
+// NewSessionHandler creates a new SessionHandler and registers routes
+func NewSessionHandler(router fiber.Router, useCase input.UserUseCase, accessAuth fiber.Handler) {
+	handler := &SessionHandler{
+		useCase:     useCase,
+		handleError: middleware.NewErrorHandler(middleware.ErrorMapping{}),
+	}
+
+	sessionParamDTO := middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyID,
+		OnLookup:   middleware.Params,
+		Model:      &dto.SessionIDInput{},
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return presenter.BadRequest(c, "invalidID")
+		},
+	})
+
+	// Sessions are managed from interactive sessions only
+	router.Use(accessAuth, middleware.RequireSession())
+	router.Get("", handler.getSessions)
+	router.Delete("/:id", sessionParamDTO, handler.revokeSession)
+}
+
+// getSessions godoc
+// @Summary      Get sessions
+// @Description  Get the devices the authenticated user is signed in on, most recently active first
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Success      200  {array}   	dto.SessionOutput
+// @Failure      401,403,500  {object}  	presenter.Response
+// @Router       /auth/sessions [get]
+// @Security	 Bearer
+func (h *SessionHandler) getSessions(c *fiber.Ctx) error {
+	sessions, err := h.useCase.GetSessions(c.UserContext(), middleware.GetUserID(c), middleware.GetSessionID(c))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(sessions)
+}
+
+// revokeSession godoc
+// @Summary      Revoke session
+// @Description  Sign the authenticated user out of a device
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		string				true	"Session ID"
+// @Success      200  {object}  	nil
+// @Failure      400,401,403,404,500  {object}  	presenter.Response
+// @Router       /auth/sessions/{id} [delete]
+// @Security	 Bearer
+func (h *SessionHandler) revokeSession(c *fiber.Ctx) error {
+	sessionID := GetLocal[dto.SessionIDInput](c, middleware.CtxKeyID)
+
+	if err := h.useCase.RevokeSession(c.UserContext(), middleware.GetUserID(c), sessionID.ID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "sessionRevoked"), nil)
+}
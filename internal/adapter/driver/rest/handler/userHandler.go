@@ -7,9 +7,12 @@ import (
 
 	"github.com/raulaguila/go-api/internal/adapter/driver/rest/middleware"
 	"github.com/raulaguila/go-api/internal/adapter/driver/rest/presenter"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
 	"github.com/raulaguila/go-api/internal/core/dto"
 	"github.com/raulaguila/go-api/internal/core/port/input"
+	"github.com/raulaguila/go-api/pkg/apperror"
 	"github.com/raulaguila/go-api/pkg/pgerror"
+	"github.com/raulaguila/go-api/pkg/spreadsheet"
 )
 
 // UserHandler handles user endpoints
@@ -48,12 +51,36 @@ func NewUserHandler(router fiber.Router, useCase input.UserUseCase, accessAuth f
 		Model:      &dto.UserInput{},
 	})
 
+	userImportInputDTO := middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyDTO,
+		OnLookup:   middleware.Body,
+		Model:      &dto.UserImportInput{},
+	})
+
 	passwordInputDTO := middleware.ParseDTO(middleware.DTOConfig{
 		ContextKey: middleware.CtxKeyDTO,
 		OnLookup:   middleware.Body,
 		Model:      &dto.PasswordInput{},
 	})
 
+	passwordResetInputDTO := middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyDTO,
+		OnLookup:   middleware.Body,
+		Model:      &dto.PasswordResetInput{},
+	})
+
+	emailInputDTO := middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyDTO,
+		OnLookup:   middleware.Body,
+		Model:      &dto.EmailInput{},
+	})
+
+	emailVerificationInputDTO := middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyDTO,
+		OnLookup:   middleware.Body,
+		Model:      &dto.EmailVerificationInput{},
+	})
+
 	idParamDTO := middleware.ParseDTO(middleware.DTOConfig{
 		ContextKey: middleware.CtxKeyID,
 		OnLookup:   middleware.Params,
@@ -65,6 +92,18 @@ func NewUserHandler(router fiber.Router, useCase input.UserUseCase, accessAuth f
 		},
 	})
 
+	sessionParamDTO := middleware.ParseDTO(middleware.DTOConfig{
+		ContextKey: middleware.CtxKeyID,
+		OnLookup:   middleware.Params,
+		Model: &struct {
+			ID      uint   `params:"id"`
+			Session string `params:"session"`
+		}{},
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return presenter.BadRequest(c, "invalidID")
+		},
+	})
+
 	idsBodyDTO := middleware.ParseDTO(middleware.DTOConfig{
 		ContextKey: middleware.CtxKeyID,
 		OnLookup:   middleware.Body,
@@ -74,16 +113,35 @@ func NewUserHandler(router fiber.Router, useCase input.UserUseCase, accessAuth f
 		},
 	})
 
-	// Public route for setting password
+	// Public routes for the password reset, invitation and email verification flows
+	router.Post("/pass", passwordResetInputDTO, handler.requestPasswordReset)
 	router.Put("/pass", passwordInputDTO, handler.setUserPassword)
+	router.Put("/invite", passwordInputDTO, handler.acceptInvitation)
+	router.Post("/email", emailInputDTO, handler.requestEmailVerification)
+	router.Put("/email", emailVerificationInputDTO, handler.verifyEmail)
+
+	canRead := middleware.RequirePermission(entity.PermissionUsersRead)
+	canWrite := middleware.RequirePermission(entity.PermissionUsersWrite)
 
 	// Protected routes
 	router.Use(accessAuth)
-	router.Delete("/pass", handler.resetUserPassword)
-	router.Get("", userFilterDTO, handler.getUsers)
-	router.Post("", userInputDTO, handler.createUser)
-	router.Put("/:id", idParamDTO, userInputDTO, handler.updateUser)
-	router.Delete("", idsBodyDTO, handler.deleteUser)
+	router.Delete("/pass", canWrite, handler.resetUserPassword)
+	router.Get("", canRead, userFilterDTO, handler.getUsers)
+	router.Post("", canWrite, userInputDTO, handler.createUser)
+	router.Post("/import", canWrite, userImportInputDTO, handler.importUsers)
+	router.Get("/invite", canRead, handler.getInvitations)
+	router.Post("/invite", canWrite, userInputDTO, handler.inviteUser)
+	router.Put("/invite/:id", canWrite, idParamDTO, handler.resendInvitation)
+	router.Delete("/invite/:id", canWrite, idParamDTO, handler.revokeInvitation)
+	router.Get("/:id", canRead, idParamDTO, handler.getUser)
+	router.Put("/:id", canWrite, idParamDTO, userInputDTO, handler.updateUser)
+	router.Patch("/:id", canWrite, idParamDTO, handler.patchUser)
+	router.Get("/:id/sessions", canRead, idParamDTO, handler.getUserSessions)
+	router.Delete("/:id/sessions", canWrite, idParamDTO, handler.revokeUserSessions)
+	router.Delete("/:id/sessions/:session", canWrite, sessionParamDTO, handler.revokeUserSession)
+	router.Delete("/:id/lock", canWrite, idParamDTO, handler.unlockUser)
+	router.Delete("", canWrite, idsBodyDTO, handler.deleteUser)
+	router.Post("/restore", canWrite, idsBodyDTO, handler.restoreUsers)
 }
 
 // getUsers godoc
@@ -96,13 +154,14 @@ func NewUserHandler(router fiber.Router, useCase input.UserUseCase, accessAuth f
 // @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        pgfilter			query		dto.UserFilter		false	"Optional Filter"
 // @Success      200  {object}   	dto.PaginatedOutput[dto.UserOutput]
-// @Failure      500  {object}  	presenter.Response
+// @Failure      403,500  {object}  	presenter.Response
 // @Router       /user [get]
 // @Security	 Bearer
+// @Security	 APIKey
 func (h *UserHandler) getUsers(c *fiber.Ctx) error {
 	filter := GetLocal[dto.UserFilter](c, middleware.CtxKeyFilter)
 
-	response, err := h.useCase.GetUsers(c.Context(), filter)
+	response, err := h.useCase.GetUsers(c.UserContext(), filter)
 	if err != nil {
 		return h.handleError(c, err)
 	}
@@ -110,6 +169,35 @@ func (h *UserHandler) getUsers(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+// getUser godoc
+// @Summary      Get user by ID
+// @Description  Get user by ID, with its version in the ETag header
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		uint				true	"User ID"
+// @Success      200  {object}  	dto.UserOutput
+// @Header       200  {string}  	ETag	"User version"
+// @Failure      400,403,404,500  {object}  	presenter.Response
+// @Router       /user/{id} [get]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *UserHandler) getUser(c *fiber.Ctx) error {
+	idStruct := GetLocal[struct {
+		ID uint `params:"id"`
+	}](c, middleware.CtxKeyID)
+
+	user, err := h.useCase.GetUserByID(c.UserContext(), idStruct.ID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	SetETag(c, user.Version)
+	return c.Status(fiber.StatusOK).JSON(user)
+}
+
 // createUser godoc
 // @Summary      Insert user
 // @Description  Insert user
@@ -120,13 +208,14 @@ func (h *UserHandler) getUsers(c *fiber.Ctx) error {
 // @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        user				body		dto.UserInput		true	"User model"
 // @Success      201  {object}  	dto.UserOutput
-// @Failure      400,409,500  {object}  	presenter.Response
+// @Failure      400,403,409,500  {object}  	presenter.Response
 // @Router       /user [post]
 // @Security	 Bearer
+// @Security	 APIKey
 func (h *UserHandler) createUser(c *fiber.Ctx) error {
 	userDTO := GetLocal[dto.UserInput](c, middleware.CtxKeyDTO)
 
-	user, err := h.useCase.CreateUser(c.Context(), userDTO)
+	user, err := h.useCase.CreateUser(c.UserContext(), userDTO)
 	if err != nil {
 		return h.handleError(c, err)
 	}
@@ -134,6 +223,57 @@ func (h *UserHandler) createUser(c *fiber.Ctx) error {
 	return presenter.Created(c, fiberi18n.MustLocalize(c, "userCreated"), user)
 }
 
+// importUsers godoc
+// @Summary      Import users
+// @Description  Create users from the rows of a CSV or XLSX file, whose first row holds the column headers, and send them the welcome message to set their password. Invalid rows and rows of existing users are reported instead; a dry run only reports them. Files with more rows than IMPORT_MAX_ROWS are refused.
+// @Tags         User
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        file				formData	file				true	"CSV or XLSX file"
+// @Param        name_column		formData	string				false	"Header of the names" default(name)
+// @Param        username_column	formData	string				false	"Header of the usernames" default(username)
+// @Param        email_column		formData	string				false	"Header of the emails" default(email)
+// @Param        profile_column		formData	string				false	"Header of the profile IDs" default(profile_id)
+// @Param        profile_id			formData	uint				false	"Profile of the users whose row names none"
+// @Param        dry_run			formData	bool				false	"Only validate the rows"
+// @Param        invite				formData	bool				false	"Create pending users and send them invitations"
+// @Success      200  {object}  	dto.UserImportOutput
+// @Failure      400,403,409,415,500  {object}  	presenter.Response
+// @Router       /user/import [post]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *UserHandler) importUsers(c *fiber.Ctx) error {
+	importDTO := GetLocal[dto.UserImportInput](c, middleware.CtxKeyDTO)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return presenter.BadRequest(c, fiberi18n.MustLocalize(c, "invalidImportFile"))
+	}
+	format, err := spreadsheet.FormatOf(header.Filename)
+	if err != nil {
+		return presenter.New(c, fiber.StatusUnsupportedMediaType, fiberi18n.MustLocalize(c, "unsupportedImportFile"), nil)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return h.handleError(c, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	if importDTO.Rows, err = spreadsheet.Read(file, format); err != nil {
+		return presenter.BadRequest(c, fiberi18n.MustLocalize(c, "invalidImportFile"))
+	}
+
+	output, err := h.useCase.ImportUsers(c.UserContext(), importDTO)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.Success(c, output)
+}
+
 // updateUser godoc
 // @Summary      Update user by ID
 // @Description  Update user by ID
@@ -142,23 +282,74 @@ func (h *UserHandler) createUser(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
 // @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        If-Match			header		string				false	"ETag of the user read, refusing the update if it changed since"
 // @Param        id					path		uint				true	"User ID"
 // @Param        user				body		dto.UserInput		true	"User model"
 // @Success      200  {object}  	dto.UserOutput
-// @Failure      400,404,500  {object}  	presenter.Response
+// @Header       200  {string}  	ETag	"User version"
+// @Failure      400,403,404,409,412,500  {object}  	presenter.Response
 // @Router       /user/{id} [put]
 // @Security	 Bearer
+// @Security	 APIKey
 func (h *UserHandler) updateUser(c *fiber.Ctx) error {
 	idStruct := GetLocal[struct {
 		ID uint `params:"id"`
 	}](c, middleware.CtxKeyID)
 	userDTO := GetLocal[dto.UserInput](c, middleware.CtxKeyDTO)
 
-	user, err := h.useCase.UpdateUser(c.Context(), idStruct.ID, userDTO)
+	versions, ok := IfMatch(c)
+	if !ok {
+		return h.handleError(c, apperror.StaleVersion("user"))
+	}
+	userDTO.Versions = versions
+
+	user, err := h.useCase.UpdateUser(c.UserContext(), idStruct.ID, userDTO)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
+	SetETag(c, user.Version)
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "userUpdated"), user)
+}
+
+// patchUser godoc
+// @Summary      Patch user by ID
+// @Description  Partially update user by ID with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902) of its document
+// @Tags         User
+// @Accept       application/merge-patch+json,application/json-patch+json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        If-Match			header		string				false	"ETag of the user read, refusing the update if it changed since"
+// @Param        id					path		uint				true	"User ID"
+// @Param        patch				body		dto.UserDocument	true	"Patch of the user document"
+// @Success      200  {object}  	dto.UserOutput
+// @Header       200  {string}  	ETag	"User version"
+// @Failure      400,403,404,409,412,415,500  {object}  	presenter.Response
+// @Router       /user/{id} [patch]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *UserHandler) patchUser(c *fiber.Ctx) error {
+	idStruct := GetLocal[struct {
+		ID uint `params:"id"`
+	}](c, middleware.CtxKeyID)
+
+	patch := ParsePatch(c)
+	if patch == nil {
+		return UnsupportedPatch(c)
+	}
+	versions, ok := IfMatch(c)
+	if !ok {
+		return h.handleError(c, apperror.StaleVersion("user"))
+	}
+	patch.Versions = versions
+
+	user, err := h.useCase.PatchUser(c.UserContext(), idStruct.ID, patch)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	SetETag(c, user.Version)
 	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "userUpdated"), user)
 }
 
@@ -172,22 +363,154 @@ func (h *UserHandler) updateUser(c *fiber.Ctx) error {
 // @Param        Accept-Language	header		string					false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        id					body		dto.IDsInput			true	"User ID"
 // @Success      204  {object}  	nil
-// @Failure      404,500  {object}  	presenter.Response
+// @Failure      403,404,500  {object}  	presenter.Response
 // @Router       /user [delete]
 // @Security	 Bearer
+// @Security	 APIKey
 func (h *UserHandler) deleteUser(c *fiber.Ctx) error {
 	toDelete := GetLocal[dto.IDsInput](c, middleware.CtxKeyID)
 
-	if err := h.useCase.DeleteUsers(c.Context(), toDelete.IDs); err != nil {
+	if err := h.useCase.DeleteUsers(c.UserContext(), toDelete.IDs); err != nil {
 		return h.handleError(c, err)
 	}
 
 	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "userDeleted"), nil)
 }
 
+// restoreUsers godoc
+// @Summary      Restore users by ID
+// @Description  Take deleted users out of the trash; users whose profile is deleted are restored after it
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool					false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string					false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					body		dto.IDsInput			true	"User ID"
+// @Success      200  {object}  	nil
+// @Failure      400,403,404,409,500  {object}  	presenter.Response
+// @Router       /user/restore [post]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *UserHandler) restoreUsers(c *fiber.Ctx) error {
+	toRestore := GetLocal[dto.IDsInput](c, middleware.CtxKeyID)
+
+	if err := h.useCase.RestoreUsers(c.UserContext(), toRestore.IDs); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "userRestored"), nil)
+}
+
+// getUserSessions godoc
+// @Summary      Get user sessions by ID
+// @Description  Get the devices the user is signed in on, most recently active first
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		uint				true	"User ID"
+// @Success      200  {array}   	dto.SessionOutput
+// @Failure      400,403,404,500  {object}  	presenter.Response
+// @Router       /user/{id}/sessions [get]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *UserHandler) getUserSessions(c *fiber.Ctx) error {
+	idStruct := GetLocal[struct {
+		ID uint `params:"id"`
+	}](c, middleware.CtxKeyID)
+
+	sessions, err := h.useCase.GetSessions(c.UserContext(), idStruct.ID, middleware.GetSessionID(c))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(sessions)
+}
+
+// revokeUserSessions godoc
+// @Summary      Revoke user sessions by ID
+// @Description  Sign the user out of every device
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		uint				true	"User ID"
+// @Success      200  {object}  	nil
+// @Failure      400,403,404,500  {object}  	presenter.Response
+// @Router       /user/{id}/sessions [delete]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *UserHandler) revokeUserSessions(c *fiber.Ctx) error {
+	idStruct := GetLocal[struct {
+		ID uint `params:"id"`
+	}](c, middleware.CtxKeyID)
+
+	if err := h.useCase.RevokeSessions(c.UserContext(), idStruct.ID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "sessionsRevoked"), nil)
+}
+
+// revokeUserSession godoc
+// @Summary      Revoke user session by ID
+// @Description  Sign the user out of a single device
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		uint				true	"User ID"
+// @Param        session			path		string				true	"Session ID"
+// @Success      200  {object}  	nil
+// @Failure      400,403,404,500  {object}  	presenter.Response
+// @Router       /user/{id}/sessions/{session} [delete]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *UserHandler) revokeUserSession(c *fiber.Ctx) error {
+	params := GetLocal[struct {
+		ID      uint   `params:"id"`
+		Session string `params:"session"`
+	}](c, middleware.CtxKeyID)
+
+	if err := h.useCase.RevokeSession(c.UserContext(), params.ID, params.Session); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "sessionRevoked"), nil)
+}
+
+// unlockUser godoc
+// @Summary      Unlock user by ID
+// @Description  Clear the failed logins of a user, lifting a lockout
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		uint				true	"User ID"
+// @Success      200  {object}  	nil
+// @Failure      400,403,404,500  {object}  	presenter.Response
+// @Router       /user/{id}/lock [delete]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *UserHandler) unlockUser(c *fiber.Ctx) error {
+	idStruct := GetLocal[struct {
+		ID uint `params:"id"`
+	}](c, middleware.CtxKeyID)
+
+	if err := h.useCase.Unlock(c.UserContext(), idStruct.ID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "accountUnlocked"), nil)
+}
+
 // resetUserPassword godoc
-// @Summary      Reset user password by ID
-// @Description  Reset user password by ID
+// @Summary      Reset user password
+// @Description  Send a single-use password reset token to the user
 // @Tags         User
 // @Accept       json
 // @Produce      json
@@ -195,50 +518,232 @@ func (h *UserHandler) deleteUser(c *fiber.Ctx) error {
 // @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        email				query		string				true 	"User email"
 // @Success      200  {object}  	nil
-// @Failure      404,500  {object}  	presenter.Response
+// @Failure      403,404,500  {object}  	presenter.Response
 // @Router       /user/pass [delete]
 // @Security	 Bearer
+// @Security	 APIKey
 func (h *UserHandler) resetUserPassword(c *fiber.Ctx) error {
 	email, err := GetQuery(c, "email")
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	if err := h.useCase.ResetPassword(c.Context(), email); err != nil {
+	if err := h.useCase.ResetPassword(c.UserContext(), email); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "passResetRequested"), nil)
+}
+
+// requestPasswordReset godoc
+// @Summary      Request password reset
+// @Description  Send a single-use password reset token to the user, if the account exists
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string					false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        email				body		dto.PasswordResetInput	true	"Account email"
+// @Success      200  {object}  	nil
+// @Failure      400,500  {object}  	presenter.Response
+// @Router       /user/pass [post]
+func (h *UserHandler) requestPasswordReset(c *fiber.Ctx) error {
+	input := GetLocal[dto.PasswordResetInput](c, middleware.CtxKeyDTO)
+	if err := input.Validate(); err != nil {
+		return h.handleError(c, err)
+	}
+
+	if err := h.useCase.ResetPassword(c.UserContext(), input.Email); err != nil {
 		return h.handleError(c, err)
 	}
 
-	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "passReset"), nil)
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "passResetRequested"), nil)
 }
 
 // setUserPassword godoc
-// @Summary      Set user password by ID
-// @Description  Set user password by ID
+// @Summary      Set user password
+// @Description  Set user password using a password reset token
 // @Tags         User
 // @Accept       json
 // @Produce      json
-// @Param        X-Skip-Auth		header		bool					false	"Skip auth" enums(true,false) default(true)
 // @Param        Accept-Language	header		string					false	"Request language" enums(en-US,pt-BR) default(en-US)
-// @Param        email				query		string					true	"User email" Format(email)
 // @Param        password			body		dto.PasswordInput		true	"Password model"
 // @Success      200  {object}  	nil
-// @Failure      404,500  {object}  	presenter.Response
+// @Failure      400,500  {object}  	presenter.Response
 // @Router       /user/pass [put]
-// @Security	 Bearer
 func (h *UserHandler) setUserPassword(c *fiber.Ctx) error {
-	email, err := GetQuery(c, "email")
+	pass := GetLocal[dto.PasswordInput](c, middleware.CtxKeyDTO)
+	if err := pass.Validate(); err != nil {
+		return h.handleError(c, err)
+	}
+
+	if err := h.useCase.SetPassword(c.UserContext(), pass); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "passSet"), nil)
+}
+
+// getInvitations godoc
+// @Summary      Get invitations
+// @Description  Get the invitations not accepted yet, the ones expiring first first
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Success      200  {array}   	dto.InvitationOutput
+// @Failure      403,500  {object}  	presenter.Response
+// @Router       /user/invite [get]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *UserHandler) getInvitations(c *fiber.Ctx) error {
+	invitations, err := h.useCase.GetInvitations(c.UserContext())
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
+	return c.Status(fiber.StatusOK).JSON(invitations)
+}
+
+// inviteUser godoc
+// @Summary      Invite user
+// @Description  Create a pending user and email an expiring invitation, accepted by setting the first password
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        user				body		dto.UserInput		true	"User model"
+// @Success      201  {object}  	dto.UserOutput
+// @Failure      400,403,404,409,500  {object}  	presenter.Response
+// @Router       /user/invite [post]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *UserHandler) inviteUser(c *fiber.Ctx) error {
+	userDTO := GetLocal[dto.UserInput](c, middleware.CtxKeyDTO)
+
+	user, err := h.useCase.InviteUser(c.UserContext(), userDTO)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.Created(c, fiberi18n.MustLocalize(c, "invitationSent"), user)
+}
+
+// resendInvitation godoc
+// @Summary      Resend invitation
+// @Description  Email a new invitation to a pending user, invalidating the previous one
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		uint				true	"User ID"
+// @Success      200  {object}  	nil
+// @Failure      400,403,404,500  {object}  	presenter.Response
+// @Router       /user/invite/{id} [put]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *UserHandler) resendInvitation(c *fiber.Ctx) error {
+	idStruct := GetLocal[struct {
+		ID uint `params:"id"`
+	}](c, middleware.CtxKeyID)
+
+	if err := h.useCase.ResendInvitation(c.UserContext(), idStruct.ID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "invitationSent"), nil)
+}
+
+// revokeInvitation godoc
+// @Summary      Revoke invitation
+// @Description  Withdraw the invitation of a pending user, deleting the user
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		uint				true	"User ID"
+// @Success      200  {object}  	nil
+// @Failure      400,403,404,500  {object}  	presenter.Response
+// @Router       /user/invite/{id} [delete]
+// @Security	 Bearer
+// @Security	 APIKey
+func (h *UserHandler) revokeInvitation(c *fiber.Ctx) error {
+	idStruct := GetLocal[struct {
+		ID uint `params:"id"`
+	}](c, middleware.CtxKeyID)
+
+	if err := h.useCase.RevokeInvitation(c.UserContext(), idStruct.ID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "invitationRevoked"), nil)
+}
+
+// acceptInvitation godoc
+// @Summary      Accept invitation
+// @Description  Accept an invitation by setting the first password with the invitation token
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string					false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        password			body		dto.PasswordInput		true	"Password model"
+// @Success      200  {object}  	nil
+// @Failure      400,500  {object}  	presenter.Response
+// @Router       /user/invite [put]
+func (h *UserHandler) acceptInvitation(c *fiber.Ctx) error {
 	pass := GetLocal[dto.PasswordInput](c, middleware.CtxKeyDTO)
-	if err := pass.Validate(); err != nil {
+
+	if err := h.useCase.AcceptInvitation(c.UserContext(), pass); err != nil {
 		return h.handleError(c, err)
 	}
 
-	if err := h.useCase.SetPassword(c.Context(), email, pass); err != nil {
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "invitationAccepted"), nil)
+}
+
+// requestEmailVerification godoc
+// @Summary      Request email verification
+// @Description  Send a single-use verification token to the email awaiting verification of the account, if any
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        email				body		dto.EmailInput		true	"Account email"
+// @Success      200  {object}  	nil
+// @Failure      400,500  {object}  	presenter.Response
+// @Router       /user/email [post]
+func (h *UserHandler) requestEmailVerification(c *fiber.Ctx) error {
+	input := GetLocal[dto.EmailInput](c, middleware.CtxKeyDTO)
+	if err := input.Validate(); err != nil {
 		return h.handleError(c, err)
 	}
 
-	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "passSet"), nil)
+	if err := h.useCase.RequestEmailVerification(c.UserContext(), input.Email); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "emailVerificationRequested"), nil)
+}
+
+// verifyEmail godoc
+// @Summary      Verify email
+// @Description  Confirm an email with a verification token, applying a pending email change
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string						false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        token				body		dto.EmailVerificationInput	true	"Verification token"
+// @Success      200  {object}  	nil
+// @Failure      400,409,500  {object}  	presenter.Response
+// @Router       /user/email [put]
+func (h *UserHandler) verifyEmail(c *fiber.Ctx) error {
+	input := GetLocal[dto.EmailVerificationInput](c, middleware.CtxKeyDTO)
+
+	if err := h.useCase.VerifyEmail(c.UserContext(), input); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return presenter.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "emailVerified"), nil)
 }
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/pkg/jwtx"
+	"github.com/raulaguila/go-api/pkg/oidc"
+)
+
+// WellKnownHandler handles the public metadata endpoints used by other services
+type WellKnownHandler struct {
+	accessKeys *jwtx.Keyring
+	metadata   *oidc.Metadata
+}
+
+// NewWellKnownHandler creates a new WellKnownHandler. issuer is the public URL of the
+// API, under which the OAuth 2.0 / OpenID Connect provider endpoints are advertised.
+func NewWellKnownHandler(router fiber.Router, accessKeys *jwtx.Keyring, issuer string) {
+	issuer = strings.TrimSuffix(issuer, "/")
+	handler := &WellKnownHandler{
+		accessKeys: accessKeys,
+		metadata: &oidc.Metadata{
+			Issuer:                            issuer,
+			AuthorizationEndpoint:             issuer + "/oauth/authorize",
+			TokenEndpoint:                     issuer + "/oauth/token",
+			UserinfoEndpoint:                  issuer + "/oauth/userinfo",
+			RevocationEndpoint:                issuer + "/oauth/revoke",
+			JWKSURI:                           issuer + "/.well-known/jwks.json",
+			ResponseTypesSupported:            []string{"code"},
+			GrantTypesSupported:               []string{entity.GrantAuthorizationCode, entity.GrantRefreshToken, entity.GrantClientCredentials},
+			SubjectTypesSupported:             []string{"public"},
+			IDTokenSigningAlgValuesSupported:  []string{"RS256"},
+			ScopesSupported:                   []string{entity.ScopeOpenID, entity.ScopeProfile, entity.ScopeEmail},
+			ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "name", "preferred_username", "email", "email_verified"},
+			TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+			CodeChallengeMethodsSupported:     []string{"S256"},
+		},
+	}
+	router.Get("/jwks.json", handler.jwks).Name("JWKS")
+	router.Get("/openid-configuration", handler.openIDConfiguration)
+}
+
+// jwks godoc
+// @Summary      JSON Web Key Set
+// @Description  Public keys verifying access tokens, selected by the token's kid header. Retired keys are listed until every token they signed expires.
+// @Tags         Auth
+// @Produce      json
+// @Success      200  {object}   jwtx.JWKS
+// @Router       /.well-known/jwks.json [get]
+func (h *WellKnownHandler) jwks(c *fiber.Ctx) error {
+	// Let verifiers cache the keys, but pick up rotations within minutes
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(h.accessKeys.JWKS())
+}
+
+// openIDConfiguration godoc
+// @Summary      OpenID Connect discovery
+// @Description  Metadata of the OAuth 2.0 / OpenID Connect provider: its endpoints and supported features
+// @Tags         OAuth
+// @Produce      json
+// @Success      200  {object}   oidc.Metadata
+// @Router       /.well-known/openid-configuration [get]
+func (h *WellKnownHandler) openIDConfiguration(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+	return c.Status(fiber.StatusOK).JSON(h.metadata)
+}
@@ -2,9 +2,10 @@ package middleware
 
 import (
 	"context"
-	"crypto/rsa"
 	"errors"
 	"log/slog"
+	"strconv"
+	"time"
 
 	"github.com/gofiber/contrib/fiberi18n/v2"
 	"github.com/gofiber/fiber/v2"
@@ -12,8 +13,13 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 
 	"github.com/raulaguila/go-api/internal/adapter/driver/rest/presenter"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
 	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/pkg/actor"
+	"github.com/raulaguila/go-api/pkg/apperror"
+	"github.com/raulaguila/go-api/pkg/jwtx"
 	"github.com/raulaguila/go-api/pkg/loggerx"
+	"github.com/raulaguila/go-api/pkg/tenant"
 )
 
 const (
@@ -21,18 +27,122 @@ const (
 	LocalUser = "localUser"
 	// LocalUserID is the context key for the authenticated user ID
 	LocalUserID = "localUserID"
+	// LocalSkipAuth is the context key set when authentication was skipped
+	LocalSkipAuth = "localSkipAuth"
+	// LocalSession is the context key for the authenticated session
+	LocalSession = "localSession"
+	// LocalTokenID is the context key for the token ID (jti) of refresh tokens
+	LocalTokenID = "localTokenID"
+	// LocalAPIKey is the context key for the API key authenticating the request
+	LocalAPIKey = "localAPIKey"
+	// LocalActor is the context key for the user acting as the authenticated user during an impersonation
+	LocalActor = "localActor"
+
+	// HeaderAPIKey is the header carrying an API key, accepted instead of a bearer token
+	HeaderAPIKey = "X-API-Key"
 )
 
 // AuthConfig holds authentication middleware configuration
 type AuthConfig struct {
-	PrivateKey    *rsa.PrivateKey
+	Keys          *jwtx.Keyring
+	TokenType     string        // Expected typ claim
+	Issuer        string        // Expected iss claim
+	Audience      string        // Audience that must be listed in the aud claim
+	Leeway        time.Duration // Clock skew tolerated on exp, nbf and iat
 	UserRepo      output.UserRepository
-	AllowSkipAuth bool            // Injected config instead of os.Getenv
-	Log           *loggerx.Logger // Injected logger instead of log.Println
+	ProfileRepo   output.ProfileRepository // Source of the users' profiles, if set, fresher than cached users
+	SessionRepo   output.SessionRepository
+	Revocations   output.RevocationStore
+	APIKeys       output.APIKeyRepository
+	Activity      output.SessionActivityRecorder // Records the last activity of sessions, if set
+	AllowSkipAuth bool                           // Injected config instead of os.Getenv
+	Log           *loggerx.Logger                // Injected logger instead of log.Println
 }
 
-// Auth creates an authentication middleware
+// Auth creates an authentication middleware. When cfg.APIKeys is set, requests
+// may authenticate with an API key in the X-API-Key header instead of a bearer token.
 func Auth(cfg AuthConfig) fiber.Handler {
+	bearer := bearerAuth(cfg)
+	if cfg.APIKeys == nil {
+		return bearer
+	}
+
+	return func(c *fiber.Ctx) error {
+		if secret := c.Get(HeaderAPIKey); secret != "" {
+			return apiKeyAuth(c, cfg, secret)
+		}
+		return bearer(c)
+	}
+}
+
+// apiKeyAuth authenticates a request with the secret of an API key
+func apiKeyAuth(c *fiber.Ctx, cfg AuthConfig, secret string) error {
+	key, err := cfg.APIKeys.FindByHash(c.Context(), entity.HashToken(secret))
+	if err != nil || !key.IsActive() {
+		if err != nil && cfg.Log != nil {
+			cfg.Log.Debug("API key lookup error", slog.String("error", err.Error()))
+		}
+		return presenter.Unauthorized(c, fiberi18n.MustLocalize(c, "invalidAPIKey"))
+	}
+
+	user, err := cfg.UserRepo.FindByID(c.Context(), key.UserID)
+	if err != nil {
+		if cfg.Log != nil {
+			cfg.Log.Debug("User lookup error", slog.String("error", err.Error()))
+		}
+		return presenter.Unauthorized(c, fiberi18n.MustLocalize(c, "errGeneric"))
+	}
+
+	if user.Auth == nil || !user.Auth.Status {
+		return presenter.Unauthorized(c, fiberi18n.MustLocalize(c, "disabledUser"))
+	}
+
+	if err := loadProfile(c.Context(), cfg, user); err != nil {
+		if cfg.Log != nil {
+			cfg.Log.Debug("Profile lookup error", slog.String("error", err.Error()))
+		}
+		return presenter.Unauthorized(c, fiberi18n.MustLocalize(c, "errGeneric"))
+	}
+
+	if key.Touch() {
+		if err := cfg.APIKeys.Touch(c.Context(), key.ID, *key.LastUsedAt); err != nil && cfg.Log != nil {
+			cfg.Log.Warn("Failed to record API key use", slog.String("error", err.Error()))
+		}
+	}
+
+	c.Locals(LocalUserID, user.ID)
+	c.Locals(LocalUser, user)
+	c.Locals(LocalAPIKey, key)
+	c.SetUserContext(actor.WithUser(tenant.WithOrganization(c.UserContext(), user.OrganizationID), &user.ID, nil))
+	return c.Next()
+}
+
+// loadProfile replaces the profile a user was loaded with by the one of cfg.ProfileRepo. Users
+// stay cached with their profile after it is updated, which would keep revoked permissions granted.
+func loadProfile(ctx context.Context, cfg AuthConfig, user *entity.User) error {
+	if cfg.ProfileRepo == nil {
+		return nil
+	}
+	profile, err := cfg.ProfileRepo.FindByID(ctx, user.Auth.ProfileID)
+	if err != nil {
+		return err
+	}
+	user.Auth.Profile = profile
+	return nil
+}
+
+// organizationClaimMatches checks that the org claim of a token names the user's organization,
+// and that tokens of users of no organization carry none
+func organizationClaimMatches(claims jwt.MapClaims, organizationID *uint) bool {
+	org, ok := claims["org"].(float64)
+	if organizationID == nil {
+		return !ok
+	}
+	return ok && uint(org) == *organizationID
+}
+
+// bearerAuth creates the middleware authenticating requests with a JWT bearer token
+func bearerAuth(cfg AuthConfig) fiber.Handler {
 	return keyauth.New(keyauth.Config{
 		KeyLookup:  "header:" + fiber.HeaderAuthorization,
 		AuthScheme: "Bearer",
@@ -41,23 +151,30 @@ func Auth(cfg AuthConfig) fiber.Handler {
 			// Skip auth if allowed via config and header is set
 			if cfg.AllowSkipAuth && c.Get("X-Skip-Auth", "false") == "true" {
 				c.Locals(LocalUserID, uint(0))
+				c.Locals(LocalSkipAuth, true)
 				return true
 			}
 			return false
 		},
 		SuccessHandler: func(c *fiber.Ctx) error {
+			// Impersonation only lets the support staff see the API as the user does
+			if GetActor(c) != nil && !readOnlyMethod(c.Method()) {
+				return handleAppError(c, apperror.Forbidden(fiberi18n.MustLocalize(c, "impersonationReadOnly")))
+			}
 			return c.Next()
 		},
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			return presenter.Unauthorized(c, err.Error())
 		},
 		Validator: func(c *fiber.Ctx, key string) (bool, error) {
-			parsedToken, err := jwt.Parse(key, func(token *jwt.Token) (any, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-					return nil, jwt.ErrTokenSignatureInvalid
-				}
-				return cfg.PrivateKey.Public(), nil
-			})
+			// The key is selected by the token's kid, so tokens signed before a key rotation stay valid
+			parsedToken, err := jwt.Parse(key, cfg.Keys.Keyfunc,
+				jwt.WithValidMethods(jwtx.ValidMethods),
+				jwt.WithIssuer(cfg.Issuer),
+				jwt.WithAudience(cfg.Audience),
+				jwt.WithLeeway(cfg.Leeway),
+				jwt.WithIssuedAt(),
+			)
 			if err != nil {
 				if cfg.Log != nil {
 					cfg.Log.Debug("JWT parse error", slog.String("error", err.Error()))
@@ -70,13 +187,56 @@ func Auth(cfg AuthConfig) fiber.Handler {
 				return false, errors.New("invalid jwt token")
 			}
 
-			token, ok := claims["token"].(string)
+			if tokenType, _ := claims["typ"].(string); tokenType != cfg.TokenType {
+				return false, errors.New("invalid token type")
+			}
+
+			sessionID, ok := claims["sid"].(string)
 			if !ok {
-				return false, errors.New("invalid token claim")
+				return false, errors.New("invalid session claim")
 			}
 
 			// Use c.Context() instead of context.Background()
-			user, err := cfg.UserRepo.FindByToken(c.Context(), token)
+			if cfg.Revocations != nil {
+				if revoked, err := cfg.Revocations.IsSessionRevoked(c.Context(), sessionID); err != nil || revoked {
+					return false, errors.New(fiberi18n.MustLocalize(c, "invalidSession"))
+				}
+			}
+
+			session, err := cfg.SessionRepo.FindByID(c.Context(), sessionID)
+			if err != nil || !session.IsActive() {
+				if err != nil && cfg.Log != nil {
+					cfg.Log.Debug("Session lookup error", slog.String("error", err.Error()))
+				}
+				return false, errors.New(fiberi18n.MustLocalize(c, "invalidSession"))
+			}
+
+			if cfg.Revocations != nil {
+				// Sessions created before a "logout everywhere" are no longer valid
+				revokedAt, err := cfg.Revocations.UserRevokedAt(c.Context(), session.UserID)
+				if err != nil || (revokedAt != nil && !session.CreatedAt.After(*revokedAt)) {
+					return false, errors.New(fiberi18n.MustLocalize(c, "invalidSession"))
+				}
+			}
+
+			if subject, _ := claims.GetSubject(); subject != strconv.FormatUint(uint64(session.UserID), 10) {
+				return false, errors.New(fiberi18n.MustLocalize(c, "invalidSession"))
+			}
+
+			// Impersonation tokens name their actor, who must be the actor of the session
+			act, _ := claims["act"].(map[string]any)
+			actorSubject, _ := act["sub"].(string)
+			var impersonator *entity.User
+			if session.IsImpersonation() || actorSubject != "" {
+				if !session.IsImpersonation() || actorSubject != strconv.FormatUint(uint64(*session.ActorID), 10) {
+					return false, errors.New(fiberi18n.MustLocalize(c, "invalidSession"))
+				}
+				if impersonator, err = cfg.UserRepo.FindByID(c.Context(), *session.ActorID); err != nil || impersonator.Auth == nil || !impersonator.Auth.Status {
+					return false, errors.New(fiberi18n.MustLocalize(c, "invalidSession"))
+				}
+			}
+
+			user, err := cfg.UserRepo.FindByID(c.Context(), session.UserID)
 			if err != nil {
 				if cfg.Log != nil {
 					cfg.Log.Debug("User lookup error", slog.String("error", err.Error()))
@@ -88,8 +248,35 @@ func Auth(cfg AuthConfig) fiber.Handler {
 				return false, errors.New(fiberi18n.MustLocalize(c, "disabledUser"))
 			}
 
+			if err := loadProfile(c.Context(), cfg, user); err != nil {
+				if cfg.Log != nil {
+					cfg.Log.Debug("Profile lookup error", slog.String("error", err.Error()))
+				}
+				return false, errors.New(fiberi18n.MustLocalize(c, "errGeneric"))
+			}
+
+			// Tokens issued before the user moved to another organization are refused
+			if !organizationClaimMatches(claims, user.OrganizationID) {
+				return false, errors.New(fiberi18n.MustLocalize(c, "invalidSession"))
+			}
+
+			// Recorded in batches, so requests do not write to the database
+			if cfg.Activity != nil && session.Touch() {
+				cfg.Activity.Record(session.ID, *session.LastActiveAt)
+			}
+
+			tokenID, _ := claims["jti"].(string)
+
 			c.Locals(LocalUserID, user.ID)
 			c.Locals(LocalUser, user)
+			c.Locals(LocalSession, session)
+			c.Locals(LocalTokenID, tokenID)
+			var impersonatorID *uint
+			if impersonator != nil {
+				c.Locals(LocalActor, impersonator)
+				impersonatorID = &impersonator.ID
+			}
+			c.SetUserContext(actor.WithUser(tenant.WithOrganization(c.UserContext(), user.OrganizationID), &user.ID, impersonatorID))
 			return true, nil
 		},
 	})
@@ -103,6 +290,51 @@ func GetUserID(c *fiber.Ctx) uint {
 	return 0
 }
 
+// GetSession retrieves the authenticated session from context
+func GetSession(c *fiber.Ctx) *entity.Session {
+	if session, ok := c.Locals(LocalSession).(*entity.Session); ok {
+		return session
+	}
+	return nil
+}
+
+// GetSessionID retrieves the ID of the authenticated session, empty for API keys
+func GetSessionID(c *fiber.Ctx) string {
+	if session := GetSession(c); session != nil {
+		return session.ID
+	}
+	return ""
+}
+
+// GetActor retrieves the user acting as the authenticated user during an impersonation, if any
+func GetActor(c *fiber.Ctx) *entity.User {
+	if actor, ok := c.Locals(LocalActor).(*entity.User); ok {
+		return actor
+	}
+	return nil
+}
+
+// readOnlyMethod checks if an HTTP method only reads
+func readOnlyMethod(method string) bool {
+	return method == fiber.MethodGet || method == fiber.MethodHead || method == fiber.MethodOptions
+}
+
+// GetAPIKey retrieves the API key authenticating the request from context, if any
+func GetAPIKey(c *fiber.Ctx) *entity.APIKey {
+	if key, ok := c.Locals(LocalAPIKey).(*entity.APIKey); ok {
+		return key
+	}
+	return nil
+}
+
+// GetTokenID retrieves the token ID (jti) of the presented token from context
+func GetTokenID(c *fiber.Ctx) string {
+	if id, ok := c.Locals(LocalTokenID).(string); ok {
+		return id
+	}
+	return ""
+}
+
 // contextKey is a type for context keys to avoid collisions
 type contextKey string
 
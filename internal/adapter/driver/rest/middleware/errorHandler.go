@@ -4,6 +4,7 @@ import (
 	"errors"
 	"log"
 	"reflect"
+	"strconv"
 
 	"github.com/gofiber/contrib/fiberi18n/v2"
 	"github.com/gofiber/fiber/v2"
@@ -60,11 +61,20 @@ func handleAppError(c *fiber.Ctx, err *apperror.Error) error {
 	status := mapAppErrorToStatus(err.Code)
 	message := err.Message
 
-	// Try to localize if message is a key
-	if localized := fiberi18n.MustLocalize(c, string(err.Code)); localized != string(err.Code) {
+	// Writes conditioned with If-Match on a stale version fail their precondition (RFC 9110 §13.1.1)
+	if err.Code == apperror.CodeConflict && err.Field == "version" && c.Get(fiber.HeaderIfMatch) != "" {
+		status = fiber.StatusPreconditionFailed
+	}
+
+	// Try to localize if the code is a message key; codes without a translation keep their message
+	if localized, lErr := fiberi18n.Localize(c, string(err.Code)); lErr == nil && localized != "" {
 		message = localized
 	}
 
+	if retryAfter, ok := err.Details["retry_after"].(int); ok {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+	}
+
 	return presenter.New(c, status, message, nil)
 }
 
@@ -72,21 +82,25 @@ func handleAppError(c *fiber.Ctx, err *apperror.Error) error {
 func mapAppErrorToStatus(code apperror.Code) int {
 	switch code {
 	// Auth errors
-	case apperror.CodeUnauthorized, apperror.CodeInvalidCredentials, apperror.CodeDisabledUser, apperror.CodeTokenExpired:
+	case apperror.CodeUnauthorized, apperror.CodeInvalidCredentials, apperror.CodeDisabledUser, apperror.CodeTokenExpired,
+		apperror.CodeExternalLoginFailed, apperror.CodeInvalidSession, apperror.CodeRefreshTokenReused:
 		return fiber.StatusUnauthorized
-	case apperror.CodeForbidden:
+	case apperror.CodeForbidden, apperror.CodeExternalAccountUnknown, apperror.CodeEmailNotVerified,
+		apperror.CodeTwoFactorRequired:
 		return fiber.StatusForbidden
+	case apperror.CodeTooManyAttempts:
+		return fiber.StatusTooManyRequests
 
 	// Resource errors
-	case apperror.CodeNotFound, apperror.CodeUserNotFound, apperror.CodeProfileNotFound:
+	case apperror.CodeNotFound, apperror.CodeUserNotFound, apperror.CodeProfileNotFound, apperror.CodeSessionNotFound, apperror.CodeInvitationNotFound:
 		return fiber.StatusNotFound
 	case apperror.CodeAlreadyExists, apperror.CodeConflict:
 		return fiber.StatusConflict
-	case apperror.CodeResourceInUse:
+	case apperror.CodeResourceInUse, apperror.CodeProfileInUse:
 		return fiber.StatusBadRequest
 
 	// Validation errors
-	case apperror.CodeInvalidInput, apperror.CodeValidationFailed, apperror.CodeUserHasPassword, apperror.CodePasswordMismatch:
+	case apperror.CodeInvalidInput, apperror.CodeValidationFailed, apperror.CodeUserHasPassword, apperror.CodePasswordMismatch, apperror.CodeInvalidToken:
 		return fiber.StatusBadRequest
 
 	// System errors
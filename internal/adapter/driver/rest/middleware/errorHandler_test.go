@@ -0,0 +1,40 @@
+package middleware_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/middleware"
+	"github.com/raulaguila/go-api/pkg/apperror"
+)
+
+func TestErrorHandler_StaleVersion(t *testing.T) {
+	tests := []struct {
+		name, ifMatch string
+		err           error
+		status        int
+	}{
+		{"with If-Match", `"1"`, apperror.StaleVersion("user"), fiber.StatusPreconditionFailed},
+		{"without If-Match", "", apperror.StaleVersion("user"), fiber.StatusConflict},
+		{"other conflict", `"1"`, apperror.Conflict("user", "patch test failed"), fiber.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: middleware.DefaultErrorHandler()})
+			app.Put("/", func(*fiber.Ctx) error { return tt.err })
+
+			req := httptest.NewRequest(fiber.MethodPut, "/", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set(fiber.HeaderIfMatch, tt.ifMatch)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
@@ -49,6 +49,12 @@ func RequestLogger(log *loggerx.Logger) fiber.Handler {
 			fields["trace_id"] = traceID
 		}
 
+		// Requests made while impersonating are marked with who acted as the user
+		if actor := GetActor(c); actor != nil {
+			fields["user_id"] = GetUserID(c)
+			fields["impersonator_id"] = actor.ID
+		}
+
 		if err != nil {
 			fields["error"] = err.Error()
 		}
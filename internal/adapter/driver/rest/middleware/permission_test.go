@@ -0,0 +1,80 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/contrib/fiberi18n/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	"github.com/raulaguila/go-api/config"
+	"github.com/raulaguila/go-api/internal/adapter/driver/rest/middleware"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/internal/core/port/output/outputtest"
+)
+
+// fakeAPIKeys implements output.APIKeyRepository with a single key
+type fakeAPIKeys struct {
+	output.APIKeyRepository
+	key *entity.APIKey
+}
+
+func (r *fakeAPIKeys) FindByHash(context.Context, string) (*entity.APIKey, error) { return r.key, nil }
+
+func (r *fakeAPIKeys) Touch(context.Context, uint, time.Time) error { return nil }
+
+// fakeUsers implements output.UserRepository, returning its user with the profile it was cached with
+type fakeUsers struct {
+	output.UserRepository
+	cached *entity.Profile
+}
+
+func (r *fakeUsers) FindByID(_ context.Context, id uint) (*entity.User, error) {
+	auth := &entity.Auth{ID: id, Status: true, ProfileID: r.cached.ID, Profile: r.cached}
+	return &entity.User{ID: id, Name: "John Doe", Username: "johndoe", Email: "john@example.com", Auth: auth}, nil
+}
+
+func TestRequirePermission_RevokedPermissionIsRefused(t *testing.T) {
+	cached := entity.NewProfile("EDITOR", []string{entity.PermissionUsersRead, entity.PermissionUsersWrite})
+	cached.ID = 2
+	current := entity.NewProfile("EDITOR", []string{entity.PermissionUsersRead})
+	current.ID, current.Version = 2, 2
+
+	app := fiber.New()
+	app.Use(fiberi18n.New(&fiberi18n.Config{
+		RootPath:        "./locales",
+		AcceptLanguages: []language.Tag{language.AmericanEnglish},
+		DefaultLanguage: language.AmericanEnglish,
+		Loader:          &fiberi18n.EmbedLoader{FS: config.Locales},
+	}))
+	auth := middleware.Auth(middleware.AuthConfig{
+		UserRepo:    &fakeUsers{cached: cached},
+		ProfileRepo: outputtest.NewProfileRepository(current),
+		APIKeys:     &fakeAPIKeys{key: &entity.APIKey{ID: 1, UserID: 7, Scopes: []string{entity.PermissionUsersRead, entity.PermissionUsersWrite}}},
+	})
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) }
+	app.Get("/users", auth, middleware.RequirePermission(entity.PermissionUsersRead), ok)
+	app.Post("/users", auth, middleware.RequirePermission(entity.PermissionUsersWrite), ok)
+
+	for _, tt := range []struct {
+		method string
+		status int
+	}{
+		{fiber.MethodGet, fiber.StatusNoContent},
+		{fiber.MethodPost, fiber.StatusForbidden},
+	} {
+		t.Run(tt.method, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/users", nil)
+			req.Header.Set(middleware.HeaderAPIKey, "secret")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
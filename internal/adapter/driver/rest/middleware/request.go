@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/raulaguila/go-api/pkg/actor"
+)
+
+// RequestContext returns a middleware that carries the request ID and the client IP in the
+// user context, so that the changes made by the request can be attributed to it. The request
+// ID is the one logged by RequestLogger, the client's one, or a new one.
+func RequestContext() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		reqID := c.GetRespHeader(fiber.HeaderXRequestID)
+		if reqID == "" {
+			reqID = c.Get(fiber.HeaderXRequestID)
+		}
+		if reqID == "" {
+			reqID = uuid.New().String()
+		}
+		c.Set(fiber.HeaderXRequestID, reqID)
+
+		c.SetUserContext(actor.WithRequest(c.UserContext(), reqID, c.IP()))
+		return c.Next()
+	}
+}
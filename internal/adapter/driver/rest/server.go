@@ -1,7 +1,6 @@
 package rest
 
 import (
-	"crypto/rsa"
 	"fmt"
 	"html/template"
 	"os"
@@ -22,19 +21,20 @@ import (
 	"github.com/raulaguila/go-api/internal/adapter/driver/rest/middleware"
 	"github.com/raulaguila/go-api/internal/adapter/driver/rest/presenter"
 	"github.com/raulaguila/go-api/internal/app"
+	"github.com/raulaguila/go-api/pkg/jwtx"
 	"github.com/raulaguila/go-api/pkg/loggerx"
 )
 
 // Config holds server configuration
 type Config struct {
-	Port              int
-	EnablePrefork     bool
-	EnableLogger      bool
-	EnableSwagger     bool
-	Version           string
-	AccessPrivateKey  *rsa.PrivateKey
-	RefreshPrivateKey *rsa.PrivateKey
-	LocalesFS         interface {
+	Port          int
+	EnablePrefork bool
+	EnableLogger  bool
+	EnableSwagger bool
+	Version       string
+	AccessKeys    *jwtx.Keyring
+	RefreshKeys   *jwtx.Keyring
+	LocalesFS     interface {
 		Open(name string) (interface{ Close() error }, error)
 	}
 }
@@ -100,6 +100,7 @@ func (s *Server) setupMiddlewares() {
 	if s.config.EnableLogger {
 		s.app.Use(middleware.RequestLogger(s.log))
 	}
+	s.app.Use(middleware.RequestContext())
 
 	s.app.Use(
 		cors.New(cors.Config{
@@ -147,24 +148,48 @@ func (s *Server) setupRoutes() {
 
 	// Auth middlewares
 	accessAuth := middleware.Auth(middleware.AuthConfig{
-		PrivateKey:    s.config.AccessPrivateKey,
+		Keys:          s.config.AccessKeys,
+		TokenType:     jwtx.TypeAccess,
+		Issuer:        s.appCtx.Config.TokenIssuer,
+		Audience:      s.appCtx.Config.TokenAudience[0],
+		Leeway:        s.appCtx.Config.TokenLeeway,
 		UserRepo:      s.appCtx.Repositories.User,
+		ProfileRepo:   s.appCtx.Repositories.Profile,
+		SessionRepo:   s.appCtx.Repositories.Session,
+		Revocations:   s.appCtx.Repositories.Revocation,
+		APIKeys:       s.appCtx.Repositories.APIKey,
+		Activity:      s.appCtx.Repositories.SessionActivity,
 		AllowSkipAuth: s.appCtx.Config.Environment == "development",
 		Log:           s.appCtx.Log,
 	})
 
 	refreshAuth := middleware.Auth(middleware.AuthConfig{
-		PrivateKey:    s.config.RefreshPrivateKey,
+		Keys:          s.config.RefreshKeys,
+		TokenType:     jwtx.TypeRefresh,
+		Issuer:        s.appCtx.Config.TokenIssuer,
+		Audience:      s.appCtx.Config.TokenIssuer,
+		Leeway:        s.appCtx.Config.TokenLeeway,
 		UserRepo:      s.appCtx.Repositories.User,
+		ProfileRepo:   s.appCtx.Repositories.Profile,
+		SessionRepo:   s.appCtx.Repositories.Session,
+		Revocations:   s.appCtx.Repositories.Revocation,
+		Activity:      s.appCtx.Repositories.SessionActivity,
 		AllowSkipAuth: s.appCtx.Config.Environment == "development",
 		Log:           s.appCtx.Log,
 	})
 
 	// Register handlers
 	handler.NewHealthHandler(s.app.Group(""), s.appCtx)
+	handler.NewWellKnownHandler(s.app.Group("/.well-known"), s.config.AccessKeys, s.appCtx.Config.OAuthIssuer)
 	handler.NewAuthHandler(s.app.Group("/auth"), s.appCtx.Auth, accessAuth, refreshAuth)
+	handler.NewAPIKeyHandler(s.app.Group("/auth/keys"), s.appCtx.APIKey, accessAuth)
+	handler.NewSessionHandler(s.app.Group("/auth/sessions"), s.appCtx.User, accessAuth)
+	handler.NewOAuthClientHandler(s.app.Group("/oauth/clients"), s.appCtx.OAuth, accessAuth)
+	handler.NewOAuthHandler(s.app.Group("/oauth"), s.appCtx.OAuth, accessAuth)
 	handler.NewProfileHandler(s.app.Group("/profile"), s.appCtx.Profile, accessAuth)
+	handler.NewOrganizationHandler(s.app.Group("/organization"), s.appCtx.Organization, accessAuth)
 	handler.NewUserHandler(s.app.Group("/user"), s.appCtx.User, accessAuth)
+	handler.NewAuditHandler(s.app.Group("/audit"), s.appCtx.Audit, accessAuth)
 
 	// 404 handler
 	s.app.All("*", func(c *fiber.Ctx) error {
@@ -19,9 +19,13 @@ type Application struct {
 	Log *loggerx.Logger
 
 	// Use Cases (Input Ports)
-	Auth    input.AuthUseCase
-	Profile input.ProfileUseCase
-	User    input.UserUseCase
+	Auth         input.AuthUseCase
+	Profile      input.ProfileUseCase
+	User         input.UserUseCase
+	APIKey       input.APIKeyUseCase
+	OAuth        input.OAuthUseCase
+	Organization input.OrganizationUseCase
+	Audit        input.AuditUseCase
 
 	// Repositories (Output Ports) - exposed for adapters that need direct access
 	Repositories *Repositories
@@ -29,8 +33,22 @@ type Application struct {
 
 // Repositories holds all repository implementations
 type Repositories struct {
-	User    output.UserRepository
-	Profile output.ProfileRepository
+	User              output.UserRepository
+	Profile           output.ProfileRepository
+	Session           output.SessionRepository
+	UserToken         output.UserTokenRepository
+	LoginAttempt      output.LoginAttemptRepository
+	APIKey            output.APIKeyRepository
+	ExternalIdentity  output.ExternalIdentityRepository
+	OAuthClient       output.OAuthClientRepository
+	Organization      output.OrganizationRepository
+	Revocation        output.RevocationStore
+	LoginThrottle     output.LoginThrottle
+	ExternalLogin     output.ExternalLoginStore
+	AuthorizationCode output.AuthorizationCodeStore
+	SessionActivity   output.SessionActivityRecorder
+	Audit             output.AuditRepository
+	Transactor        output.Transactor
 }
 
 // Options holds optional dependencies for the application
@@ -56,6 +74,10 @@ func New(
 	authUC input.AuthUseCase,
 	profileUC input.ProfileUseCase,
 	userUC input.UserUseCase,
+	apiKeyUC input.APIKeyUseCase,
+	oauthUC input.OAuthUseCase,
+	organizationUC input.OrganizationUseCase,
+	auditUC input.AuditUseCase,
 	repos *Repositories,
 	opts ...Option,
 ) *Application {
@@ -65,6 +87,10 @@ func New(
 		Auth:         authUC,
 		Profile:      profileUC,
 		User:         userUC,
+		APIKey:       apiKeyUC,
+		OAuth:        oauthUC,
+		Organization: organizationUC,
+		Audit:        auditUC,
 		Repositories: repos,
 	}
 
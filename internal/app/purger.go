@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/pkg/loggerx"
+)
+
+// Purger permanently deletes, every interval, the users and profiles that stayed in the trash
+// longer than the retention period. Users are purged first, so that profiles only granted to
+// them are purged in the same run.
+type Purger struct {
+	users     output.UserRepository
+	profiles  output.ProfileRepository
+	retention time.Duration
+	log       *loggerx.Logger
+	stop      chan struct{}
+	done      chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewPurger creates a new Purger running every interval
+func NewPurger(users output.UserRepository, profiles output.ProfileRepository, retention, interval time.Duration, log *loggerx.Logger) *Purger {
+	p := &Purger{
+		users:     users,
+		profiles:  profiles,
+		retention: retention,
+		log:       log,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	go p.run(interval)
+	return p
+}
+
+// Purge permanently deletes the users and profiles moved to the trash before the retention
+// period and returns how many of each were deleted
+func (p *Purger) Purge(ctx context.Context) (users, profiles int64, err error) {
+	before := time.Now().Add(-p.retention)
+
+	if users, err = p.users.Purge(ctx, before); err != nil {
+		return 0, 0, err
+	}
+	if profiles, err = p.profiles.Purge(ctx, before); err != nil {
+		return users, 0, err
+	}
+	return users, profiles, nil
+}
+
+// Close stops the periodic purges, waiting for a running one to finish
+func (p *Purger) Close() error {
+	p.stopOnce.Do(func() { close(p.stop) })
+	<-p.done
+	return nil
+}
+
+// run purges the trash every interval until Close
+func (p *Purger) run(interval time.Duration) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			users, profiles, err := p.Purge(context.Background())
+			if p.log == nil {
+				continue
+			}
+			if err != nil {
+				p.log.Warn("Failed to purge the trash", slog.String("error", err.Error()))
+			} else if users > 0 || profiles > 0 {
+				p.log.Info("Trash purged", slog.Int64("users", users), slog.Int64("profiles", profiles))
+			}
+		}
+	}
+}
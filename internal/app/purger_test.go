@@ -0,0 +1,84 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/internal/app"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+)
+
+// trash records the times a repository was asked to purge before
+type trash struct {
+	purged int64
+	err    error
+	before []time.Time
+}
+
+func (f *trash) Purge(_ context.Context, before time.Time) (int64, error) {
+	f.before = append(f.before, before)
+	return f.purged, f.err
+}
+
+// fakeUserTrash implements the purges of output.UserRepository
+type fakeUserTrash struct {
+	output.UserRepository
+	*trash
+}
+
+func (f fakeUserTrash) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return f.trash.Purge(ctx, before)
+}
+
+// fakeProfileTrash implements the purges of output.ProfileRepository
+type fakeProfileTrash struct {
+	output.ProfileRepository
+	*trash
+}
+
+func (f fakeProfileTrash) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return f.trash.Purge(ctx, before)
+}
+
+func TestPurger_Purge(t *testing.T) {
+	users, profiles := &trash{purged: 3}, &trash{purged: 1}
+	purger := app.NewPurger(fakeUserTrash{trash: users}, fakeProfileTrash{trash: profiles}, 24*time.Hour, time.Hour, nil)
+	defer purger.Close()
+
+	purgedUsers, purgedProfiles, err := purger.Purge(context.Background())
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, purgedUsers)
+	assert.EqualValues(t, 1, purgedProfiles)
+
+	require.Len(t, users.before, 1)
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), users.before[0], time.Minute, "only records past the retention are purged")
+	assert.Equal(t, users.before, profiles.before)
+}
+
+func TestPurger_ProfilesWaitForUsers(t *testing.T) {
+	users, profiles := &trash{err: errors.New("connection lost")}, &trash{}
+	purger := app.NewPurger(fakeUserTrash{trash: users}, fakeProfileTrash{trash: profiles}, time.Hour, time.Hour, nil)
+	defer purger.Close()
+
+	_, _, err := purger.Purge(context.Background())
+	assert.Error(t, err)
+	assert.Empty(t, profiles.before, "profiles granted to users not purged yet cannot be purged")
+}
+
+func TestPurger_RunsEveryInterval(t *testing.T) {
+	users, profiles := &trash{}, &trash{}
+	purger := app.NewPurger(fakeUserTrash{trash: users}, fakeProfileTrash{trash: profiles}, time.Hour, 10*time.Millisecond, nil)
+
+	time.Sleep(55 * time.Millisecond)
+	require.NoError(t, purger.Close())
+	runs := len(users.before)
+	assert.GreaterOrEqual(t, runs, 2)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, runs, len(users.before), "no purge runs after Close")
+}
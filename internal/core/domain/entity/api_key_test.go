@@ -0,0 +1,69 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	key, secret, err := entity.NewAPIKey(7, " deploy ", []string{"users:read"}, nil)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(secret, entity.APIKeyPrefix))
+	assert.True(t, strings.HasPrefix(secret, key.Prefix))
+	assert.Equal(t, entity.HashToken(secret), key.KeyHash)
+	assert.NotContains(t, key.KeyHash, secret, "only the hash of the secret is kept")
+	assert.Equal(t, "deploy", key.Name)
+	assert.True(t, key.IsActive())
+}
+
+func TestNewAPIKey_Invalid(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	_, _, err := entity.NewAPIKey(7, " ", []string{"users:read"}, nil)
+	assert.Error(t, err)
+
+	_, _, err = entity.NewAPIKey(7, "deploy", nil, nil)
+	assert.Error(t, err)
+
+	_, _, err = entity.NewAPIKey(7, "deploy", []string{"users:read"}, &past)
+	assert.Error(t, err)
+}
+
+func TestAPIKey_IsActive(t *testing.T) {
+	key, _, err := entity.NewAPIKey(7, "deploy", []string{"users:read"}, nil)
+	require.NoError(t, err)
+
+	past := time.Now().Add(-time.Minute)
+	key.ExpiresAt = &past
+	assert.False(t, key.IsActive(), "expired keys are rejected")
+
+	key.ExpiresAt = nil
+	key.Revoke()
+	assert.False(t, key.IsActive(), "revoked keys are rejected")
+}
+
+func TestAPIKey_HasScope(t *testing.T) {
+	key := &entity.APIKey{Scopes: []string{"users:read", "profiles:*"}}
+
+	assert.True(t, key.HasScope("users:read"))
+	assert.False(t, key.HasScope("users:write"))
+	assert.True(t, key.HasScope("profiles:write"))
+}
+
+func TestAPIKey_Touch(t *testing.T) {
+	key := &entity.APIKey{}
+
+	assert.True(t, key.Touch(), "the first use is recorded")
+	assert.False(t, key.Touch(), "uses within a minute are not recorded again")
+
+	earlier := time.Now().Add(-2 * time.Minute)
+	key.LastUsedAt = &earlier
+	assert.True(t, key.Touch())
+}
@@ -0,0 +1,168 @@
+package entity
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/raulaguila/go-api/pkg/passwd"
+)
+
+// Audited actions
+const (
+	AuditActionCreate            = "create"
+	AuditActionUpdate            = "update"
+	AuditActionDelete            = "delete"
+	AuditActionRestore           = "restore"
+	AuditActionInvite            = "invite"
+	AuditActionResendInvitation  = "resend_invitation"
+	AuditActionRevokeInvitation  = "revoke_invitation"
+	AuditActionAcceptInvitation  = "accept_invitation"
+	AuditActionSetPassword       = "set_password"
+	AuditActionChangePassword    = "change_password"
+	AuditActionVerifyEmail       = "verify_email"
+	AuditActionUnlock            = "unlock"
+	AuditActionEnableTwoFactor   = "enable_2fa"
+	AuditActionDisableTwoFactor  = "disable_2fa"
+	AuditActionRevokeSession     = "revoke_session"
+	AuditActionRevokeAllSessions = "revoke_sessions"
+	AuditActionImpersonate       = "impersonate"
+)
+
+// Audited entity types
+const (
+	AuditEntityUser    = "user"
+	AuditEntityProfile = "profile"
+	AuditEntitySession = "session"
+)
+
+// AuditState is the state of an entity as recorded in the audit log, by field name.
+// It holds copies of the values, so it is not affected by later changes of the entity.
+type AuditState map[string]any
+
+// AuditChange is the value of a field before and after an audited action
+type AuditChange struct {
+	Before any
+	After  any
+}
+
+// AuditRecord records who performed an action on an entity, from where, and what changed.
+// Records are append-only.
+type AuditRecord struct {
+	ID             uint
+	OrganizationID *uint // Organization of the entity; nil for entities of super-admins
+	ActorID        *uint // Nil for actions of the API itself
+	ImpersonatorID *uint // Set when the actor was impersonated
+	Action         string
+	EntityType     string
+	EntityID       string
+	Changes        map[string]AuditChange // Only the fields that changed
+	RequestID      string
+	IP             string
+	CreatedAt      time.Time
+}
+
+// NewAuditRecord creates a new AuditRecord entity with the fields that differ between
+// before and after. Before is nil for created entities and after is nil for deleted ones.
+func NewAuditRecord(action, entityType, entityID string, organizationID *uint, before, after AuditState) *AuditRecord {
+	return &AuditRecord{
+		OrganizationID: organizationID,
+		Action:         action,
+		EntityType:     entityType,
+		EntityID:       entityID,
+		Changes:        DiffAuditStates(before, after),
+		CreatedAt:      time.Now(),
+	}
+}
+
+// DiffAuditStates returns the fields whose value differs between before and after
+func DiffAuditStates(before, after AuditState) map[string]AuditChange {
+	changes := make(map[string]AuditChange)
+	for field, value := range before {
+		if !reflect.DeepEqual(value, after[field]) {
+			changes[field] = AuditChange{Before: value, After: after[field]}
+		}
+	}
+	for field, value := range after {
+		if _, ok := before[field]; !ok && value != nil {
+			changes[field] = AuditChange{After: value}
+		}
+	}
+	return changes
+}
+
+// AuditEntityID formats the ID of an entity for the audit log
+func AuditEntityID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// AuditState returns the audited fields of the user; secrets are never recorded
+func (u *User) AuditState() AuditState {
+	state := AuditState{
+		"name":              u.Name,
+		"username":          u.Username,
+		"email":             u.Email,
+		"pending_email":     auditValue(u.PendingEmail),
+		"email_verified_at": auditTime(u.EmailVerifiedAt),
+		"organization_id":   auditValue(u.OrganizationID),
+	}
+	if u.Auth != nil {
+		state["status"] = u.Auth.Status
+		state["pending"] = u.Auth.Pending
+		state["profile_id"] = u.Auth.ProfileID
+		state["totp_enabled"] = u.Auth.TOTPEnabled
+		state["password_changed_at"] = auditTime(u.Auth.PasswordChangedAt)
+	}
+	return state
+}
+
+// AuditState returns the audited fields of the profile
+func (p *Profile) AuditState() AuditState {
+	permissions := slices.Clone(p.Permissions)
+	if permissions == nil {
+		permissions = []string{}
+	}
+	var policy *passwd.Policy
+	if p.PasswordPolicy != nil {
+		copied := *p.PasswordPolicy
+		policy = &copied
+	}
+	return AuditState{
+		"name":                   p.Name,
+		"permissions":            permissions,
+		"require_2fa":            p.RequireTwoFactor,
+		"require_verified_email": p.RequireVerifiedEmail,
+		"password_policy":        auditValue(policy),
+		"organization_id":        auditValue(p.OrganizationID),
+	}
+}
+
+// AuditState returns the audited fields of the session
+func (s *Session) AuditState() AuditState {
+	return AuditState{
+		"user_id":    s.UserID,
+		"actor_id":   auditValue(s.ActorID),
+		"client_id":  s.ClientID,
+		"ip":         s.IP,
+		"user_agent": s.UserAgent,
+		"expires_at": auditTime(s.ExpiresAt),
+		"revoked_at": auditTime(s.RevokedAt),
+	}
+}
+
+// auditValue returns the value v points to, or nil
+func auditValue[T any](v *T) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// auditTime returns t in RFC 3339, or nil, so that equal instants compare equal
+func auditTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
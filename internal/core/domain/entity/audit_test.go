@@ -0,0 +1,45 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuditRecord_RecordsOnlyChangedFields(t *testing.T) {
+	auth, err := entity.NewAuth(1, true)
+	assert.NoError(t, err)
+	user, err := entity.NewUser("John Doe", "johndoe", "john@example.com", auth)
+	assert.NoError(t, err)
+
+	before := user.AuditState()
+	user.UpdateName("John Smith")
+	user.Auth.Disable()
+	record := entity.NewAuditRecord(entity.AuditActionUpdate, entity.AuditEntityUser, "7", nil, before, user.AuditState())
+
+	assert.Equal(t, map[string]entity.AuditChange{
+		"name":   {Before: "John Doe", After: "John Smith"},
+		"status": {Before: true, After: false},
+	}, record.Changes)
+}
+
+func TestNewAuditRecord_CreateAndDelete(t *testing.T) {
+	profile := entity.NewProfile("Support", []string{"users:read"})
+
+	created := entity.NewAuditRecord(entity.AuditActionCreate, entity.AuditEntityProfile, "1", nil, nil, profile.AuditState())
+	assert.Equal(t, entity.AuditChange{After: "Support"}, created.Changes["name"])
+	assert.NotContains(t, created.Changes, "password_policy", "unset fields are not recorded")
+
+	deleted := entity.NewAuditRecord(entity.AuditActionDelete, entity.AuditEntityProfile, "1", nil, profile.AuditState(), nil)
+	assert.Equal(t, entity.AuditChange{Before: []string{"users:read"}}, deleted.Changes["permissions"])
+}
+
+func TestProfileAuditState_IsACopy(t *testing.T) {
+	profile := entity.NewProfile("Support", []string{"users:read"})
+
+	state := profile.AuditState()
+	profile.Permissions[0] = "users:write"
+
+	assert.Equal(t, []string{"users:read"}, state["permissions"])
+}
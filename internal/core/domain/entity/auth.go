@@ -1,21 +1,38 @@
 package entity
 
 import (
+	"crypto/rand"
+	"encoding/base32"
+	"slices"
+	"strings"
 	"time"
 
-	"golang.org/x/crypto/bcrypt"
-
+	"github.com/raulaguila/go-api/pkg/passwd"
+	"github.com/raulaguila/go-api/pkg/totp"
 	"github.com/raulaguila/go-api/pkg/validator"
 )
 
+// recoveryCodeCount is the amount of recovery codes issued when 2FA is activated
+const recoveryCodeCount = 10
+
 // Auth represents the authentication information for a user
 type Auth struct {
 	ID        uint
 	Status    bool
+	Pending   bool // Invited users are pending until they accept the invitation
 	ProfileID uint
 	Profile   *Profile
-	Token     *string
 	Password  *string
+
+	PasswordChangedAt *time.Time
+	PasswordHistory   []string // Hashes of the previous passwords, most recent first
+
+	// Two-factor authentication. The secret is pending until confirmed with a code.
+	TOTPSecret    *string
+	TOTPEnabled   bool
+	TOTPLastStep  int64
+	RecoveryCodes []string // Hashes of the unused recovery codes
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -35,40 +52,96 @@ func NewAuth(profileID uint, status bool) (*Auth, error) {
 }
 
 // SetPassword hashes and sets the password
-func (a *Auth) SetPassword(password string) error {
+func (a *Auth) SetPassword(password string, hasher passwd.Hasher) error {
 	if len(password) < validator.MinPasswordLength {
 		return ErrPasswordTooShort()
 	}
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hash, err := hasher.Hash(password)
 	if err != nil {
 		return err
 	}
 
-	hashed := string(hash)
-	a.Password = &hashed
-	a.UpdatedAt = time.Now()
+	now := time.Now()
+	a.Password = &hash
+	a.PasswordChangedAt = &now
+	a.UpdatedAt = now
 	return nil
 }
 
+// ChangePassword sets a password following policy, checked against the personal values
+// it must not resemble. The current and previous passwords count towards the history.
+func (a *Auth) ChangePassword(password string, policy passwd.Policy, hasher passwd.Hasher, personal ...string) error {
+	if err := policy.Check(password, personal...); err != nil {
+		return ErrPasswordPolicy(err)
+	}
+
+	recent := a.recentPasswords()
+	for _, hash := range recent[:min(len(recent), policy.History)] {
+		if hasher.Verify(password, hash) {
+			return ErrPasswordReused(policy.History)
+		}
+	}
+
+	if err := a.SetPassword(password, hasher); err != nil {
+		return err
+	}
+
+	// Only the previous passwords the policy remembers are kept
+	a.PasswordHistory = recent[:min(len(recent), max(policy.History-1, 0))]
+	return nil
+}
+
+// recentPasswords returns the hashes of the current and previous passwords, most recent first
+func (a *Auth) recentPasswords() []string {
+	if a.Password == nil {
+		return a.PasswordHistory
+	}
+	return append([]string{*a.Password}, a.PasswordHistory...)
+}
+
+// PasswordExpired checks if the password is older than maxAge; zero never expires
+func (a *Auth) PasswordExpired(maxAge time.Duration) bool {
+	return maxAge > 0 && a.Password != nil && a.PasswordChangedAt != nil && time.Since(*a.PasswordChangedAt) > maxAge
+}
+
 // ValidatePassword checks if the provided password matches the stored hash
-func (a *Auth) ValidatePassword(password string) bool {
+func (a *Auth) ValidatePassword(password string, hasher passwd.Hasher) bool {
 	if a.Password == nil {
+		CompareDummyPassword(password, hasher)
 		return false
 	}
-	return bcrypt.CompareHashAndPassword([]byte(*a.Password), []byte(password)) == nil
+	return hasher.Verify(password, *a.Password)
 }
 
-// ResetPassword clears the password and token
-func (a *Auth) ResetPassword() {
-	a.Token = nil
-	a.Password = nil
+// RehashPassword hashes again a password just validated when its hash was made with
+// outdated parameters or algorithm, and reports if it did. The password age is kept.
+func (a *Auth) RehashPassword(password string, hasher passwd.Hasher) (bool, error) {
+	if a.Password == nil || !hasher.NeedsRehash(*a.Password) {
+		return false, nil
+	}
+
+	hash, err := hasher.Hash(password)
+	if err != nil {
+		return false, err
+	}
+
+	a.Password = &hash
 	a.UpdatedAt = time.Now()
+	return true, nil
+}
+
+// CompareDummyPassword spends the time of a password check when there is no password to check,
+// so that logins of unknown users and users without a password take as long as a wrong password
+func CompareDummyPassword(password string, hasher passwd.Hasher) {
+	// Hashing costs as much as verifying a hash of the same parameters
+	_, _ = hasher.Hash(password)
 }
 
-// SetToken sets the authentication token
-func (a *Auth) SetToken(token string) {
-	a.Token = &token
+// ResetPassword clears the password, which is remembered in the history
+func (a *Auth) ResetPassword() {
+	a.PasswordHistory = a.recentPasswords()
+	a.Password = nil
 	a.UpdatedAt = time.Now()
 }
 
@@ -82,6 +155,12 @@ func (a *Auth) IsActive() bool {
 	return a.Status && a.Password != nil
 }
 
+// AcceptInvitation ends the pending state of an invited user
+func (a *Auth) AcceptInvitation() {
+	a.Pending = false
+	a.UpdatedAt = time.Now()
+}
+
 // Enable enables the auth
 func (a *Auth) Enable() {
 	a.Status = true
@@ -93,3 +172,118 @@ func (a *Auth) Disable() {
 	a.Status = false
 	a.UpdatedAt = time.Now()
 }
+
+// TwoFactorRequired checks if the user's profile makes 2FA mandatory
+func (a *Auth) TwoFactorRequired() bool {
+	return a.Profile != nil && a.Profile.RequireTwoFactor
+}
+
+// EnrollTOTP generates a new pending TOTP secret, replacing any previous one.
+// It only becomes active after ConfirmTOTP.
+func (a *Auth) EnrollTOTP() (string, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", err
+	}
+
+	a.TOTPSecret = &secret
+	a.TOTPEnabled = false
+	a.TOTPLastStep = 0
+	a.RecoveryCodes = nil
+	a.UpdatedAt = time.Now()
+	return secret, nil
+}
+
+// HasPendingTOTP checks if a TOTP secret awaits confirmation
+func (a *Auth) HasPendingTOTP() bool {
+	return a.TOTPSecret != nil && !a.TOTPEnabled
+}
+
+// ConfirmTOTP activates the pending TOTP secret and returns a new set of recovery codes
+func (a *Auth) ConfirmTOTP(code string) ([]string, error) {
+	if !a.HasPendingTOTP() {
+		return nil, ErrInvalidTwoFactorCode()
+	}
+
+	step, ok := totp.Validate(*a.TOTPSecret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode()
+	}
+
+	codes, err := a.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	a.TOTPEnabled = true
+	a.TOTPLastStep = step
+	a.UpdatedAt = time.Now()
+	return codes, nil
+}
+
+// VerifyTOTP checks a code of the active secret. Each time step is accepted only once.
+func (a *Auth) VerifyTOTP(code string) bool {
+	if !a.TOTPEnabled || a.TOTPSecret == nil {
+		return false
+	}
+
+	step, ok := totp.Validate(*a.TOTPSecret, code, time.Now())
+	if !ok || step <= a.TOTPLastStep {
+		return false
+	}
+
+	a.TOTPLastStep = step
+	a.UpdatedAt = time.Now()
+	return true
+}
+
+// UseRecoveryCode consumes a recovery code, which cannot be used again
+func (a *Auth) UseRecoveryCode(code string) bool {
+	hash := HashToken(normalizeRecoveryCode(code))
+	idx := slices.Index(a.RecoveryCodes, hash)
+	if !a.TOTPEnabled || idx < 0 {
+		return false
+	}
+
+	a.RecoveryCodes = slices.Delete(a.RecoveryCodes, idx, idx+1)
+	a.UpdatedAt = time.Now()
+	return true
+}
+
+// VerifyTwoFactor checks a TOTP code or, failing that, consumes a recovery code
+func (a *Auth) VerifyTwoFactor(code string) bool {
+	return a.VerifyTOTP(code) || a.UseRecoveryCode(code)
+}
+
+// DisableTOTP removes the TOTP secret and recovery codes
+func (a *Auth) DisableTOTP() {
+	a.TOTPSecret = nil
+	a.TOTPEnabled = false
+	a.TOTPLastStep = 0
+	a.RecoveryCodes = nil
+	a.UpdatedAt = time.Now()
+}
+
+// generateRecoveryCodes replaces the recovery codes, returning them in plain text
+func (a *Auth) generateRecoveryCodes() ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	raw := make([]byte, 5)
+	for i := range codes {
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(raw)) // 8 characters
+		codes[i] = code[:4] + "-" + code[4:]
+		hashes[i] = HashToken(code)
+	}
+
+	a.RecoveryCodes = hashes
+	return codes, nil
+}
+
+// normalizeRecoveryCode drops separators and case from a recovery code
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,117 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/pkg/passwd"
+	"github.com/raulaguila/go-api/pkg/totp"
+)
+
+// testHasher hashes passwords with the default parameters
+var testHasher = passwd.NewHasher(passwd.DefaultArgon2id, passwd.DefaultBcrypt)
+
+func enabledTOTPAuth(t *testing.T) (*entity.Auth, string, []string) {
+	auth, err := entity.NewAuth(1, true)
+	require.NoError(t, err)
+
+	secret, err := auth.EnrollTOTP()
+	require.NoError(t, err)
+	assert.True(t, auth.HasPendingTOTP())
+
+	// Confirm with the previous step so the current one is still usable afterwards
+	code, err := totp.Code(secret, totp.Step(time.Now())-1)
+	require.NoError(t, err)
+
+	recovery, err := auth.ConfirmTOTP(code)
+	require.NoError(t, err)
+	return auth, secret, recovery
+}
+
+func TestAuth_ConfirmTOTP(t *testing.T) {
+	auth, err := entity.NewAuth(1, true)
+	require.NoError(t, err)
+
+	_, err = auth.ConfirmTOTP("123456")
+	assert.Error(t, err, "confirming without enrollment must fail")
+
+	_, err = auth.EnrollTOTP()
+	require.NoError(t, err)
+	_, err = auth.ConfirmTOTP("000000x")
+	assert.Error(t, err)
+	assert.False(t, auth.TOTPEnabled)
+
+	auth, _, recovery := enabledTOTPAuth(t)
+	assert.True(t, auth.TOTPEnabled)
+	assert.Len(t, recovery, 10)
+	assert.NotContains(t, auth.RecoveryCodes, recovery[0], "recovery codes must be stored hashed")
+}
+
+func TestAuth_VerifyTOTP_RejectsReplay(t *testing.T) {
+	auth, secret, _ := enabledTOTPAuth(t)
+
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+
+	assert.True(t, auth.VerifyTOTP(code))
+	assert.False(t, auth.VerifyTOTP(code), "a code must not be accepted twice")
+}
+
+func TestAuth_UseRecoveryCode(t *testing.T) {
+	auth, _, recovery := enabledTOTPAuth(t)
+
+	assert.True(t, auth.UseRecoveryCode(recovery[0]))
+	assert.False(t, auth.UseRecoveryCode(recovery[0]), "a recovery code must be single use")
+	assert.True(t, auth.VerifyTwoFactor(recovery[1]))
+	assert.Len(t, auth.RecoveryCodes, 8)
+
+	auth.DisableTOTP()
+	assert.False(t, auth.UseRecoveryCode(recovery[2]))
+}
+
+func TestAuth_ChangePassword_History(t *testing.T) {
+	auth, err := entity.NewAuth(1, true)
+	require.NoError(t, err)
+	policy := passwd.Policy{History: 2}
+
+	require.NoError(t, auth.ChangePassword("first-pass", policy, testHasher))
+	require.NoError(t, auth.ChangePassword("second-pass", policy, testHasher))
+
+	assert.Error(t, auth.ChangePassword("second-pass", policy, testHasher), "the current password must not be reused")
+	assert.Error(t, auth.ChangePassword("first-pass", policy, testHasher), "the previous password must not be reused")
+
+	require.NoError(t, auth.ChangePassword("third-pass", policy, testHasher))
+	assert.Len(t, auth.PasswordHistory, 1)
+	assert.NoError(t, auth.ChangePassword("first-pass", policy, testHasher), "passwords older than the history can be reused")
+	assert.True(t, auth.ValidatePassword("first-pass", testHasher))
+
+	auth.ResetPassword()
+	assert.Error(t, auth.ChangePassword("first-pass", policy, testHasher), "a reset password is still remembered")
+}
+
+func TestAuth_ChangePassword_Policy(t *testing.T) {
+	auth, err := entity.NewAuth(1, true)
+	require.NoError(t, err)
+
+	err = auth.ChangePassword("johndoe2024", passwd.Policy{RejectSimilar: true}, testHasher, "johndoe", "john@example.com")
+	assert.Error(t, err)
+	assert.Nil(t, auth.Password)
+}
+
+func TestAuth_PasswordExpired(t *testing.T) {
+	auth, err := entity.NewAuth(1, true)
+	require.NoError(t, err)
+	assert.False(t, auth.PasswordExpired(time.Hour), "users without a password have nothing to change")
+
+	require.NoError(t, auth.SetPassword("12345678", testHasher))
+	assert.False(t, auth.PasswordExpired(time.Hour))
+
+	changedAt := time.Now().Add(-2 * time.Hour)
+	auth.PasswordChangedAt = &changedAt
+	assert.True(t, auth.PasswordExpired(time.Hour))
+	assert.False(t, auth.PasswordExpired(0), "a zero max age never expires")
+}
@@ -1,6 +1,10 @@
 package entity
 
-import "github.com/raulaguila/go-api/pkg/apperror"
+import (
+	"fmt"
+
+	"github.com/raulaguila/go-api/pkg/apperror"
+)
 
 // Entity validation errors - using apperror for consistent error handling
 
@@ -38,3 +42,73 @@ func ErrProfileRequired() *apperror.Error {
 func ErrPasswordTooShort() *apperror.Error {
 	return apperror.InvalidInput("password", "password must be at least 6 characters")
 }
+
+// ErrPasswordPolicy returns error for a password breaking the password policy
+func ErrPasswordPolicy(err error) *apperror.Error {
+	return apperror.InvalidInput("password", err.Error())
+}
+
+// ErrPasswordReused returns error for a password among the recent ones
+func ErrPasswordReused(history int) *apperror.Error {
+	return apperror.InvalidInput("password", fmt.Sprintf("password must differ from the last %d passwords", history))
+}
+
+// ErrInvalidTwoFactorCode returns error for a wrong TOTP or recovery code
+func ErrInvalidTwoFactorCode() *apperror.Error {
+	return apperror.InvalidInput("code", "invalid two-factor code")
+}
+
+// ErrAPIKeyNameRequired returns error when an API key has no name
+func ErrAPIKeyNameRequired() *apperror.Error {
+	return apperror.InvalidInput("name", "name is required")
+}
+
+// ErrAPIKeyScopesRequired returns error when an API key has no scopes
+func ErrAPIKeyScopesRequired() *apperror.Error {
+	return apperror.InvalidInput("scopes", "at least one scope is required")
+}
+
+// ErrAPIKeyExpired returns error when an API key would be created already expired
+func ErrAPIKeyExpired() *apperror.Error {
+	return apperror.InvalidInput("expires_at", "expiration must be in the future")
+}
+
+// ErrAPIKeyScopeNotGranted returns error when an API key asks for a permission its owner lacks
+func ErrAPIKeyScopeNotGranted(scope string) *apperror.Error {
+	return apperror.InvalidInput("scopes", "scope not granted by the user profile: "+scope)
+}
+
+// ErrOrganizationNameRequired returns error when an organization has no name
+func ErrOrganizationNameRequired() *apperror.Error {
+	return apperror.InvalidInput("name", "name is required")
+}
+
+// ErrOAuthClientNameRequired returns error when an OAuth client has no name
+func ErrOAuthClientNameRequired() *apperror.Error {
+	return apperror.InvalidInput("name", "name is required")
+}
+
+// ErrOAuthClientScopesRequired returns error when an OAuth client has no scopes
+func ErrOAuthClientScopesRequired() *apperror.Error {
+	return apperror.InvalidInput("scopes", "at least one scope is required")
+}
+
+// ErrOAuthClientGrantTypeInvalid returns error for a grant type a client cannot be registered for
+func ErrOAuthClientGrantTypeInvalid(grant string) *apperror.Error {
+	return apperror.InvalidInput("grant_types", "invalid grant type: "+grant)
+}
+
+// ErrOAuthClientSecretRequired returns error when a public client asks for a grant that needs a secret
+func ErrOAuthClientSecretRequired() *apperror.Error {
+	return apperror.InvalidInput("confidential", "the client_credentials grant requires a confidential client")
+}
+
+// ErrOAuthClientRedirectURIRequired returns error when a client using codes has no redirect URI
+func ErrOAuthClientRedirectURIRequired() *apperror.Error {
+	return apperror.InvalidInput("redirect_uris", "at least one redirect URI is required")
+}
+
+// ErrOAuthClientRedirectURIInvalid returns error for a redirect URI that is not an absolute URL
+func ErrOAuthClientRedirectURIInvalid(uri string) *apperror.Error {
+	return apperror.InvalidInput("redirect_uris", "redirect URI must be an absolute URL without fragment: "+uri)
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+)
+
+// ExternalIdentity links a user to an account at an external identity provider
+type ExternalIdentity struct {
+	ID        uint
+	UserID    uint
+	Provider  string
+	Subject   string // Identifier of the account at the provider, stable unlike the email
+	Email     string
+	CreatedAt time.Time
+}
+
+// NewExternalIdentity creates a new ExternalIdentity entity
+func NewExternalIdentity(userID uint, provider, subject, email string) *ExternalIdentity {
+	return &ExternalIdentity{
+		UserID:    userID,
+		Provider:  provider,
+		Subject:   subject,
+		Email:     email,
+		CreatedAt: time.Now(),
+	}
+}
+
+// ExternalLogin is a login started at an external identity provider, kept until the
+// provider redirects the user back. The state identifies it, the nonce binds the ID
+// token to it and the code verifier proves the code is redeemed by whoever asked for it.
+type ExternalLogin struct {
+	State        string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	Expiration   bool // Whether the session opened by the login expires
+	CreatedAt    time.Time
+}
+
+// NewExternalLogin creates a new ExternalLogin entity with random state, nonce and verifier
+func NewExternalLogin(provider string, expiration bool) (*ExternalLogin, error) {
+	login := &ExternalLogin{Provider: provider, Expiration: expiration, CreatedAt: time.Now()}
+	for _, value := range []*string{&login.State, &login.Nonce, &login.CodeVerifier} {
+		raw := make([]byte, tokenBytes)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		*value = base64.RawURLEncoding.EncodeToString(raw)
+	}
+	return login, nil
+}
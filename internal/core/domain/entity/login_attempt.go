@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LoginFailureReason explains why a login attempt failed
+type LoginFailureReason string
+
+const (
+	LoginFailureUnknownUser     LoginFailureReason = "unknown_user"
+	LoginFailureInvalidPassword LoginFailureReason = "invalid_password"
+	LoginFailureInvalidCode     LoginFailureReason = "invalid_2fa_code"
+	LoginFailureDisabledUser    LoginFailureReason = "disabled_user"
+	LoginFailureUnverifiedEmail LoginFailureReason = "unverified_email"
+	LoginFailureLocked          LoginFailureReason = "locked"
+)
+
+// LoginAttempt is the audit record of a single login attempt
+type LoginAttempt struct {
+	ID        uint
+	UserID    *uint
+	Login     string
+	IP        string
+	UserAgent string
+	Success   bool
+	Reason    LoginFailureReason
+	CreatedAt time.Time
+}
+
+// NewLoginAttempt creates a new LoginAttempt entity. An empty reason records a successful attempt.
+func NewLoginAttempt(userID *uint, login, ip, userAgent string, reason LoginFailureReason) *LoginAttempt {
+	return &LoginAttempt{
+		UserID:    userID,
+		Login:     login,
+		IP:        ip,
+		UserAgent: userAgent,
+		Success:   reason == "",
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+}
+
+// LoginFailures counts the consecutive failed logins of a username or an IP
+type LoginFailures struct {
+	Count       int
+	LastFailure time.Time
+}
+
+// LockoutPolicy defines how failed logins are throttled. Every failure doubles the
+// time to wait before the next attempt, starting at Backoff, and MaxFailures
+// consecutive failures lock further attempts out for Lockout.
+type LockoutPolicy struct {
+	MaxFailures int
+	Backoff     time.Duration
+	Lockout     time.Duration
+}
+
+// BlockedUntil returns when new attempts are allowed again under the policy
+func (f *LoginFailures) BlockedUntil(policy LockoutPolicy) time.Time {
+	if f == nil || f.Count == 0 {
+		return time.Time{}
+	}
+
+	if policy.MaxFailures > 0 && f.Count >= policy.MaxFailures {
+		return f.LastFailure.Add(policy.Lockout)
+	}
+
+	wait := policy.Lockout
+	if shift := f.Count - 1; shift < 32 && policy.Backoff<<shift < policy.Lockout {
+		wait = policy.Backoff << shift
+	}
+	return f.LastFailure.Add(wait)
+}
+
+// AccountThrottleKey returns the key counting the failed logins of a user
+func AccountThrottleKey(userID uint) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// LoginThrottleKey returns the key counting the failed logins of a login name matching no user
+func LoginThrottleKey(login string) string {
+	return "login:" + strings.ToLower(strings.TrimSpace(login))
+}
+
+// IPThrottleKey returns the key counting the failed logins coming from an IP
+func IPThrottleKey(ip string) string {
+	return "ip:" + ip
+}
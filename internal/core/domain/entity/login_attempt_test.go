@@ -0,0 +1,43 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+func TestLoginFailures_BlockedUntil(t *testing.T) {
+	policy := entity.LockoutPolicy{MaxFailures: 5, Backoff: time.Second, Lockout: 15 * time.Minute}
+	last := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		count int
+		wait  time.Duration
+	}{
+		{0, 0},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{5, 15 * time.Minute},
+		{9, 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		failures := &entity.LoginFailures{Count: tt.count, LastFailure: last}
+		if tt.count == 0 {
+			assert.True(t, failures.BlockedUntil(policy).IsZero())
+			continue
+		}
+		assert.Equal(t, last.Add(tt.wait), failures.BlockedUntil(policy), "count %d", tt.count)
+	}
+
+	var none *entity.LoginFailures
+	assert.True(t, none.BlockedUntil(policy).IsZero())
+
+	// Without a lockout threshold the backoff is capped at the lockout duration
+	unlimited := entity.LockoutPolicy{Backoff: time.Second, Lockout: time.Minute}
+	failures := &entity.LoginFailures{Count: 40, LastFailure: last}
+	assert.Equal(t, last.Add(time.Minute), failures.BlockedUntil(unlimited))
+}
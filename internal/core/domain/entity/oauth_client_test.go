@@ -0,0 +1,100 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+func TestNewOAuthClient(t *testing.T) {
+	client, secret, err := entity.NewOAuthClient(" Dashboard ", []string{"https://dashboard.example.com/callback"},
+		[]string{"openid", "users:read"}, []string{entity.GrantAuthorizationCode}, true)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(secret, entity.OAuthClientSecretPrefix))
+	assert.Equal(t, "Dashboard", client.Name)
+	assert.False(t, client.IsPublic())
+	assert.True(t, client.Authenticate(secret))
+	assert.False(t, client.Authenticate(""))
+
+	public, secret, err := entity.NewOAuthClient("Mobile", []string{"com.example.app:/callback"},
+		[]string{"openid"}, []string{entity.GrantAuthorizationCode, entity.GrantRefreshToken}, false)
+	require.NoError(t, err)
+	assert.Empty(t, secret)
+	assert.True(t, public.IsPublic())
+	assert.True(t, public.Authenticate(""))
+	assert.False(t, public.Authenticate("gcs_anything"))
+}
+
+func TestNewOAuthClient_Invalid(t *testing.T) {
+	uris := []string{"https://dashboard.example.com/callback"}
+	scopes := []string{"openid"}
+
+	for name, call := range map[string]func() error{
+		"no name": func() error {
+			_, _, err := entity.NewOAuthClient(" ", uris, scopes, []string{"authorization_code"}, true)
+			return err
+		},
+		"no scopes": func() error {
+			_, _, err := entity.NewOAuthClient("app", uris, nil, []string{"authorization_code"}, true)
+			return err
+		},
+		"password grant": func() error {
+			_, _, err := entity.NewOAuthClient("app", uris, scopes, []string{"password"}, true)
+			return err
+		},
+		"refresh without code": func() error {
+			_, _, err := entity.NewOAuthClient("app", uris, scopes, []string{"client_credentials", "refresh_token"}, true)
+			return err
+		},
+		"public client credentials": func() error {
+			_, _, err := entity.NewOAuthClient("app", nil, scopes, []string{"client_credentials"}, false)
+			return err
+		},
+		"code without redirect": func() error {
+			_, _, err := entity.NewOAuthClient("app", nil, scopes, []string{"authorization_code"}, true)
+			return err
+		},
+		"relative redirect": func() error {
+			_, _, err := entity.NewOAuthClient("app", []string{"/callback"}, scopes, []string{"authorization_code"}, true)
+			return err
+		},
+		"redirect with fragment": func() error {
+			_, _, err := entity.NewOAuthClient("app", []string{"https://app.example.com/#cb"}, scopes, []string{"authorization_code"}, true)
+			return err
+		},
+	} {
+		assert.Error(t, call(), name)
+	}
+}
+
+func TestOAuthClient_GrantScopes(t *testing.T) {
+	client := &entity.OAuthClient{Scopes: []string{"openid", "email", "users:read"}}
+
+	granted, denied := client.GrantScopes(nil)
+	assert.Equal(t, []string{"openid", "email", "users:read"}, granted)
+	assert.Empty(t, denied)
+
+	granted, denied = client.GrantScopes([]string{"openid", "openid", "users:read"})
+	assert.Equal(t, []string{"openid", "users:read"}, granted)
+	assert.Empty(t, denied)
+
+	_, denied = client.GrantScopes([]string{"openid", "users:write"})
+	assert.Equal(t, "users:write", denied)
+}
+
+func TestAuthorizationCode_VerifyCodeVerifier(t *testing.T) {
+	// Example of RFC 7636 appendix B
+	code := &entity.AuthorizationCode{CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"}
+	assert.True(t, code.VerifyCodeVerifier("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+	assert.False(t, code.VerifyCodeVerifier("other"))
+	assert.False(t, code.VerifyCodeVerifier(""))
+
+	plain := &entity.AuthorizationCode{}
+	assert.True(t, plain.VerifyCodeVerifier(""))
+	assert.False(t, plain.VerifyCodeVerifier("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
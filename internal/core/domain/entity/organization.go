@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// Organization is a customer hosted by the deployment, a tenant. Its users and
+// profiles, and so its permissions, are invisible to every other organization.
+// Users of no organization are super-admins, who manage the organizations.
+type Organization struct {
+	ID        uint
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewOrganization creates a new Organization entity
+func NewOrganization(name string) (*Organization, error) {
+	now := time.Now()
+	o := &Organization{
+		Name:      strings.TrimSpace(name),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Validate validates the organization entity
+func (o *Organization) Validate() error {
+	if o.Name == "" {
+		return ErrOrganizationNameRequired()
+	}
+	return nil
+}
+
+// UpdateName updates the organization name
+func (o *Organization) UpdateName(name string) {
+	o.Name = strings.TrimSpace(name)
+	o.UpdatedAt = time.Now()
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+)
+
+// Permission names checked by the REST layer. A permission follows the
+// "resource:action" format; see grantsPermission for how bare resource
+// names and wildcards are matched.
+const (
+	PermissionUsersRead     = "users:read"
+	PermissionUsersWrite    = "users:write"
+	PermissionProfilesRead  = "profiles:read"
+	PermissionProfilesWrite = "profiles:write"
+	PermissionClientsRead   = "clients:read"
+	PermissionClientsWrite  = "clients:write"
+
+	PermissionOrganizationsRead  = "organizations:read"
+	PermissionOrganizationsWrite = "organizations:write"
+
+	PermissionAuditRead = "audit:read"
+)
+
+// permissionWildcard grants every action of a resource (or every permission when used alone)
+const permissionWildcard = "*"
+
+// grantsPermission checks if a list of granted permissions includes permission.
+// A "resource:action" permission is also granted by the bare resource name
+// (e.g. "users" grants "users:write"), by "resource:*" or by "*".
+func grantsPermission(granted []string, permission string) bool {
+	if slices.Contains(granted, permission) || slices.Contains(granted, permissionWildcard) {
+		return true
+	}
+
+	resource, _, found := strings.Cut(permission, ":")
+	if !found {
+		return false
+	}
+	return slices.Contains(granted, resource) || slices.Contains(granted, resource+":"+permissionWildcard)
+}
@@ -1,19 +1,26 @@
 package entity
 
 import (
-	"slices"
 	"time"
 
+	"github.com/raulaguila/go-api/pkg/passwd"
 	"github.com/raulaguila/go-api/pkg/validator"
 )
 
-// Profile represents a user profile with permissions in the domain
+// Profile represents a user profile with permissions in the domain.
+// Profile names are unique within an organization.
 type Profile struct {
-	ID          uint
-	Name        string
-	Permissions []string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID                   uint
+	OrganizationID       *uint // Nil for the profiles of super-admins
+	Name                 string
+	Permissions          []string
+	RequireTwoFactor     bool
+	RequireVerifiedEmail bool
+	PasswordPolicy       *passwd.Policy // Replaces the environment's policy for the profile's users
+	Version              uint           // Incremented by every update, so that concurrent updates do not overwrite each other
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	DeletedAt            *time.Time // Set while the profile is in the trash, until restored or purged
 }
 
 // NewProfile creates a new Profile entity
@@ -22,6 +29,7 @@ func NewProfile(name string, permissions []string) *Profile {
 	return &Profile{
 		Name:        name,
 		Permissions: permissions,
+		Version:     1,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
@@ -47,9 +55,33 @@ func (p *Profile) UpdatePermissions(permissions []string) {
 	p.UpdatedAt = time.Now()
 }
 
-// HasPermission checks if profile has a specific permission
+// SetRequireTwoFactor sets whether users of the profile must use 2FA
+func (p *Profile) SetRequireTwoFactor(required bool) {
+	p.RequireTwoFactor = required
+	p.UpdatedAt = time.Now()
+}
+
+// SetRequireVerifiedEmail sets whether users of the profile must verify their email before logging in
+func (p *Profile) SetRequireVerifiedEmail(required bool) {
+	p.RequireVerifiedEmail = required
+	p.UpdatedAt = time.Now()
+}
+
+// SetPasswordPolicy sets the password policy of the profile's users; nil or an
+// empty policy restores the environment's policy
+func (p *Profile) SetPasswordPolicy(policy *passwd.Policy) {
+	if policy != nil && policy.IsZero() {
+		policy = nil
+	}
+	p.PasswordPolicy = policy
+	p.UpdatedAt = time.Now()
+}
+
+// HasPermission checks if profile has a specific permission.
+// A "resource:action" permission is also granted by the bare resource name
+// (e.g. "users" grants "users:write"), by "resource:*" or by "*".
 func (p *Profile) HasPermission(permission string) bool {
-	return slices.Contains(p.Permissions, permission)
+	return grantsPermission(p.Permissions, permission)
 }
 
 // IsRoot checks if this is the root profile (ID = 1)
@@ -0,0 +1,32 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+func TestProfile_HasPermission(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		permission  string
+		want        bool
+	}{
+		{name: "Exact match", permissions: []string{"users:read"}, permission: "users:read", want: true},
+		{name: "Other action", permissions: []string{"users:read"}, permission: "users:write", want: false},
+		{name: "Bare resource", permissions: []string{"users"}, permission: "users:write", want: true},
+		{name: "Resource wildcard", permissions: []string{"users:*"}, permission: "users:write", want: true},
+		{name: "Global wildcard", permissions: []string{"*"}, permission: "profiles:write", want: true},
+		{name: "Other resource", permissions: []string{"profiles"}, permission: "users:read", want: false},
+		{name: "No permissions", permissions: nil, permission: "users:read", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := entity.NewProfile("Tester", tt.permissions)
+			assert.Equal(t, tt.want, profile.HasPermission(tt.permission))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+func TestSessionTouch(t *testing.T) {
+	session := entity.NewSession(7, "curl/8.4.0", "10.0.0.1", nil)
+	assert.False(t, session.Touch(), "a new session was just active")
+
+	stale := time.Now().Add(-2 * time.Minute)
+	session.LastActiveAt = &stale
+	assert.True(t, session.Touch())
+	assert.WithinDuration(t, time.Now(), *session.LastActiveAt, time.Second)
+	assert.False(t, session.Touch(), "activity is recorded at most once a minute")
+}
@@ -3,19 +3,27 @@ package entity
 import (
 	"time"
 
+	"github.com/raulaguila/go-api/pkg/passwd"
 	"github.com/raulaguila/go-api/pkg/validator"
 )
 
 // User represents a user in the domain
 type User struct {
-	ID        uint
-	Name      string
-	Username  string
-	Email     string
-	AuthID    uint
+	ID             uint
+	OrganizationID *uint // Nil for super-admins
+	Name           string
+	Username       string
+	Email          string
+	AuthID         uint
+
+	EmailVerifiedAt *time.Time // When the current email was last proven to belong to the user
+	PendingEmail    *string    // New email, applied once confirmed
+
 	Auth      *Auth
+	Version   uint // Incremented by every update, so that concurrent updates do not overwrite each other
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt *time.Time // Set while the user is in the trash, until restored or purged
 }
 
 // NewUser creates a new User entity
@@ -26,6 +34,7 @@ func NewUser(name, username, email string, auth *Auth) (*User, error) {
 		Username:  username,
 		Email:     email,
 		Auth:      auth,
+		Version:   1,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -51,12 +60,31 @@ func (u *User) Validate() error {
 	if !validator.IsValidEmail(u.Email) {
 		return ErrInvalidEmailFormat()
 	}
+	if u.PendingEmail != nil && !validator.IsValidEmail(*u.PendingEmail) {
+		return ErrInvalidEmailFormat()
+	}
 	if u.Auth == nil || u.Auth.ProfileID == 0 {
 		return ErrProfileRequired()
 	}
 	return nil
 }
 
+// IsSuperAdmin checks if the user belongs to no organization, managing every one
+func (u *User) IsSuperAdmin() bool {
+	return u.OrganizationID == nil
+}
+
+// SetProfile assigns a profile to the user, who joins the profile's organization,
+// so that permissions never cross organizations
+func (u *User) SetProfile(profile *Profile) {
+	if u.Auth != nil {
+		u.Auth.ProfileID = profile.ID
+		u.Auth.Profile = profile
+	}
+	u.OrganizationID = profile.OrganizationID
+	u.UpdatedAt = time.Now()
+}
+
 // UpdateName updates the user's name
 func (u *User) UpdateName(name string) {
 	u.Name = name
@@ -69,29 +97,95 @@ func (u *User) UpdateUsername(username string) {
 	u.UpdatedAt = time.Now()
 }
 
-// UpdateEmail updates the user's email
-func (u *User) UpdateEmail(email string) {
-	u.Email = email
+// ChangeEmail stores email as pending until it is confirmed with ConfirmEmail, and reports
+// whether it needs a confirmation. Changing back to the current email cancels a pending change.
+func (u *User) ChangeEmail(email string) bool {
 	u.UpdatedAt = time.Now()
+	if email == u.Email {
+		u.PendingEmail = nil
+		return false
+	}
+	u.PendingEmail = &email
+	return true
+}
+
+// VerificationEmail returns the email awaiting verification: the pending one, or the current
+// one if it was never verified. It is empty when there is nothing to verify.
+func (u *User) VerificationEmail() string {
+	switch {
+	case u.PendingEmail != nil:
+		return *u.PendingEmail
+	case u.EmailVerifiedAt == nil:
+		return u.Email
+	default:
+		return ""
+	}
+}
+
+// ConfirmEmail applies the pending email, if any, and marks the email as verified
+func (u *User) ConfirmEmail() {
+	if u.PendingEmail != nil {
+		u.Email = *u.PendingEmail
+		u.PendingEmail = nil
+	}
+	u.MarkEmailVerified()
+}
+
+// MarkEmailVerified marks the current email as verified, e.g. after a token sent to it was used
+func (u *User) MarkEmailVerified() {
+	now := time.Now()
+	u.EmailVerifiedAt = &now
+	u.UpdatedAt = now
+}
+
+// IsEmailVerified checks if the current email was verified
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// EmailVerificationRequired checks if the user's profile requires a verified email the user lacks
+func (u *User) EmailVerificationRequired() bool {
+	return !u.IsEmailVerified() && u.Auth != nil && u.Auth.Profile != nil && u.Auth.Profile.RequireVerifiedEmail
 }
 
 // SetPassword sets the user's password through Auth
-func (u *User) SetPassword(password string) error {
+func (u *User) SetPassword(password string, hasher passwd.Hasher) error {
 	if u.Auth == nil {
 		var err error
 		if u.Auth, err = NewAuth(0, true); err != nil {
 			return err
 		}
 	}
-	return u.Auth.SetPassword(password)
+	return u.Auth.SetPassword(password, hasher)
+}
+
+// ChangePassword sets a password following policy, which must not resemble the username or email
+func (u *User) ChangePassword(password string, policy passwd.Policy, hasher passwd.Hasher) error {
+	if u.Auth == nil {
+		return ErrProfileRequired()
+	}
+	if err := u.Auth.ChangePassword(password, policy, hasher, u.Username, u.Email); err != nil {
+		return err
+	}
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
+// PasswordPolicy returns the password policy of the user's profile, or fallback when it has none
+func (u *User) PasswordPolicy(fallback passwd.Policy) passwd.Policy {
+	if u.Auth != nil && u.Auth.Profile != nil && u.Auth.Profile.PasswordPolicy != nil {
+		return *u.Auth.Profile.PasswordPolicy
+	}
+	return fallback
 }
 
 // ValidatePassword validates the password through Auth
-func (u *User) ValidatePassword(password string) bool {
+func (u *User) ValidatePassword(password string, hasher passwd.Hasher) bool {
 	if u.Auth == nil {
+		CompareDummyPassword(password, hasher)
 		return false
 	}
-	return u.Auth.ValidatePassword(password)
+	return u.Auth.ValidatePassword(password, hasher)
 }
 
 // ResetPassword resets the user's password through Auth
@@ -102,6 +196,11 @@ func (u *User) ResetPassword() {
 	u.UpdatedAt = time.Now()
 }
 
+// IsPending checks if the user was invited and has not accepted the invitation yet
+func (u *User) IsPending() bool {
+	return u.Auth != nil && u.Auth.Pending
+}
+
 // IsActive checks if the user is active
 func (u *User) IsActive() bool {
 	return u.Auth != nil && u.Auth.IsActive()
@@ -77,3 +77,44 @@ func TestNewUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUserSetProfile(t *testing.T) {
+	auth, err := entity.NewAuth(1, true)
+	assert.NoError(t, err)
+	user, err := entity.NewUser("John Doe", "johndoe", "john@example.com", auth)
+	assert.NoError(t, err)
+	assert.True(t, user.IsSuperAdmin())
+
+	acme := uint(3)
+	user.SetProfile(&entity.Profile{ID: 2, Name: "ADMIN", OrganizationID: &acme})
+	assert.Equal(t, uint(2), user.GetProfileID())
+	assert.Equal(t, &acme, user.OrganizationID)
+	assert.False(t, user.IsSuperAdmin())
+}
+
+func TestUserChangeEmail(t *testing.T) {
+	auth, err := entity.NewAuth(1, true)
+	assert.NoError(t, err)
+	user, err := entity.NewUser("John Doe", "johndoe", "john@example.com", auth)
+	assert.NoError(t, err)
+	assert.Equal(t, "john@example.com", user.VerificationEmail(), "new emails await verification")
+
+	user.MarkEmailVerified()
+	assert.Empty(t, user.VerificationEmail())
+
+	assert.True(t, user.ChangeEmail("john.doe@example.com"))
+	assert.Equal(t, "john@example.com", user.Email)
+	assert.Equal(t, "john.doe@example.com", user.VerificationEmail())
+
+	assert.False(t, user.ChangeEmail("john@example.com"), "changing back cancels the pending change")
+	assert.Nil(t, user.PendingEmail)
+
+	user.ChangeEmail("invalid-email")
+	assert.Error(t, user.Validate())
+
+	user.ChangeEmail("john.doe@example.com")
+	user.ConfirmEmail()
+	assert.Equal(t, "john.doe@example.com", user.Email)
+	assert.Nil(t, user.PendingEmail)
+	assert.True(t, user.IsEmailVerified())
+}
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+// TokenPurpose identifies what a UserToken can be used for
+type TokenPurpose string
+
+const (
+	// TokenPurposePasswordReset allows setting a new password
+	TokenPurposePasswordReset TokenPurpose = "password_reset"
+
+	// TokenPurposeInvitation allows an invited user to accept the invitation, setting the first password
+	TokenPurposeInvitation TokenPurpose = "invitation"
+
+	// TokenPurposeEmailVerification proves that an email, current or pending, belongs to the user
+	TokenPurposeEmailVerification TokenPurpose = "email_verification"
+)
+
+// tokenBytes is the amount of random bytes in a token secret
+const tokenBytes = 32
+
+// UserToken is a single-use, time-limited secret delivered to a user out of band.
+// Only the hash of the secret is stored.
+type UserToken struct {
+	ID        uint
+	UserID    uint
+	User      *User // Owner of the token, when loaded
+	Purpose   TokenPurpose
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// NewUserToken creates a new UserToken entity and returns it with its plain secret
+func NewUserToken(userID uint, purpose TokenPurpose, ttl time.Duration) (*UserToken, string, error) {
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := time.Now()
+	return &UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: HashToken(secret),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, secret, nil
+}
+
+// HashToken returns the stored representation of a token secret
+func HashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsExpired checks if the token has expired
+func (t *UserToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// IsUsed checks if the token was already used
+func (t *UserToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+// IsValid checks if the token can still be used
+func (t *UserToken) IsValid() bool {
+	return !t.IsUsed() && !t.IsExpired()
+}
+
+// Use marks the token as used
+func (t *UserToken) Use() {
+	now := time.Now()
+	t.UsedAt = &now
+}
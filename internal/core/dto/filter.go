@@ -10,18 +10,38 @@ type Filter struct {
 	Order  string `query:"order" form:"order"`
 }
 
+// Values of the deleted filter; deleted records are left out by default
+const (
+	DeletedOnly    = "only"    // Lists only the records in the trash
+	DeletedInclude = "include" // Lists the records in the trash along with the others
+)
+
 // ProfileFilter represents filtering options for profiles
 type ProfileFilter struct {
 	Filter
-	WithPermissions *bool `query:"with_permissions" form:"with_permissions"`
-	ListRoot        bool  `query:"list_root" form:"list_root"`
+	WithPermissions *bool  `query:"with_permissions" form:"with_permissions"`
+	ListRoot        bool   `query:"list_root" form:"list_root"`
+	OrganizationID  *uint  `query:"organization_id" form:"organization_id"` // Only narrows the profiles seen by super-admins
+	Deleted         string `query:"deleted" form:"deleted" enums:"only,include"`
 }
 
 // UserFilter represents filtering options for users
 type UserFilter struct {
 	Filter
-	ProfileID uint  `query:"profile_id" form:"profile_id"`
-	Status    *bool `query:"status" form:"status"`
+	ProfileID      uint   `query:"profile_id" form:"profile_id"`
+	Status         *bool  `query:"status" form:"status"`
+	OrganizationID *uint  `query:"organization_id" form:"organization_id"` // Only narrows the users seen by super-admins
+	Deleted        string `query:"deleted" form:"deleted" enums:"only,include"`
+}
+
+// AuditFilter represents filtering options for audit records
+type AuditFilter struct {
+	Filter
+	ActorID    *uint  `query:"actor_id" form:"actor_id"`
+	Action     string `query:"action" form:"action"`
+	EntityType string `query:"entity_type" form:"entity_type" enums:"user,profile,session"`
+	EntityID   string `query:"entity_id" form:"entity_id"`
+	RequestID  string `query:"request_id" form:"request_id"`
 }
 
 // ApplyPagination returns pagination values
@@ -1,14 +1,25 @@
 package dto
 
 import (
+	"strings"
+	"time"
+
 	"github.com/raulaguila/go-api/pkg/apperror"
+	"github.com/raulaguila/go-api/pkg/passwd"
 	"github.com/raulaguila/go-api/pkg/validator"
 )
 
 // ProfileInput represents input data for creating/updating a profile
 type ProfileInput struct {
-	Name        *string   `json:"name" validate:"omitempty,min=4,max=100"`
-	Permissions *[]string `json:"permissions"`
+	Name                 *string        `json:"name" validate:"omitempty,min=4,max=100"`
+	Permissions          *[]string      `json:"permissions"`
+	RequireTwoFactor     *bool          `json:"require_2fa"`
+	RequireVerifiedEmail *bool          `json:"require_verified_email"` // Users must verify their email before logging in
+	PasswordPolicy       *passwd.Policy `json:"password_policy"`        // Overrides the environment's policy for the profile's users
+
+	OrganizationID *uint `json:"organization_id"` // Organization of a new profile, chosen by super-admins only
+
+	Versions []uint `json:"-"` // Versions the update is conditioned on, from the If-Match header; nil when unconditioned
 }
 
 // Validate validates the ProfileInput
@@ -19,13 +30,20 @@ func (p *ProfileInput) Validate() error {
 	return nil
 }
 
+// OrganizationInput represents input data for an organization
+type OrganizationInput struct {
+	Name *string `json:"name" validate:"omitempty,max=100" example:"ACME"`
+}
+
 // UserInput represents input data for creating/updating a user
 type UserInput struct {
 	Name      *string `json:"name" validate:"omitempty,min=5,max=100"`
 	Username  *string `json:"username" validate:"omitempty,min=5,max=50"`
 	Email     *string `json:"email" validate:"omitempty,email"`
 	Status    *bool   `json:"status"`
-	ProfileID *uint   `json:"profile_id" validate:"omitempty,min=1"`
+	ProfileID *uint   `json:"profile_id" validate:"omitempty,min=1"` // Users join the organization of their profile
+
+	Versions []uint `json:"-"` // Versions the update is conditioned on, from the If-Match header; nil when unconditioned
 }
 
 // Validate validates the UserInput
@@ -42,14 +60,63 @@ func (u *UserInput) Validate() error {
 	return nil
 }
 
+// UserImportInput represents a bulk import of users from a spreadsheet, whose columns are
+// found by their header. The options are sent as form fields along with the file.
+type UserImportInput struct {
+	NameColumn     string `form:"name_column"`     // Header of the names, "name" by default
+	UsernameColumn string `form:"username_column"` // Header of the usernames, "username" by default
+	EmailColumn    string `form:"email_column"`    // Header of the emails, "email" by default
+	ProfileColumn  string `form:"profile_column"`  // Header of the profile IDs, "profile_id" by default
+	ProfileID      *uint  `form:"profile_id"`      // Profile of the users whose row names none
+	DryRun         bool   `form:"dry_run"`         // Validates every row without creating any user
+	Invite         bool   `form:"invite"`          // Creates pending users and sends them invitations
+
+	Rows [][]string `form:"-"` // Rows of the spreadsheet, the first one holding the headers
+}
+
+// PasswordResetInput represents input data for requesting a password reset
+type PasswordResetInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// Validate validates the PasswordResetInput
+func (p *PasswordResetInput) Validate() error {
+	if !validator.IsValidEmail(p.Email) {
+		return apperror.InvalidInput("email", "invalid email format")
+	}
+	return nil
+}
+
+// EmailInput represents input data for requesting an email verification
+type EmailInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// Validate validates the EmailInput
+func (e *EmailInput) Validate() error {
+	if !validator.IsValidEmail(e.Email) {
+		return apperror.InvalidInput("email", "invalid email format")
+	}
+	return nil
+}
+
+// EmailVerificationInput represents input data for confirming an email with a verification token
+type EmailVerificationInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
 // PasswordInput represents input data for setting a password
 type PasswordInput struct {
+	Token           string `json:"token" validate:"required"`
 	Password        string `json:"password" validate:"required,min=6,max=128"`
 	PasswordConfirm string `json:"password_confirm" validate:"required,eqfield=Password"`
 }
 
 // Validate validates password input
 func (p *PasswordInput) Validate() error {
+	if p.Token == "" {
+		return apperror.InvalidInput("token", "token is required")
+	}
 	if p.Password == "" {
 		return apperror.InvalidInput("password", "password is required")
 	}
@@ -64,9 +131,11 @@ func (p *PasswordInput) Validate() error {
 
 // LoginInput represents input data for login
 type LoginInput struct {
-	Login      string `json:"login" validate:"required"`
+	Login      string `json:"login" validate:"required" example:"admin"` // Username or email
 	Password   string `json:"password" validate:"required"`
 	Expiration bool   `json:"expiration"`
+	UserAgent  string `json:"-"`
+	IP         string `json:"-"`
 }
 
 // Validate validates the LoginInput
@@ -80,6 +149,177 @@ func (l *LoginInput) Validate() error {
 	return nil
 }
 
+// TwoFactorInput represents input data for the second step of a login
+type TwoFactorInput struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"` // TOTP or recovery code
+	UserAgent      string `json:"-"`
+	IP             string `json:"-"`
+}
+
+// Validate validates the TwoFactorInput
+func (t *TwoFactorInput) Validate() error {
+	if t.ChallengeToken == "" {
+		return apperror.InvalidInput("challenge_token", "challenge token is required")
+	}
+	if t.Code == "" {
+		return apperror.InvalidInput("code", "code is required")
+	}
+	return nil
+}
+
+// ExpiredPasswordInput represents input data for replacing an expired password during a login
+type ExpiredPasswordInput struct {
+	ChallengeToken  string `json:"challenge_token" validate:"required"`
+	Password        string `json:"password" validate:"required,max=128"`
+	PasswordConfirm string `json:"password_confirm" validate:"required,eqfield=Password"`
+}
+
+// Validate validates the ExpiredPasswordInput
+func (p *ExpiredPasswordInput) Validate() error {
+	if p.ChallengeToken == "" {
+		return apperror.InvalidInput("challenge_token", "challenge token is required")
+	}
+	if p.Password == "" {
+		return apperror.InvalidInput("password", "password is required")
+	}
+	if p.Password != p.PasswordConfirm {
+		return apperror.InvalidInput("password_confirm", "passwords do not match")
+	}
+	return nil
+}
+
+// TwoFactorCodeInput represents a TOTP or recovery code
+type TwoFactorCodeInput struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// Validate validates the TwoFactorCodeInput
+func (t *TwoFactorCodeInput) Validate() error {
+	if t.Code == "" {
+		return apperror.InvalidInput("code", "code is required")
+	}
+	return nil
+}
+
+// ExternalLoginInput represents the redirect of an external identity provider back to the API
+type ExternalLoginInput struct {
+	Provider  string `json:"-"`
+	Code      string `json:"code" query:"code"`
+	State     string `json:"state" query:"state"`
+	UserAgent string `json:"-"`
+	IP        string `json:"-"`
+}
+
+// Validate validates the ExternalLoginInput
+func (e *ExternalLoginInput) Validate() error {
+	if e.Code == "" {
+		return apperror.InvalidInput("code", "code is required")
+	}
+	if e.State == "" {
+		return apperror.InvalidInput("state", "state is required")
+	}
+	return nil
+}
+
+// APIKeyInput represents input data for creating an API key
+type APIKeyInput struct {
+	Name      string     `json:"name" validate:"required" example:"deploy script"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1" example:"users:view"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Validate validates the APIKeyInput
+func (a *APIKeyInput) Validate() error {
+	if strings.TrimSpace(a.Name) == "" {
+		return apperror.InvalidInput("name", "name is required")
+	}
+	if len(a.Scopes) == 0 {
+		return apperror.InvalidInput("scopes", "at least one scope is required")
+	}
+	return nil
+}
+
+// OAuthClientInput represents input data for registering an OAuth client
+type OAuthClientInput struct {
+	Name         string   `json:"name" validate:"required" example:"reports"`
+	RedirectURIs []string `json:"redirect_uris" example:"https://reports.example.com/callback"`
+	Scopes       []string `json:"scopes" validate:"required,min=1" example:"openid,profile,email,users:read"`
+	GrantTypes   []string `json:"grant_types" validate:"required,min=1" example:"authorization_code,refresh_token"`
+	Confidential bool     `json:"confidential"` // Whether the client can keep a secret, e.g. a server-side app
+}
+
+// Validate validates the OAuthClientInput
+func (o *OAuthClientInput) Validate() error {
+	if strings.TrimSpace(o.Name) == "" {
+		return apperror.InvalidInput("name", "name is required")
+	}
+	if len(o.Scopes) == 0 {
+		return apperror.InvalidInput("scopes", "at least one scope is required")
+	}
+	if len(o.GrantTypes) == 0 {
+		return apperror.InvalidInput("grant_types", "at least one grant type is required")
+	}
+	return nil
+}
+
+// AuthorizeInput represents an OAuth 2.0 authorization request (RFC 6749 section 4.1.1)
+type AuthorizeInput struct {
+	ResponseType        string `json:"response_type" query:"response_type" example:"code"`
+	ClientID            string `json:"client_id" query:"client_id"`
+	RedirectURI         string `json:"redirect_uri" query:"redirect_uri"`
+	Scope               string `json:"scope" query:"scope" example:"openid profile"` // Space separated
+	State               string `json:"state" query:"state"`
+	Nonce               string `json:"nonce" query:"nonce"`
+	CodeChallenge       string `json:"code_challenge" query:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method" query:"code_challenge_method" example:"S256"`
+}
+
+// TokenInput represents a request to the OAuth 2.0 token endpoint. The client
+// credentials come from the Basic authorization header or from the form.
+type TokenInput struct {
+	GrantType    string `form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	RefreshToken string `form:"refresh_token"`
+	Scope        string `form:"scope"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	UserAgent    string `form:"-"`
+	IP           string `form:"-"`
+}
+
+// RevokeTokenInput represents a token revocation request (RFC 7009)
+type RevokeTokenInput struct {
+	Token         string `form:"token"`
+	TokenTypeHint string `form:"token_type_hint"`
+	ClientID      string `form:"client_id"`
+	ClientSecret  string `form:"client_secret"`
+}
+
+// ClientSessionInput represents a login of a user into an OAuth client
+type ClientSessionInput struct {
+	UserID    uint
+	ClientID  string
+	Scopes    []string
+	UserAgent string
+	IP        string
+}
+
+// ImpersonationInput represents a member of the support staff acting as a user
+type ImpersonationInput struct {
+	ActorID   uint
+	UserID    uint
+	UserAgent string
+	IP        string
+}
+
+// SessionIDInput represents the ID of a session in the path
+type SessionIDInput struct {
+	ID string `params:"id"`
+}
+
 // IDsInput represents multiple IDs input
 type IDsInput struct {
 	IDs []uint `json:"ids" validate:"required,min=1,dive,min=1"`
@@ -19,7 +19,10 @@
 //	outputs := dto.EntitiesToUserOutputs(users)
 package dto
 
-import "github.com/raulaguila/go-api/internal/core/domain/entity"
+import (
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/pkg/useragent"
+)
 
 // EntityToUserOutput converts a User entity to UserOutput DTO.
 // Returns nil if the input user is nil.
@@ -47,10 +50,18 @@ func EntityToUserOutput(user *entity.User) *UserOutput {
 		Username: &user.Username,
 		Email:    &user.Email,
 		New:      &isNew,
+
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		PendingEmail:    user.PendingEmail,
+
+		OrganizationID: user.OrganizationID,
+		DeletedAt:      user.DeletedAt,
+		Version:        &user.Version,
 	}
 
 	if user.Auth != nil {
 		output.Status = &user.Auth.Status
+		output.Pending = &user.Auth.Pending
 		if user.Auth.Profile != nil {
 			output.Profile = EntityToProfileOutput(user.Auth.Profile, true)
 		}
@@ -76,8 +87,14 @@ func EntityToProfileOutput(profile *entity.Profile, includePermissions bool) *Pr
 	}
 
 	output := &ProfileOutput{
-		ID:   &profile.ID,
-		Name: &profile.Name,
+		ID:                   &profile.ID,
+		Name:                 &profile.Name,
+		RequireTwoFactor:     &profile.RequireTwoFactor,
+		RequireVerifiedEmail: &profile.RequireVerifiedEmail,
+		PasswordPolicy:       profile.PasswordPolicy,
+		OrganizationID:       profile.OrganizationID,
+		DeletedAt:            profile.DeletedAt,
+		Version:              &profile.Version,
 	}
 
 	if includePermissions {
@@ -88,6 +105,34 @@ func EntityToProfileOutput(profile *entity.Profile, includePermissions bool) *Pr
 	return output
 }
 
+// EntityToUserDocument converts a User entity to the UserDocument PATCH requests modify
+func EntityToUserDocument(user *entity.User) UserDocument {
+	name, username, email := user.Name, user.Username, user.Email
+	status, profileID := false, uint(0)
+	if user.Auth != nil {
+		status, profileID = user.Auth.Status, user.Auth.ProfileID
+	}
+	return UserDocument{
+		Name:      &name,
+		Username:  &username,
+		Email:     &email,
+		Status:    &status,
+		ProfileID: &profileID,
+	}
+}
+
+// EntityToProfileDocument converts a Profile entity to the ProfileDocument PATCH requests modify
+func EntityToProfileDocument(profile *entity.Profile) ProfileDocument {
+	name, requireTwoFactor, requireVerifiedEmail := profile.Name, profile.RequireTwoFactor, profile.RequireVerifiedEmail
+	return ProfileDocument{
+		Name:                 &name,
+		Permissions:          profile.Permissions,
+		RequireTwoFactor:     &requireTwoFactor,
+		RequireVerifiedEmail: &requireVerifiedEmail,
+		PasswordPolicy:       profile.PasswordPolicy,
+	}
+}
+
 // EntitiesToUserOutputs converts a slice of User entities to UserOutput DTOs.
 // This function is optimized for use with PaginatedOutput which requires []UserOutput.
 //
@@ -130,3 +175,178 @@ func EntitiesToProfileOutputs(profiles []*entity.Profile, includePermissions boo
 	}
 	return outputs
 }
+
+// EntityToAPIKeyOutput converts an APIKey entity to APIKeyOutput DTO, without its secret.
+func EntityToAPIKeyOutput(key *entity.APIKey) *APIKeyOutput {
+	if key == nil {
+		return nil
+	}
+
+	return &APIKeyOutput{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+// EntitiesToAPIKeyOutputs converts a slice of APIKey entities to APIKeyOutput DTOs.
+func EntitiesToAPIKeyOutputs(keys []*entity.APIKey) []APIKeyOutput {
+	outputs := make([]APIKeyOutput, len(keys))
+	for i, key := range keys {
+		if out := EntityToAPIKeyOutput(key); out != nil {
+			outputs[i] = *out
+		}
+	}
+	return outputs
+}
+
+// EntityToSessionOutput converts a Session entity to SessionOutput DTO, flagging it as
+// current when it is the session of the request.
+func EntityToSessionOutput(session *entity.Session, currentSessionID string) *SessionOutput {
+	if session == nil {
+		return nil
+	}
+
+	return &SessionOutput{
+		ID:             session.ID,
+		Device:         useragent.Device(session.UserAgent),
+		UserAgent:      session.UserAgent,
+		IP:             session.IP,
+		ClientID:       session.ClientID,
+		ImpersonatedBy: session.ActorID,
+		Current:        currentSessionID != "" && session.ID == currentSessionID,
+		CreatedAt:      session.CreatedAt,
+		LastActiveAt:   session.LastActiveAt,
+		ExpiresAt:      session.ExpiresAt,
+	}
+}
+
+// EntitiesToSessionOutputs converts a slice of Session entities to SessionOutput DTOs.
+func EntitiesToSessionOutputs(sessions []*entity.Session, currentSessionID string) []SessionOutput {
+	outputs := make([]SessionOutput, len(sessions))
+	for i, session := range sessions {
+		if out := EntityToSessionOutput(session, currentSessionID); out != nil {
+			outputs[i] = *out
+		}
+	}
+	return outputs
+}
+
+// EntityToInvitationOutput converts an invitation token, with its user, to InvitationOutput DTO.
+func EntityToInvitationOutput(token *entity.UserToken) *InvitationOutput {
+	if token == nil {
+		return nil
+	}
+
+	return &InvitationOutput{
+		User:      EntityToUserOutput(token.User),
+		InvitedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+		Expired:   token.IsExpired(),
+	}
+}
+
+// EntitiesToInvitationOutputs converts invitation tokens to InvitationOutput DTOs
+func EntitiesToInvitationOutputs(tokens []*entity.UserToken) []InvitationOutput {
+	outputs := make([]InvitationOutput, len(tokens))
+	for i, token := range tokens {
+		if out := EntityToInvitationOutput(token); out != nil {
+			outputs[i] = *out
+		}
+	}
+	return outputs
+}
+
+// EntityToOrganizationOutput converts an Organization entity to OrganizationOutput DTO.
+func EntityToOrganizationOutput(organization *entity.Organization) *OrganizationOutput {
+	if organization == nil {
+		return nil
+	}
+
+	return &OrganizationOutput{
+		ID:        organization.ID,
+		Name:      organization.Name,
+		CreatedAt: organization.CreatedAt,
+	}
+}
+
+// EntitiesToOrganizationOutputs converts a slice of Organization entities to OrganizationOutput DTOs.
+func EntitiesToOrganizationOutputs(organizations []*entity.Organization) []OrganizationOutput {
+	outputs := make([]OrganizationOutput, len(organizations))
+	for i, organization := range organizations {
+		if out := EntityToOrganizationOutput(organization); out != nil {
+			outputs[i] = *out
+		}
+	}
+	return outputs
+}
+
+// EntityToOAuthClientOutput converts an OAuthClient entity to OAuthClientOutput DTO, without its secret.
+func EntityToOAuthClientOutput(client *entity.OAuthClient) *OAuthClientOutput {
+	if client == nil {
+		return nil
+	}
+
+	return &OAuthClientOutput{
+		ID:           client.ID,
+		ClientID:     client.ClientID,
+		Name:         client.Name,
+		Confidential: !client.IsPublic(),
+		RedirectURIs: client.RedirectURIs,
+		Scopes:       client.Scopes,
+		GrantTypes:   client.GrantTypes,
+		CreatedAt:    client.CreatedAt,
+	}
+}
+
+// EntitiesToOAuthClientOutputs converts a slice of OAuthClient entities to OAuthClientOutput DTOs.
+func EntitiesToOAuthClientOutputs(clients []*entity.OAuthClient) []OAuthClientOutput {
+	outputs := make([]OAuthClientOutput, len(clients))
+	for i, client := range clients {
+		if out := EntityToOAuthClientOutput(client); out != nil {
+			outputs[i] = *out
+		}
+	}
+	return outputs
+}
+
+// EntityToAuditOutput converts an AuditRecord entity to AuditOutput DTO.
+func EntityToAuditOutput(record *entity.AuditRecord) *AuditOutput {
+	if record == nil {
+		return nil
+	}
+
+	changes := make(map[string]AuditChangeOutput, len(record.Changes))
+	for field, change := range record.Changes {
+		changes[field] = AuditChangeOutput{Before: change.Before, After: change.After}
+	}
+
+	return &AuditOutput{
+		ID:             record.ID,
+		OrganizationID: record.OrganizationID,
+		ActorID:        record.ActorID,
+		ImpersonatorID: record.ImpersonatorID,
+		Action:         record.Action,
+		EntityType:     record.EntityType,
+		EntityID:       record.EntityID,
+		Changes:        changes,
+		RequestID:      record.RequestID,
+		IP:             record.IP,
+		CreatedAt:      record.CreatedAt,
+	}
+}
+
+// EntitiesToAuditOutputs converts a slice of AuditRecord entities to AuditOutput DTOs.
+func EntitiesToAuditOutputs(records []*entity.AuditRecord) []AuditOutput {
+	outputs := make([]AuditOutput, len(records))
+	for i, record := range records {
+		if out := EntityToAuditOutput(record); out != nil {
+			outputs[i] = *out
+		}
+	}
+	return outputs
+}
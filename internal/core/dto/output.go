@@ -1,28 +1,191 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/raulaguila/go-api/pkg/passwd"
+)
+
 // ProfileOutput represents output data for a profile
 type ProfileOutput struct {
-	ID          *uint     `json:"id,omitempty"`
-	Name        *string   `json:"name,omitempty"`
-	Permissions *[]string `json:"permissions,omitempty"`
+	ID                   *uint          `json:"id,omitempty"`
+	Name                 *string        `json:"name,omitempty"`
+	Permissions          *[]string      `json:"permissions,omitempty"`
+	RequireTwoFactor     *bool          `json:"require_2fa,omitempty"`
+	RequireVerifiedEmail *bool          `json:"require_verified_email,omitempty"`
+	PasswordPolicy       *passwd.Policy `json:"password_policy,omitempty"`
+	OrganizationID       *uint          `json:"organization_id,omitempty"`
+	DeletedAt            *time.Time     `json:"deleted_at,omitempty"` // Only set for profiles in the trash
+	Version              *uint          `json:"version,omitempty"`    // Also sent as the ETag header
+}
+
+// OrganizationOutput represents output data for an organization
+type OrganizationOutput struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // UserOutput represents output data for a user
 type UserOutput struct {
-	ID       *uint          `json:"id,omitempty"`
-	Name     *string        `json:"name,omitempty"`
-	Username *string        `json:"corp_id,omitempty"`
-	Email    *string        `json:"email,omitempty"`
-	Status   *bool          `json:"status,omitempty"`
-	New      *bool          `json:"new,omitempty"`
-	Profile  *ProfileOutput `json:"profile,omitempty"`
+	ID       *uint   `json:"id,omitempty"`
+	Name     *string `json:"name,omitempty"`
+	Username *string `json:"corp_id,omitempty"`
+	Email    *string `json:"email,omitempty"`
+	Status   *bool   `json:"status,omitempty"`
+	New      *bool   `json:"new,omitempty"`
+	Pending  *bool   `json:"pending,omitempty"` // Invited and not accepted yet
+
+	EmailVerifiedAt *time.Time     `json:"email_verified_at,omitempty"`
+	PendingEmail    *string        `json:"pending_email,omitempty"` // Applied once the user confirms it
+	Profile         *ProfileOutput `json:"profile,omitempty"`
+
+	OrganizationID *uint      `json:"organization_id,omitempty"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"` // Only set for users in the trash
+	Version        *uint      `json:"version,omitempty"`    // Also sent as the ETag header
+
+	ImpersonatedBy *UserOutput `json:"impersonated_by,omitempty"` // Actor of an impersonation, who sees the API as the user
 }
 
-// AuthOutput represents output data for authentication
+// AuthOutput represents output data for authentication.
+// When a second factor is required, only the challenge fields are set.
 type AuthOutput struct {
 	User         *UserOutput `json:"user,omitempty"`
 	AccessToken  string      `json:"accesstoken"`
 	RefreshToken string      `json:"refreshtoken"`
+
+	TwoFactorRequired      bool                  `json:"two_factor_required,omitempty"`
+	PasswordChangeRequired bool                  `json:"password_change_required,omitempty"`
+	ChallengeToken         string                `json:"challenge_token,omitempty"`
+	TwoFactorSetup         *TwoFactorSetupOutput `json:"two_factor_setup,omitempty"`
+	RecoveryCodes          []string              `json:"recovery_codes,omitempty"`
+}
+
+// TwoFactorSetupOutput represents the data to enroll an authenticator app
+type TwoFactorSetupOutput struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+	QRCode string `json:"qr_code"` // PNG image of the URI, as a data URI
+}
+
+// RecoveryCodesOutput represents 2FA recovery codes, shown only once
+type RecoveryCodesOutput struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// ExternalLoginOutput represents a login started at an external identity provider
+type ExternalLoginOutput struct {
+	URL   string `json:"url"`   // Login page of the provider
+	State string `json:"state"` // Returned by the provider, binds the redirect to the browser that started the login
+}
+
+// APIKeyOutput represents output data for an API key
+type APIKeyOutput struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// SessionOutput represents output data for a session, a device the user is signed in on
+type SessionOutput struct {
+	ID             string     `json:"id"`
+	Device         string     `json:"device"`
+	UserAgent      string     `json:"user_agent"`
+	IP             string     `json:"ip"`
+	ClientID       string     `json:"client_id,omitempty"`       // OAuth client the session was opened for
+	ImpersonatedBy *uint      `json:"impersonated_by,omitempty"` // Actor of an impersonation session
+	Current        bool       `json:"current"`                   // Session of the request
+	CreatedAt      time.Time  `json:"created_at"`
+	LastActiveAt   *time.Time `json:"last_active_at,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+}
+
+// InvitationOutput represents an invitation not accepted yet
+type InvitationOutput struct {
+	User      *UserOutput `json:"user"`
+	InvitedAt time.Time   `json:"invited_at"`
+	ExpiresAt time.Time   `json:"expires_at"`
+	Expired   bool        `json:"expired"` // Expired invitations must be resent before they can be accepted
+}
+
+// UserImportOutput reports the outcome of a bulk import of users
+type UserImportOutput struct {
+	DryRun  bool                  `json:"dry_run"`
+	Created int                   `json:"created"` // Users created, or that would be in a dry run
+	Skipped int                   `json:"skipped"` // Rows of users that already exist
+	Failed  int                   `json:"failed"`  // Rows that are invalid
+	Rows    []UserImportRowOutput `json:"rows"`    // Skipped and failed rows, with the reasons why
+}
+
+// UserImportRowOutput reports a row of a bulk import that created no user
+type UserImportRowOutput struct {
+	Row     int      `json:"row"` // Line of the row in the spreadsheet, the headers being line 1
+	Skipped bool     `json:"skipped"`
+	Errors  []string `json:"errors"`
+}
+
+// AuditOutput represents an audit record: who performed an action on an entity, from where, and what changed
+type AuditOutput struct {
+	ID             uint                         `json:"id"`
+	OrganizationID *uint                        `json:"organization_id,omitempty"`
+	ActorID        *uint                        `json:"actor_id,omitempty"`
+	ImpersonatorID *uint                        `json:"impersonator_id,omitempty"` // Set when the actor was impersonated
+	Action         string                       `json:"action"`
+	EntityType     string                       `json:"entity_type"`
+	EntityID       string                       `json:"entity_id"`
+	Changes        map[string]AuditChangeOutput `json:"changes"`
+	RequestID      string                       `json:"request_id,omitempty"`
+	IP             string                       `json:"ip,omitempty"`
+	CreatedAt      time.Time                    `json:"created_at"`
+}
+
+// AuditChangeOutput represents the value of a field before and after an audited action
+type AuditChangeOutput struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// APIKeyCreatedOutput represents a new API key with its secret, shown only once
+type APIKeyCreatedOutput struct {
+	APIKeyOutput
+	Key string `json:"key"`
+}
+
+// OAuthClientOutput represents output data for an OAuth client
+type OAuthClientOutput struct {
+	ID           uint      `json:"id"`
+	ClientID     string    `json:"client_id"`
+	Name         string    `json:"name"`
+	Confidential bool      `json:"confidential"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	Scopes       []string  `json:"scopes"`
+	GrantTypes   []string  `json:"grant_types"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// OAuthClientCreatedOutput represents a new OAuth client with its secret, shown only once
+type OAuthClientCreatedOutput struct {
+	OAuthClientOutput
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// AuthorizeOutput represents where to send the browser next during an authorization request
+type AuthorizeOutput struct {
+	RedirectURI string `json:"redirect_uri"`
+}
+
+// UserInfoOutput represents the claims about the user of an OpenID Connect token,
+// limited to the scopes the client was granted
+type UserInfoOutput struct {
+	Subject           string `json:"sub"`
+	Name              string `json:"name,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     *bool  `json:"email_verified,omitempty"`
 }
 
 // ItemOutput represents a simple item output (id + name)
@@ -42,11 +205,11 @@ type PaginationOutput struct {
 // paginableOutput defines which types can be used in PaginatedOutput
 // This provides type safety - only these types are allowed in paginated responses
 type paginableOutput interface {
-	ProfileOutput | UserOutput | ItemOutput
+	ProfileOutput | UserOutput | ItemOutput | AuditOutput
 }
 
 // PaginatedOutput represents a paginated list of items
-// T must be one of: ProfileOutput, UserOutput, ItemOutput
+// T must be one of: ProfileOutput, UserOutput, ItemOutput, AuditOutput
 type PaginatedOutput[T paginableOutput] struct {
 	Items      []T              `json:"items"`
 	Pagination PaginationOutput `json:"pagination"`
@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/raulaguila/go-api/pkg/apperror"
+	"github.com/raulaguila/go-api/pkg/jsonpatch"
+	"github.com/raulaguila/go-api/pkg/passwd"
+)
+
+// PatchFormat is the media type of a partial update
+type PatchFormat string
+
+const (
+	MergePatch PatchFormat = "application/merge-patch+json" // JSON Merge Patch, RFC 7396
+	JSONPatch  PatchFormat = "application/json-patch+json"  // JSON Patch, RFC 6902
+)
+
+// PatchInput represents a partial update of a resource, applied to its document
+type PatchInput struct {
+	Format   PatchFormat
+	Patch    []byte
+	Versions []uint // Versions the update is conditioned on, from the If-Match header; nil when unconditioned
+}
+
+// UserDocument is the representation of a user that PATCH requests modify.
+// Every member is required: a patch cannot remove one, nor set it to null.
+type UserDocument struct {
+	Name      *string `json:"name"`
+	Username  *string `json:"username"`
+	Email     *string `json:"email"` // Changes are pending until the user confirms the new address
+	Status    *bool   `json:"status"`
+	ProfileID *uint   `json:"profile_id"`
+}
+
+// Validate checks that a patched UserDocument still has every member
+func (d *UserDocument) Validate() error {
+	switch {
+	case d.Name == nil:
+		return apperror.InvalidInput("name", "name is required")
+	case d.Username == nil:
+		return apperror.InvalidInput("username", "username is required")
+	case d.Email == nil:
+		return apperror.InvalidInput("email", "email is required")
+	case d.Status == nil:
+		return apperror.InvalidInput("status", "status is required")
+	case d.ProfileID == nil:
+		return apperror.InvalidInput("profile_id", "profile_id is required")
+	}
+	return nil
+}
+
+// ProfileDocument is the representation of a profile that PATCH requests modify.
+// Removing permissions or password_policy clears them: a profile without password_policy follows
+// the environment's policy. The other members are required.
+type ProfileDocument struct {
+	Name                 *string        `json:"name"`
+	Permissions          []string       `json:"permissions"`
+	RequireTwoFactor     *bool          `json:"require_2fa"`
+	RequireVerifiedEmail *bool          `json:"require_verified_email"`
+	PasswordPolicy       *passwd.Policy `json:"password_policy"`
+}
+
+// Validate checks that a patched ProfileDocument still has every required member
+func (d *ProfileDocument) Validate() error {
+	switch {
+	case d.Name == nil:
+		return apperror.InvalidInput("name", "name is required")
+	case d.RequireTwoFactor == nil:
+		return apperror.InvalidInput("require_2fa", "require_2fa is required")
+	case d.RequireVerifiedEmail == nil:
+		return apperror.InvalidInput("require_verified_email", "require_verified_email is required")
+	}
+	return nil
+}
+
+// ApplyPatch applies a patch to doc and returns the patched document.
+// Patches that cannot be applied, that leave members unknown to the document, or that fail the
+// Validate method of documents having one, are invalid input; a failed JSON Patch test operation
+// is a conflict with the current state of the document.
+func ApplyPatch[T any](doc T, patch *PatchInput) (T, error) {
+	var patched T
+
+	current, err := json.Marshal(doc)
+	if err != nil {
+		return patched, err
+	}
+
+	var result []byte
+	switch patch.Format {
+	case MergePatch:
+		result, err = jsonpatch.MergePatch(current, patch.Patch)
+	case JSONPatch:
+		result, err = jsonpatch.Apply(current, patch.Patch)
+	default:
+		return patched, apperror.InvalidInput("patch", "unsupported patch format")
+	}
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		return patched, apperror.Conflict("patch", err.Error())
+	}
+	if err != nil {
+		return patched, apperror.InvalidInput("patch", err.Error())
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(result))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patched); err != nil {
+		return patched, apperror.InvalidInput("patch", err.Error())
+	}
+	if v, ok := any(&patched).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return patched, err
+		}
+	}
+	return patched, nil
+}
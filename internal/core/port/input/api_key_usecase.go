@@ -0,0 +1,19 @@
+package input
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/internal/core/dto"
+)
+
+// APIKeyUseCase defines the interface for API key operations
+type APIKeyUseCase interface {
+	// CreateAPIKey creates a key for a user and returns it with its secret
+	CreateAPIKey(ctx context.Context, userID uint, input *dto.APIKeyInput) (*dto.APIKeyCreatedOutput, error)
+
+	// GetAPIKeys returns the keys of a user that were not revoked
+	GetAPIKeys(ctx context.Context, userID uint) ([]dto.APIKeyOutput, error)
+
+	// RevokeAPIKey revokes a key of a user
+	RevokeAPIKey(ctx context.Context, userID, id uint) error
+}
@@ -0,0 +1,13 @@
+package input
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/internal/core/dto"
+)
+
+// AuditUseCase defines the interface for audit log operations
+type AuditUseCase interface {
+	// GetAuditRecords returns a paginated list of audit records, most recent first
+	GetAuditRecords(ctx context.Context, filter *dto.AuditFilter) (*dto.PaginatedOutput[dto.AuditOutput], error)
+}
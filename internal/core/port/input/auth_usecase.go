@@ -11,8 +11,50 @@ type AuthUseCase interface {
 	// Login authenticates a user and returns tokens
 	Login(ctx context.Context, input *dto.LoginInput) (*dto.AuthOutput, error)
 
-	// Refresh refreshes the authentication tokens
-	Refresh(ctx context.Context, userID uint, expiration bool) (*dto.AuthOutput, error)
+	// Refresh rotates the session's refresh token and returns new tokens
+	Refresh(ctx context.Context, sessionID, tokenID string, expiration bool) (*dto.AuthOutput, error)
+
+	// ExternalProviders returns the names of the identity providers users can log in with
+	ExternalProviders() []string
+
+	// BeginExternalLogin starts a login at an external identity provider
+	BeginExternalLogin(ctx context.Context, provider string, expiration bool) (*dto.ExternalLoginOutput, error)
+
+	// CompleteExternalLogin completes a login when the identity provider redirects the user back
+	CompleteExternalLogin(ctx context.Context, input *dto.ExternalLoginInput) (*dto.AuthOutput, error)
+
+	// OpenClientSession logs a user into an OAuth client, limited to the scopes it was granted
+	OpenClientSession(ctx context.Context, input *dto.ClientSessionInput) (*dto.AuthOutput, error)
+
+	// RefreshClientSession rotates the refresh token of a session opened for an OAuth client
+	RefreshClientSession(ctx context.Context, clientID, refreshToken string) (*dto.AuthOutput, error)
+
+	// RevokeClientToken revokes the session of a token issued to an OAuth client
+	RevokeClientToken(ctx context.Context, clientID, token string) error
+
+	// Impersonate returns a short-lived access token of a root user acting as another user
+	Impersonate(ctx context.Context, input *dto.ImpersonationInput) (*dto.AuthOutput, error)
+
+	// VerifyTwoFactor completes a login that requires a second factor
+	VerifyTwoFactor(ctx context.Context, input *dto.TwoFactorInput) (*dto.AuthOutput, error)
+
+	// ChangeExpiredPassword replaces an expired password during a login
+	ChangeExpiredPassword(ctx context.Context, input *dto.ExpiredPasswordInput) error
+
+	// SetupTwoFactor starts the 2FA enrollment of a user
+	SetupTwoFactor(ctx context.Context, userID uint) (*dto.TwoFactorSetupOutput, error)
+
+	// ConfirmTwoFactor activates the pending 2FA enrollment and returns recovery codes
+	ConfirmTwoFactor(ctx context.Context, userID uint, code string) (*dto.RecoveryCodesOutput, error)
+
+	// DisableTwoFactor turns 2FA off for a user
+	DisableTwoFactor(ctx context.Context, userID uint, code string) error
+
+	// Logout revokes a single session
+	Logout(ctx context.Context, sessionID string) error
+
+	// LogoutAll revokes every session of a user
+	LogoutAll(ctx context.Context, userID uint) error
 
 	// Me returns the current authenticated user information
 	Me(ctx context.Context, userID uint) (*dto.UserOutput, error)
@@ -0,0 +1,38 @@
+package input
+
+import (
+	"context"
+	"time"
+
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/pkg/oidc"
+)
+
+// OAuthUseCase defines the interface for the OAuth 2.0 / OpenID Connect provider,
+// letting registered client applications log users in with the API
+type OAuthUseCase interface {
+	// RequestAuthorization validates an authorization request and returns where to send the
+	// browser: the login page, or the client's redirect URI when the request is refused
+	RequestAuthorization(ctx context.Context, input *dto.AuthorizeInput) (*dto.AuthorizeOutput, error)
+
+	// Authorize issues an authorization code for a logged in user and returns the client's redirect URI
+	Authorize(ctx context.Context, userID uint, authTime time.Time, input *dto.AuthorizeInput) (*dto.AuthorizeOutput, error)
+
+	// Token serves the token endpoint: authorization_code, refresh_token and client_credentials grants
+	Token(ctx context.Context, input *dto.TokenInput) (*oidc.Tokens, error)
+
+	// UserInfo returns the claims about a user allowed by the scopes of the token
+	UserInfo(ctx context.Context, userID uint, scopes []string) (*dto.UserInfoOutput, error)
+
+	// Revoke revokes a token issued to a client
+	Revoke(ctx context.Context, input *dto.RevokeTokenInput) error
+
+	// GetClients returns every registered client
+	GetClients(ctx context.Context) ([]dto.OAuthClientOutput, error)
+
+	// CreateClient registers a client and returns its secret, only once
+	CreateClient(ctx context.Context, input *dto.OAuthClientInput) (*dto.OAuthClientCreatedOutput, error)
+
+	// DeleteClient removes a client, ending every session opened for it
+	DeleteClient(ctx context.Context, id uint) error
+}
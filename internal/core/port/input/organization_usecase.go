@@ -0,0 +1,26 @@
+package input
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/internal/core/dto"
+)
+
+// OrganizationUseCase defines the interface for the management of organizations, the tenants
+// hosted by the deployment
+type OrganizationUseCase interface {
+	// GetOrganizations returns every organization
+	GetOrganizations(ctx context.Context) ([]dto.OrganizationOutput, error)
+
+	// GetOrganizationByID returns an organization by its ID
+	GetOrganizationByID(ctx context.Context, id uint) (*dto.OrganizationOutput, error)
+
+	// CreateOrganization creates a new organization
+	CreateOrganization(ctx context.Context, input *dto.OrganizationInput) (*dto.OrganizationOutput, error)
+
+	// UpdateOrganization updates an existing organization
+	UpdateOrganization(ctx context.Context, id uint, input *dto.OrganizationInput) (*dto.OrganizationOutput, error)
+
+	// DeleteOrganization removes an organization without users or profiles
+	DeleteOrganization(ctx context.Context, id uint) error
+}
@@ -23,6 +23,12 @@ type ProfileUseCase interface {
 	// UpdateProfile updates an existing profile
 	UpdateProfile(ctx context.Context, id uint, input *dto.ProfileInput) (*dto.ProfileOutput, error)
 
-	// DeleteProfiles deletes profiles by their IDs
+	// PatchProfile updates an existing profile with a patch of its document
+	PatchProfile(ctx context.Context, id uint, patch *dto.PatchInput) (*dto.ProfileOutput, error)
+
+	// DeleteProfiles moves profiles to the trash by their IDs
 	DeleteProfiles(ctx context.Context, ids []uint) error
+
+	// RestoreProfiles takes profiles out of the trash by their IDs
+	RestoreProfiles(ctx context.Context, ids []uint) error
 }
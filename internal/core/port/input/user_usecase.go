@@ -20,12 +20,54 @@ type UserUseCase interface {
 	// UpdateUser updates an existing user
 	UpdateUser(ctx context.Context, id uint, input *dto.UserInput) (*dto.UserOutput, error)
 
-	// DeleteUsers deletes users by their IDs
+	// PatchUser updates an existing user with a patch of its document
+	PatchUser(ctx context.Context, id uint, patch *dto.PatchInput) (*dto.UserOutput, error)
+
+	// DeleteUsers moves users to the trash by their IDs
 	DeleteUsers(ctx context.Context, ids []uint) error
 
-	// ResetPassword resets a user's password
+	// RestoreUsers takes users out of the trash by their IDs
+	RestoreUsers(ctx context.Context, ids []uint) error
+
+	// GetSessions returns the active sessions of a user, flagging currentSessionID as current
+	GetSessions(ctx context.Context, id uint, currentSessionID string) ([]dto.SessionOutput, error)
+
+	// RevokeSession revokes a single session of a user
+	RevokeSession(ctx context.Context, id uint, sessionID string) error
+
+	// RevokeSessions revokes every session of a user
+	RevokeSessions(ctx context.Context, id uint) error
+
+	// Unlock clears the failed logins of a user, lifting a lockout
+	Unlock(ctx context.Context, id uint) error
+
+	// InviteUser creates a pending user and delivers an invitation to accept by setting the first password
+	InviteUser(ctx context.Context, input *dto.UserInput) (*dto.UserOutput, error)
+
+	// ImportUsers creates users from the rows of a spreadsheet, or only validates them in a dry run
+	ImportUsers(ctx context.Context, input *dto.UserImportInput) (*dto.UserImportOutput, error)
+
+	// GetInvitations returns the invitations not accepted yet, the ones expiring first first
+	GetInvitations(ctx context.Context) ([]dto.InvitationOutput, error)
+
+	// ResendInvitation delivers a new invitation to a pending user, invalidating the previous one
+	ResendInvitation(ctx context.Context, id uint) error
+
+	// RevokeInvitation withdraws the invitation of a pending user, deleting the user
+	RevokeInvitation(ctx context.Context, id uint) error
+
+	// AcceptInvitation sets the first password of a pending user using an invitation token
+	AcceptInvitation(ctx context.Context, input *dto.PasswordInput) error
+
+	// RequestEmailVerification delivers a verification token for the email awaiting verification of a user
+	RequestEmailVerification(ctx context.Context, email string) error
+
+	// VerifyEmail confirms the email awaiting verification of a user using a verification token
+	VerifyEmail(ctx context.Context, input *dto.EmailVerificationInput) error
+
+	// ResetPassword issues a password reset token and delivers it to the user
 	ResetPassword(ctx context.Context, email string) error
 
-	// SetPassword sets a user's password
-	SetPassword(ctx context.Context, email string, input *dto.PasswordInput) error
+	// SetPassword sets a user's password using a password reset token
+	SetPassword(ctx context.Context, input *dto.PasswordInput) error
 }
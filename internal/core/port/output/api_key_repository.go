@@ -0,0 +1,26 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+// APIKeyRepository defines the interface for API key persistence operations
+type APIKeyRepository interface {
+	// FindByHash returns a key by the hash of its secret
+	FindByHash(ctx context.Context, hash string) (*entity.APIKey, error)
+
+	// FindByUser returns the keys of a user that were not revoked
+	FindByUser(ctx context.Context, userID uint) ([]*entity.APIKey, error)
+
+	// Create creates a new key
+	Create(ctx context.Context, key *entity.APIKey) error
+
+	// Revoke revokes a key of a user, failing if the user has no such active key
+	Revoke(ctx context.Context, userID, id uint) error
+
+	// Touch records when a key was last used
+	Touch(ctx context.Context, id uint, usedAt time.Time) error
+}
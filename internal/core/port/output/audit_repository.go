@@ -0,0 +1,20 @@
+package output
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+)
+
+// AuditRepository defines the interface for the append-only audit log
+type AuditRepository interface {
+	// Create appends a record to the audit log
+	Create(ctx context.Context, record *entity.AuditRecord) error
+
+	// Count returns the number of records matching the filter
+	Count(ctx context.Context, filter *dto.AuditFilter) (int64, error)
+
+	// FindAll returns the records matching the filter, most recent first
+	FindAll(ctx context.Context, filter *dto.AuditFilter) ([]*entity.AuditRecord, error)
+}
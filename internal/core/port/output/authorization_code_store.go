@@ -0,0 +1,19 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+// AuthorizationCodeStore defines the interface for keeping the authorization codes
+// issued to OAuth clients until they are redeemed
+type AuthorizationCodeStore interface {
+	// Save keeps a code for ttl
+	Save(ctx context.Context, code *entity.AuthorizationCode, ttl time.Duration) error
+
+	// Take returns and forgets a code, so it can only be redeemed once.
+	// It returns nil when there is no such code or it expired.
+	Take(ctx context.Context, code string) (*entity.AuthorizationCode, error)
+}
@@ -0,0 +1,7 @@
+package output
+
+import "github.com/raulaguila/go-api/pkg/apperror"
+
+// ErrStaleVersion is returned by repositories refusing to update a record that was updated
+// since it was read, so that concurrent updates do not overwrite each other
+var ErrStaleVersion = apperror.StaleVersion("resource")
@@ -0,0 +1,16 @@
+package output
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+// ExternalIdentityRepository defines the interface for external identity persistence operations
+type ExternalIdentityRepository interface {
+	// FindBySubject returns the identity of an account at a provider
+	FindBySubject(ctx context.Context, provider, subject string) (*entity.ExternalIdentity, error)
+
+	// Create links a new identity to a user
+	Create(ctx context.Context, identity *entity.ExternalIdentity) error
+}
@@ -0,0 +1,19 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+// ExternalLoginStore defines the interface for keeping logins started at external
+// identity providers until the provider redirects the user back
+type ExternalLoginStore interface {
+	// Save keeps a login for ttl
+	Save(ctx context.Context, login *entity.ExternalLogin, ttl time.Duration) error
+
+	// Take returns and forgets the login of a state, so it can only be completed once.
+	// It returns nil when there is no such login or it expired.
+	Take(ctx context.Context, state string) (*entity.ExternalLogin, error)
+}
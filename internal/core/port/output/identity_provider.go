@@ -0,0 +1,21 @@
+package output
+
+import "context"
+
+// ExternalClaims are the verified claims of a user authenticated by an external identity provider
+type ExternalClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Username      string
+}
+
+// IdentityProvider defines the interface for an external identity provider users can log in with
+type IdentityProvider interface {
+	// AuthCodeURL returns the URL of the provider's login page for a new login
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+
+	// Exchange redeems the authorization code the provider sent back and returns the verified claims
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*ExternalClaims, error)
+}
@@ -0,0 +1,13 @@
+package output
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+// LoginAttemptRepository defines the interface for the login attempts audit trail
+type LoginAttemptRepository interface {
+	// Create records a login attempt
+	Create(ctx context.Context, attempt *entity.LoginAttempt) error
+}
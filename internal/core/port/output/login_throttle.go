@@ -0,0 +1,21 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+// LoginThrottle defines the interface for counting consecutive failed logins per key
+// (a username or an IP), shared by every instance of the API
+type LoginThrottle interface {
+	// Failures returns the failures recorded for key, or nil if there are none
+	Failures(ctx context.Context, key string) (*entity.LoginFailures, error)
+
+	// AddFailure records a failure for key, forgotten ttl after the last one, and returns the updated count
+	AddFailure(ctx context.Context, key string, ttl time.Duration) (*entity.LoginFailures, error)
+
+	// Reset clears the failures recorded for key
+	Reset(ctx context.Context, key string) error
+}
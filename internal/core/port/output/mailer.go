@@ -0,0 +1,19 @@
+package output
+
+import (
+	"context"
+)
+
+// MailMessage is an email rendered from a localized template before delivery
+type MailMessage struct {
+	To       []string
+	Template string
+	Language string // Empty uses the default language
+	Data     map[string]any
+}
+
+// Mailer defines the interface for delivering email messages
+type Mailer interface {
+	// Send renders the message template and delivers it
+	Send(ctx context.Context, msg *MailMessage) error
+}
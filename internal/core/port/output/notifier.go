@@ -0,0 +1,29 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+// Notifier defines the interface for delivering messages to users out of band
+type Notifier interface {
+	// SendWelcome delivers the account creation message, with a token to set the first password
+	SendWelcome(ctx context.Context, user *entity.User, token string, expiresAt time.Time) error
+
+	// SendInvitation delivers an invitation, with a token to accept it by setting the first password
+	SendInvitation(ctx context.Context, user *entity.User, token string, expiresAt time.Time) error
+
+	// SendPasswordReset delivers a password reset token to the user
+	SendPasswordReset(ctx context.Context, user *entity.User, token string, expiresAt time.Time) error
+
+	// SendEmailVerification delivers a token proving that email, current or pending, belongs to the user
+	SendEmailVerification(ctx context.Context, user *entity.User, email, token string, expiresAt time.Time) error
+
+	// SendEmailChangeRequested tells the user, at the current email, that a change to newEmail is pending
+	SendEmailChangeRequested(ctx context.Context, user *entity.User, newEmail string) error
+
+	// SendAccountDisabled tells the user their account was disabled
+	SendAccountDisabled(ctx context.Context, user *entity.User) error
+}
@@ -0,0 +1,22 @@
+package output
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+// OAuthClientRepository defines the interface for OAuth client persistence operations
+type OAuthClientRepository interface {
+	// FindAll returns every registered client
+	FindAll(ctx context.Context) ([]*entity.OAuthClient, error)
+
+	// FindByClientID returns a client by its public client ID
+	FindByClientID(ctx context.Context, clientID string) (*entity.OAuthClient, error)
+
+	// Create registers a new client
+	Create(ctx context.Context, client *entity.OAuthClient) error
+
+	// Delete removes a client, failing if there is no such client
+	Delete(ctx context.Context, id uint) error
+}
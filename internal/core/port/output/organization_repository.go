@@ -0,0 +1,26 @@
+package output
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+// OrganizationRepository defines the interface for organization persistence operations
+type OrganizationRepository interface {
+	// FindAll returns every organization
+	FindAll(ctx context.Context) ([]*entity.Organization, error)
+
+	// FindByID returns an organization by its ID
+	FindByID(ctx context.Context, id uint) (*entity.Organization, error)
+
+	// Create creates a new organization
+	Create(ctx context.Context, organization *entity.Organization) error
+
+	// Update updates an existing organization
+	Update(ctx context.Context, organization *entity.Organization) error
+
+	// Delete removes an organization, failing if there is no such organization
+	// or if it still has users or profiles
+	Delete(ctx context.Context, id uint) error
+}
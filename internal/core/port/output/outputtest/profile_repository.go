@@ -0,0 +1,76 @@
+package outputtest
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/pkg/tenant"
+)
+
+// ProfileRepository implements output.ProfileRepository over a map, scoping lookups to the
+// organization of ctx, and keeps the last profile updated and the last IDs deleted
+type ProfileRepository struct {
+	Profiles  map[uint]*entity.Profile
+	UpdateErr error // Returned by Update instead of saving the profile
+	Updated   *entity.Profile
+	Deleted   []uint
+}
+
+// NewProfileRepository creates a ProfileRepository holding profiles
+func NewProfileRepository(profiles ...*entity.Profile) *ProfileRepository {
+	r := &ProfileRepository{Profiles: make(map[uint]*entity.Profile)}
+	for _, p := range profiles {
+		r.Profiles[p.ID] = p
+	}
+	return r
+}
+
+func (r *ProfileRepository) Count(context.Context, *dto.ProfileFilter) (int64, error) {
+	return int64(len(r.Profiles)), nil
+}
+
+func (r *ProfileRepository) FindAll(context.Context, *dto.ProfileFilter) ([]*entity.Profile, error) {
+	profiles := make([]*entity.Profile, 0, len(r.Profiles))
+	for _, p := range r.Profiles {
+		profiles = append(profiles, p)
+	}
+	return profiles, nil
+}
+
+func (r *ProfileRepository) FindByID(ctx context.Context, id uint) (*entity.Profile, error) {
+	if p, ok := r.Profiles[id]; ok && tenant.Allows(ctx, p.OrganizationID) {
+		return p, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *ProfileRepository) FindByName(context.Context, string) (*entity.Profile, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *ProfileRepository) Create(context.Context, *entity.Profile) error { return nil }
+
+// Update saves a profile, bumping its version as the database does
+func (r *ProfileRepository) Update(_ context.Context, p *entity.Profile) error {
+	if r.UpdateErr != nil {
+		return r.UpdateErr
+	}
+	r.Updated = p
+	p.Version++
+	return nil
+}
+
+func (r *ProfileRepository) InUse(context.Context, []uint) (bool, error) { return false, nil }
+
+func (r *ProfileRepository) Delete(_ context.Context, ids []uint) error {
+	r.Deleted = ids
+	return nil
+}
+
+func (r *ProfileRepository) Restore(context.Context, []uint) error { return nil }
+
+func (r *ProfileRepository) Purge(context.Context, time.Time) (int64, error) { return 0, nil }
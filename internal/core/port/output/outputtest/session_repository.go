@@ -0,0 +1,51 @@
+package outputtest
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+// SessionRepository is a mock of output.SessionRepository
+type SessionRepository struct {
+	mock.Mock
+}
+
+func (m *SessionRepository) FindByID(ctx context.Context, id string) (*entity.Session, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Session), args.Error(1)
+}
+
+func (m *SessionRepository) Create(ctx context.Context, s *entity.Session) error {
+	return m.Called(ctx, s).Error(0)
+}
+
+func (m *SessionRepository) Rotate(ctx context.Context, s *entity.Session, previousTokenID string) error {
+	return m.Called(ctx, s, previousTokenID).Error(0)
+}
+
+func (m *SessionRepository) Revoke(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *SessionRepository) RevokeByUser(ctx context.Context, userID uint) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func (m *SessionRepository) FindActiveByUser(ctx context.Context, userID uint) ([]*entity.Session, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Session), args.Error(1)
+}
+
+func (m *SessionRepository) SaveActivity(ctx context.Context, activity map[string]time.Time) error {
+	return m.Called(ctx, activity).Error(0)
+}
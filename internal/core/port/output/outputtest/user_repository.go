@@ -0,0 +1,96 @@
+// Package outputtest provides test doubles of the output ports, shared by the tests of the
+// use cases and adapters that depend on them.
+package outputtest
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+)
+
+// UserRepository is a mock of output.UserRepository
+type UserRepository struct {
+	mock.Mock
+}
+
+func (m *UserRepository) Count(ctx context.Context, filter *dto.UserFilter) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *UserRepository) FindAll(ctx context.Context, filter *dto.UserFilter) ([]*entity.User, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*entity.User), args.Error(1)
+}
+
+func (m *UserRepository) FindByID(ctx context.Context, id uint) (*entity.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *UserRepository) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *UserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *UserRepository) FindByLogin(ctx context.Context, login string, caseInsensitive bool) (*entity.User, error) {
+	args := m.Called(ctx, login, caseInsensitive)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *UserRepository) Create(ctx context.Context, u *entity.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *UserRepository) FindRegistered(ctx context.Context, emails, usernames []string) (map[string]bool, map[string]bool, error) {
+	args := m.Called(ctx, emails, usernames)
+	return args.Get(0).(map[string]bool), args.Get(1).(map[string]bool), args.Error(2)
+}
+
+func (m *UserRepository) CreateBatch(ctx context.Context, users []*entity.User) ([]int, error) {
+	args := m.Called(ctx, users)
+	skipped, _ := args.Get(0).([]int)
+	return skipped, args.Error(1)
+}
+
+func (m *UserRepository) Update(ctx context.Context, u *entity.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *UserRepository) Delete(ctx context.Context, ids []uint) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func (m *UserRepository) Restore(ctx context.Context, ids []uint) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func (m *UserRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,14 @@
+package output
+
+// PasswordHasher defines the interface for hashing passwords into PHC strings,
+// such as the hashers of passwd.NewHasher
+type PasswordHasher interface {
+	// Hash returns the hash of password, with a new random salt
+	Hash(password string) (string, error)
+
+	// Verify checks if password matches hash
+	Verify(password, hash string) bool
+
+	// NeedsRehash checks if hash was made with an outdated algorithm or parameters
+	NeedsRehash(hash string) bool
+}
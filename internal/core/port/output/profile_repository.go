@@ -2,6 +2,7 @@ package output
 
 import (
 	"context"
+	"time"
 
 	"github.com/raulaguila/go-api/internal/core/domain/entity"
 	"github.com/raulaguila/go-api/internal/core/dto"
@@ -24,9 +25,18 @@ type ProfileRepository interface {
 	// Create creates a new profile
 	Create(ctx context.Context, profile *entity.Profile) error
 
-	// Update updates an existing profile
+	// Update updates an existing profile, or returns ErrStaleVersion if it changed since it was read
 	Update(ctx context.Context, profile *entity.Profile) error
 
-	// Delete deletes profiles by their IDs
+	// InUse checks if any of the profiles is granted to users not in the trash
+	InUse(ctx context.Context, ids []uint) (bool, error)
+
+	// Delete moves profiles to the trash by their IDs
 	Delete(ctx context.Context, ids []uint) error
+
+	// Restore takes profiles out of the trash by their IDs
+	Restore(ctx context.Context, ids []uint) error
+
+	// Purge permanently deletes the profiles moved to the trash before a time and returns how many were deleted
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
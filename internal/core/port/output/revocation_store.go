@@ -0,0 +1,22 @@
+package output
+
+import (
+	"context"
+	"time"
+)
+
+// RevocationStore defines the interface for a fast lookup of revoked sessions,
+// letting the authentication layer reject revoked tokens before they expire
+type RevocationStore interface {
+	// RevokeSession marks a session as revoked for ttl (zero keeps it forever)
+	RevokeSession(ctx context.Context, sessionID string, ttl time.Duration) error
+
+	// IsSessionRevoked checks if a session was revoked
+	IsSessionRevoked(ctx context.Context, sessionID string) (bool, error)
+
+	// RevokeUser marks every session of a user created until now as revoked
+	RevokeUser(ctx context.Context, userID uint) error
+
+	// UserRevokedAt returns when the user's sessions were last revoked, or nil if never
+	UserRevokedAt(ctx context.Context, userID uint) (*time.Time, error)
+}
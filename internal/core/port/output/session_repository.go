@@ -0,0 +1,41 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+// SessionRepository defines the interface for session persistence operations
+type SessionRepository interface {
+	// FindByID returns a session by its ID
+	FindByID(ctx context.Context, id string) (*entity.Session, error)
+
+	// FindActiveByUser returns the sessions of a user that are neither revoked nor expired,
+	// most recently active first
+	FindActiveByUser(ctx context.Context, userID uint) ([]*entity.Session, error)
+
+	// Create creates a new session
+	Create(ctx context.Context, session *entity.Session) error
+
+	// Rotate stores the session's new refresh token ID, but only if the current
+	// one still equals previousTokenID and the session is not revoked
+	Rotate(ctx context.Context, session *entity.Session, previousTokenID string) error
+
+	// Revoke revokes a session
+	Revoke(ctx context.Context, id string) error
+
+	// RevokeByUser revokes all active sessions of a user
+	RevokeByUser(ctx context.Context, userID uint) error
+
+	// SaveActivity stores the last activity of sessions by ID, never moving it back in time
+	SaveActivity(ctx context.Context, activity map[string]time.Time) error
+}
+
+// SessionActivityRecorder defines the interface for recording the last activity of sessions.
+// Implementations may persist it later, in batches.
+type SessionActivityRecorder interface {
+	// Record records that a session was active at a time
+	Record(sessionID string, at time.Time)
+}
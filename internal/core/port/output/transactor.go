@@ -0,0 +1,10 @@
+package output
+
+import "context"
+
+// Transactor defines the interface for making changes through several repositories at once
+type Transactor interface {
+	// Transaction runs fn in a transaction, committed if fn returns nil and rolled back otherwise.
+	// The repositories fn calls with the context it receives take part in the transaction.
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -2,6 +2,7 @@ package output
 
 import (
 	"context"
+	"time"
 
 	"github.com/raulaguila/go-api/internal/core/domain/entity"
 	"github.com/raulaguila/go-api/internal/core/dto"
@@ -24,15 +25,29 @@ type UserRepository interface {
 	// FindByEmail returns a user by its email
 	FindByEmail(ctx context.Context, email string) (*entity.User, error)
 
-	// FindByToken returns a user by its authentication token
-	FindByToken(ctx context.Context, token string) (*entity.User, error)
+	// FindByLogin returns the user whose username or email is login, preferring a username match
+	FindByLogin(ctx context.Context, login string, caseInsensitive bool) (*entity.User, error)
+
+	// FindRegistered returns which of the emails and usernames users of any organization registered,
+	// compared case-insensitively like logins; the keys are lowercased
+	FindRegistered(ctx context.Context, emails, usernames []string) (registeredEmails, registeredUsernames map[string]bool, err error)
 
 	// Create creates a new user
 	Create(ctx context.Context, user *entity.User) error
 
-	// Update updates an existing user
+	// CreateBatch creates users in one transaction, except those whose email or username was
+	// registered meanwhile, whose indexes it returns
+	CreateBatch(ctx context.Context, users []*entity.User) (skipped []int, err error)
+
+	// Update updates an existing user, or returns ErrStaleVersion if it changed since it was read
 	Update(ctx context.Context, user *entity.User) error
 
-	// Delete deletes users by their IDs
+	// Delete moves users to the trash by their IDs
 	Delete(ctx context.Context, ids []uint) error
+
+	// Restore takes users out of the trash by their IDs
+	Restore(ctx context.Context, ids []uint) error
+
+	// Purge permanently deletes the users moved to the trash before a time and returns how many were deleted
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,25 @@
+package output
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+)
+
+// UserTokenRepository defines the interface for single-use user token persistence operations
+type UserTokenRepository interface {
+	// FindByHash returns a token by its purpose and hash
+	FindByHash(ctx context.Context, purpose entity.TokenPurpose, hash string) (*entity.UserToken, error)
+
+	// FindUnused returns the unused tokens with the given purpose, with their users, the ones expiring first first
+	FindUnused(ctx context.Context, purpose entity.TokenPurpose) ([]*entity.UserToken, error)
+
+	// Create creates a new token
+	Create(ctx context.Context, token *entity.UserToken) error
+
+	// Consume marks the token as used, but only if it was not used before
+	Consume(ctx context.Context, token *entity.UserToken) error
+
+	// DeleteByUser deletes all tokens of a user with the given purpose
+	DeleteByUser(ctx context.Context, userID uint, purpose entity.TokenPurpose) error
+}
@@ -0,0 +1,78 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/input"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/pkg/apperror"
+)
+
+// apiKeyUseCase implements the APIKeyUseCase interface
+type apiKeyUseCase struct {
+	keyRepo  output.APIKeyRepository
+	userRepo output.UserRepository
+}
+
+// NewAPIKeyUseCase creates a new APIKeyUseCase instance
+func NewAPIKeyUseCase(keyRepo output.APIKeyRepository, userRepo output.UserRepository) input.APIKeyUseCase {
+	return &apiKeyUseCase{
+		keyRepo:  keyRepo,
+		userRepo: userRepo,
+	}
+}
+
+// CreateAPIKey creates a key for a user. Every scope must be granted by the user's profile.
+func (uc *apiKeyUseCase) CreateAPIKey(ctx context.Context, userID uint, input *dto.APIKeyInput) (*dto.APIKeyCreatedOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.UserNotFound()
+	}
+
+	profile := user.GetProfile()
+	if profile == nil {
+		return nil, apperror.Forbidden("user has no profile")
+	}
+	if !profile.IsRoot() {
+		for _, scope := range input.Scopes {
+			if !profile.HasPermission(scope) {
+				return nil, entity.ErrAPIKeyScopeNotGranted(scope)
+			}
+		}
+	}
+
+	key, secret, err := entity.NewAPIKey(user.ID, input.Name, input.Scopes, input.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.keyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &dto.APIKeyCreatedOutput{
+		APIKeyOutput: *dto.EntityToAPIKeyOutput(key),
+		Key:          secret,
+	}, nil
+}
+
+// GetAPIKeys returns the keys of a user that were not revoked
+func (uc *apiKeyUseCase) GetAPIKeys(ctx context.Context, userID uint) ([]dto.APIKeyOutput, error) {
+	keys, err := uc.keyRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.EntitiesToAPIKeyOutputs(keys), nil
+}
+
+// RevokeAPIKey revokes a key of a user; keys of other users are reported as not found
+func (uc *apiKeyUseCase) RevokeAPIKey(ctx context.Context, userID, id uint) error {
+	return uc.keyRepo.Revoke(ctx, userID, id)
+}
@@ -0,0 +1,121 @@
+package apikey_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/internal/core/usecase/apikey"
+	"github.com/raulaguila/go-api/pkg/apperror"
+)
+
+// fakeUserRepo implements the lookups of output.UserRepository used by API keys
+type fakeUserRepo struct {
+	output.UserRepository
+	users map[uint]*entity.User
+}
+
+func (r *fakeUserRepo) FindByID(_ context.Context, id uint) (*entity.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// fakeKeyRepo implements output.APIKeyRepository in memory for testing
+type fakeKeyRepo struct {
+	keys []*entity.APIKey
+}
+
+func (r *fakeKeyRepo) FindByHash(_ context.Context, hash string) (*entity.APIKey, error) {
+	for _, k := range r.keys {
+		if k.KeyHash == hash {
+			return k, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeKeyRepo) FindByUser(_ context.Context, userID uint) ([]*entity.APIKey, error) {
+	var keys []*entity.APIKey
+	for _, k := range r.keys {
+		if k.UserID == userID && !k.IsRevoked() {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (r *fakeKeyRepo) Create(_ context.Context, key *entity.APIKey) error {
+	key.ID = uint(len(r.keys) + 1)
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *fakeKeyRepo) Revoke(_ context.Context, userID, id uint) error {
+	for _, k := range r.keys {
+		if k.ID == id && k.UserID == userID && !k.IsRevoked() {
+			k.Revoke()
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *fakeKeyRepo) Touch(_ context.Context, id uint, usedAt time.Time) error {
+	return nil
+}
+
+func newTestUseCase() (*fakeKeyRepo, *fakeUserRepo) {
+	users := &fakeUserRepo{users: map[uint]*entity.User{
+		1: {ID: 1, Auth: &entity.Auth{Profile: &entity.Profile{ID: 1, Name: "ROOT"}}},
+		2: {ID: 2, Auth: &entity.Auth{Profile: &entity.Profile{ID: 2, Name: "READER", Permissions: []string{"users:read"}}}},
+	}}
+	return &fakeKeyRepo{}, users
+}
+
+func TestCreateAPIKey_ScopesWithinProfile(t *testing.T) {
+	keys, users := newTestUseCase()
+	uc := apikey.NewAPIKeyUseCase(keys, users)
+	ctx := context.Background()
+
+	out, err := uc.CreateAPIKey(ctx, 2, &dto.APIKeyInput{Name: "reports", Scopes: []string{"users:read"}})
+	require.NoError(t, err)
+	assert.NotEmpty(t, out.Key)
+	assert.Equal(t, out.Key[:len(out.Prefix)], out.Prefix)
+
+	stored, err := keys.FindByHash(ctx, entity.HashToken(out.Key))
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), stored.UserID)
+
+	_, err = uc.CreateAPIKey(ctx, 2, &dto.APIKeyInput{Name: "admin", Scopes: []string{"users:read", "users:write"}})
+	var appErr *apperror.Error
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.CodeInvalidInput, appErr.Code, "a key cannot grant more than its owner's profile")
+
+	_, err = uc.CreateAPIKey(ctx, 1, &dto.APIKeyInput{Name: "root", Scopes: []string{"users:write"}})
+	assert.NoError(t, err, "the root profile may grant any scope")
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	keys, users := newTestUseCase()
+	uc := apikey.NewAPIKeyUseCase(keys, users)
+	ctx := context.Background()
+
+	out, err := uc.CreateAPIKey(ctx, 2, &dto.APIKeyInput{Name: "reports", Scopes: []string{"users:read"}})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, uc.RevokeAPIKey(ctx, 1, out.ID), gorm.ErrRecordNotFound, "keys of other users cannot be revoked")
+	require.NoError(t, uc.RevokeAPIKey(ctx, 2, out.ID))
+
+	listed, err := uc.GetAPIKeys(ctx, 2)
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+}
@@ -0,0 +1,98 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/input"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/pkg/actor"
+	"github.com/raulaguila/go-api/pkg/tenant"
+)
+
+// auditUseCase implements the AuditUseCase interface
+type auditUseCase struct {
+	auditRepo output.AuditRepository
+}
+
+// NewAuditUseCase creates a new AuditUseCase instance
+func NewAuditUseCase(auditRepo output.AuditRepository) input.AuditUseCase {
+	return &auditUseCase{
+		auditRepo: auditRepo,
+	}
+}
+
+// GetAuditRecords returns a paginated list of audit records, most recent first
+func (uc *auditUseCase) GetAuditRecords(ctx context.Context, filter *dto.AuditFilter) (*dto.PaginatedOutput[dto.AuditOutput], error) {
+	records, err := uc.auditRepo.FindAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := uc.auditRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := dto.EntitiesToAuditOutputs(records)
+	return dto.NewPaginatedOutput(outputs, filter.Page, filter.Limit, count), nil
+}
+
+// Recorder appends the changes made by the other use cases to the audit log, attributing
+// them to the actor of the request. A nil Recorder records nothing.
+type Recorder struct {
+	auditRepo  output.AuditRepository
+	transactor output.Transactor
+}
+
+// NewRecorder creates a new Recorder; without a transactor, changes and their records are
+// written separately
+func NewRecorder(auditRepo output.AuditRepository, transactor output.Transactor) *Recorder {
+	return &Recorder{auditRepo: auditRepo, transactor: transactor}
+}
+
+// Transaction runs fn, which makes changes and records them, in a transaction: no change is
+// kept without its records, nor recorded if it fails
+func (r *Recorder) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r == nil || r.transactor == nil {
+		return fn(ctx)
+	}
+	return r.transactor.Transaction(ctx, fn)
+}
+
+// Record appends a record to the audit log, stamped with the actor, request ID and IP of ctx.
+// Records of no organization made within an organization's context belong to that organization.
+func (r *Recorder) Record(ctx context.Context, record *entity.AuditRecord) error {
+	if r == nil {
+		return nil
+	}
+
+	who := actor.FromContext(ctx)
+	record.ActorID = who.UserID
+	record.ImpersonatorID = who.ImpersonatorID
+	record.RequestID = who.RequestID
+	record.IP = who.IP
+	if record.OrganizationID == nil {
+		record.OrganizationID = tenant.Resolve(ctx, nil)
+	}
+
+	return r.auditRepo.Create(ctx, record)
+}
+
+// User records an action on a user, given the user's state before it (nil for created users)
+// and after it (nil for deleted users)
+func (r *Recorder) User(ctx context.Context, action string, user *entity.User, before, after entity.AuditState) error {
+	return r.Record(ctx, entity.NewAuditRecord(action, entity.AuditEntityUser, entity.AuditEntityID(user.ID), user.OrganizationID, before, after))
+}
+
+// Profile records an action on a profile, given the profile's state before it (nil for created
+// profiles) and after it (nil for deleted profiles)
+func (r *Recorder) Profile(ctx context.Context, action string, profile *entity.Profile, before, after entity.AuditState) error {
+	return r.Record(ctx, entity.NewAuditRecord(action, entity.AuditEntityProfile, entity.AuditEntityID(profile.ID), profile.OrganizationID, before, after))
+}
+
+// Session records an action on a session, given the session's state before and after it
+func (r *Recorder) Session(ctx context.Context, action string, session *entity.Session, before, after entity.AuditState) error {
+	return r.Record(ctx, entity.NewAuditRecord(action, entity.AuditEntitySession, session.ID, nil, before, after))
+}
@@ -0,0 +1,108 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/usecase/audit"
+	"github.com/raulaguila/go-api/pkg/actor"
+	"github.com/raulaguila/go-api/pkg/tenant"
+)
+
+// fakeAuditRepo implements output.AuditRepository, keeping the records in memory
+type fakeAuditRepo struct {
+	records []*entity.AuditRecord
+}
+
+func (r *fakeAuditRepo) Create(_ context.Context, record *entity.AuditRecord) error {
+	record.ID = uint(len(r.records) + 1)
+	r.records = append(r.records, record)
+	return nil
+}
+
+func (r *fakeAuditRepo) Count(context.Context, *dto.AuditFilter) (int64, error) {
+	return int64(len(r.records)), nil
+}
+
+func (r *fakeAuditRepo) FindAll(context.Context, *dto.AuditFilter) ([]*entity.AuditRecord, error) {
+	return r.records, nil
+}
+
+// fakeTransactor implements output.Transactor, discarding the records of failed transactions
+type fakeTransactor struct {
+	repo *fakeAuditRepo
+}
+
+func (t *fakeTransactor) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	committed := len(t.repo.records)
+	if err := fn(ctx); err != nil {
+		t.repo.records = t.repo.records[:committed]
+		return err
+	}
+	return nil
+}
+
+func TestRecorder_StampsActor(t *testing.T) {
+	repo := &fakeAuditRepo{}
+	recorder := audit.NewRecorder(repo, nil)
+	acme, user, admin := uint(1), uint(7), uint(2)
+	ctx := tenant.WithOrganization(actor.WithRequest(context.Background(), "req-1", "10.0.0.1"), &acme)
+	ctx = actor.WithUser(ctx, &user, &admin)
+	session := entity.NewSession(user, "curl/8.4.0", "10.0.0.1", nil)
+
+	require.NoError(t, recorder.Session(ctx, entity.AuditActionRevokeSession, session, nil, nil))
+
+	require.Len(t, repo.records, 1)
+	record := repo.records[0]
+	assert.Equal(t, &user, record.ActorID)
+	assert.Equal(t, &admin, record.ImpersonatorID)
+	assert.Equal(t, "req-1", record.RequestID)
+	assert.Equal(t, "10.0.0.1", record.IP)
+	assert.Equal(t, &acme, record.OrganizationID, "records of no organization belong to the organization of the request")
+	assert.Equal(t, session.ID, record.EntityID)
+}
+
+func TestRecorder_NilRecordsNothing(t *testing.T) {
+	var recorder *audit.Recorder
+	assert.NoError(t, recorder.Profile(context.Background(), entity.AuditActionCreate, entity.NewProfile("Support", nil), nil, nil))
+
+	changed := false
+	assert.NoError(t, recorder.Transaction(context.Background(), func(context.Context) error {
+		changed = true
+		return nil
+	}))
+	assert.True(t, changed, "changes are made without being recorded")
+}
+
+func TestRecorder_FailedChangesAreNotRecorded(t *testing.T) {
+	repo := &fakeAuditRepo{}
+	recorder := audit.NewRecorder(repo, &fakeTransactor{repo: repo})
+	profile := entity.NewProfile("Support", nil)
+	failed := errors.New("change failed")
+
+	err := recorder.Transaction(context.Background(), func(ctx context.Context) error {
+		require.NoError(t, recorder.Profile(ctx, entity.AuditActionCreate, profile, nil, profile.AuditState()))
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+	assert.Empty(t, repo.records)
+}
+
+func TestGetAuditRecords(t *testing.T) {
+	repo := &fakeAuditRepo{}
+	profile := entity.NewProfile("Support", []string{entity.PermissionUsersRead})
+	require.NoError(t, audit.NewRecorder(repo, nil).Profile(context.Background(), entity.AuditActionCreate, profile, nil, profile.AuditState()))
+
+	out, err := audit.NewAuditUseCase(repo).GetAuditRecords(context.Background(), &dto.AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, out.Items, 1)
+	assert.Equal(t, entity.AuditEntityProfile, out.Items[0].EntityType)
+	assert.Equal(t, dto.AuditChangeOutput{After: "Support"}, out.Items[0].Changes["name"])
+	assert.Equal(t, uint(1), out.Pagination.TotalItems)
+}
@@ -2,7 +2,9 @@ package auth
 
 import (
 	"context"
-	"crypto/rsa"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,57 +14,330 @@ import (
 	"github.com/raulaguila/go-api/internal/core/dto"
 	"github.com/raulaguila/go-api/internal/core/port/input"
 	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/internal/core/usecase/audit"
+	"github.com/raulaguila/go-api/pkg/actor"
 	"github.com/raulaguila/go-api/pkg/apperror"
+	"github.com/raulaguila/go-api/pkg/jwtx"
+	"github.com/raulaguila/go-api/pkg/passwd"
+	"github.com/raulaguila/go-api/pkg/totp"
 )
 
-// Config holds JWT configuration
+// Challenge token types: a token only allows completing the step of a login it was issued for
+const (
+	challengeTokenType      = "2fa"
+	passwordChangeTokenType = "password"
+)
+
+// Config holds JWT, 2FA, login throttling and external login configuration
 type Config struct {
-	AccessPrivateKey  *rsa.PrivateKey
-	AccessExpiration  time.Duration
-	RefreshPrivateKey *rsa.PrivateKey
-	RefreshExpiration time.Duration
+	AccessKeys              *jwtx.Keyring
+	AccessExpiration        time.Duration
+	RefreshKeys             *jwtx.Keyring
+	RefreshExpiration       time.Duration
+	ChallengeExpiration     time.Duration
+	TokenIssuer             string               // iss claim of every token
+	TokenAudience           []string             // aud claim of access tokens
+	TokenLeeway             time.Duration        // Clock skew tolerated when validating tokens
+	TwoFactorIssuer         string               // Shown by authenticator apps
+	AccountLockout          entity.LockoutPolicy // Failed logins per account
+	IPLockout               entity.LockoutPolicy // Failed logins per client IP
+	CaseInsensitiveLogin    bool                 // Match usernames and emails regardless of case
+	ImpersonationExpiration time.Duration        // Lifetime of impersonation tokens, which cannot be refreshed
+	PasswordPolicy          passwd.Policy        // Applies to users whose profile sets none
+	PasswordHasher          output.PasswordHasher
+	Audit                   *audit.Recorder // Records the changes made to users and sessions; nil records nothing
+
+	ExternalProviders       map[string]ExternalProvider // Identity providers by name
+	ExternalLoginExpiration time.Duration               // Time to log in at an identity provider
 }
 
 // authUseCase implements the AuthUseCase interface
 type authUseCase struct {
-	userRepo output.UserRepository
-	config   Config
+	userRepo       output.UserRepository
+	sessionRepo    output.SessionRepository
+	attemptRepo    output.LoginAttemptRepository
+	identityRepo   output.ExternalIdentityRepository
+	revocations    output.RevocationStore
+	throttle       output.LoginThrottle
+	externalLogins output.ExternalLoginStore
+	config         Config
 }
 
 // NewAuthUseCase creates a new AuthUseCase instance
-func NewAuthUseCase(userRepo output.UserRepository, config Config) input.AuthUseCase {
+func NewAuthUseCase(
+	userRepo output.UserRepository,
+	sessionRepo output.SessionRepository,
+	attemptRepo output.LoginAttemptRepository,
+	identityRepo output.ExternalIdentityRepository,
+	revocations output.RevocationStore,
+	throttle output.LoginThrottle,
+	externalLogins output.ExternalLoginStore,
+	config Config,
+) input.AuthUseCase {
 	return &authUseCase{
-		userRepo: userRepo,
-		config:   config,
+		userRepo:       userRepo,
+		sessionRepo:    sessionRepo,
+		attemptRepo:    attemptRepo,
+		identityRepo:   identityRepo,
+		revocations:    revocations,
+		throttle:       throttle,
+		externalLogins: externalLogins,
+		config:         config,
 	}
 }
 
-// Login authenticates a user and returns tokens
+// Login authenticates a user, opens a new session and returns its tokens.
+// Failed attempts are throttled per account and per IP before any password is checked.
 func (uc *authUseCase) Login(ctx context.Context, input *dto.LoginInput) (*dto.AuthOutput, error) {
-	user, err := uc.userRepo.FindByUsername(ctx, input.Login)
+	user, err := uc.userRepo.FindByLogin(ctx, strings.TrimSpace(input.Login), uc.config.CaseInsensitiveLogin)
 	if err != nil {
-		return nil, apperror.UserNotFound()
+		user = nil
 	}
 
-	if !user.ValidatePassword(input.Password) {
-		return nil, apperror.InvalidCredentials()
+	attempt := func(reason entity.LoginFailureReason) *entity.LoginAttempt {
+		return entity.NewLoginAttempt(userID(user), input.Login, input.IP, input.UserAgent, reason)
+	}
+
+	accountKey := entity.LoginThrottleKey(input.Login)
+	if user != nil {
+		accountKey = entity.AccountThrottleKey(user.ID)
+	}
+
+	throttles := uc.throttles(accountKey, input.IP)
+	if err := uc.checkThrottles(ctx, throttles); err != nil {
+		uc.recordAttempt(ctx, attempt(entity.LoginFailureLocked))
+		return nil, err
+	}
+
+	// Unknown users get the answer, and the delay, of a wrong password, so logins do not reveal which accounts exist
+	if user == nil {
+		entity.CompareDummyPassword(input.Password, uc.config.PasswordHasher)
+		return nil, uc.loginFailed(ctx, throttles, attempt(entity.LoginFailureUnknownUser), apperror.InvalidCredentials())
+	}
+
+	if !user.ValidatePassword(input.Password, uc.config.PasswordHasher) {
+		return nil, uc.loginFailed(ctx, throttles, attempt(entity.LoginFailureInvalidPassword), apperror.InvalidCredentials())
 	}
 
 	if user.Auth == nil || !user.Auth.Status || user.Auth.Password == nil {
+		uc.recordAttempt(ctx, attempt(entity.LoginFailureDisabledUser))
 		return nil, apperror.DisabledUser()
 	}
 
-	return uc.generateAuthOutput(user, input.Expiration)
+	if user.EmailVerificationRequired() {
+		uc.recordAttempt(ctx, attempt(entity.LoginFailureUnverifiedEmail))
+		return nil, apperror.EmailNotVerified()
+	}
+
+	// The password is only known now: upgrade its hash if the hashing configuration changed
+	if rehashed, err := user.Auth.RehashPassword(input.Password, uc.config.PasswordHasher); err != nil {
+		return nil, err
+	} else if rehashed {
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	if user.Auth.PasswordExpired(user.PasswordPolicy(uc.config.PasswordPolicy).MaxAge()) {
+		// No session is opened until the password is changed and the user logs in again
+		token, err := uc.challengeToken(user, passwordChangeTokenType, input.Expiration)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.AuthOutput{PasswordChangeRequired: true, ChallengeToken: token}, nil
+	}
+
+	if user.Auth.TOTPEnabled || user.Auth.TwoFactorRequired() {
+		// The attempt only succeeds, and the account counter is only reset, once the code is verified
+		return uc.twoFactorChallenge(ctx, user, input.Expiration)
+	}
+
+	if err := uc.loginSucceeded(ctx, attempt("")); err != nil {
+		return nil, err
+	}
+
+	return uc.openSession(ctx, user, input.UserAgent, input.IP, input.Expiration)
 }
 
-// Refresh refreshes the authentication tokens
-func (uc *authUseCase) Refresh(ctx context.Context, userID uint, expiration bool) (*dto.AuthOutput, error) {
+// VerifyTwoFactor completes a login started with Login using a TOTP or recovery code.
+// When 2FA was being enrolled during the login, the code confirms it and recovery codes are returned.
+func (uc *authUseCase) VerifyTwoFactor(ctx context.Context, input *dto.TwoFactorInput) (*dto.AuthOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	userID, expiration, err := uc.parseChallengeToken(input.ChallengeToken, challengeTokenType)
+	if err != nil {
+		return nil, err
+	}
+
 	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil || user.Auth == nil {
+		return nil, apperror.InvalidCredentials()
+	}
+
+	attempt := func(reason entity.LoginFailureReason) *entity.LoginAttempt {
+		return entity.NewLoginAttempt(&user.ID, user.Username, input.IP, input.UserAgent, reason)
+	}
+
+	throttles := uc.throttles(entity.AccountThrottleKey(user.ID), input.IP)
+	if err := uc.checkThrottles(ctx, throttles); err != nil {
+		uc.recordAttempt(ctx, attempt(entity.LoginFailureLocked))
+		return nil, err
+	}
+
+	if !user.Auth.Status {
+		uc.recordAttempt(ctx, attempt(entity.LoginFailureDisabledUser))
+		return nil, apperror.DisabledUser()
+	}
+
+	var recoveryCodes []string
+	if user.Auth.HasPendingTOTP() {
+		if recoveryCodes, err = user.Auth.ConfirmTOTP(input.Code); err != nil {
+			return nil, uc.loginFailed(ctx, throttles, attempt(entity.LoginFailureInvalidCode), apperror.InvalidCredentials())
+		}
+	} else if !user.Auth.VerifyTwoFactor(input.Code) {
+		return nil, uc.loginFailed(ctx, throttles, attempt(entity.LoginFailureInvalidCode), apperror.InvalidCredentials())
+	}
+
+	// Persist the used step or recovery code so it cannot be replayed
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := uc.loginSucceeded(ctx, attempt("")); err != nil {
+		return nil, err
+	}
+
+	output, err := uc.openSession(ctx, user, input.UserAgent, input.IP, expiration)
+	if err != nil {
+		return nil, err
+	}
+
+	output.RecoveryCodes = recoveryCodes
+	return output, nil
+}
+
+// ChangeExpiredPassword replaces an expired password using the challenge token returned by Login.
+// The user then logs in with the new password, so the second factor is still required.
+func (uc *authUseCase) ChangeExpiredPassword(ctx context.Context, input *dto.ExpiredPasswordInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	userID, _, err := uc.parseChallengeToken(input.ChallengeToken, passwordChangeTokenType)
 	if err != nil {
+		return err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil || user.Auth == nil || !user.Auth.Status {
+		return apperror.InvalidCredentials()
+	}
+	before := user.AuditState()
+
+	// The expired password must be replaced, whatever the history the policy keeps
+	policy := user.PasswordPolicy(uc.config.PasswordPolicy)
+	policy.History = max(policy.History, 1)
+	if err := user.ChangePassword(input.Password, policy, uc.config.PasswordHasher); err != nil {
+		return err
+	}
+
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	// The challenge token was issued to the user
+	return uc.config.Audit.User(actor.WithUser(ctx, &user.ID, nil), entity.AuditActionChangePassword, user, before, user.AuditState())
+}
+
+// SetupTwoFactor starts the 2FA enrollment of a user
+func (uc *authUseCase) SetupTwoFactor(ctx context.Context, userID uint) (*dto.TwoFactorSetupOutput, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil || user.Auth == nil {
 		return nil, apperror.UserNotFound()
 	}
 
-	return uc.generateAuthOutput(user, expiration)
+	if user.Auth.TOTPEnabled {
+		return nil, apperror.Conflict("two-factor authentication", "already enabled")
+	}
+
+	return uc.enrollTwoFactor(ctx, user)
+}
+
+// ConfirmTwoFactor activates the pending 2FA enrollment of a user and returns the recovery codes
+func (uc *authUseCase) ConfirmTwoFactor(ctx context.Context, userID uint, code string) (*dto.RecoveryCodesOutput, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil || user.Auth == nil {
+		return nil, apperror.UserNotFound()
+	}
+
+	before := user.AuditState()
+	codes, err := user.Auth.ConfirmTOTP(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := uc.config.Audit.User(ctx, entity.AuditActionEnableTwoFactor, user, before, user.AuditState()); err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesOutput{RecoveryCodes: codes}, nil
+}
+
+// DisableTwoFactor turns 2FA off for a user, unless the user's profile requires it
+func (uc *authUseCase) DisableTwoFactor(ctx context.Context, userID uint, code string) error {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil || user.Auth == nil {
+		return apperror.UserNotFound()
+	}
+
+	if user.Auth.TwoFactorRequired() {
+		return apperror.TwoFactorRequired()
+	}
+
+	if !user.Auth.VerifyTwoFactor(code) {
+		return entity.ErrInvalidTwoFactorCode()
+	}
+
+	before := user.AuditState()
+	user.Auth.DisableTOTP()
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	return uc.config.Audit.User(ctx, entity.AuditActionDisableTwoFactor, user, before, user.AuditState())
+}
+
+// Refresh rotates the session's refresh token and returns new tokens.
+// Presenting a refresh token that was already rotated revokes the whole session.
+func (uc *authUseCase) Refresh(ctx context.Context, sessionID, tokenID string, expiration bool) (*dto.AuthOutput, error) {
+	session, err := uc.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil || !session.IsActive() || session.IsImpersonation() {
+		return nil, apperror.InvalidSession()
+	}
+
+	if session.RefreshTokenID != tokenID {
+		return nil, uc.revokeReusedSession(ctx, session)
+	}
+
+	session.Rotate(uc.sessionExpiration(expiration))
+	if err := uc.sessionRepo.Rotate(ctx, session, tokenID); err != nil {
+		// Another request rotated the token first: the same token was used twice
+		return nil, uc.revokeReusedSession(ctx, session)
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, session.UserID)
+	if err != nil {
+		return nil, apperror.UserNotFound()
+	}
+
+	if user.Auth == nil || !user.Auth.Status {
+		return nil, apperror.DisabledUser()
+	}
+
+	return uc.generateAuthOutput(user, session, expiration)
 }
 
 // Me returns the current authenticated user information
@@ -75,15 +350,227 @@ func (uc *authUseCase) Me(ctx context.Context, userID uint) (*dto.UserOutput, er
 	return dto.EntityToUserOutput(user), nil
 }
 
-// generateAuthOutput creates authentication output with tokens
-func (uc *authUseCase) generateAuthOutput(user *entity.User, expiration bool) (*dto.AuthOutput, error) {
-	// Generate new token if not exists
-	if user.Auth.Token == nil {
-		token := uuid.New().String()
-		user.Auth.SetToken(token)
+// Logout revokes a single session
+func (uc *authUseCase) Logout(ctx context.Context, sessionID string) error {
+	session, err := uc.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return apperror.InvalidSession()
+	}
+
+	before := session.AuditState()
+	if err := uc.revokeSession(ctx, session); err != nil {
+		return err
+	}
+	session.Revoke()
+	return uc.config.Audit.Session(ctx, entity.AuditActionRevokeSession, session, before, session.AuditState())
+}
+
+// LogoutAll revokes every session of a user
+func (uc *authUseCase) LogoutAll(ctx context.Context, userID uint) error {
+	if err := uc.sessionRepo.RevokeByUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := uc.revocations.RevokeUser(ctx, userID); err != nil {
+		return err
+	}
+	// Users sign themselves out, within their own organization
+	return uc.config.Audit.Record(ctx, entity.NewAuditRecord(entity.AuditActionRevokeAllSessions, entity.AuditEntityUser, entity.AuditEntityID(userID), nil, nil, nil))
+}
+
+// revokeSession revokes the session in the repository and in the revocation store
+func (uc *authUseCase) revokeSession(ctx context.Context, session *entity.Session) error {
+	if err := uc.sessionRepo.Revoke(ctx, session.ID); err != nil {
+		return err
+	}
+	return uc.revocations.RevokeSession(ctx, session.ID, session.RemainingLifetime())
+}
+
+// revokeReusedSession revokes a session whose refresh token was replayed
+func (uc *authUseCase) revokeReusedSession(ctx context.Context, session *entity.Session) error {
+	if err := uc.revokeSession(ctx, session); err != nil {
+		return err
+	}
+	return apperror.RefreshTokenReused()
+}
+
+// throttle is a failed logins counter and the policy applied to it
+type throttle struct {
+	key    string
+	policy entity.LockoutPolicy
+}
+
+// throttles returns the counters that apply to a login of an account from an IP
+func (uc *authUseCase) throttles(accountKey, ip string) []throttle {
+	throttles := []throttle{{key: accountKey, policy: uc.config.AccountLockout}}
+	if ip != "" {
+		throttles = append(throttles, throttle{key: entity.IPThrottleKey(ip), policy: uc.config.IPLockout})
+	}
+	return throttles
+}
+
+// checkThrottles refuses the login while any of its counters is backing off or locked out
+func (uc *authUseCase) checkThrottles(ctx context.Context, throttles []throttle) error {
+	var blockedUntil time.Time
+	for _, t := range throttles {
+		failures, err := uc.throttle.Failures(ctx, t.key)
+		if err != nil {
+			return err
+		}
+		if until := failures.BlockedUntil(t.policy); until.After(blockedUntil) {
+			blockedUntil = until
+		}
+	}
+
+	if wait := time.Until(blockedUntil); wait > 0 {
+		return apperror.TooManyAttempts(wait)
+	}
+	return nil
+}
+
+// loginFailed counts a failed attempt on every counter of the login, records it and returns cause
+func (uc *authUseCase) loginFailed(ctx context.Context, throttles []throttle, attempt *entity.LoginAttempt, cause error) error {
+	uc.recordAttempt(ctx, attempt)
+	for _, t := range throttles {
+		if _, err := uc.throttle.AddFailure(ctx, t.key, t.policy.Lockout); err != nil {
+			return err
+		}
+	}
+	return cause
+}
+
+// loginSucceeded records a successful attempt and clears the failures of the account.
+// The IP counter is kept, so a valid account does not reset the limit of a client guessing others.
+func (uc *authUseCase) loginSucceeded(ctx context.Context, attempt *entity.LoginAttempt) error {
+	uc.recordAttempt(ctx, attempt)
+	return uc.throttle.Reset(ctx, entity.AccountThrottleKey(*attempt.UserID))
+}
+
+// recordAttempt adds an attempt to the audit trail; failing to record it never blocks a login
+func (uc *authUseCase) recordAttempt(ctx context.Context, attempt *entity.LoginAttempt) {
+	_ = uc.attemptRepo.Create(ctx, attempt)
+}
+
+// userID returns the ID of a user, or nil when there is no user
+func userID(user *entity.User) *uint {
+	if user == nil {
+		return nil
+	}
+	return &user.ID
+}
+
+// openSession creates a new session for the user and returns its tokens
+func (uc *authUseCase) openSession(ctx context.Context, user *entity.User, userAgent, ip string, expiration bool) (*dto.AuthOutput, error) {
+	session := entity.NewSession(user.ID, userAgent, ip, uc.sessionExpiration(expiration))
+	if err := uc.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return uc.generateAuthOutput(user, session, expiration)
+}
+
+// twoFactorChallenge returns a challenge token to be exchanged with VerifyTwoFactor.
+// Users that must use 2FA but never enrolled get the enrollment data as well.
+func (uc *authUseCase) twoFactorChallenge(ctx context.Context, user *entity.User, expiration bool) (*dto.AuthOutput, error) {
+	output := &dto.AuthOutput{TwoFactorRequired: true}
+
+	if !user.Auth.TOTPEnabled {
+		setup, err := uc.enrollTwoFactor(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		output.TwoFactorSetup = setup
+	}
+
+	token, err := uc.challengeToken(user, challengeTokenType, expiration)
+	if err != nil {
+		return nil, err
+	}
+
+	output.ChallengeToken = token
+	return output, nil
+}
+
+// challengeToken issues a short-lived token of tokenType to continue the login of user
+func (uc *authUseCase) challengeToken(user *entity.User, tokenType string, expiration bool) (string, error) {
+	return uc.generateToken(jwt.MapClaims{
+		"sub":        subject(user.ID),
+		"aud":        jwt.ClaimStrings{uc.config.TokenIssuer},
+		"typ":        tokenType,
+		"expiration": expiration,
+	}, uc.config.AccessKeys, &uc.config.ChallengeExpiration)
+}
+
+// enrollTwoFactor stores a new pending TOTP secret and returns the data to register it
+func (uc *authUseCase) enrollTwoFactor(ctx context.Context, user *entity.User) (*dto.TwoFactorSetupOutput, error) {
+	secret, err := user.Auth.EnrollTOTP()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	uri := totp.URI(uc.config.TwoFactorIssuer, user.Email, secret)
+	qrCode, err := totp.QRCode(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorSetupOutput{Secret: secret, URI: uri, QRCode: qrCode}, nil
+}
+
+// parseChallengeToken validates a challenge token of tokenType and returns its user ID and session expiration flag
+func (uc *authUseCase) parseChallengeToken(token, tokenType string) (uint, bool, error) {
+	claims, err := uc.parseToken(token, uc.config.AccessKeys, uc.config.TokenIssuer, tokenType)
+	if err != nil {
+		return 0, false, apperror.Unauthorized("invalid or expired challenge token")
 	}
 
-	accessToken, err := uc.generateToken(user, uc.config.AccessPrivateKey, func() *time.Duration {
+	subject, _ := claims["sub"].(string)
+	userID, err := strconv.ParseUint(subject, 10, 64)
+	if err != nil {
+		return 0, false, apperror.Unauthorized("invalid or expired challenge token")
+	}
+
+	expiration, _ := claims["expiration"].(bool)
+	return uint(userID), expiration, nil
+}
+
+// parseToken validates a token issued by the API for audience and returns its claims
+func (uc *authUseCase) parseToken(token string, keys *jwtx.Keyring, audience, tokenType string) (jwt.MapClaims, error) {
+	parsed, err := jwt.Parse(token, keys.Keyfunc,
+		jwt.WithValidMethods(jwtx.ValidMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer(uc.config.TokenIssuer),
+		jwt.WithAudience(audience),
+		jwt.WithLeeway(uc.config.TokenLeeway),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] != tokenType {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+	return claims, nil
+}
+
+// sessionExpiration returns the session expiration, or nil for non-expiring sessions
+func (uc *authUseCase) sessionExpiration(expiration bool) *time.Time {
+	if !expiration {
+		return nil
+	}
+	expiresAt := time.Now().Add(uc.config.RefreshExpiration)
+	return &expiresAt
+}
+
+// generateAuthOutput creates authentication output with tokens bound to the session.
+// Access tokens are self-contained, so other services can authorize requests without calling the API.
+// Tokens of OAuth clients carry the granted scopes instead of the profile's permissions.
+func (uc *authUseCase) generateAuthOutput(user *entity.User, session *entity.Session, expiration bool) (*dto.AuthOutput, error) {
+	accessToken, err := uc.generateToken(uc.accessClaims(user, session), uc.config.AccessKeys, func() *time.Duration {
 		if expiration {
 			return &uc.config.AccessExpiration
 		}
@@ -93,7 +580,14 @@ func (uc *authUseCase) generateAuthOutput(user *entity.User, expiration bool) (*
 		return nil, err
 	}
 
-	refreshToken, err := uc.generateToken(user, uc.config.RefreshPrivateKey, func() *time.Duration {
+	// Refresh tokens are only accepted by the API itself
+	refreshToken, err := uc.generateToken(withOrganization(jwt.MapClaims{
+		"sub": subject(user.ID),
+		"aud": jwt.ClaimStrings{uc.config.TokenIssuer},
+		"jti": session.RefreshTokenID,
+		"typ": jwtx.TypeRefresh,
+		"sid": session.ID,
+	}, user), uc.config.RefreshKeys, func() *time.Duration {
 		if expiration {
 			return &uc.config.RefreshExpiration
 		}
@@ -110,17 +604,50 @@ func (uc *authUseCase) generateAuthOutput(user *entity.User, expiration bool) (*
 	}, nil
 }
 
-// generateToken generates a JWT token
-func (uc *authUseCase) generateToken(user *entity.User, privateKey *rsa.PrivateKey, expire *time.Duration) (string, error) {
-	now := time.Now()
+// accessClaims returns the claims of an access token of user bound to session
+func (uc *authUseCase) accessClaims(user *entity.User, session *entity.Session) jwt.MapClaims {
 	claims := jwt.MapClaims{
-		"token": user.Auth.Token,
-		"iat":   now.Unix(),
+		"sub": subject(user.ID),
+		"aud": jwt.ClaimStrings(uc.config.TokenAudience),
+		"jti": uuid.New().String(),
+		"typ": jwtx.TypeAccess,
+		"sid": session.ID,
 	}
+	if session.IssuedToClient() {
+		claims["aud"] = append(jwt.ClaimStrings(slices.Clone(uc.config.TokenAudience)), session.ClientID)
+		claims["client_id"] = session.ClientID
+		claims["scope"] = strings.Join(session.Scopes, " ")
+	} else if user.Auth != nil && user.Auth.Profile != nil {
+		claims["profile"] = user.Auth.Profile.Name
+		claims["permissions"] = user.Auth.Profile.Permissions
+	}
+	return withOrganization(claims, user)
+}
+
+// withOrganization adds the org claim, the organization of the user, which scopes every
+// request made with the token; tokens of super-admins carry none
+func withOrganization(claims jwt.MapClaims, user *entity.User) jwt.MapClaims {
+	if user.OrganizationID != nil {
+		claims["org"] = *user.OrganizationID
+	}
+	return claims
+}
+
+// generateToken generates a JWT token signed with the active key of the keyring
+func (uc *authUseCase) generateToken(claims jwt.MapClaims, keys *jwtx.Keyring, expire *time.Duration) (string, error) {
+	now := time.Now()
+	claims["iss"] = uc.config.TokenIssuer
+	claims["iat"] = now.Unix()
+	claims["nbf"] = now.Unix()
 
 	if expire != nil {
 		claims["exp"] = now.Add(*expire).Unix()
 	}
 
-	return jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(privateKey)
+	return keys.Sign(claims)
+}
+
+// subject returns the sub claim identifying a user
+func subject(userID uint) string {
+	return strconv.FormatUint(uint64(userID), 10)
 }
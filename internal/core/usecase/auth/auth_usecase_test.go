@@ -0,0 +1,529 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/storage/memory"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/output/outputtest"
+	"github.com/raulaguila/go-api/internal/core/usecase/auth"
+	"github.com/raulaguila/go-api/pkg/apperror"
+	"github.com/raulaguila/go-api/pkg/jwtx"
+	"github.com/raulaguila/go-api/pkg/passwd"
+	"github.com/raulaguila/go-api/pkg/totp"
+)
+
+// fakeAttemptRepo implements output.LoginAttemptRepository keeping attempts in memory
+type fakeAttemptRepo struct {
+	attempts []*entity.LoginAttempt
+}
+
+func (r *fakeAttemptRepo) Create(_ context.Context, attempt *entity.LoginAttempt) error {
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+func (r *fakeAttemptRepo) reasons() []entity.LoginFailureReason {
+	reasons := make([]entity.LoginFailureReason, len(r.attempts))
+	for i, a := range r.attempts {
+		reasons[i] = a.Reason
+	}
+	return reasons
+}
+
+func newTestConfig(t *testing.T) auth.Config {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return auth.Config{
+		AccessKeys:          jwtx.NewKeyring(key),
+		AccessExpiration:    time.Minute,
+		RefreshKeys:         jwtx.NewKeyring(key),
+		RefreshExpiration:   time.Hour,
+		ChallengeExpiration: time.Minute,
+		TokenIssuer:         "go-api",
+		TokenAudience:       []string{"go-api", "reports"},
+		TokenLeeway:         time.Second,
+		TwoFactorIssuer:     "API",
+		AccountLockout:      entity.LockoutPolicy{MaxFailures: 3, Lockout: time.Minute},
+		IPLockout:           entity.LockoutPolicy{MaxFailures: 5, Lockout: time.Minute},
+		PasswordHasher:      testHasher,
+	}
+}
+
+// testHasher hashes passwords with the default parameters
+var testHasher = passwd.NewHasher(passwd.DefaultArgon2id, passwd.DefaultBcrypt)
+
+func newTestUser(t *testing.T) *entity.User {
+	a, err := entity.NewAuth(1, true)
+	require.NoError(t, err)
+	require.NoError(t, a.SetPassword("12345678", testHasher))
+	u, err := entity.NewUser("John Doe", "johndoe", "john@example.com", a)
+	require.NoError(t, err)
+	u.ID = 7
+	return u
+}
+
+func TestLogin_CreatesSession(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	userRepo.On("FindByLogin", ctx, "johndoe", false).Return(newTestUser(t), nil)
+	sessionRepo.On("Create", ctx, mock.MatchedBy(func(s *entity.Session) bool {
+		return s.UserID == 7 && s.UserAgent == "test-agent" && s.ExpiresAt != nil
+	})).Return(nil)
+
+	out, err := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "12345678", Expiration: true, UserAgent: "test-agent"})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, out.AccessToken)
+	assert.NotEmpty(t, out.RefreshToken)
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestLogin_UpgradesOutdatedHash(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	u := newTestUser(t)
+	require.NoError(t, u.SetPassword("12345678", passwd.Bcrypt{Cost: 4}))
+	changedAt := *u.Auth.PasswordChangedAt
+
+	userRepo.On("FindByLogin", ctx, "johndoe", false).Return(u, nil)
+	userRepo.On("Update", ctx, u).Return(nil).Once()
+	sessionRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	_, err := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "12345678"})
+	require.NoError(t, err)
+	assert.True(t, passwd.DefaultArgon2id.Identifies(*u.Auth.Password), "the bcrypt hash must be replaced")
+	assert.Equal(t, changedAt, *u.Auth.PasswordChangedAt, "a rehash is not a password change")
+
+	_, err = uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "12345678"})
+	require.NoError(t, err)
+	userRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestLogin_WithEmail(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	cfg := newTestConfig(t)
+	cfg.CaseInsensitiveLogin = true
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), cfg)
+	ctx := context.Background()
+
+	userRepo.On("FindByLogin", ctx, "John@Example.com", true).Return(newTestUser(t), nil)
+	sessionRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	out, err := uc.Login(ctx, &dto.LoginInput{Login: " John@Example.com ", Password: "12345678"})
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), *out.User.ID)
+}
+
+func TestLogin_UnknownUserLooksLikeWrongPassword(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	userRepo.On("FindByLogin", ctx, "johndoe", false).Return(newTestUser(t), nil)
+	userRepo.On("FindByLogin", ctx, "nobody", false).Return(nil, gorm.ErrRecordNotFound)
+
+	_, wrongPassword := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "wrong", IP: "10.0.0.1"})
+	_, unknownUser := uc.Login(ctx, &dto.LoginInput{Login: "nobody", Password: "wrong", IP: "10.0.0.2"})
+
+	assert.True(t, apperror.IsCode(unknownUser, apperror.CodeInvalidCredentials))
+	assert.Equal(t, wrongPassword, unknownUser)
+}
+
+func TestLogin_AccessTokenClaims(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	cfg := newTestConfig(t)
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), cfg)
+	ctx := context.Background()
+
+	u := newTestUser(t)
+	u.Auth.Profile = &entity.Profile{ID: 1, Name: "ADMIN", Permissions: []string{"users:read"}}
+	userRepo.On("FindByLogin", ctx, "johndoe", false).Return(u, nil)
+	sessionRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	out, err := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "12345678", Expiration: true})
+	require.NoError(t, err)
+
+	// Verified the way a downstream service would: offline, with the published keys
+	parsed, err := jwt.Parse(out.AccessToken, cfg.AccessKeys.Keyfunc,
+		jwt.WithValidMethods(jwtx.ValidMethods),
+		jwt.WithIssuer("go-api"),
+		jwt.WithAudience("reports"),
+		jwt.WithExpirationRequired(),
+	)
+	require.NoError(t, err)
+	claims := parsed.Claims.(jwt.MapClaims)
+
+	assert.Equal(t, "7", claims["sub"])
+	assert.Equal(t, jwtx.TypeAccess, claims["typ"])
+	assert.NotEmpty(t, claims["sid"])
+	assert.NotEmpty(t, claims["jti"])
+	assert.NotNil(t, claims["nbf"])
+	assert.Equal(t, "ADMIN", claims["profile"])
+	assert.Equal(t, []any{"users:read"}, claims["permissions"])
+
+	_, err = jwt.Parse(out.RefreshToken, cfg.RefreshKeys.Keyfunc, jwt.WithAudience("reports"))
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience, "refresh tokens are only meant for the API")
+}
+
+func TestRefresh_RotatesToken(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	session := entity.NewSession(7, "", "", nil)
+	current := session.RefreshTokenID
+
+	sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+	sessionRepo.On("Rotate", ctx, session, current).Return(nil)
+	userRepo.On("FindByID", ctx, uint(7)).Return(newTestUser(t), nil)
+
+	out, err := uc.Refresh(ctx, session.ID, current, true)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, out.RefreshToken)
+	assert.NotEqual(t, current, session.RefreshTokenID)
+	sessionRepo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything)
+}
+
+func TestRefresh_ReusedTokenRevokesSession(t *testing.T) {
+	userRepo, sessionRepo, revocations := new(outputtest.UserRepository), new(outputtest.SessionRepository), memory.NewRevocationStore()
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, revocations, memory.NewLoginThrottle(), memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	session := entity.NewSession(7, "", "", nil)
+
+	sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+	sessionRepo.On("Revoke", ctx, session.ID).Return(nil)
+
+	out, err := uc.Refresh(ctx, session.ID, "already-rotated-token-id", true)
+
+	assert.Nil(t, out)
+	assert.True(t, apperror.IsCode(err, apperror.CodeRefreshTokenReused))
+	sessionRepo.AssertCalled(t, "Revoke", ctx, session.ID)
+	sessionRepo.AssertNotCalled(t, "Rotate", mock.Anything, mock.Anything, mock.Anything)
+
+	revoked, err := revocations.IsSessionRevoked(ctx, session.ID)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestRefresh_ConcurrentRotationRevokesSession(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	session := entity.NewSession(7, "", "", nil)
+	current := session.RefreshTokenID
+
+	sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+	sessionRepo.On("Rotate", ctx, session, current).Return(gorm.ErrRecordNotFound)
+	sessionRepo.On("Revoke", ctx, session.ID).Return(nil)
+
+	_, err := uc.Refresh(ctx, session.ID, current, true)
+
+	assert.True(t, apperror.IsCode(err, apperror.CodeRefreshTokenReused))
+	sessionRepo.AssertCalled(t, "Revoke", ctx, session.ID)
+}
+
+func TestLogout_RevokesSession(t *testing.T) {
+	userRepo, sessionRepo, revocations := new(outputtest.UserRepository), new(outputtest.SessionRepository), memory.NewRevocationStore()
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, revocations, memory.NewLoginThrottle(), memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(time.Hour)
+	session := entity.NewSession(7, "", "", &expiresAt)
+
+	sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+	sessionRepo.On("Revoke", ctx, session.ID).Return(nil)
+
+	assert.NoError(t, uc.Logout(ctx, session.ID))
+
+	revoked, err := revocations.IsSessionRevoked(ctx, session.ID)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestLogoutAll_RevokesUserSessions(t *testing.T) {
+	userRepo, sessionRepo, revocations := new(outputtest.UserRepository), new(outputtest.SessionRepository), memory.NewRevocationStore()
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, revocations, memory.NewLoginThrottle(), memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	sessionRepo.On("RevokeByUser", ctx, uint(7)).Return(nil)
+
+	assert.NoError(t, uc.LogoutAll(ctx, 7))
+
+	revokedAt, err := revocations.UserRevokedAt(ctx, 7)
+	assert.NoError(t, err)
+	assert.NotNil(t, revokedAt)
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestLogin_TwoFactorChallenge(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	u := newTestUser(t)
+	secret, err := u.Auth.EnrollTOTP()
+	require.NoError(t, err)
+	code, _ := totp.Code(secret, totp.Step(time.Now())-1)
+	_, err = u.Auth.ConfirmTOTP(code)
+	require.NoError(t, err)
+
+	userRepo.On("FindByLogin", ctx, "johndoe", false).Return(u, nil)
+	userRepo.On("FindByID", ctx, uint(7)).Return(u, nil)
+	userRepo.On("Update", ctx, u).Return(nil)
+	sessionRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	challenge, err := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "12345678"})
+	require.NoError(t, err)
+	assert.True(t, challenge.TwoFactorRequired)
+	assert.NotEmpty(t, challenge.ChallengeToken)
+	assert.Empty(t, challenge.AccessToken)
+	sessionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	_, err = uc.VerifyTwoFactor(ctx, &dto.TwoFactorInput{ChallengeToken: challenge.ChallengeToken, Code: "000000"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidCredentials))
+
+	code, _ = totp.Code(secret, totp.Step(time.Now()))
+	out, err := uc.VerifyTwoFactor(ctx, &dto.TwoFactorInput{ChallengeToken: challenge.ChallengeToken, Code: code})
+	require.NoError(t, err)
+	assert.NotEmpty(t, out.AccessToken)
+	assert.NotEmpty(t, out.RefreshToken)
+
+	_, err = uc.VerifyTwoFactor(ctx, &dto.TwoFactorInput{ChallengeToken: challenge.ChallengeToken, Code: code})
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidCredentials), "a TOTP code must not be replayed")
+}
+
+func TestLogin_MandatoryTwoFactorEnrollsOnLogin(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	u := newTestUser(t)
+	u.Auth.Profile = &entity.Profile{ID: 2, Name: "ADMIN", RequireTwoFactor: true}
+
+	userRepo.On("FindByLogin", ctx, "johndoe", false).Return(u, nil)
+	userRepo.On("FindByID", ctx, uint(7)).Return(u, nil)
+	userRepo.On("Update", ctx, u).Return(nil)
+	sessionRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	challenge, err := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "12345678"})
+	require.NoError(t, err)
+	require.NotNil(t, challenge.TwoFactorSetup)
+	assert.Contains(t, challenge.TwoFactorSetup.URI, "otpauth://totp/API:")
+	assert.Contains(t, challenge.TwoFactorSetup.QRCode, "data:image/png;base64,")
+
+	code, _ := totp.Code(challenge.TwoFactorSetup.Secret, totp.Step(time.Now()))
+	out, err := uc.VerifyTwoFactor(ctx, &dto.TwoFactorInput{ChallengeToken: challenge.ChallengeToken, Code: code})
+	require.NoError(t, err)
+	assert.NotEmpty(t, out.AccessToken)
+	assert.Len(t, out.RecoveryCodes, 10)
+	assert.True(t, u.Auth.TOTPEnabled)
+
+	err = uc.DisableTwoFactor(ctx, u.ID, out.RecoveryCodes[0])
+	assert.True(t, apperror.IsCode(err, apperror.CodeTwoFactorRequired), "profiles requiring 2FA cannot disable it")
+}
+
+func TestLogin_ProfileRequiresVerifiedEmail(t *testing.T) {
+	userRepo, sessionRepo, attempts := new(outputtest.UserRepository), new(outputtest.SessionRepository), &fakeAttemptRepo{}
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, attempts, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	u := newTestUser(t)
+	u.Auth.Profile = &entity.Profile{ID: 2, Name: "STAFF", RequireVerifiedEmail: true}
+
+	userRepo.On("FindByLogin", ctx, "johndoe", false).Return(u, nil)
+	sessionRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	_, err := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "12345678"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeEmailNotVerified))
+	assert.Equal(t, []entity.LoginFailureReason{entity.LoginFailureUnverifiedEmail}, attempts.reasons())
+	sessionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	u.MarkEmailVerified()
+	out, err := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "12345678"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, out.AccessToken)
+}
+
+func TestLogin_ExpiredPasswordMustBeChanged(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	cfg := newTestConfig(t)
+	cfg.PasswordPolicy = passwd.Policy{MaxAgeDays: 90}
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), cfg)
+	ctx := context.Background()
+
+	u := newTestUser(t)
+	changedAt := time.Now().AddDate(0, 0, -91)
+	u.Auth.PasswordChangedAt = &changedAt
+
+	userRepo.On("FindByLogin", ctx, "johndoe", false).Return(u, nil)
+	userRepo.On("FindByID", ctx, uint(7)).Return(u, nil)
+	userRepo.On("Update", ctx, u).Return(nil)
+	sessionRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	challenge, err := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "12345678"})
+	require.NoError(t, err)
+	assert.True(t, challenge.PasswordChangeRequired)
+	assert.Empty(t, challenge.AccessToken)
+	sessionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	_, err = uc.VerifyTwoFactor(ctx, &dto.TwoFactorInput{ChallengeToken: challenge.ChallengeToken, Code: "000000"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeUnauthorized), "a password change token must not complete a 2FA login")
+
+	err = uc.ChangeExpiredPassword(ctx, &dto.ExpiredPasswordInput{ChallengeToken: challenge.ChallengeToken, Password: "12345678", PasswordConfirm: "12345678"})
+	assert.Error(t, err, "the expired password must be replaced")
+
+	err = uc.ChangeExpiredPassword(ctx, &dto.ExpiredPasswordInput{ChallengeToken: challenge.ChallengeToken, Password: "n3w-passw0rd", PasswordConfirm: "n3w-passw0rd"})
+	require.NoError(t, err)
+
+	out, err := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "n3w-passw0rd"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, out.AccessToken)
+}
+
+func TestLogin_LocksAccountAfterMaxFailures(t *testing.T) {
+	userRepo, sessionRepo, attempts := new(outputtest.UserRepository), new(outputtest.SessionRepository), &fakeAttemptRepo{}
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, attempts, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	userRepo.On("FindByLogin", ctx, "johndoe", false).Return(newTestUser(t), nil)
+
+	for i, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		_, err := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "wrong", IP: ip})
+		assert.True(t, apperror.IsCode(err, apperror.CodeInvalidCredentials), "attempt %d", i+1)
+	}
+
+	_, err := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "12345678", IP: "10.0.0.4"})
+	require.True(t, apperror.IsCode(err, apperror.CodeTooManyAttempts), "the account must be locked from any IP")
+	var appErr *apperror.Error
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 60, appErr.Details["retry_after"])
+	sessionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	assert.Equal(t, []entity.LoginFailureReason{
+		entity.LoginFailureInvalidPassword,
+		entity.LoginFailureInvalidPassword,
+		entity.LoginFailureInvalidPassword,
+		entity.LoginFailureLocked,
+	}, attempts.reasons())
+	assert.Equal(t, uint(7), *attempts.attempts[0].UserID)
+}
+
+func TestLogin_BacksOffBetweenFailures(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	cfg := newTestConfig(t)
+	cfg.AccountLockout.Backoff = 10 * time.Second
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), cfg)
+	ctx := context.Background()
+
+	userRepo.On("FindByLogin", ctx, "johndoe", false).Return(newTestUser(t), nil)
+
+	_, err := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "wrong"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidCredentials))
+
+	_, err = uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "12345678"})
+	require.True(t, apperror.IsCode(err, apperror.CodeTooManyAttempts))
+	var appErr *apperror.Error
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 10, appErr.Details["retry_after"])
+}
+
+func TestLogin_SuccessResetsAccountButNotIP(t *testing.T) {
+	userRepo, sessionRepo, attempts := new(outputtest.UserRepository), new(outputtest.SessionRepository), &fakeAttemptRepo{}
+	throttle := memory.NewLoginThrottle()
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, attempts, &fakeIdentityRepo{}, memory.NewRevocationStore(), throttle, memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	userRepo.On("FindByLogin", ctx, "johndoe", false).Return(newTestUser(t), nil)
+	sessionRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	for range 2 {
+		_, err := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "wrong", IP: "10.0.0.1"})
+		assert.True(t, apperror.IsCode(err, apperror.CodeInvalidCredentials))
+	}
+
+	_, err := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "12345678", IP: "10.0.0.1"})
+	require.NoError(t, err)
+	assert.True(t, attempts.attempts[2].Success)
+
+	account, err := throttle.Failures(ctx, entity.AccountThrottleKey(7))
+	require.NoError(t, err)
+	assert.Nil(t, account)
+
+	ip, err := throttle.Failures(ctx, entity.IPThrottleKey("10.0.0.1"))
+	require.NoError(t, err)
+	require.NotNil(t, ip)
+	assert.Equal(t, 2, ip.Count)
+}
+
+func TestLogin_LocksIPGuessingUsernames(t *testing.T) {
+	userRepo, sessionRepo, attempts := new(outputtest.UserRepository), new(outputtest.SessionRepository), &fakeAttemptRepo{}
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, attempts, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	userRepo.On("FindByLogin", ctx, mock.Anything, false).Return(nil, gorm.ErrRecordNotFound)
+
+	for i := range 5 {
+		_, err := uc.Login(ctx, &dto.LoginInput{Login: fmt.Sprintf("user%d", i), Password: "wrong", IP: "10.0.0.1"})
+		assert.True(t, apperror.IsCode(err, apperror.CodeInvalidCredentials))
+	}
+
+	_, err := uc.Login(ctx, &dto.LoginInput{Login: "user9", Password: "wrong", IP: "10.0.0.1"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeTooManyAttempts))
+	assert.Nil(t, attempts.attempts[0].UserID)
+	assert.Equal(t, entity.LoginFailureUnknownUser, attempts.attempts[0].Reason)
+
+	_, err = uc.Login(ctx, &dto.LoginInput{Login: "user9", Password: "wrong", IP: "10.0.0.2"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidCredentials), "other IPs are not affected")
+}
+
+func TestVerifyTwoFactor_FailuresLockAccount(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	u := newTestUser(t)
+	secret, err := u.Auth.EnrollTOTP()
+	require.NoError(t, err)
+	code, _ := totp.Code(secret, totp.Step(time.Now())-1)
+	_, err = u.Auth.ConfirmTOTP(code)
+	require.NoError(t, err)
+
+	userRepo.On("FindByLogin", ctx, "johndoe", false).Return(u, nil)
+	userRepo.On("FindByID", ctx, uint(7)).Return(u, nil)
+
+	challenge, err := uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "12345678"})
+	require.NoError(t, err)
+
+	for range 3 {
+		_, err = uc.VerifyTwoFactor(ctx, &dto.TwoFactorInput{ChallengeToken: challenge.ChallengeToken, Code: "000000"})
+		assert.True(t, apperror.IsCode(err, apperror.CodeInvalidCredentials))
+	}
+
+	code, _ = totp.Code(secret, totp.Step(time.Now()))
+	_, err = uc.VerifyTwoFactor(ctx, &dto.TwoFactorInput{ChallengeToken: challenge.ChallengeToken, Code: code})
+	assert.True(t, apperror.IsCode(err, apperror.CodeTooManyAttempts))
+
+	_, err = uc.Login(ctx, &dto.LoginInput{Login: "johndoe", Password: "12345678"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeTooManyAttempts), "a new challenge must not reset the counter")
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/pkg/apperror"
+	"github.com/raulaguila/go-api/pkg/jwtx"
+)
+
+// OpenClientSession opens a session of a user for an OAuth client, once the user
+// authorized it, and returns its tokens. Client sessions always expire.
+func (uc *authUseCase) OpenClientSession(ctx context.Context, input *dto.ClientSessionInput) (*dto.AuthOutput, error) {
+	user, err := uc.userRepo.FindByID(ctx, input.UserID)
+	if err != nil {
+		return nil, apperror.UserNotFound()
+	}
+
+	if user.Auth == nil || !user.Auth.Status {
+		return nil, apperror.DisabledUser()
+	}
+
+	session := entity.NewSession(user.ID, input.UserAgent, input.IP, uc.sessionExpiration(true))
+	session.ClientID = input.ClientID
+	session.Scopes = input.Scopes
+	if err := uc.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return uc.generateAuthOutput(user, session, true)
+}
+
+// RefreshClientSession rotates the refresh token of a session opened for an OAuth client.
+// A client can only use the refresh tokens issued to itself.
+func (uc *authUseCase) RefreshClientSession(ctx context.Context, clientID, refreshToken string) (*dto.AuthOutput, error) {
+	claims, err := uc.parseToken(refreshToken, uc.config.RefreshKeys, uc.config.TokenIssuer, jwtx.TypeRefresh)
+	if err != nil {
+		return nil, apperror.Unauthorized("invalid or expired refresh token")
+	}
+
+	sessionID, _ := claims["sid"].(string)
+	tokenID, _ := claims["jti"].(string)
+
+	session, err := uc.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil || session.ClientID != clientID {
+		return nil, apperror.Unauthorized("invalid or expired refresh token")
+	}
+
+	return uc.Refresh(ctx, session.ID, tokenID, true)
+}
+
+// RevokeClientToken revokes the session of an access or refresh token issued to an OAuth client.
+// Like RFC 7009 requires, tokens that are invalid or issued to another client are ignored.
+func (uc *authUseCase) RevokeClientToken(ctx context.Context, clientID, token string) error {
+	claims, err := uc.parseToken(token, uc.config.RefreshKeys, uc.config.TokenIssuer, jwtx.TypeRefresh)
+	if err != nil {
+		if claims, err = uc.parseToken(token, uc.config.AccessKeys, clientID, jwtx.TypeAccess); err != nil {
+			return nil
+		}
+	}
+
+	sessionID, _ := claims["sid"].(string)
+	session, err := uc.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil || session.ClientID != clientID || session.IsRevoked() {
+		return nil
+	}
+
+	return uc.revokeSession(ctx, session)
+}
@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/pkg/actor"
+	"github.com/raulaguila/go-api/pkg/apperror"
+)
+
+// ExternalProvider is an identity provider users can log in with
+type ExternalProvider struct {
+	IdentityProvider output.IdentityProvider
+	DefaultProfileID uint // Profile of users provisioned on their first login; zero only lets existing users in
+}
+
+// ExternalProviders returns the names of the identity providers users can log in with
+func (uc *authUseCase) ExternalProviders() []string {
+	names := make([]string, 0, len(uc.config.ExternalProviders))
+	for name := range uc.config.ExternalProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BeginExternalLogin starts a login at an external identity provider and returns the URL of its login page
+func (uc *authUseCase) BeginExternalLogin(ctx context.Context, provider string, expiration bool) (*dto.ExternalLoginOutput, error) {
+	idp, ok := uc.config.ExternalProviders[provider]
+	if !ok {
+		return nil, apperror.NotFound("identity provider")
+	}
+
+	login, err := entity.NewExternalLogin(provider, expiration)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := idp.IdentityProvider.AuthCodeURL(ctx, login.State, login.Nonce, login.CodeVerifier)
+	if err != nil {
+		return nil, apperror.Wrap(apperror.CodeExternalService, "identity provider unavailable", err)
+	}
+
+	if err := uc.externalLogins.Save(ctx, login, uc.config.ExternalLoginExpiration); err != nil {
+		return nil, err
+	}
+
+	return &dto.ExternalLoginOutput{URL: url, State: login.State}, nil
+}
+
+// CompleteExternalLogin completes a login started with BeginExternalLogin. The user is found by
+// the identity linked to the provider's account, else by a verified email, else provisioned.
+func (uc *authUseCase) CompleteExternalLogin(ctx context.Context, input *dto.ExternalLoginInput) (*dto.AuthOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	login, err := uc.externalLogins.Take(ctx, input.State)
+	if err != nil {
+		return nil, err
+	}
+	if login == nil || login.Provider != input.Provider {
+		return nil, apperror.ExternalLoginFailed(nil)
+	}
+
+	idp, ok := uc.config.ExternalProviders[login.Provider]
+	if !ok {
+		return nil, apperror.ExternalLoginFailed(nil)
+	}
+
+	claims, err := idp.IdentityProvider.Exchange(ctx, input.Code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		return nil, apperror.ExternalLoginFailed(err)
+	}
+
+	user, err := uc.externalUser(ctx, login.Provider, idp, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := func(reason entity.LoginFailureReason) *entity.LoginAttempt {
+		return entity.NewLoginAttempt(&user.ID, login.Provider+":"+claims.Subject, input.IP, input.UserAgent, reason)
+	}
+
+	if user.Auth == nil || !user.Auth.Status {
+		uc.recordAttempt(ctx, attempt(entity.LoginFailureDisabledUser))
+		return nil, apperror.DisabledUser()
+	}
+
+	if user.EmailVerificationRequired() {
+		uc.recordAttempt(ctx, attempt(entity.LoginFailureUnverifiedEmail))
+		return nil, apperror.EmailNotVerified()
+	}
+
+	// The provider proved the first factor; the second one is still required where it would be for a password login
+	if user.Auth.TOTPEnabled || user.Auth.TwoFactorRequired() {
+		return uc.twoFactorChallenge(ctx, user, login.Expiration)
+	}
+
+	if err := uc.loginSucceeded(ctx, attempt("")); err != nil {
+		return nil, err
+	}
+
+	return uc.openSession(ctx, user, input.UserAgent, input.IP, login.Expiration)
+}
+
+// externalUser returns the user of an external account, linking or provisioning it on its first login
+func (uc *authUseCase) externalUser(ctx context.Context, provider string, idp ExternalProvider, claims *output.ExternalClaims) (*entity.User, error) {
+	if identity, err := uc.identityRepo.FindBySubject(ctx, provider, claims.Subject); err == nil {
+		user, err := uc.userRepo.FindByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, apperror.UserNotFound()
+		}
+		return user, nil
+	}
+
+	// Only a verified email proves the account belongs to the user with that email
+	if claims.Email != "" && claims.EmailVerified {
+		if user, err := uc.userRepo.FindByEmail(ctx, claims.Email); err == nil {
+			if err := uc.linkIdentity(ctx, user, provider, claims); err != nil {
+				return nil, err
+			}
+			return user, nil
+		}
+	}
+
+	if idp.DefaultProfileID == 0 {
+		return nil, apperror.ExternalAccountUnknown()
+	}
+
+	return uc.provisionUser(ctx, provider, idp, claims)
+}
+
+// provisionUser creates the user of an external account in the provider's default profile
+func (uc *authUseCase) provisionUser(ctx context.Context, provider string, idp ExternalProvider, claims *output.ExternalClaims) (*entity.User, error) {
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, apperror.ExternalAccountUnknown()
+	}
+
+	username := claims.Username
+	if username == "" {
+		username, _, _ = strings.Cut(claims.Email, "@")
+	}
+	name := claims.Name
+	if name == "" {
+		name = username
+	}
+
+	if _, err := uc.userRepo.FindByUsername(ctx, username); err == nil {
+		return nil, apperror.AlreadyExists("username " + username)
+	}
+
+	auth, err := entity.NewAuth(idp.DefaultProfileID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := entity.NewUser(name, username, claims.Email, auth)
+	if err != nil {
+		return nil, err
+	}
+	// The provider verified the email
+	user.MarkEmailVerified()
+
+	if err := uc.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := uc.linkIdentity(ctx, user, provider, claims); err != nil {
+		return nil, err
+	}
+
+	// Reload to get the profile, which defines the permissions of the tokens
+	user, err = uc.userRepo.FindByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Provisioned users create themselves
+	if err := uc.config.Audit.User(actor.WithUser(ctx, &user.ID, nil), entity.AuditActionCreate, user, nil, user.AuditState()); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// linkIdentity links an external account to a user
+func (uc *authUseCase) linkIdentity(ctx context.Context, user *entity.User, provider string, claims *output.ExternalClaims) error {
+	return uc.identityRepo.Create(ctx, entity.NewExternalIdentity(user.ID, provider, claims.Subject, claims.Email))
+}
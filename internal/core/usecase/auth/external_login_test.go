@@ -0,0 +1,201 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/identity"
+	"github.com/raulaguila/go-api/internal/adapter/driven/storage/memory"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/input"
+	"github.com/raulaguila/go-api/internal/core/port/output/outputtest"
+	"github.com/raulaguila/go-api/internal/core/usecase/auth"
+	"github.com/raulaguila/go-api/pkg/apperror"
+	"github.com/raulaguila/go-api/pkg/oidc"
+	"github.com/raulaguila/go-api/pkg/oidc/oidctest"
+)
+
+// fakeIdentityRepo implements output.ExternalIdentityRepository in memory for testing
+type fakeIdentityRepo struct {
+	identities []*entity.ExternalIdentity
+}
+
+func (r *fakeIdentityRepo) FindBySubject(_ context.Context, provider, subject string) (*entity.ExternalIdentity, error) {
+	for _, i := range r.identities {
+		if i.Provider == provider && i.Subject == subject {
+			return i, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeIdentityRepo) Create(_ context.Context, identity *entity.ExternalIdentity) error {
+	identity.ID = uint(len(r.identities) + 1)
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+// externalLoginTest is an auth use case logging users in at a fake identity provider named corp
+type externalLoginTest struct {
+	uc          input.AuthUseCase
+	idp         *oidctest.Server
+	userRepo    *outputtest.UserRepository
+	sessionRepo *outputtest.SessionRepository
+	identities  *fakeIdentityRepo
+}
+
+func newExternalLoginTest(t *testing.T, defaultProfileID uint) *externalLoginTest {
+	idp := oidctest.NewServer(t, "go-api", "s3cr3t")
+	client := oidc.NewClient(oidc.Config{
+		Issuer:       idp.Issuer(),
+		ClientID:     "go-api",
+		ClientSecret: "s3cr3t",
+		RedirectURL:  "https://api.example.com/auth/oidc/corp/callback",
+	})
+
+	cfg := newTestConfig(t)
+	cfg.ExternalProviders = map[string]auth.ExternalProvider{
+		"corp": {IdentityProvider: identity.NewOIDCProvider(client, identity.DefaultClaimMapping), DefaultProfileID: defaultProfileID},
+	}
+	cfg.ExternalLoginExpiration = time.Minute
+
+	test := &externalLoginTest{
+		idp:         idp,
+		userRepo:    new(outputtest.UserRepository),
+		sessionRepo: new(outputtest.SessionRepository),
+		identities:  &fakeIdentityRepo{},
+	}
+	test.uc = auth.NewAuthUseCase(test.userRepo, test.sessionRepo, &fakeAttemptRepo{}, test.identities,
+		memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), cfg)
+	return test
+}
+
+// login starts a login, logs user in at the provider and completes the login with the redirect
+func (e *externalLoginTest) login(t *testing.T, ctx context.Context, user oidctest.User) (*dto.AuthOutput, error) {
+	started, err := e.uc.BeginExternalLogin(ctx, "corp", true)
+	require.NoError(t, err)
+
+	code, state := e.idp.Authorize(t, started.URL, user)
+	require.Equal(t, started.State, state)
+
+	return e.uc.CompleteExternalLogin(ctx, &dto.ExternalLoginInput{Provider: "corp", Code: code, State: state})
+}
+
+func TestExternalLogin_LinkedIdentity(t *testing.T) {
+	test := newExternalLoginTest(t, 0)
+	ctx := context.Background()
+
+	u := newTestUser(t)
+	require.NoError(t, test.identities.Create(ctx, entity.NewExternalIdentity(u.ID, "corp", "idp-42", u.Email)))
+	test.userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	test.sessionRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	out, err := test.login(t, ctx, oidctest.User{Subject: "idp-42", Email: "changed@example.com"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, out.AccessToken)
+	assert.Equal(t, u.ID, *out.User.ID)
+}
+
+func TestExternalLogin_LinksVerifiedEmail(t *testing.T) {
+	test := newExternalLoginTest(t, 0)
+	ctx := context.Background()
+
+	u := newTestUser(t)
+	test.userRepo.On("FindByEmail", ctx, u.Email).Return(u, nil)
+	test.sessionRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	out, err := test.login(t, ctx, oidctest.User{Subject: "idp-42", Email: u.Email, EmailVerified: true})
+	require.NoError(t, err)
+	assert.Equal(t, u.ID, *out.User.ID)
+
+	linked, err := test.identities.FindBySubject(ctx, "corp", "idp-42")
+	require.NoError(t, err)
+	assert.Equal(t, u.ID, linked.UserID)
+}
+
+func TestExternalLogin_UnverifiedEmailIsNotLinked(t *testing.T) {
+	test := newExternalLoginTest(t, 0)
+	ctx := context.Background()
+
+	_, err := test.login(t, ctx, oidctest.User{Subject: "idp-42", Email: "john@example.com"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeExternalAccountUnknown))
+	test.userRepo.AssertNotCalled(t, "FindByEmail", mock.Anything, mock.Anything)
+	assert.Empty(t, test.identities.identities)
+}
+
+func TestExternalLogin_ProvisionsUser(t *testing.T) {
+	test := newExternalLoginTest(t, 3)
+	ctx := context.Background()
+
+	test.userRepo.On("FindByEmail", ctx, "janeroe@example.com").Return(nil, gorm.ErrRecordNotFound)
+	test.userRepo.On("FindByUsername", ctx, "janeroe").Return(nil, gorm.ErrRecordNotFound)
+	test.userRepo.On("Create", ctx, mock.MatchedBy(func(u *entity.User) bool {
+		return u.Username == "janeroe" && u.Name == "Jane Roe" && u.Email == "janeroe@example.com" &&
+			u.Auth.ProfileID == 3 && u.Auth.Status && !u.Auth.HasPassword()
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*entity.User).ID = 9
+	}).Return(nil)
+
+	provisioned := &entity.User{ID: 9, Name: "Jane Roe", Username: "janeroe", Email: "janeroe@example.com",
+		Auth: &entity.Auth{Status: true, ProfileID: 3, Profile: &entity.Profile{ID: 3, Name: "STAFF", Permissions: []string{"users:read"}}}}
+	test.userRepo.On("FindByID", ctx, uint(9)).Return(provisioned, nil)
+	test.sessionRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	out, err := test.login(t, ctx, oidctest.User{Subject: "idp-7", Email: "janeroe@example.com", EmailVerified: true, Name: "Jane Roe", Username: "janeroe"})
+	require.NoError(t, err)
+	assert.Equal(t, uint(9), *out.User.ID)
+	test.userRepo.AssertExpectations(t)
+
+	linked, err := test.identities.FindBySubject(ctx, "corp", "idp-7")
+	require.NoError(t, err)
+	assert.Equal(t, uint(9), linked.UserID)
+}
+
+func TestExternalLogin_UnknownAccountWithoutProvisioning(t *testing.T) {
+	test := newExternalLoginTest(t, 0)
+	ctx := context.Background()
+
+	test.userRepo.On("FindByEmail", ctx, "janeroe@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := test.login(t, ctx, oidctest.User{Subject: "idp-7", Email: "janeroe@example.com", EmailVerified: true})
+	assert.True(t, apperror.IsCode(err, apperror.CodeExternalAccountUnknown))
+	test.userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestExternalLogin_StateIsSingleUse(t *testing.T) {
+	test := newExternalLoginTest(t, 0)
+	ctx := context.Background()
+
+	u := newTestUser(t)
+	require.NoError(t, test.identities.Create(ctx, entity.NewExternalIdentity(u.ID, "corp", "idp-42", u.Email)))
+	test.userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	test.sessionRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	started, err := test.uc.BeginExternalLogin(ctx, "corp", true)
+	require.NoError(t, err)
+	code, state := test.idp.Authorize(t, started.URL, oidctest.User{Subject: "idp-42"})
+
+	_, err = test.uc.CompleteExternalLogin(ctx, &dto.ExternalLoginInput{Provider: "corp", Code: code, State: state})
+	require.NoError(t, err)
+
+	_, err = test.uc.CompleteExternalLogin(ctx, &dto.ExternalLoginInput{Provider: "corp", Code: code, State: state})
+	assert.True(t, apperror.IsCode(err, apperror.CodeExternalLoginFailed))
+
+	_, err = test.uc.CompleteExternalLogin(ctx, &dto.ExternalLoginInput{Provider: "corp", Code: code, State: "forged"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeExternalLoginFailed))
+}
+
+func TestExternalLogin_UnknownProvider(t *testing.T) {
+	test := newExternalLoginTest(t, 0)
+
+	_, err := test.uc.BeginExternalLogin(context.Background(), "other", true)
+	assert.True(t, apperror.IsNotFound(err))
+	assert.Equal(t, []string{"corp"}, test.uc.ExternalProviders())
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/pkg/apperror"
+)
+
+// Impersonate lets a root user act as another user and returns a short-lived access token
+// with the user as subject and the root user as actor (act claim, RFC 8693). The token has
+// the user's permissions and cannot be refreshed; its session records who acted as whom.
+func (uc *authUseCase) Impersonate(ctx context.Context, input *dto.ImpersonationInput) (*dto.AuthOutput, error) {
+	actor, err := uc.userRepo.FindByID(ctx, input.ActorID)
+	if err != nil {
+		return nil, apperror.UserNotFound()
+	}
+	if profile := actor.GetProfile(); profile == nil || !profile.IsRoot() {
+		return nil, apperror.Forbidden("only root users can impersonate")
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, input.UserID)
+	if err != nil {
+		return nil, apperror.UserNotFound()
+	}
+	if user.ID == actor.ID {
+		return nil, apperror.InvalidInput("id", "users cannot impersonate themselves")
+	}
+	// Acting as another root user would only hide who did what
+	if profile := user.GetProfile(); profile != nil && profile.IsRoot() {
+		return nil, apperror.Forbidden("root users cannot be impersonated")
+	}
+	if user.Auth == nil || !user.Auth.Status {
+		return nil, apperror.DisabledUser()
+	}
+
+	session := entity.NewImpersonationSession(user.ID, actor.ID, input.UserAgent, input.IP, time.Now().Add(uc.config.ImpersonationExpiration))
+	if err := uc.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	if err := uc.config.Audit.Session(ctx, entity.AuditActionImpersonate, session, nil, session.AuditState()); err != nil {
+		return nil, err
+	}
+
+	claims := uc.accessClaims(user, session)
+	claims["act"] = map[string]any{"sub": subject(actor.ID)}
+
+	accessToken, err := uc.generateToken(claims, uc.config.AccessKeys, &uc.config.ImpersonationExpiration)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.EntityToUserOutput(user)
+	output.ImpersonatedBy = dto.EntityToUserOutput(actor)
+	return &dto.AuthOutput{User: output, AccessToken: accessToken}, nil
+}
@@ -0,0 +1,101 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/storage/memory"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/output/outputtest"
+	"github.com/raulaguila/go-api/internal/core/usecase/auth"
+	"github.com/raulaguila/go-api/pkg/apperror"
+	"github.com/raulaguila/go-api/pkg/jwtx"
+)
+
+// newRootUser returns a user of the root profile
+func newRootUser(t *testing.T) *entity.User {
+	a, err := entity.NewAuth(1, true)
+	require.NoError(t, err)
+	a.Profile = &entity.Profile{ID: 1, Name: "ROOT"}
+	u, err := entity.NewUser("Administrator", "admin", "admin@example.com", a)
+	require.NoError(t, err)
+	u.ID = 1
+	return u
+}
+
+func TestImpersonate(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	cfg := newTestConfig(t)
+	cfg.ImpersonationExpiration = 5 * time.Minute
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), cfg)
+	ctx := context.Background()
+
+	admin, u := newRootUser(t), newTestUser(t)
+	u.Auth.Profile = &entity.Profile{ID: 2, Name: "STAFF", Permissions: []string{"users:read"}}
+
+	var session *entity.Session
+	userRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	sessionRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		session = args.Get(1).(*entity.Session)
+	}).Return(nil)
+
+	out, err := uc.Impersonate(ctx, &dto.ImpersonationInput{ActorID: admin.ID, UserID: u.ID, IP: "10.0.0.1"})
+	require.NoError(t, err)
+	assert.Empty(t, out.RefreshToken, "impersonation tokens cannot be refreshed")
+	assert.Equal(t, u.ID, *out.User.ID)
+	assert.Equal(t, admin.ID, *out.User.ImpersonatedBy.ID)
+
+	require.NotNil(t, session)
+	assert.Equal(t, u.ID, session.UserID)
+	assert.Equal(t, admin.ID, *session.ActorID, "the session records who acted as the user")
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), *session.ExpiresAt, time.Second)
+
+	parsed, err := jwt.Parse(out.AccessToken, cfg.AccessKeys.Keyfunc, jwt.WithAudience("go-api"))
+	require.NoError(t, err)
+	claims := parsed.Claims.(jwt.MapClaims)
+	assert.Equal(t, "7", claims["sub"])
+	assert.Equal(t, map[string]any{"sub": "1"}, claims["act"])
+	assert.Equal(t, jwtx.TypeAccess, claims["typ"])
+	assert.Equal(t, []any{"users:read"}, claims["permissions"], "the token has the user's permissions")
+
+	sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+	_, err = uc.Refresh(ctx, session.ID, session.RefreshTokenID, true)
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidSession))
+}
+
+func TestImpersonate_Forbidden(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	uc := auth.NewAuthUseCase(userRepo, sessionRepo, &fakeAttemptRepo{}, &fakeIdentityRepo{}, memory.NewRevocationStore(), memory.NewLoginThrottle(), memory.NewExternalLoginStore(), newTestConfig(t))
+	ctx := context.Background()
+
+	admin, u := newRootUser(t), newTestUser(t)
+	u.Auth.Profile = &entity.Profile{ID: 2, Name: "STAFF"}
+	otherAdmin := newRootUser(t)
+	otherAdmin.ID = 2
+
+	userRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+	userRepo.On("FindByID", ctx, otherAdmin.ID).Return(otherAdmin, nil)
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+
+	_, err := uc.Impersonate(ctx, &dto.ImpersonationInput{ActorID: u.ID, UserID: admin.ID})
+	assert.True(t, apperror.IsCode(err, apperror.CodeForbidden), "only root users can impersonate")
+
+	_, err = uc.Impersonate(ctx, &dto.ImpersonationInput{ActorID: admin.ID, UserID: otherAdmin.ID})
+	assert.True(t, apperror.IsCode(err, apperror.CodeForbidden), "root users cannot be impersonated")
+
+	_, err = uc.Impersonate(ctx, &dto.ImpersonationInput{ActorID: admin.ID, UserID: admin.ID})
+	assert.Error(t, err)
+
+	u.Auth.Status = false
+	_, err = uc.Impersonate(ctx, &dto.ImpersonationInput{ActorID: admin.ID, UserID: u.ID})
+	assert.Error(t, err)
+	sessionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
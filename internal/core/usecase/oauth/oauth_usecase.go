@@ -0,0 +1,453 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/input"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/pkg/apperror"
+	"github.com/raulaguila/go-api/pkg/jwtx"
+	"github.com/raulaguila/go-api/pkg/oidc"
+)
+
+// Config holds the OAuth 2.0 / OpenID Connect provider configuration
+type Config struct {
+	Issuer           string        // Public URL of the API, iss claim of ID tokens
+	LoginURL         string        // Page where users log in and authorize clients
+	Keys             *jwtx.Keyring // Signs ID tokens and client credentials tokens
+	TokenIssuer      string        // iss claim of access tokens
+	TokenAudience    []string      // aud claim of client credentials tokens
+	AccessExpiration time.Duration // Lifetime of access and ID tokens
+	CodeExpiration   time.Duration // Time to redeem an authorization code
+}
+
+// oauthUseCase implements the OAuthUseCase interface on top of the AuthUseCase,
+// which opens, refreshes and revokes the sessions of users logged into clients
+type oauthUseCase struct {
+	auth       input.AuthUseCase
+	userRepo   output.UserRepository
+	clientRepo output.OAuthClientRepository
+	codes      output.AuthorizationCodeStore
+	config     Config
+}
+
+// NewOAuthUseCase creates a new OAuthUseCase instance
+func NewOAuthUseCase(
+	auth input.AuthUseCase,
+	userRepo output.UserRepository,
+	clientRepo output.OAuthClientRepository,
+	codes output.AuthorizationCodeStore,
+	config Config,
+) input.OAuthUseCase {
+	config.Issuer = strings.TrimSuffix(config.Issuer, "/")
+	return &oauthUseCase{
+		auth:       auth,
+		userRepo:   userRepo,
+		clientRepo: clientRepo,
+		codes:      codes,
+		config:     config,
+	}
+}
+
+// RequestAuthorization validates an authorization request and sends the browser to the
+// login page, which calls Authorize once the user logged in and agreed. Errors are only
+// returned when the client cannot be trusted with a redirect.
+func (uc *oauthUseCase) RequestAuthorization(ctx context.Context, input *dto.AuthorizeInput) (*dto.AuthorizeOutput, error) {
+	client, redirectURI, err := uc.authorizationClient(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, oauthErr := validateAuthorization(client, input); oauthErr != nil {
+		return errorRedirect(redirectURI, input.State, oauthErr), nil
+	}
+
+	params := url.Values{}
+	for name, value := range map[string]string{
+		"response_type":         input.ResponseType,
+		"client_id":             input.ClientID,
+		"redirect_uri":          input.RedirectURI,
+		"scope":                 input.Scope,
+		"state":                 input.State,
+		"nonce":                 input.Nonce,
+		"code_challenge":        input.CodeChallenge,
+		"code_challenge_method": input.CodeChallengeMethod,
+	} {
+		if value != "" {
+			params.Set(name, value)
+		}
+	}
+
+	loginURL, err := withQuery(uc.config.LoginURL, params)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AuthorizeOutput{RedirectURI: loginURL}, nil
+}
+
+// Authorize issues an authorization code for a user and returns the client's redirect URI carrying it
+func (uc *oauthUseCase) Authorize(ctx context.Context, userID uint, authTime time.Time, input *dto.AuthorizeInput) (*dto.AuthorizeOutput, error) {
+	client, redirectURI, err := uc.authorizationClient(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes, oauthErr := validateAuthorization(client, input)
+	if oauthErr != nil {
+		return errorRedirect(redirectURI, input.State, oauthErr), nil
+	}
+
+	// The redirect URI is kept as requested: the token request must repeat it when it was given
+	code, err := entity.NewAuthorizationCode(client.ClientID, userID, input.RedirectURI, scopes, input.Nonce, input.CodeChallenge, authTime)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.codes.Save(ctx, code, uc.config.CodeExpiration); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{"code": {code.Code}}
+	if input.State != "" {
+		params.Set("state", input.State)
+	}
+
+	location, err := withQuery(redirectURI, params)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AuthorizeOutput{RedirectURI: location}, nil
+}
+
+// Token authenticates the client and serves the grant it asked for
+func (uc *oauthUseCase) Token(ctx context.Context, input *dto.TokenInput) (*oidc.Tokens, error) {
+	client, err := uc.authenticateClient(ctx, input.ClientID, input.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains([]string{entity.GrantAuthorizationCode, entity.GrantRefreshToken, entity.GrantClientCredentials}, input.GrantType) {
+		return nil, &oidc.Error{Code: oidc.ErrorUnsupportedGrantType}
+	}
+	if !client.AllowsGrant(input.GrantType) {
+		return nil, &oidc.Error{Code: oidc.ErrorUnauthorizedClient, Description: "grant type not allowed for the client"}
+	}
+
+	switch input.GrantType {
+	case entity.GrantAuthorizationCode:
+		return uc.exchangeCode(ctx, client, input)
+	case entity.GrantRefreshToken:
+		return uc.refresh(ctx, client, input)
+	default:
+		return uc.clientCredentials(client, input)
+	}
+}
+
+// UserInfo returns the claims about a user allowed by the scopes of the token
+func (uc *oauthUseCase) UserInfo(ctx context.Context, userID uint, scopes []string) (*dto.UserInfoOutput, error) {
+	if !slices.Contains(scopes, entity.ScopeOpenID) {
+		return nil, &oidc.Error{Code: oidc.ErrorInsufficientScope, Description: "the token was not granted the openid scope"}
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, &oidc.Error{Code: oidc.ErrorInvalidToken}
+	}
+
+	return userInfo(user, scopes), nil
+}
+
+// Revoke revokes a token issued to the authenticated client
+func (uc *oauthUseCase) Revoke(ctx context.Context, input *dto.RevokeTokenInput) error {
+	client, err := uc.authenticateClient(ctx, input.ClientID, input.ClientSecret)
+	if err != nil {
+		return err
+	}
+
+	if input.Token == "" {
+		return &oidc.Error{Code: oidc.ErrorInvalidRequest, Description: "token is required"}
+	}
+
+	return uc.auth.RevokeClientToken(ctx, client.ClientID, input.Token)
+}
+
+// GetClients returns every registered client
+func (uc *oauthUseCase) GetClients(ctx context.Context) ([]dto.OAuthClientOutput, error) {
+	clients, err := uc.clientRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.EntitiesToOAuthClientOutputs(clients), nil
+}
+
+// CreateClient registers a client and returns its secret, only once
+func (uc *oauthUseCase) CreateClient(ctx context.Context, input *dto.OAuthClientInput) (*dto.OAuthClientCreatedOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	client, secret, err := entity.NewOAuthClient(input.Name, input.RedirectURIs, input.Scopes, input.GrantTypes, input.Confidential)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.clientRepo.Create(ctx, client); err != nil {
+		return nil, err
+	}
+
+	return &dto.OAuthClientCreatedOutput{
+		OAuthClientOutput: *dto.EntityToOAuthClientOutput(client),
+		ClientSecret:      secret,
+	}, nil
+}
+
+// DeleteClient removes a client; the sessions opened for it go with it
+func (uc *oauthUseCase) DeleteClient(ctx context.Context, id uint) error {
+	return uc.clientRepo.Delete(ctx, id)
+}
+
+// authorizationClient returns the client of an authorization request and the redirect URI to answer to.
+// Unknown clients and unregistered redirect URIs are errors, never redirects, to not become an open redirector.
+func (uc *oauthUseCase) authorizationClient(ctx context.Context, input *dto.AuthorizeInput) (*entity.OAuthClient, string, error) {
+	client, err := uc.clientRepo.FindByClientID(ctx, input.ClientID)
+	if err != nil {
+		return nil, "", &oidc.Error{Code: oidc.ErrorInvalidRequest, Description: "unknown client"}
+	}
+
+	redirectURI := input.RedirectURI
+	if redirectURI == "" && len(client.RedirectURIs) == 1 {
+		redirectURI = client.RedirectURIs[0]
+	}
+	if !client.HasRedirectURI(redirectURI) {
+		return nil, "", &oidc.Error{Code: oidc.ErrorInvalidRequest, Description: "redirect_uri is not registered for the client"}
+	}
+
+	return client, redirectURI, nil
+}
+
+// validateAuthorization checks an authorization request and returns the granted scopes
+func validateAuthorization(client *entity.OAuthClient, input *dto.AuthorizeInput) ([]string, *oidc.Error) {
+	if !client.AllowsGrant(entity.GrantAuthorizationCode) {
+		return nil, &oidc.Error{Code: oidc.ErrorUnauthorizedClient, Description: "grant type not allowed for the client"}
+	}
+	if input.ResponseType != "code" {
+		return nil, &oidc.Error{Code: oidc.ErrorUnsupportedResponseType, Description: "only the code response type is supported"}
+	}
+
+	scopes, denied := client.GrantScopes(strings.Fields(input.Scope))
+	if denied != "" {
+		return nil, &oidc.Error{Code: oidc.ErrorInvalidScope, Description: "scope not allowed for the client: " + denied}
+	}
+
+	if input.CodeChallenge != "" && input.CodeChallengeMethod != "S256" {
+		return nil, &oidc.Error{Code: oidc.ErrorInvalidRequest, Description: "code_challenge_method must be S256"}
+	}
+	if client.IsPublic() && input.CodeChallenge == "" {
+		return nil, &oidc.Error{Code: oidc.ErrorInvalidRequest, Description: "public clients must use PKCE"}
+	}
+
+	return scopes, nil
+}
+
+// authenticateClient finds a client and checks its secret
+func (uc *oauthUseCase) authenticateClient(ctx context.Context, clientID, secret string) (*entity.OAuthClient, error) {
+	client, err := uc.clientRepo.FindByClientID(ctx, clientID)
+	if err != nil || !client.Authenticate(secret) {
+		return nil, &oidc.Error{Code: oidc.ErrorInvalidClient, Description: "client authentication failed"}
+	}
+	return client, nil
+}
+
+// exchangeCode redeems an authorization code for the tokens of a new session of its user
+func (uc *oauthUseCase) exchangeCode(ctx context.Context, client *entity.OAuthClient, input *dto.TokenInput) (*oidc.Tokens, error) {
+	code, err := uc.codes.Take(ctx, input.Code)
+	if err != nil {
+		return nil, err
+	}
+	if code == nil || code.ClientID != client.ClientID || code.RedirectURI != input.RedirectURI || !code.VerifyCodeVerifier(input.CodeVerifier) {
+		return nil, &oidc.Error{Code: oidc.ErrorInvalidGrant, Description: "invalid or expired authorization code"}
+	}
+
+	auth, err := uc.auth.OpenClientSession(ctx, &dto.ClientSessionInput{
+		UserID:    code.UserID,
+		ClientID:  client.ClientID,
+		Scopes:    code.Scopes,
+		UserAgent: input.UserAgent,
+		IP:        input.IP,
+	})
+	if err != nil {
+		return nil, invalidGrant(err)
+	}
+
+	tokens := uc.tokens(client, auth, code.Scopes)
+	if slices.Contains(code.Scopes, entity.ScopeOpenID) {
+		if tokens.IDToken, err = uc.idToken(ctx, client.ClientID, code); err != nil {
+			return nil, err
+		}
+	}
+
+	return tokens, nil
+}
+
+// refresh rotates the refresh token of a session opened for the client
+func (uc *oauthUseCase) refresh(ctx context.Context, client *entity.OAuthClient, input *dto.TokenInput) (*oidc.Tokens, error) {
+	auth, err := uc.auth.RefreshClientSession(ctx, client.ClientID, input.RefreshToken)
+	if err != nil {
+		return nil, invalidGrant(err)
+	}
+
+	return uc.tokens(client, auth, nil), nil
+}
+
+// clientCredentials issues a token for the client itself. Such tokens authorize calls
+// between services and carry no session, so the API's own endpoints do not accept them.
+func (uc *oauthUseCase) clientCredentials(client *entity.OAuthClient, input *dto.TokenInput) (*oidc.Tokens, error) {
+	scopes, denied := client.GrantScopes(strings.Fields(input.Scope))
+	if denied != "" {
+		return nil, &oidc.Error{Code: oidc.ErrorInvalidScope, Description: "scope not allowed for the client: " + denied}
+	}
+
+	// OpenID Connect scopes are about users, and there is no user here
+	scopes = slices.DeleteFunc(scopes, func(scope string) bool {
+		return slices.Contains([]string{entity.ScopeOpenID, entity.ScopeProfile, entity.ScopeEmail}, scope)
+	})
+
+	now := time.Now()
+	token, err := uc.config.Keys.Sign(jwt.MapClaims{
+		"iss":       uc.config.TokenIssuer,
+		"sub":       client.ClientID,
+		"aud":       jwt.ClaimStrings(uc.config.TokenAudience),
+		"jti":       uuid.New().String(),
+		"typ":       jwtx.TypeAccess,
+		"client_id": client.ClientID,
+		"scope":     strings.Join(scopes, " "),
+		"iat":       now.Unix(),
+		"nbf":       now.Unix(),
+		"exp":       now.Add(uc.config.AccessExpiration).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &oidc.Tokens{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(uc.config.AccessExpiration.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
+
+// tokens returns the token response of a client session; refresh tokens are only
+// handed to clients allowed to use them
+func (uc *oauthUseCase) tokens(client *entity.OAuthClient, auth *dto.AuthOutput, scopes []string) *oidc.Tokens {
+	tokens := &oidc.Tokens{
+		AccessToken: auth.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(uc.config.AccessExpiration.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}
+	if client.AllowsGrant(entity.GrantRefreshToken) {
+		tokens.RefreshToken = auth.RefreshToken
+	}
+	return tokens
+}
+
+// idToken signs the ID token of the user who authorized a code
+func (uc *oauthUseCase) idToken(ctx context.Context, clientID string, code *entity.AuthorizationCode) (string, error) {
+	user, err := uc.userRepo.FindByID(ctx, code.UserID)
+	if err != nil {
+		return "", apperror.UserNotFound()
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":       uc.config.Issuer,
+		"sub":       strconv.FormatUint(uint64(user.ID), 10),
+		"aud":       clientID,
+		"iat":       now.Unix(),
+		"exp":       now.Add(uc.config.AccessExpiration).Unix(),
+		"auth_time": code.AuthTime.Unix(),
+	}
+	if code.Nonce != "" {
+		claims["nonce"] = code.Nonce
+	}
+
+	info := userInfo(user, code.Scopes)
+	for name, value := range map[string]string{
+		"name":               info.Name,
+		"preferred_username": info.PreferredUsername,
+		"email":              info.Email,
+	} {
+		if value != "" {
+			claims[name] = value
+		}
+	}
+
+	return uc.config.Keys.Sign(claims)
+}
+
+// userInfo returns the claims about a user allowed by scopes
+func userInfo(user *entity.User, scopes []string) *dto.UserInfoOutput {
+	info := &dto.UserInfoOutput{Subject: strconv.FormatUint(uint64(user.ID), 10)}
+	if slices.Contains(scopes, entity.ScopeProfile) {
+		info.Name = user.Name
+		info.PreferredUsername = user.Username
+	}
+	if slices.Contains(scopes, entity.ScopeEmail) {
+		info.Email = user.Email
+		verified := user.IsEmailVerified()
+		info.EmailVerified = &verified
+	}
+	return info
+}
+
+// invalidGrant reports refused sessions (e.g. of disabled users) as an invalid grant
+func invalidGrant(err error) error {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		return &oidc.Error{Code: oidc.ErrorInvalidGrant, Description: appErr.Message}
+	}
+	return err
+}
+
+// errorRedirect returns the client's redirect URI carrying an error (RFC 6749 section 4.1.2.1)
+func errorRedirect(redirectURI, state string, oauthErr *oidc.Error) *dto.AuthorizeOutput {
+	params := url.Values{"error": {oauthErr.Code}}
+	if oauthErr.Description != "" {
+		params.Set("error_description", oauthErr.Description)
+	}
+	if state != "" {
+		params.Set("state", state)
+	}
+
+	// Registered redirect URIs were validated as absolute URLs
+	location, _ := withQuery(redirectURI, params)
+	return &dto.AuthorizeOutput{RedirectURI: location}
+}
+
+// withQuery adds parameters to the query of a URL, keeping the ones it has
+func withQuery(rawURL string, params url.Values) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	for name, values := range params {
+		query[name] = values
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
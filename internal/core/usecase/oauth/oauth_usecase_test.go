@@ -0,0 +1,382 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/adapter/driven/storage/memory"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/input"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/internal/core/usecase/oauth"
+	"github.com/raulaguila/go-api/pkg/jwtx"
+	"github.com/raulaguila/go-api/pkg/oidc"
+)
+
+// fakeUserRepo implements the lookups of output.UserRepository used by the provider
+type fakeUserRepo struct {
+	output.UserRepository
+	users map[uint]*entity.User
+}
+
+func (r *fakeUserRepo) FindByID(_ context.Context, id uint) (*entity.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// fakeClientRepo implements output.OAuthClientRepository in memory for testing
+type fakeClientRepo struct {
+	clients []*entity.OAuthClient
+}
+
+func (r *fakeClientRepo) FindAll(_ context.Context) ([]*entity.OAuthClient, error) {
+	return r.clients, nil
+}
+
+func (r *fakeClientRepo) FindByClientID(_ context.Context, clientID string) (*entity.OAuthClient, error) {
+	for _, c := range r.clients {
+		if c.ClientID == clientID {
+			return c, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeClientRepo) Create(_ context.Context, client *entity.OAuthClient) error {
+	client.ID = uint(len(r.clients) + 1)
+	r.clients = append(r.clients, client)
+	return nil
+}
+
+func (r *fakeClientRepo) Delete(_ context.Context, id uint) error {
+	for i, c := range r.clients {
+		if c.ID == id {
+			r.clients = append(r.clients[:i], r.clients[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// fakeAuth implements the client sessions of input.AuthUseCase, issuing one
+// refresh token per session
+type fakeAuth struct {
+	input.AuthUseCase
+	sessions map[string]*dto.ClientSessionInput
+	revoked  []string
+}
+
+func (a *fakeAuth) OpenClientSession(_ context.Context, in *dto.ClientSessionInput) (*dto.AuthOutput, error) {
+	refresh := "refresh-" + in.ClientID
+	a.sessions[refresh] = in
+	return &dto.AuthOutput{AccessToken: "access-" + in.ClientID, RefreshToken: refresh}, nil
+}
+
+func (a *fakeAuth) RefreshClientSession(_ context.Context, clientID, refreshToken string) (*dto.AuthOutput, error) {
+	if session, ok := a.sessions[refreshToken]; !ok || session.ClientID != clientID {
+		return nil, &oidc.Error{Code: oidc.ErrorInvalidGrant}
+	}
+	return &dto.AuthOutput{AccessToken: "access-" + clientID, RefreshToken: refreshToken}, nil
+}
+
+func (a *fakeAuth) RevokeClientToken(_ context.Context, clientID, token string) error {
+	if session, ok := a.sessions[token]; ok && session.ClientID == clientID {
+		a.revoked = append(a.revoked, token)
+	}
+	return nil
+}
+
+type providerTest struct {
+	uc      input.OAuthUseCase
+	auth    *fakeAuth
+	clients *fakeClientRepo
+	keys    *jwtx.Keyring
+}
+
+func newProviderTest(t *testing.T) *providerTest {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	test := &providerTest{
+		auth:    &fakeAuth{sessions: map[string]*dto.ClientSessionInput{}},
+		clients: &fakeClientRepo{},
+		keys:    jwtx.NewKeyring(key),
+	}
+	users := &fakeUserRepo{users: map[uint]*entity.User{
+		7: {ID: 7, Name: "John Doe", Username: "johndoe", Email: "john@example.com"},
+	}}
+	test.uc = oauth.NewOAuthUseCase(test.auth, users, test.clients, memory.NewAuthorizationCodeStore(), oauth.Config{
+		Issuer:           "https://api.example.com",
+		LoginURL:         "https://app.example.com/login?theme=dark",
+		Keys:             test.keys,
+		TokenIssuer:      "go-api",
+		TokenAudience:    []string{"go-api"},
+		AccessExpiration: 15 * time.Minute,
+		CodeExpiration:   time.Minute,
+	})
+	return test
+}
+
+// register registers a client and returns it with its secret
+func (p *providerTest) register(t *testing.T, confidential bool, grants ...string) (*dto.OAuthClientCreatedOutput, string) {
+	out, err := p.uc.CreateClient(context.Background(), &dto.OAuthClientInput{
+		Name:         "Dashboard",
+		RedirectURIs: []string{"https://dashboard.example.com/callback"},
+		Scopes:       []string{entity.ScopeOpenID, entity.ScopeProfile, entity.ScopeEmail, "users:read"},
+		GrantTypes:   grants,
+		Confidential: confidential,
+	})
+	require.NoError(t, err)
+	return out, out.ClientSecret
+}
+
+// authorize has user 7 authorize a request and returns the query of the redirect to the client
+func (p *providerTest) authorize(t *testing.T, in *dto.AuthorizeInput) url.Values {
+	out, err := p.uc.Authorize(context.Background(), 7, time.Now(), in)
+	require.NoError(t, err)
+
+	location, err := url.Parse(out.RedirectURI)
+	require.NoError(t, err)
+	return location.Query()
+}
+
+func challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func TestOAuth_AuthorizationCodeWithPKCE(t *testing.T) {
+	p := newProviderTest(t)
+	ctx := context.Background()
+	client, _ := p.register(t, false, entity.GrantAuthorizationCode, entity.GrantRefreshToken)
+
+	query := p.authorize(t, &dto.AuthorizeInput{
+		ResponseType:        "code",
+		ClientID:            client.ClientID,
+		RedirectURI:         "https://dashboard.example.com/callback",
+		Scope:               "openid profile",
+		State:               "xyz",
+		Nonce:               "n-0S6",
+		CodeChallenge:       challenge("verifier-verifier-verifier-verifier-verifier"),
+		CodeChallengeMethod: "S256",
+	})
+	assert.Equal(t, "xyz", query.Get("state"))
+
+	tokens, err := p.uc.Token(ctx, &dto.TokenInput{
+		GrantType:    entity.GrantAuthorizationCode,
+		ClientID:     client.ClientID,
+		Code:         query.Get("code"),
+		RedirectURI:  "https://dashboard.example.com/callback",
+		CodeVerifier: "verifier-verifier-verifier-verifier-verifier",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", tokens.TokenType)
+	assert.Equal(t, "openid profile", tokens.Scope)
+	assert.Equal(t, "refresh-"+client.ClientID, tokens.RefreshToken)
+	assert.Equal(t, []string{"openid", "profile"}, p.auth.sessions[tokens.RefreshToken].Scopes)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(tokens.IDToken, claims, p.keys.Keyfunc,
+		jwt.WithIssuer("https://api.example.com"), jwt.WithAudience(client.ClientID))
+	require.NoError(t, err)
+	assert.Equal(t, "7", claims["sub"])
+	assert.Equal(t, "n-0S6", claims["nonce"])
+	assert.Equal(t, "johndoe", claims["preferred_username"])
+	assert.NotContains(t, claims, "email")
+
+	// Codes are single use
+	_, err = p.uc.Token(ctx, &dto.TokenInput{
+		GrantType:    entity.GrantAuthorizationCode,
+		ClientID:     client.ClientID,
+		Code:         query.Get("code"),
+		RedirectURI:  "https://dashboard.example.com/callback",
+		CodeVerifier: "verifier-verifier-verifier-verifier-verifier",
+	})
+	assert.Equal(t, &oidc.Error{Code: oidc.ErrorInvalidGrant, Description: "invalid or expired authorization code"}, err)
+}
+
+func TestOAuth_CodeBoundToVerifierAndRedirect(t *testing.T) {
+	p := newProviderTest(t)
+	ctx := context.Background()
+	client, secret := p.register(t, true, entity.GrantAuthorizationCode)
+
+	for name, in := range map[string]*dto.TokenInput{
+		"wrong verifier": {RedirectURI: "https://dashboard.example.com/callback", CodeVerifier: "other"},
+		"no verifier":    {RedirectURI: "https://dashboard.example.com/callback"},
+		"wrong redirect": {RedirectURI: "https://evil.example.com/callback", CodeVerifier: "verifier"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			query := p.authorize(t, &dto.AuthorizeInput{
+				ResponseType:        "code",
+				ClientID:            client.ClientID,
+				RedirectURI:         "https://dashboard.example.com/callback",
+				CodeChallenge:       challenge("verifier"),
+				CodeChallengeMethod: "S256",
+			})
+
+			in.GrantType, in.ClientID, in.ClientSecret, in.Code = entity.GrantAuthorizationCode, client.ClientID, secret, query.Get("code")
+			_, err := p.uc.Token(ctx, in)
+
+			var oauthErr *oidc.Error
+			require.ErrorAs(t, err, &oauthErr)
+			assert.Equal(t, oidc.ErrorInvalidGrant, oauthErr.Code)
+		})
+	}
+	assert.Empty(t, p.auth.sessions)
+}
+
+func TestOAuth_ClientAuthentication(t *testing.T) {
+	p := newProviderTest(t)
+	ctx := context.Background()
+	confidential, secret := p.register(t, true, entity.GrantClientCredentials)
+	public, _ := p.register(t, false, entity.GrantAuthorizationCode)
+
+	for name, in := range map[string]*dto.TokenInput{
+		"wrong secret":     {ClientID: confidential.ClientID, ClientSecret: "gcs_wrong"},
+		"missing secret":   {ClientID: confidential.ClientID},
+		"unknown client":   {ClientID: "unknown", ClientSecret: secret},
+		"secret of public": {ClientID: public.ClientID, ClientSecret: secret},
+	} {
+		t.Run(name, func(t *testing.T) {
+			in.GrantType = entity.GrantClientCredentials
+			_, err := p.uc.Token(ctx, in)
+
+			var oauthErr *oidc.Error
+			require.ErrorAs(t, err, &oauthErr)
+			assert.Equal(t, oidc.ErrorInvalidClient, oauthErr.Code)
+		})
+	}
+
+	// Public clients cannot use grants they were not registered for
+	_, err := p.uc.Token(ctx, &dto.TokenInput{GrantType: entity.GrantClientCredentials, ClientID: public.ClientID})
+	assert.Equal(t, oidc.ErrorUnauthorizedClient, err.(*oidc.Error).Code)
+
+	_, err = p.uc.Token(ctx, &dto.TokenInput{GrantType: "password", ClientID: public.ClientID})
+	assert.Equal(t, oidc.ErrorUnsupportedGrantType, err.(*oidc.Error).Code)
+}
+
+func TestOAuth_ClientCredentials(t *testing.T) {
+	p := newProviderTest(t)
+	client, secret := p.register(t, true, entity.GrantClientCredentials)
+
+	tokens, err := p.uc.Token(context.Background(), &dto.TokenInput{
+		GrantType:    entity.GrantClientCredentials,
+		ClientID:     client.ClientID,
+		ClientSecret: secret,
+		Scope:        "openid users:read",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "users:read", tokens.Scope)
+	assert.Empty(t, tokens.RefreshToken)
+	assert.Empty(t, tokens.IDToken)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(tokens.AccessToken, claims, p.keys.Keyfunc, jwt.WithAudience("go-api"))
+	require.NoError(t, err)
+	assert.Equal(t, client.ClientID, claims["sub"])
+	assert.Equal(t, jwtx.TypeAccess, claims["typ"])
+	assert.NotContains(t, claims, "sid")
+
+	_, err = p.uc.Token(context.Background(), &dto.TokenInput{
+		GrantType:    entity.GrantClientCredentials,
+		ClientID:     client.ClientID,
+		ClientSecret: secret,
+		Scope:        "users:write",
+	})
+	assert.Equal(t, oidc.ErrorInvalidScope, err.(*oidc.Error).Code)
+}
+
+func TestOAuth_RefreshBoundToClient(t *testing.T) {
+	p := newProviderTest(t)
+	ctx := context.Background()
+	first, _ := p.register(t, false, entity.GrantAuthorizationCode, entity.GrantRefreshToken)
+	second, _ := p.register(t, false, entity.GrantAuthorizationCode, entity.GrantRefreshToken)
+	withoutRefresh, _ := p.register(t, false, entity.GrantAuthorizationCode)
+
+	query := p.authorize(t, &dto.AuthorizeInput{ResponseType: "code", ClientID: first.ClientID,
+		CodeChallenge: challenge("verifier"), CodeChallengeMethod: "S256"})
+	tokens, err := p.uc.Token(ctx, &dto.TokenInput{GrantType: entity.GrantAuthorizationCode, ClientID: first.ClientID,
+		Code: query.Get("code"), CodeVerifier: "verifier"})
+	require.NoError(t, err)
+
+	_, err = p.uc.Token(ctx, &dto.TokenInput{GrantType: entity.GrantRefreshToken, ClientID: first.ClientID, RefreshToken: tokens.RefreshToken})
+	require.NoError(t, err)
+
+	_, err = p.uc.Token(ctx, &dto.TokenInput{GrantType: entity.GrantRefreshToken, ClientID: second.ClientID, RefreshToken: tokens.RefreshToken})
+	assert.Equal(t, oidc.ErrorInvalidGrant, err.(*oidc.Error).Code)
+
+	_, err = p.uc.Token(ctx, &dto.TokenInput{GrantType: entity.GrantRefreshToken, ClientID: withoutRefresh.ClientID, RefreshToken: tokens.RefreshToken})
+	assert.Equal(t, oidc.ErrorUnauthorizedClient, err.(*oidc.Error).Code)
+
+	// Revoking with another client is silently ignored
+	require.NoError(t, p.uc.Revoke(ctx, &dto.RevokeTokenInput{Token: tokens.RefreshToken, ClientID: second.ClientID}))
+	assert.Empty(t, p.auth.revoked)
+	require.NoError(t, p.uc.Revoke(ctx, &dto.RevokeTokenInput{Token: tokens.RefreshToken, ClientID: first.ClientID}))
+	assert.Equal(t, []string{tokens.RefreshToken}, p.auth.revoked)
+}
+
+func TestOAuth_AuthorizationErrors(t *testing.T) {
+	p := newProviderTest(t)
+	ctx := context.Background()
+	client, _ := p.register(t, false, entity.GrantAuthorizationCode)
+
+	// Requests that cannot be trusted with a redirect are refused outright
+	_, err := p.uc.RequestAuthorization(ctx, &dto.AuthorizeInput{ResponseType: "code", ClientID: "unknown"})
+	assert.Equal(t, oidc.ErrorInvalidRequest, err.(*oidc.Error).Code)
+	_, err = p.uc.RequestAuthorization(ctx, &dto.AuthorizeInput{ResponseType: "code", ClientID: client.ClientID, RedirectURI: "https://evil.example.com"})
+	assert.Equal(t, oidc.ErrorInvalidRequest, err.(*oidc.Error).Code)
+
+	// Others are sent back to the client
+	for in, code := range map[dto.AuthorizeInput]string{
+		{ResponseType: "token", CodeChallenge: challenge("v"), CodeChallengeMethod: "S256"}:                      oidc.ErrorUnsupportedResponseType,
+		{ResponseType: "code", Scope: "users:write", CodeChallenge: challenge("v"), CodeChallengeMethod: "S256"}: oidc.ErrorInvalidScope,
+		{ResponseType: "code", CodeChallenge: "v", CodeChallengeMethod: "plain"}:                                 oidc.ErrorInvalidRequest,
+		{ResponseType: "code"}: oidc.ErrorInvalidRequest,
+	} {
+		in.ClientID, in.State = client.ClientID, "xyz"
+		out, err := p.uc.RequestAuthorization(ctx, &in)
+		require.NoError(t, err)
+
+		location, err := url.Parse(out.RedirectURI)
+		require.NoError(t, err)
+		assert.Equal(t, "dashboard.example.com", location.Host)
+		assert.Equal(t, code, location.Query().Get("error"))
+		assert.Equal(t, "xyz", location.Query().Get("state"))
+	}
+
+	out, err := p.uc.RequestAuthorization(ctx, &dto.AuthorizeInput{ResponseType: "code", ClientID: client.ClientID,
+		CodeChallenge: challenge("v"), CodeChallengeMethod: "S256"})
+	require.NoError(t, err)
+	location, err := url.Parse(out.RedirectURI)
+	require.NoError(t, err)
+	assert.Equal(t, "app.example.com", location.Host)
+	assert.Equal(t, "dark", location.Query().Get("theme"))
+	assert.Equal(t, client.ClientID, location.Query().Get("client_id"))
+}
+
+func TestOAuth_UserInfo(t *testing.T) {
+	p := newProviderTest(t)
+	ctx := context.Background()
+
+	_, err := p.uc.UserInfo(ctx, 7, []string{"users:read"})
+	assert.Equal(t, oidc.ErrorInsufficientScope, err.(*oidc.Error).Code)
+
+	info, err := p.uc.UserInfo(ctx, 7, []string{entity.ScopeOpenID, entity.ScopeEmail})
+	require.NoError(t, err)
+	verified := false
+	assert.Equal(t, &dto.UserInfoOutput{Subject: "7", Email: "john@example.com", EmailVerified: &verified}, info)
+}
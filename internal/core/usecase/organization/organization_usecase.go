@@ -0,0 +1,80 @@
+package organization
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/input"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/pkg/utils"
+)
+
+// organizationUseCase implements the OrganizationUseCase interface
+type organizationUseCase struct {
+	organizationRepo output.OrganizationRepository
+}
+
+// NewOrganizationUseCase creates a new OrganizationUseCase instance
+func NewOrganizationUseCase(organizationRepo output.OrganizationRepository) input.OrganizationUseCase {
+	return &organizationUseCase{
+		organizationRepo: organizationRepo,
+	}
+}
+
+// GetOrganizations returns every organization
+func (uc *organizationUseCase) GetOrganizations(ctx context.Context) ([]dto.OrganizationOutput, error) {
+	organizations, err := uc.organizationRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.EntitiesToOrganizationOutputs(organizations), nil
+}
+
+// GetOrganizationByID returns an organization by its ID
+func (uc *organizationUseCase) GetOrganizationByID(ctx context.Context, id uint) (*dto.OrganizationOutput, error) {
+	organization, err := uc.organizationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.EntityToOrganizationOutput(organization), nil
+}
+
+// CreateOrganization creates a new organization
+func (uc *organizationUseCase) CreateOrganization(ctx context.Context, input *dto.OrganizationInput) (*dto.OrganizationOutput, error) {
+	organization, err := entity.NewOrganization(utils.Deref(input.Name, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.organizationRepo.Create(ctx, organization); err != nil {
+		return nil, err
+	}
+	return dto.EntityToOrganizationOutput(organization), nil
+}
+
+// UpdateOrganization updates an existing organization
+func (uc *organizationUseCase) UpdateOrganization(ctx context.Context, id uint, input *dto.OrganizationInput) (*dto.OrganizationOutput, error) {
+	organization, err := uc.organizationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		organization.UpdateName(*input.Name)
+	}
+
+	if err := organization.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.organizationRepo.Update(ctx, organization); err != nil {
+		return nil, err
+	}
+	return dto.EntityToOrganizationOutput(organization), nil
+}
+
+// DeleteOrganization removes an organization without users or profiles
+func (uc *organizationUseCase) DeleteOrganization(ctx context.Context, id uint) error {
+	return uc.organizationRepo.Delete(ctx, id)
+}
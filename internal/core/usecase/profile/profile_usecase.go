@@ -2,24 +2,30 @@ package profile
 
 import (
 	"context"
+	"errors"
+	"slices"
 
 	"github.com/raulaguila/go-api/internal/core/domain/entity"
 	"github.com/raulaguila/go-api/internal/core/dto"
 	"github.com/raulaguila/go-api/internal/core/port/input"
 	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/internal/core/usecase/audit"
 	"github.com/raulaguila/go-api/pkg/apperror"
+	"github.com/raulaguila/go-api/pkg/tenant"
 	"github.com/raulaguila/go-api/pkg/utils"
 )
 
 // profileUseCase implements the ProfileUseCase interface
 type profileUseCase struct {
 	profileRepo output.ProfileRepository
+	audit       *audit.Recorder
 }
 
-// NewProfileUseCase creates a new ProfileUseCase instance
-func NewProfileUseCase(profileRepo output.ProfileRepository) input.ProfileUseCase {
+// NewProfileUseCase creates a new ProfileUseCase instance; a nil recorder records nothing
+func NewProfileUseCase(profileRepo output.ProfileRepository, recorder *audit.Recorder) input.ProfileUseCase {
 	return &profileUseCase{
 		profileRepo: profileRepo,
+		audit:       recorder,
 	}
 }
 
@@ -78,12 +84,16 @@ func (uc *profileUseCase) CreateProfile(ctx context.Context, input *dto.ProfileI
 		utils.Deref(input.Name, ""),
 		utils.Deref(input.Permissions, []string{}),
 	)
+	profile.SetRequireTwoFactor(utils.Deref(input.RequireTwoFactor, false))
+	profile.SetRequireVerifiedEmail(utils.Deref(input.RequireVerifiedEmail, false))
+	profile.SetPasswordPolicy(input.PasswordPolicy)
+	profile.OrganizationID = tenant.Resolve(ctx, input.OrganizationID)
 
 	if err := profile.Validate(); err != nil {
 		return nil, err
 	}
 
-	if err := uc.profileRepo.Create(ctx, profile); err != nil {
+	if err := uc.saveAudited(ctx, entity.AuditActionCreate, profile, nil, uc.profileRepo.Create); err != nil {
 		return nil, err
 	}
 
@@ -96,6 +106,11 @@ func (uc *profileUseCase) UpdateProfile(ctx context.Context, id uint, input *dto
 	if err != nil {
 		return nil, apperror.ProfileNotFound()
 	}
+	// An update conditioned on another version would overwrite changes its client never saw
+	if input.Versions != nil && !slices.Contains(input.Versions, profile.Version) {
+		return nil, apperror.StaleVersion("profile")
+	}
+	before := profile.AuditState()
 
 	if input.Name != nil {
 		profile.UpdateName(*input.Name)
@@ -103,22 +118,142 @@ func (uc *profileUseCase) UpdateProfile(ctx context.Context, id uint, input *dto
 	if input.Permissions != nil {
 		profile.UpdatePermissions(*input.Permissions)
 	}
+	if input.RequireTwoFactor != nil {
+		profile.SetRequireTwoFactor(*input.RequireTwoFactor)
+	}
+	if input.RequireVerifiedEmail != nil {
+		profile.SetRequireVerifiedEmail(*input.RequireVerifiedEmail)
+	}
+	if input.PasswordPolicy != nil {
+		profile.SetPasswordPolicy(input.PasswordPolicy)
+	}
+
+	return uc.save(ctx, profile, before)
+}
+
+// PatchProfile updates an existing profile with a JSON Merge Patch or a JSON Patch of its
+// ProfileDocument. Unlike UpdateProfile, it can clear the password policy.
+func (uc *profileUseCase) PatchProfile(ctx context.Context, id uint, patch *dto.PatchInput) (*dto.ProfileOutput, error) {
+	profile, err := uc.profileRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperror.ProfileNotFound()
+	}
+	if patch.Versions != nil && !slices.Contains(patch.Versions, profile.Version) {
+		return nil, apperror.StaleVersion("profile")
+	}
+	before := profile.AuditState()
 
+	patched, err := dto.ApplyPatch(dto.EntityToProfileDocument(profile), patch)
+	if err != nil {
+		return nil, err
+	}
+	// Removing the permissions member clears them
+	if patched.Permissions == nil {
+		patched.Permissions = []string{}
+	}
+	profile.UpdateName(*patched.Name)
+	profile.UpdatePermissions(patched.Permissions)
+	profile.SetRequireTwoFactor(*patched.RequireTwoFactor)
+	profile.SetRequireVerifiedEmail(*patched.RequireVerifiedEmail)
+	profile.SetPasswordPolicy(patched.PasswordPolicy)
+
+	return uc.save(ctx, profile, before)
+}
+
+// save validates and saves an updated profile
+func (uc *profileUseCase) save(ctx context.Context, profile *entity.Profile, before entity.AuditState) (*dto.ProfileOutput, error) {
 	if err := profile.Validate(); err != nil {
 		return nil, err
 	}
 
-	if err := uc.profileRepo.Update(ctx, profile); err != nil {
+	if err := uc.saveAudited(ctx, entity.AuditActionUpdate, profile, before, uc.profileRepo.Update); err != nil {
+		if errors.Is(err, output.ErrStaleVersion) {
+			return nil, apperror.StaleVersion("profile")
+		}
 		return nil, err
 	}
 
 	return dto.EntityToProfileOutput(profile, true), nil
 }
 
-// DeleteProfiles deletes profiles by their IDs
+// saveAudited writes a profile with write and records it as action in one transaction, given the
+// profile's state before the change (nil for created profiles)
+func (uc *profileUseCase) saveAudited(ctx context.Context, action string, profile *entity.Profile, before entity.AuditState, write func(context.Context, *entity.Profile) error) error {
+	return uc.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := write(ctx, profile); err != nil {
+			return err
+		}
+		return uc.audit.Profile(ctx, action, profile, before, profile.AuditState())
+	})
+}
+
+// DeleteProfiles moves profiles to the trash by their IDs; profiles granted to users cannot be deleted
 func (uc *profileUseCase) DeleteProfiles(ctx context.Context, ids []uint) error {
 	if len(ids) == 0 {
 		return nil
 	}
-	return uc.profileRepo.Delete(ctx, ids)
+
+	// Only profiles visible within ctx are deleted, so only they are recorded
+	var visible []*entity.Profile
+	var visibleIDs []uint
+	for _, id := range ids {
+		if profile, err := uc.profileRepo.FindByID(ctx, id); err == nil {
+			visible = append(visible, profile)
+			visibleIDs = append(visibleIDs, id)
+		}
+	}
+	if len(visible) == 0 {
+		return apperror.ProfileNotFound()
+	}
+
+	inUse, err := uc.profileRepo.InUse(ctx, visibleIDs)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return apperror.ProfileInUse()
+	}
+
+	return uc.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := uc.profileRepo.Delete(ctx, visibleIDs); err != nil {
+			return err
+		}
+		for _, profile := range visible {
+			if err := uc.audit.Profile(ctx, entity.AuditActionDelete, profile, profile.AuditState(), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RestoreProfiles takes profiles out of the trash by their IDs
+func (uc *profileUseCase) RestoreProfiles(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// Profiles found before the restore were not in the trash
+	var trashed []uint
+	for _, id := range ids {
+		if _, err := uc.profileRepo.FindByID(ctx, id); err != nil {
+			trashed = append(trashed, id)
+		}
+	}
+
+	return uc.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := uc.profileRepo.Restore(ctx, ids); err != nil {
+			return err
+		}
+		for _, id := range trashed {
+			profile, err := uc.profileRepo.FindByID(ctx, id)
+			if err != nil {
+				continue
+			}
+			if err := uc.audit.Profile(ctx, entity.AuditActionRestore, profile, nil, profile.AuditState()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
@@ -0,0 +1,120 @@
+package profile_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/internal/core/port/output/outputtest"
+	"github.com/raulaguila/go-api/internal/core/usecase/profile"
+	"github.com/raulaguila/go-api/pkg/apperror"
+	"github.com/raulaguila/go-api/pkg/passwd"
+)
+
+func newTestProfile() *entity.Profile {
+	p := entity.NewProfile("SUPPORT", []string{"users:read", "profiles:read"})
+	p.ID = 3
+	return p
+}
+
+func TestUpdateProfile_ConcurrentUpdateIsRefused(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		versions []uint
+	}{
+		{"with If-Match", []uint{1}},
+		{"without If-Match", nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := outputtest.NewProfileRepository(newTestProfile())
+			repo.UpdateErr = output.ErrStaleVersion
+			uc := profile.NewProfileUseCase(repo, nil)
+			name := "HELPDESK"
+
+			_, err := uc.UpdateProfile(context.Background(), 3, &dto.ProfileInput{Name: &name, Versions: tt.versions})
+			assert.True(t, apperror.IsCode(err, apperror.CodeConflict))
+			assert.NotSame(t, output.ErrStaleVersion, err, "repository errors are not returned as is")
+
+			_, err = uc.PatchProfile(context.Background(), 3, &dto.PatchInput{Format: dto.MergePatch, Patch: []byte(`{"name":"HELPDESK"}`), Versions: tt.versions})
+			assert.True(t, apperror.IsCode(err, apperror.CodeConflict))
+		})
+	}
+}
+
+func TestPatchProfile(t *testing.T) {
+	p := newTestProfile()
+	p.RequireTwoFactor = true
+	p.SetPasswordPolicy(&passwd.Policy{MinLength: 12})
+	repo := outputtest.NewProfileRepository(p)
+	uc := profile.NewProfileUseCase(repo, nil)
+	ctx := context.Background()
+
+	out, err := uc.PatchProfile(ctx, p.ID, &dto.PatchInput{Format: dto.MergePatch, Patch: []byte(`{"name":"HELPDESK","password_policy":null}`)})
+	require.NoError(t, err)
+	assert.Equal(t, "HELPDESK", p.Name)
+	assert.Nil(t, p.PasswordPolicy, "a removed password policy is cleared")
+	assert.True(t, p.RequireTwoFactor, "members left out of a merge patch are kept")
+	assert.Equal(t, p.Permissions, *out.Permissions)
+
+	_, err = uc.PatchProfile(ctx, p.ID, &dto.PatchInput{Format: dto.JSONPatch, Patch: []byte(`[
+		{"op":"test","path":"/require_2fa","value":true},
+		{"op":"replace","path":"/require_2fa","value":false},
+		{"op":"add","path":"/permissions/-","value":"audit:read"}
+	]`)})
+	require.NoError(t, err)
+	assert.False(t, p.RequireTwoFactor)
+	assert.Equal(t, []string{"users:read", "profiles:read", "audit:read"}, p.Permissions)
+
+	_, err = uc.PatchProfile(ctx, p.ID, &dto.PatchInput{Format: dto.JSONPatch, Patch: []byte(`[{"op":"remove","path":"/permissions"}]`)})
+	require.NoError(t, err)
+	assert.Empty(t, p.Permissions, "removed permissions are cleared")
+	assert.NotNil(t, p.Permissions)
+	assert.Same(t, p, repo.Updated)
+}
+
+func TestPatchProfile_Refused(t *testing.T) {
+	patches := map[string]*dto.PatchInput{
+		"stale If-Match": {Format: dto.MergePatch, Patch: []byte(`{"name":"HELPDESK"}`), Versions: []uint{7}},
+		"failed test":    {Format: dto.JSONPatch, Patch: []byte(`[{"op":"test","path":"/name","value":"ADMIN"}]`)},
+		"unknown member": {Format: dto.MergePatch, Patch: []byte(`{"organization_id":2}`)},
+		"short name":     {Format: dto.MergePatch, Patch: []byte(`{"name":"HR"}`)},
+	}
+	codes := map[string]apperror.Code{"stale If-Match": apperror.CodeConflict, "failed test": apperror.CodeConflict}
+	for _, member := range []string{"name", "require_2fa", "require_verified_email"} {
+		patches["removed "+member] = &dto.PatchInput{Format: dto.JSONPatch, Patch: []byte(`[{"op":"remove","path":"/` + member + `"}]`)}
+		patches["null "+member] = &dto.PatchInput{Format: dto.MergePatch, Patch: []byte(`{"` + member + `":null}`)}
+	}
+
+	for name, patch := range patches {
+		t.Run(name, func(t *testing.T) {
+			repo := outputtest.NewProfileRepository(newTestProfile())
+			uc := profile.NewProfileUseCase(repo, nil)
+
+			code, ok := codes[name]
+			if !ok {
+				code = apperror.CodeInvalidInput
+			}
+			_, err := uc.PatchProfile(context.Background(), 3, patch)
+			assert.True(t, apperror.IsCode(err, code), "got %v", err)
+			assert.Nil(t, repo.Updated)
+		})
+	}
+}
+
+func TestDeleteProfiles_OnlyVisibleProfilesAreDeleted(t *testing.T) {
+	repo := outputtest.NewProfileRepository(newTestProfile())
+	uc := profile.NewProfileUseCase(repo, nil)
+
+	require.NoError(t, uc.DeleteProfiles(context.Background(), []uint{3, 9}))
+	assert.Equal(t, []uint{3}, repo.Deleted, "profiles that were not found are neither deleted nor recorded")
+
+	repo.Deleted = nil
+	err := uc.DeleteProfiles(context.Background(), []uint{9})
+	assert.True(t, apperror.IsCode(err, apperror.CodeProfileNotFound))
+	assert.Nil(t, repo.Deleted)
+}
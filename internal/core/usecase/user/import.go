@@ -0,0 +1,304 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/pkg/apperror"
+	"github.com/raulaguila/go-api/pkg/utils"
+)
+
+// Default headers of the columns of an import
+const (
+	importNameColumn     = "name"
+	importUsernameColumn = "username"
+	importEmailColumn    = "email"
+	importProfileColumn  = "profile_id"
+)
+
+// importColumns holds the index of the column of each user field, -1 for absent columns
+type importColumns struct {
+	name, username, email, profile int
+}
+
+// importRow is a row of an import being validated
+type importRow struct {
+	line           int
+	name, username string
+	email          string
+	profileID      string
+}
+
+// ImportUsers creates users from the rows of a spreadsheet. Invalid rows, and rows of users whose
+// email or username is registered in any organization, create no user and are reported; the users
+// of the other rows are created in one transaction, or only validated in a dry run. Users are
+// created like CreateUser does, with the welcome message to set their password, or pending like
+// InviteUser does when invitations are requested.
+func (uc *userUseCase) ImportUsers(ctx context.Context, input *dto.UserImportInput) (*dto.UserImportOutput, error) {
+	if len(input.Rows) == 0 {
+		return nil, apperror.InvalidInput("file", "the file has no rows")
+	}
+	if limit := uc.config.ImportMaxRows; limit > 0 && len(input.Rows)-1 > limit {
+		return nil, apperror.InvalidInput("file", fmt.Sprintf("the file has more than %d rows", limit))
+	}
+	columns, err := findImportColumns(input)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &dto.UserImportOutput{DryRun: input.DryRun, Rows: []dto.UserImportRowOutput{}}
+	profiles := map[uint]*entity.Profile{}
+	emails, usernames := map[string]int{}, map[string]int{}
+	var users []*entity.User
+	var lines []int
+
+	for i, cells := range input.Rows[1:] {
+		row, ok := columns.read(i+2, cells)
+		if !ok {
+			continue
+		}
+
+		user, errs := uc.importUser(ctx, row, input, profiles)
+
+		// Users appearing twice in the file cannot both be created
+		if line := firstLine(emails, strings.ToLower(row.email), row.line); line != row.line {
+			errs = append(errs, fmt.Sprintf("email repeats row %d", line))
+		}
+		if line := firstLine(usernames, strings.ToLower(row.username), row.line); line != row.line {
+			errs = append(errs, fmt.Sprintf("username repeats row %d", line))
+		}
+
+		if len(errs) > 0 {
+			output.Failed++
+			output.Rows = append(output.Rows, dto.UserImportRowOutput{Row: row.line, Errors: errs})
+			continue
+		}
+		users = append(users, user)
+		lines = append(lines, row.line)
+	}
+
+	users, lines, err = uc.skipRegistered(ctx, users, lines, output)
+	if err != nil {
+		return nil, err
+	}
+
+	if !input.DryRun && len(users) > 0 {
+		action := entity.AuditActionCreate
+		if input.Invite {
+			action = entity.AuditActionInvite
+		}
+		err := uc.config.Audit.Transaction(ctx, func(ctx context.Context) error {
+			skipped, err := uc.userRepo.CreateBatch(ctx, users)
+			if err != nil {
+				return err
+			}
+			// Users registered since they were checked, by another import or request
+			for _, i := range slices.Backward(skipped) {
+				output.Skipped++
+				output.Rows = append(output.Rows, dto.UserImportRowOutput{Row: lines[i], Skipped: true, Errors: []string{"email or username already registered"}})
+				users = slices.Delete(users, i, i+1)
+			}
+			for _, user := range users {
+				if err := uc.config.Audit.User(ctx, action, user, nil, user.AuditState()); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortFunc(output.Rows, func(a, b dto.UserImportRowOutput) int { return a.Row - b.Row })
+	output.Created = len(users)
+	if input.DryRun {
+		return output, nil
+	}
+
+	// Best-effort: invitations can be resent, and users can request a password reset,
+	// if the message cannot be queued
+	for _, user := range users {
+		if input.Invite {
+			_ = uc.sendInvitation(ctx, user)
+		} else {
+			_ = uc.sendWelcome(ctx, user)
+		}
+	}
+
+	return output, nil
+}
+
+// importUser builds the user of a row, returning why the row is invalid if it is
+func (uc *userUseCase) importUser(ctx context.Context, row importRow, input *dto.UserImportInput, profiles map[uint]*entity.Profile) (*entity.User, []string) {
+	var errs []string
+
+	profile, err := uc.importProfile(ctx, row.profileID, input.ProfileID, profiles)
+	if err != nil {
+		errs = append(errs, importError(err))
+	}
+
+	var auth *entity.Auth
+	if profile != nil {
+		if auth, err = entity.NewAuth(profile.ID, true); err != nil {
+			return nil, append(errs, importError(err))
+		}
+		auth.Pending = input.Invite
+	}
+
+	user, err := entity.NewUser(row.name, row.username, row.email, auth)
+	// Without a profile, the other fields are valid if only the profile is missing
+	if err != nil && (auth != nil || err.Error() != entity.ErrProfileRequired().Error()) {
+		errs = append(errs, importError(err))
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	user.SetProfile(profile)
+	return user, nil
+}
+
+// importProfile returns the profile a row names, or the default one of the import. Only profiles
+// visible within ctx can be assigned; those already looked up are kept in profiles.
+func (uc *userUseCase) importProfile(ctx context.Context, cell string, defaultID *uint, profiles map[uint]*entity.Profile) (*entity.Profile, error) {
+	id := utils.Deref(defaultID, uint(0))
+	if cell != "" {
+		parsed, err := strconv.ParseUint(cell, 10, 0)
+		if err != nil {
+			return nil, apperror.InvalidInput("profile_id", fmt.Sprintf("invalid profile %q", cell))
+		}
+		id = uint(parsed)
+	}
+	if id == 0 {
+		return nil, entity.ErrProfileRequired()
+	}
+
+	profile, ok := profiles[id]
+	if !ok {
+		profile, _ = uc.profileRepo.FindByID(ctx, id)
+		profiles[id] = profile
+	}
+	if profile == nil {
+		return nil, apperror.ProfileNotFound()
+	}
+	return profile, nil
+}
+
+// skipRegistered reports the users whose email or username is already registered, in any
+// organization and in any case, and returns the others with their lines
+func (uc *userUseCase) skipRegistered(ctx context.Context, users []*entity.User, lines []int, output *dto.UserImportOutput) ([]*entity.User, []int, error) {
+	if len(users) == 0 {
+		return users, lines, nil
+	}
+	emails, usernames := make([]string, len(users)), make([]string, len(users))
+	for i, user := range users {
+		emails[i], usernames[i] = user.Email, user.Username
+	}
+	registeredEmails, registeredUsernames, err := uc.userRepo.FindRegistered(ctx, emails, usernames)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var kept []*entity.User
+	var keptLines []int
+	for i, user := range users {
+		var errs []string
+		if registeredEmails[strings.ToLower(user.Email)] {
+			errs = append(errs, "email already registered")
+		}
+		if registeredUsernames[strings.ToLower(user.Username)] {
+			errs = append(errs, "username already registered")
+		}
+		if len(errs) > 0 {
+			output.Skipped++
+			output.Rows = append(output.Rows, dto.UserImportRowOutput{Row: lines[i], Skipped: true, Errors: errs})
+			continue
+		}
+		kept = append(kept, user)
+		keptLines = append(keptLines, lines[i])
+	}
+	return kept, keptLines, nil
+}
+
+// findImportColumns finds the columns of the user fields in the headers of an import.
+// The profile column is only required when the import sets no default profile.
+func findImportColumns(input *dto.UserImportInput) (importColumns, error) {
+	headers := map[string]int{}
+	for i, header := range input.Rows[0] {
+		headers[strings.ToLower(strings.TrimSpace(header))] = i
+	}
+	find := func(field, header, fallback string, required bool) (int, error) {
+		if header == "" {
+			header = fallback
+		}
+		if i, ok := headers[strings.ToLower(strings.TrimSpace(header))]; ok {
+			return i, nil
+		}
+		if required {
+			return -1, apperror.InvalidInput(field, fmt.Sprintf("no %q column in the file", header))
+		}
+		return -1, nil
+	}
+
+	var columns importColumns
+	var err error
+	if columns.name, err = find("name_column", input.NameColumn, importNameColumn, true); err != nil {
+		return columns, err
+	}
+	if columns.username, err = find("username_column", input.UsernameColumn, importUsernameColumn, true); err != nil {
+		return columns, err
+	}
+	if columns.email, err = find("email_column", input.EmailColumn, importEmailColumn, true); err != nil {
+		return columns, err
+	}
+	columns.profile, err = find("profile_column", input.ProfileColumn, importProfileColumn, input.ProfileID == nil)
+	return columns, err
+}
+
+// read reads the cells of a row, which is false for blank rows
+func (c importColumns) read(line int, cells []string) (importRow, bool) {
+	cell := func(column int) string {
+		if column < 0 || column >= len(cells) {
+			return ""
+		}
+		return strings.TrimSpace(cells[column])
+	}
+
+	row := importRow{
+		line:      line,
+		name:      cell(c.name),
+		username:  cell(c.username),
+		email:     cell(c.email),
+		profileID: cell(c.profile),
+	}
+	return row, row != importRow{line: line}
+}
+
+// firstLine returns the line a value first appeared on, recording it on line if it did not yet.
+// Blank values never repeat.
+func firstLine(lines map[string]int, value string, line int) int {
+	if value == "" {
+		return line
+	}
+	if first, ok := lines[value]; ok {
+		return first
+	}
+	lines[value] = line
+	return line
+}
+
+// importError returns the message of an error for the report of a row
+func importError(err error) string {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		return appErr.Message
+	}
+	return err.Error()
+}
@@ -0,0 +1,177 @@
+package user_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/output/outputtest"
+	"github.com/raulaguila/go-api/internal/core/usecase/user"
+	"github.com/raulaguila/go-api/pkg/apperror"
+)
+
+func TestImportUsers_DryRunReportsRows(t *testing.T) {
+	userRepo := new(outputtest.UserRepository)
+	uc := user.NewUserUseCase(userRepo, outputtest.NewProfileRepository(&entity.Profile{ID: 2, Name: "USER"}), nil, &fakeTokenRepo{}, nil, nil, &fakeNotifier{}, user.Config{})
+	ctx := context.Background()
+	profileID := uint(2)
+
+	// Registered to users of other organizations, in another case
+	userRepo.On("FindRegistered", ctx, mock.Anything, mock.Anything).
+		Return(map[string]bool{"taken@example.com": true}, map[string]bool{"maryminor": true}, nil)
+
+	output, err := uc.ImportUsers(ctx, &dto.UserImportInput{
+		NameColumn:     "Full name",
+		UsernameColumn: "Login",
+		EmailColumn:    "E-mail",
+		ProfileColumn:  "Profile",
+		ProfileID:      &profileID,
+		DryRun:         true,
+		Rows: [][]string{
+			{"Full name", "Login", "E-mail", "Profile"},
+			{"John Doe", "johndoe", "john@example.com"},
+			{"Jo", "jodoe", "jo@example.com"},
+			{"Mary Major", "marymajor", "TAKEN@example.com"},
+			{"Mary Minor", "MaryMinor", "mary@example.com"},
+			{"", "", "", ""},
+			{"Johnny Doe", "johnnydoe", "JOHN@example.com"},
+			{"Jane Roe", "janeroe", "jane@example.com", "9"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, output.DryRun)
+	assert.Equal(t, 1, output.Created)
+	assert.Equal(t, 2, output.Skipped)
+	assert.Equal(t, 3, output.Failed)
+	assert.Equal(t, []dto.UserImportRowOutput{
+		{Row: 3, Errors: []string{"name must be at least 5 characters"}},
+		{Row: 4, Skipped: true, Errors: []string{"email already registered"}},
+		{Row: 5, Skipped: true, Errors: []string{"username already registered"}},
+		{Row: 7, Errors: []string{"email repeats row 2"}},
+		{Row: 8, Errors: []string{"profile not found"}},
+	}, output.Rows)
+	userRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+}
+
+func TestImportUsers_CreatesAndInvites(t *testing.T) {
+	userRepo, tokens, notifier := new(outputtest.UserRepository), &fakeTokenRepo{}, &fakeNotifier{}
+	uc := user.NewUserUseCase(userRepo, outputtest.NewProfileRepository(&entity.Profile{ID: 2, Name: "USER"}), nil, tokens, nil, nil, notifier, user.Config{InvitationExpiration: time.Hour})
+	ctx := context.Background()
+
+	userRepo.On("FindRegistered", ctx, mock.Anything, mock.Anything).Return(map[string]bool{}, map[string]bool{}, nil)
+	userRepo.On("CreateBatch", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		for i, u := range args.Get(1).([]*entity.User) {
+			u.ID = uint(10 + i)
+		}
+	}).Return(nil, nil)
+
+	output, err := uc.ImportUsers(ctx, &dto.UserImportInput{
+		Invite: true,
+		Rows: [][]string{
+			{"name", "username", "email", "profile_id"},
+			{"John Doe", "johndoe", "john@example.com", "2"},
+			{"Jane Roe", "janeroe", "jane@example.com", "2"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, output.Created)
+	assert.Empty(t, output.Rows)
+	users := userRepo.Calls[len(userRepo.Calls)-1].Arguments.Get(1).([]*entity.User)
+	require.Len(t, users, 2)
+	for _, u := range users {
+		assert.True(t, u.IsPending(), "invited users accept the invitation to set their password")
+		assert.Equal(t, uint(2), u.Auth.ProfileID)
+	}
+	assert.Len(t, tokens.tokens, 2)
+	assert.NotEmpty(t, notifier.token)
+}
+
+func TestImportUsers_CreatedUsersSetTheirPassword(t *testing.T) {
+	userRepo, tokens, notifier := new(outputtest.UserRepository), &fakeTokenRepo{}, &fakeNotifier{}
+	uc := user.NewUserUseCase(userRepo, outputtest.NewProfileRepository(&entity.Profile{ID: 2, Name: "USER"}), nil, tokens, nil, nil, notifier, user.Config{PasswordResetExpiration: time.Hour})
+	ctx := context.Background()
+
+	userRepo.On("FindRegistered", ctx, mock.Anything, mock.Anything).Return(map[string]bool{}, map[string]bool{}, nil)
+	userRepo.On("CreateBatch", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		for i, u := range args.Get(1).([]*entity.User) {
+			u.ID = uint(10 + i)
+		}
+	}).Return(nil, nil)
+
+	output, err := uc.ImportUsers(ctx, &dto.UserImportInput{
+		Rows: [][]string{
+			{"name", "username", "email", "profile_id"},
+			{"John Doe", "johndoe", "john@example.com", "2"},
+			{"Jane Roe", "janeroe", "jane@example.com", "2"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, output.Created)
+	require.Len(t, tokens.tokens, 2, "created users are welcomed like CreateUser does")
+	for _, token := range tokens.tokens {
+		assert.Equal(t, entity.TokenPurposePasswordReset, token.Purpose)
+	}
+	assert.NotEmpty(t, notifier.token)
+}
+
+func TestImportUsers_SkipsUsersRegisteredMeanwhile(t *testing.T) {
+	userRepo, tokens := new(outputtest.UserRepository), &fakeTokenRepo{}
+	uc := user.NewUserUseCase(userRepo, outputtest.NewProfileRepository(&entity.Profile{ID: 2, Name: "USER"}), nil, tokens, nil, nil, &fakeNotifier{}, user.Config{InvitationExpiration: time.Hour})
+	ctx := context.Background()
+
+	userRepo.On("FindRegistered", ctx, []string{"john@example.com", "jane@example.com"}, []string{"johndoe", "janeroe"}).
+		Return(map[string]bool{}, map[string]bool{}, nil)
+	userRepo.On("CreateBatch", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).([]*entity.User)[1].ID = 11
+	}).Return([]int{0}, nil)
+
+	output, err := uc.ImportUsers(ctx, &dto.UserImportInput{
+		Invite: true,
+		Rows: [][]string{
+			{"name", "username", "email", "profile_id"},
+			{"John Doe", "johndoe", "john@example.com", "2"},
+			{"Jane Roe", "janeroe", "jane@example.com", "2"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, output.Created)
+	assert.Equal(t, 1, output.Skipped)
+	assert.Equal(t, []dto.UserImportRowOutput{
+		{Row: 2, Skipped: true, Errors: []string{"email or username already registered"}},
+	}, output.Rows)
+	assert.Len(t, tokens.tokens, 1, "skipped users are not invited")
+}
+
+func TestImportUsers_MissingColumn(t *testing.T) {
+	uc := user.NewUserUseCase(new(outputtest.UserRepository), outputtest.NewProfileRepository(), nil, &fakeTokenRepo{}, nil, nil, &fakeNotifier{}, user.Config{})
+
+	_, err := uc.ImportUsers(context.Background(), &dto.UserImportInput{
+		Rows: [][]string{{"name", "username", "email"}, {"John Doe", "johndoe", "john@example.com"}},
+	})
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidInput), "rows naming no profile need a default one")
+}
+
+func TestImportUsers_TooManyRows(t *testing.T) {
+	userRepo := new(outputtest.UserRepository)
+	uc := user.NewUserUseCase(userRepo, outputtest.NewProfileRepository(&entity.Profile{ID: 2, Name: "USER"}), nil, &fakeTokenRepo{}, nil, nil, &fakeNotifier{}, user.Config{ImportMaxRows: 1})
+
+	_, err := uc.ImportUsers(context.Background(), &dto.UserImportInput{
+		Rows: [][]string{
+			{"name", "username", "email", "profile_id"},
+			{"John Doe", "johndoe", "john@example.com", "2"},
+			{"Jane Roe", "janeroe", "jane@example.com", "2"},
+		},
+	})
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidInput))
+	userRepo.AssertNotCalled(t, "FindRegistered", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -2,26 +2,64 @@ package user
 
 import (
 	"context"
-
-	"github.com/google/uuid"
+	"errors"
+	"slices"
+	"time"
 
 	"github.com/raulaguila/go-api/internal/core/domain/entity"
 	"github.com/raulaguila/go-api/internal/core/dto"
 	"github.com/raulaguila/go-api/internal/core/port/input"
 	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/internal/core/usecase/audit"
+	"github.com/raulaguila/go-api/pkg/actor"
 	"github.com/raulaguila/go-api/pkg/apperror"
+	"github.com/raulaguila/go-api/pkg/passwd"
 	"github.com/raulaguila/go-api/pkg/utils"
 )
 
+// Config holds user account configuration
+type Config struct {
+	PasswordResetExpiration     time.Duration
+	InvitationExpiration        time.Duration
+	EmailVerificationExpiration time.Duration
+	ImportMaxRows               int           // Maximum rows of an import, headers excluded; 0 for no limit
+	PasswordPolicy              passwd.Policy // Applies to users whose profile sets none
+	PasswordHasher              output.PasswordHasher
+	Audit                       *audit.Recorder // Records the changes made to users; nil records nothing
+}
+
 // userUseCase implements the UserUseCase interface
 type userUseCase struct {
-	userRepo output.UserRepository
+	userRepo    output.UserRepository
+	profileRepo output.ProfileRepository
+	sessionRepo output.SessionRepository
+	tokenRepo   output.UserTokenRepository
+	revocations output.RevocationStore
+	throttle    output.LoginThrottle
+	notifier    output.Notifier
+	config      Config
 }
 
 // NewUserUseCase creates a new UserUseCase instance
-func NewUserUseCase(userRepo output.UserRepository) input.UserUseCase {
+func NewUserUseCase(
+	userRepo output.UserRepository,
+	profileRepo output.ProfileRepository,
+	sessionRepo output.SessionRepository,
+	tokenRepo output.UserTokenRepository,
+	revocations output.RevocationStore,
+	throttle output.LoginThrottle,
+	notifier output.Notifier,
+	config Config,
+) input.UserUseCase {
 	return &userUseCase{
-		userRepo: userRepo,
+		userRepo:    userRepo,
+		profileRepo: profileRepo,
+		sessionRepo: sessionRepo,
+		tokenRepo:   tokenRepo,
+		revocations: revocations,
+		throttle:    throttle,
+		notifier:    notifier,
+		config:      config,
 	}
 }
 
@@ -75,7 +113,15 @@ func (uc *userUseCase) CreateUser(ctx context.Context, input *dto.UserInput) (*d
 		return nil, err
 	}
 
-	if err := uc.userRepo.Create(ctx, user); err != nil {
+	if err := uc.assignProfile(ctx, user, auth.ProfileID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.saveAudited(ctx, entity.AuditActionCreate, user, nil, uc.userRepo.Create); err != nil {
+		return nil, err
+	}
+
+	if err := uc.sendWelcome(ctx, user); err != nil {
 		return nil, err
 	}
 
@@ -94,6 +140,54 @@ func (uc *userUseCase) UpdateUser(ctx context.Context, id uint, input *dto.UserI
 	if err != nil {
 		return nil, apperror.UserNotFound()
 	}
+	// An update conditioned on another version would overwrite changes its client never saw
+	if input.Versions != nil && !slices.Contains(input.Versions, user.Version) {
+		return nil, apperror.StaleVersion("user")
+	}
+
+	return uc.update(ctx, user, input)
+}
+
+// PatchUser updates an existing user with a JSON Merge Patch or a JSON Patch of its UserDocument
+func (uc *userUseCase) PatchUser(ctx context.Context, id uint, patch *dto.PatchInput) (*dto.UserOutput, error) {
+	user, err := uc.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperror.UserNotFound()
+	}
+	if patch.Versions != nil && !slices.Contains(patch.Versions, user.Version) {
+		return nil, apperror.StaleVersion("user")
+	}
+
+	current := dto.EntityToUserDocument(user)
+	patched, err := dto.ApplyPatch(current, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the members the patch changed are updated, as if they were the only ones sent to UpdateUser
+	input := &dto.UserInput{Versions: patch.Versions}
+	if *patched.Name != *current.Name {
+		input.Name = patched.Name
+	}
+	if *patched.Username != *current.Username {
+		input.Username = patched.Username
+	}
+	if *patched.Email != *current.Email {
+		input.Email = patched.Email
+	}
+	if *patched.Status != *current.Status {
+		input.Status = patched.Status
+	}
+	if *patched.ProfileID != *current.ProfileID {
+		input.ProfileID = patched.ProfileID
+	}
+
+	return uc.update(ctx, user, input)
+}
+
+// update applies input to user, which is then validated and saved
+func (uc *userUseCase) update(ctx context.Context, user *entity.User, input *dto.UserInput) (*dto.UserOutput, error) {
+	before := user.AuditState()
 
 	if input.Name != nil {
 		user.UpdateName(*input.Name)
@@ -101,30 +195,60 @@ func (uc *userUseCase) UpdateUser(ctx context.Context, id uint, input *dto.UserI
 	if input.Username != nil {
 		user.UpdateUsername(*input.Username)
 	}
+	// Email changes are pending until the user confirms the new address
+	emailChanged, emailChangeCancelled := false, false
 	if input.Email != nil {
-		user.UpdateEmail(*input.Email)
+		hadPendingEmail := user.PendingEmail != nil
+		emailChanged = user.ChangeEmail(*input.Email)
+		emailChangeCancelled = hadPendingEmail && user.PendingEmail == nil
 	}
+	disabled := false
 	if input.Status != nil && user.Auth != nil {
+		disabled = user.Auth.Status && !*input.Status
 		if *input.Status {
 			user.Auth.Enable()
 		} else {
 			user.Auth.Disable()
 		}
 	}
-	if input.ProfileID != nil && user.Auth != nil {
-		user.Auth.ProfileID = *input.ProfileID
+	if input.ProfileID != nil {
+		if err := uc.assignProfile(ctx, user, *input.ProfileID); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := user.Validate(); err != nil {
 		return nil, err
 	}
 
-	if err := uc.userRepo.Update(ctx, user); err != nil {
+	if err := uc.saveAudited(ctx, entity.AuditActionUpdate, user, before, uc.userRepo.Update); err != nil {
+		if errors.Is(err, output.ErrStaleVersion) {
+			return nil, apperror.StaleVersion("user")
+		}
 		return nil, err
 	}
 
+	if disabled {
+		// Best-effort: the account is disabled even if the message cannot be queued
+		_ = uc.notifier.SendAccountDisabled(ctx, user)
+	}
+
+	switch {
+	case emailChanged:
+		if err := uc.sendEmailVerification(ctx, user); err != nil {
+			return nil, err
+		}
+		// Best-effort: warn the current address, in case the change was not requested by its owner
+		_ = uc.notifier.SendEmailChangeRequested(ctx, user, *user.PendingEmail)
+	case emailChangeCancelled:
+		// The verification sent to the abandoned address must not verify the current one
+		if err := uc.tokenRepo.DeleteByUser(ctx, user.ID, entity.TokenPurposeEmailVerification); err != nil {
+			return nil, err
+		}
+	}
+
 	// Reload user with relations
-	user, err = uc.userRepo.FindByID(ctx, id)
+	user, err := uc.userRepo.FindByID(ctx, user.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -132,48 +256,442 @@ func (uc *userUseCase) UpdateUser(ctx context.Context, id uint, input *dto.UserI
 	return dto.EntityToUserOutput(user), nil
 }
 
-// DeleteUsers deletes users by their IDs
+// saveAudited writes a user with write and records it as action in one transaction, given the
+// user's state before the change (nil for created users)
+func (uc *userUseCase) saveAudited(ctx context.Context, action string, user *entity.User, before entity.AuditState, write func(context.Context, *entity.User) error) error {
+	return uc.config.Audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := write(ctx, user); err != nil {
+			return err
+		}
+		return uc.config.Audit.User(ctx, action, user, before, user.AuditState())
+	})
+}
+
+// assignProfile assigns a profile to a user, who joins its organization. Only profiles
+// visible within ctx can be assigned, so no organization can grant another one's permissions.
+func (uc *userUseCase) assignProfile(ctx context.Context, user *entity.User, profileID uint) error {
+	profile, err := uc.profileRepo.FindByID(ctx, profileID)
+	if err != nil {
+		return apperror.ProfileNotFound()
+	}
+	user.SetProfile(profile)
+	return nil
+}
+
+// DeleteUsers moves users to the trash by their IDs, signing them out of every device so that
+// restored users sign in again
 func (uc *userUseCase) DeleteUsers(ctx context.Context, ids []uint) error {
-	return uc.userRepo.Delete(ctx, ids)
+	// Only users visible within ctx are deleted, so only their sessions are revoked
+	var visible []*entity.User
+	var visibleIDs []uint
+	for _, id := range ids {
+		if user, err := uc.userRepo.FindByID(ctx, id); err == nil {
+			visible = append(visible, user)
+			visibleIDs = append(visibleIDs, id)
+		}
+	}
+	if len(visible) == 0 {
+		return apperror.UserNotFound()
+	}
+
+	err := uc.config.Audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.Delete(ctx, visibleIDs); err != nil {
+			return err
+		}
+		for _, user := range visible {
+			if err := uc.config.Audit.User(ctx, entity.AuditActionDelete, user, user.AuditState(), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// A failure to sign out one user does not keep the others signed in
+	var errs []error
+	for _, user := range visible {
+		errs = append(errs, uc.revokeSessions(ctx, user.ID))
+	}
+	return errors.Join(errs...)
 }
 
-// ResetPassword resets a user's password
+// RestoreUsers takes users out of the trash by their IDs
+func (uc *userUseCase) RestoreUsers(ctx context.Context, ids []uint) error {
+	// Users found before the restore were not in the trash
+	var trashed []uint
+	for _, id := range ids {
+		if _, err := uc.userRepo.FindByID(ctx, id); err != nil {
+			trashed = append(trashed, id)
+		}
+	}
+
+	return uc.config.Audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.Restore(ctx, ids); err != nil {
+			return err
+		}
+		for _, id := range trashed {
+			user, err := uc.userRepo.FindByID(ctx, id)
+			if err != nil {
+				continue
+			}
+			if err := uc.config.Audit.User(ctx, entity.AuditActionRestore, user, nil, user.AuditState()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetSessions returns the active sessions of a user, most recently active first
+func (uc *userUseCase) GetSessions(ctx context.Context, id uint, currentSessionID string) ([]dto.SessionOutput, error) {
+	if _, err := uc.userRepo.FindByID(ctx, id); err != nil {
+		return nil, apperror.UserNotFound()
+	}
+
+	sessions, err := uc.sessionRepo.FindActiveByUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.EntitiesToSessionOutputs(sessions, currentSessionID), nil
+}
+
+// RevokeSession revokes a single session of a user; sessions of other users are reported as not found
+func (uc *userUseCase) RevokeSession(ctx context.Context, id uint, sessionID string) error {
+	if _, err := uc.userRepo.FindByID(ctx, id); err != nil {
+		return apperror.UserNotFound()
+	}
+
+	session, err := uc.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil || session.UserID != id || !session.IsActive() {
+		return apperror.SessionNotFound()
+	}
+
+	if err := uc.sessionRepo.Revoke(ctx, session.ID); err != nil {
+		return err
+	}
+	before := session.AuditState()
+	session.Revoke()
+	if err := uc.config.Audit.Session(ctx, entity.AuditActionRevokeSession, session, before, session.AuditState()); err != nil {
+		return err
+	}
+	return uc.revocations.RevokeSession(ctx, session.ID, session.RemainingLifetime())
+}
+
+// RevokeSessions revokes every session of a user
+func (uc *userUseCase) RevokeSessions(ctx context.Context, id uint) error {
+	user, err := uc.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return apperror.UserNotFound()
+	}
+
+	if err := uc.revokeSessions(ctx, id); err != nil {
+		return err
+	}
+	return uc.config.Audit.User(ctx, entity.AuditActionRevokeAllSessions, user, nil, nil)
+}
+
+// Unlock clears the failed logins of a user, lifting a lockout
+func (uc *userUseCase) Unlock(ctx context.Context, id uint) error {
+	user, err := uc.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return apperror.UserNotFound()
+	}
+
+	if err := uc.throttle.Reset(ctx, entity.AccountThrottleKey(id)); err != nil {
+		return err
+	}
+	return uc.config.Audit.User(ctx, entity.AuditActionUnlock, user, nil, nil)
+}
+
+// revokeSessions revokes the user's sessions in the repository and in the revocation store
+func (uc *userUseCase) revokeSessions(ctx context.Context, userID uint) error {
+	if err := uc.sessionRepo.RevokeByUser(ctx, userID); err != nil {
+		return err
+	}
+	return uc.revocations.RevokeUser(ctx, userID)
+}
+
+// ResetPassword issues a single-use password reset token and delivers it to the user.
+// Unknown emails are ignored so the caller cannot tell which accounts exist, and so are
+// pending users, who set their first password by accepting the invitation.
 func (uc *userUseCase) ResetPassword(ctx context.Context, email string) error {
 	user, err := uc.userRepo.FindByEmail(ctx, email)
+	if err != nil || user.IsPending() {
+		return nil
+	}
+
+	token, secret, err := uc.issueToken(ctx, user, entity.TokenPurposePasswordReset, uc.config.PasswordResetExpiration)
 	if err != nil {
-		return apperror.UserNotFound()
+		return err
+	}
+
+	return uc.notifier.SendPasswordReset(ctx, user, secret, token.ExpiresAt)
+}
+
+// issueToken creates a new single-use token for the user, valid for ttl, invalidating the
+// previous ones with the same purpose, and returns it with its plain secret
+func (uc *userUseCase) issueToken(ctx context.Context, user *entity.User, purpose entity.TokenPurpose, ttl time.Duration) (*entity.UserToken, string, error) {
+	// Only the latest issued token stays valid
+	if err := uc.tokenRepo.DeleteByUser(ctx, user.ID, purpose); err != nil {
+		return nil, "", err
+	}
+
+	token, secret, err := entity.NewUserToken(user.ID, purpose, ttl)
+	if err != nil {
+		return nil, "", err
 	}
 
-	if user.Auth == nil || (user.Auth.Password == nil && user.Auth.Token == nil) {
-		return nil // Already reset
+	if err := uc.tokenRepo.Create(ctx, token); err != nil {
+		return nil, "", err
 	}
 
-	user.ResetPassword()
-	return uc.userRepo.Update(ctx, user)
+	return token, secret, nil
 }
 
-// SetPassword sets a user's password
-func (uc *userUseCase) SetPassword(ctx context.Context, email string, input *dto.PasswordInput) error {
+// SetPassword sets a user's password using a password reset token
+func (uc *userUseCase) SetPassword(ctx context.Context, input *dto.PasswordInput) error {
 	if err := input.Validate(); err != nil {
 		return err
 	}
 
-	user, err := uc.userRepo.FindByEmail(ctx, email)
+	token, err := uc.tokenRepo.FindByHash(ctx, entity.TokenPurposePasswordReset, entity.HashToken(input.Token))
+	if err != nil || !token.IsValid() {
+		return apperror.InvalidToken()
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, token.UserID)
 	if err != nil {
-		return apperror.UserNotFound()
+		return apperror.InvalidToken()
+	}
+	before := user.AuditState()
+
+	if err := user.ChangePassword(input.Password, user.PasswordPolicy(uc.config.PasswordPolicy), uc.config.PasswordHasher); err != nil {
+		return err
+	}
+	// The token was delivered to the current email
+	user.MarkEmailVerified()
+
+	// Consuming is conditional, so a token can only be used once even under concurrent requests
+	if err := uc.tokenRepo.Consume(ctx, token); err != nil {
+		return apperror.InvalidToken()
+	}
+
+	if err := uc.config.Audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return uc.config.Audit.User(actingAs(ctx, user), entity.AuditActionSetPassword, user, before, user.AuditState())
+	}); err != nil {
+		return err
+	}
+
+	// Sign the user out of every device
+	return uc.revokeSessions(ctx, user.ID)
+}
+
+// InviteUser creates a pending user and delivers an invitation to accept by setting the first password
+func (uc *userUseCase) InviteUser(ctx context.Context, input *dto.UserInput) (*dto.UserOutput, error) {
+	auth, err := entity.NewAuth(utils.Deref(input.ProfileID, uint(0)), true)
+	if err != nil {
+		return nil, err
+	}
+	auth.Pending = true
+
+	user, err := entity.NewUser(
+		utils.Deref(input.Name, ""),
+		utils.Deref(input.Username, ""),
+		utils.Deref(input.Email, ""),
+		auth,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.assignProfile(ctx, user, auth.ProfileID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.saveAudited(ctx, entity.AuditActionInvite, user, nil, uc.userRepo.Create); err != nil {
+		return nil, err
+	}
+
+	// Best-effort: the invitation can be resent if the message cannot be queued
+	_ = uc.sendInvitation(ctx, user)
+
+	user, err = uc.userRepo.FindByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.EntityToUserOutput(user), nil
+}
+
+// GetInvitations returns the invitations not accepted yet, the ones expiring first first
+func (uc *userUseCase) GetInvitations(ctx context.Context) ([]dto.InvitationOutput, error) {
+	tokens, err := uc.tokenRepo.FindUnused(ctx, entity.TokenPurposeInvitation)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.EntitiesToInvitationOutputs(tokens), nil
+}
+
+// ResendInvitation delivers a new invitation to a pending user, invalidating the previous one
+func (uc *userUseCase) ResendInvitation(ctx context.Context, id uint) error {
+	user, err := uc.pendingUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.sendInvitation(ctx, user); err != nil {
+		return err
+	}
+	return uc.config.Audit.User(ctx, entity.AuditActionResendInvitation, user, nil, nil)
+}
+
+// RevokeInvitation withdraws the invitation of a pending user, deleting the user
+func (uc *userUseCase) RevokeInvitation(ctx context.Context, id uint) error {
+	user, err := uc.pendingUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// A restored user is not invited again
+	if err := uc.tokenRepo.DeleteByUser(ctx, user.ID, entity.TokenPurposeInvitation); err != nil {
+		return err
+	}
+
+	return uc.config.Audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.Delete(ctx, []uint{user.ID}); err != nil {
+			return err
+		}
+		return uc.config.Audit.User(ctx, entity.AuditActionRevokeInvitation, user, user.AuditState(), nil)
+	})
+}
+
+// AcceptInvitation sets the first password of a pending user using an invitation token
+func (uc *userUseCase) AcceptInvitation(ctx context.Context, input *dto.PasswordInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	token, err := uc.tokenRepo.FindByHash(ctx, entity.TokenPurposeInvitation, entity.HashToken(input.Token))
+	if err != nil || !token.IsValid() {
+		return apperror.InvalidToken()
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, token.UserID)
+	if err != nil || !user.IsPending() {
+		return apperror.InvalidToken()
+	}
+	before := user.AuditState()
+
+	if err := user.ChangePassword(input.Password, user.PasswordPolicy(uc.config.PasswordPolicy), uc.config.PasswordHasher); err != nil {
+		return err
+	}
+	user.Auth.AcceptInvitation()
+	// The invitation was delivered to the current email
+	user.MarkEmailVerified()
+
+	// Consuming is conditional, so an invitation can only be accepted once even under concurrent requests
+	if err := uc.tokenRepo.Consume(ctx, token); err != nil {
+		return apperror.InvalidToken()
+	}
+
+	return uc.config.Audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return uc.config.Audit.User(actingAs(ctx, user), entity.AuditActionAcceptInvitation, user, before, user.AuditState())
+	})
+}
+
+// actingAs returns a copy of ctx acting as the user a single-use token was delivered to:
+// whoever presents the token proves to be the user
+func actingAs(ctx context.Context, user *entity.User) context.Context {
+	return actor.WithUser(ctx, &user.ID, nil)
+}
+
+// pendingUser returns a user that was invited and has not accepted the invitation yet
+func (uc *userUseCase) pendingUser(ctx context.Context, id uint) (*entity.User, error) {
+	user, err := uc.userRepo.FindByID(ctx, id)
+	if err != nil || !user.IsPending() {
+		return nil, apperror.InvitationNotFound()
 	}
+	return user, nil
+}
 
-	if user.Auth != nil && user.Auth.Password != nil {
-		return apperror.UserHasPassword()
+// sendWelcome issues the token new users set their first password with and sends it in the
+// welcome message. Sending is best-effort: the account exists even if the message cannot be queued.
+func (uc *userUseCase) sendWelcome(ctx context.Context, user *entity.User) error {
+	token, secret, err := uc.issueToken(ctx, user, entity.TokenPurposePasswordReset, uc.config.PasswordResetExpiration)
+	if err != nil {
+		return err
 	}
 
-	if err := user.SetPassword(input.Password); err != nil {
+	_ = uc.notifier.SendWelcome(ctx, user, secret, token.ExpiresAt)
+	return nil
+}
+
+// sendInvitation issues an invitation token for a pending user and delivers it
+func (uc *userUseCase) sendInvitation(ctx context.Context, user *entity.User) error {
+	token, secret, err := uc.issueToken(ctx, user, entity.TokenPurposeInvitation, uc.config.InvitationExpiration)
+	if err != nil {
 		return err
 	}
 
-	// Generate token for the user
-	token := uuid.New().String()
-	user.Auth.SetToken(token)
+	return uc.notifier.SendInvitation(ctx, user, secret, token.ExpiresAt)
+}
+
+// RequestEmailVerification delivers a new verification token for the email awaiting
+// verification of a user. Unknown emails, and users with nothing to verify, are ignored
+// so the caller cannot tell which accounts exist.
+func (uc *userUseCase) RequestEmailVerification(ctx context.Context, email string) error {
+	user, err := uc.userRepo.FindByEmail(ctx, email)
+	if err != nil || user.VerificationEmail() == "" {
+		return nil
+	}
+
+	return uc.sendEmailVerification(ctx, user)
+}
+
+// VerifyEmail confirms the email awaiting verification of a user using a verification token,
+// applying a pending email change
+func (uc *userUseCase) VerifyEmail(ctx context.Context, input *dto.EmailVerificationInput) error {
+	token, err := uc.tokenRepo.FindByHash(ctx, entity.TokenPurposeEmailVerification, entity.HashToken(input.Token))
+	if err != nil || !token.IsValid() {
+		return apperror.InvalidToken()
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, token.UserID)
+	if err != nil || user.VerificationEmail() == "" {
+		return apperror.InvalidToken()
+	}
+	before := user.AuditState()
+	user.ConfirmEmail()
+
+	// Consuming is conditional, so a token can only be used once even under concurrent requests
+	if err := uc.tokenRepo.Consume(ctx, token); err != nil {
+		return apperror.InvalidToken()
+	}
+
+	return uc.config.Audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return uc.config.Audit.User(actingAs(ctx, user), entity.AuditActionVerifyEmail, user, before, user.AuditState())
+	})
+}
+
+// sendEmailVerification issues an email verification token and delivers it to the email awaiting verification
+func (uc *userUseCase) sendEmailVerification(ctx context.Context, user *entity.User) error {
+	token, secret, err := uc.issueToken(ctx, user, entity.TokenPurposeEmailVerification, uc.config.EmailVerificationExpiration)
+	if err != nil {
+		return err
+	}
 
-	return uc.userRepo.Update(ctx, user)
+	return uc.notifier.SendEmailVerification(ctx, user, user.VerificationEmail(), secret, token.ExpiresAt)
 }
@@ -3,80 +3,150 @@ package user_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 
+	"github.com/raulaguila/go-api/internal/adapter/driven/storage/memory"
 	"github.com/raulaguila/go-api/internal/core/domain/entity"
 	"github.com/raulaguila/go-api/internal/core/dto"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/internal/core/port/output/outputtest"
+	"github.com/raulaguila/go-api/internal/core/usecase/audit"
 	"github.com/raulaguila/go-api/internal/core/usecase/user"
+	"github.com/raulaguila/go-api/pkg/actor"
+	"github.com/raulaguila/go-api/pkg/apperror"
+	"github.com/raulaguila/go-api/pkg/passwd"
+	"github.com/raulaguila/go-api/pkg/tenant"
 )
 
-// MockUserRepo implements output.UserRepository for testing
-type MockUserRepo struct {
-	mock.Mock
+// fakeTokenRepo implements output.UserTokenRepository in memory for testing
+type fakeTokenRepo struct {
+	tokens []*entity.UserToken
 }
 
-func (m *MockUserRepo) Count(ctx context.Context, filter *dto.UserFilter) (int64, error) {
-	args := m.Called(ctx, filter)
-	return args.Get(0).(int64), args.Error(1)
+func (r *fakeTokenRepo) FindByHash(_ context.Context, purpose entity.TokenPurpose, hash string) (*entity.UserToken, error) {
+	for _, t := range r.tokens {
+		if t.Purpose == purpose && t.TokenHash == hash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
 }
 
-func (m *MockUserRepo) FindAll(ctx context.Context, filter *dto.UserFilter) ([]*entity.User, error) {
-	args := m.Called(ctx, filter)
-	return args.Get(0).([]*entity.User), args.Error(1)
+func (r *fakeTokenRepo) FindUnused(_ context.Context, purpose entity.TokenPurpose) ([]*entity.UserToken, error) {
+	var unused []*entity.UserToken
+	for _, t := range r.tokens {
+		if t.Purpose == purpose && !t.IsUsed() {
+			unused = append(unused, t)
+		}
+	}
+	return unused, nil
 }
 
-func (m *MockUserRepo) FindByID(ctx context.Context, id uint) (*entity.User, error) {
-	args := m.Called(ctx, id)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*entity.User), args.Error(1)
+func (r *fakeTokenRepo) Create(_ context.Context, token *entity.UserToken) error {
+	token.ID = uint(len(r.tokens) + 1)
+	r.tokens = append(r.tokens, token)
+	return nil
 }
 
-func (m *MockUserRepo) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
-	args := m.Called(ctx, username)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
+func (r *fakeTokenRepo) Consume(_ context.Context, token *entity.UserToken) error {
+	for _, t := range r.tokens {
+		if t.ID == token.ID && t.UsedAt == nil {
+			t.Use()
+			return nil
+		}
 	}
-	return args.Get(0).(*entity.User), args.Error(1)
+	return gorm.ErrRecordNotFound
 }
 
-func (m *MockUserRepo) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
-	args := m.Called(ctx, email)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
+func (r *fakeTokenRepo) DeleteByUser(_ context.Context, userID uint, purpose entity.TokenPurpose) error {
+	kept := r.tokens[:0]
+	for _, t := range r.tokens {
+		if t.UserID != userID || t.Purpose != purpose {
+			kept = append(kept, t)
+		}
 	}
-	return args.Get(0).(*entity.User), args.Error(1)
+	r.tokens = kept
+	return nil
 }
 
-func (m *MockUserRepo) FindByToken(ctx context.Context, token string) (*entity.User, error) {
-	args := m.Called(ctx, token)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*entity.User), args.Error(1)
+// fakeNotifier implements output.Notifier by recording the last delivered token
+type fakeNotifier struct {
+	token       string
+	tokenEmail  string // Address of the last email verification
+	changeEmail string // New email of the last email change warning
+	disabled    bool
+}
+
+func (n *fakeNotifier) SendWelcome(_ context.Context, _ *entity.User, token string, _ time.Time) error {
+	n.token = token
+	return nil
+}
+
+func (n *fakeNotifier) SendInvitation(_ context.Context, _ *entity.User, token string, _ time.Time) error {
+	n.token = token
+	return nil
+}
+
+func (n *fakeNotifier) SendPasswordReset(_ context.Context, _ *entity.User, token string, _ time.Time) error {
+	n.token = token
+	return nil
+}
+
+func (n *fakeNotifier) SendEmailVerification(_ context.Context, _ *entity.User, email, token string, _ time.Time) error {
+	n.token, n.tokenEmail = token, email
+	return nil
 }
 
-func (m *MockUserRepo) Create(ctx context.Context, u *entity.User) error {
-	args := m.Called(ctx, u)
-	return args.Error(0)
+func (n *fakeNotifier) SendEmailChangeRequested(_ context.Context, _ *entity.User, newEmail string) error {
+	n.changeEmail = newEmail
+	return nil
 }
 
-func (m *MockUserRepo) Update(ctx context.Context, u *entity.User) error {
-	args := m.Called(ctx, u)
-	return args.Error(0)
+func (n *fakeNotifier) SendAccountDisabled(_ context.Context, _ *entity.User) error {
+	n.disabled = true
+	return nil
 }
 
-func (m *MockUserRepo) Delete(ctx context.Context, ids []uint) error {
-	args := m.Called(ctx, ids)
-	return args.Error(0)
+// fakeAuditRepo implements output.AuditRepository, keeping the records in memory
+type fakeAuditRepo struct {
+	records []*entity.AuditRecord
+}
+
+func (r *fakeAuditRepo) Create(_ context.Context, record *entity.AuditRecord) error {
+	r.records = append(r.records, record)
+	return nil
+}
+
+func (r *fakeAuditRepo) Count(context.Context, *dto.AuditFilter) (int64, error) {
+	return int64(len(r.records)), nil
+}
+
+func (r *fakeAuditRepo) FindAll(context.Context, *dto.AuditFilter) ([]*entity.AuditRecord, error) {
+	return r.records, nil
+}
+
+// testHasher hashes passwords with the default parameters
+var testHasher = passwd.NewHasher(passwd.DefaultArgon2id, passwd.DefaultBcrypt)
+
+func newResetTestUser(t *testing.T) *entity.User {
+	a, err := entity.NewAuth(1, true)
+	require.NoError(t, err)
+	require.NoError(t, a.SetPassword("12345678", testHasher))
+	u, err := entity.NewUser("John Doe", "johndoe", "john@example.com", a)
+	require.NoError(t, err)
+	u.ID = 7
+	return u
 }
 
 func TestCreateUser_Success(t *testing.T) {
-	mockRepo := new(MockUserRepo)
-	uc := user.NewUserUseCase(mockRepo)
+	mockRepo, notifier := new(outputtest.UserRepository), &fakeNotifier{}
+	uc := user.NewUserUseCase(mockRepo, outputtest.NewProfileRepository(&entity.Profile{ID: 1, Name: "USER"}), nil, &fakeTokenRepo{}, nil, nil, notifier, user.Config{PasswordResetExpiration: time.Hour, PasswordHasher: testHasher})
 
 	ctx := context.Background()
 	name := "John Doe"
@@ -109,5 +179,588 @@ func TestCreateUser_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, created)
 	assert.Equal(t, name, *created.Name)
+	assert.NotEmpty(t, notifier.token, "welcome message must carry a password token")
 	mockRepo.AssertExpectations(t)
 }
+
+func TestPasswordReset_TokenIsSingleUse(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	tokens, notifier := &fakeTokenRepo{}, &fakeNotifier{}
+	uc := user.NewUserUseCase(userRepo, nil, sessionRepo, tokens, memory.NewRevocationStore(), nil, notifier, user.Config{PasswordResetExpiration: time.Hour, PasswordHasher: testHasher})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+
+	userRepo.On("FindByEmail", ctx, u.Email).Return(u, nil)
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	userRepo.On("Update", ctx, u).Return(nil)
+	sessionRepo.On("RevokeByUser", ctx, u.ID).Return(nil)
+
+	require.NoError(t, uc.ResetPassword(ctx, u.Email))
+	require.NotEmpty(t, notifier.token)
+	assert.NotEqual(t, notifier.token, tokens.tokens[0].TokenHash, "only the hash must be stored")
+
+	input := &dto.PasswordInput{Token: notifier.token, Password: "newpassword", PasswordConfirm: "newpassword"}
+	assert.NoError(t, uc.SetPassword(ctx, input))
+	assert.True(t, u.ValidatePassword("newpassword", testHasher))
+	sessionRepo.AssertCalled(t, "RevokeByUser", ctx, u.ID)
+
+	err := uc.SetPassword(ctx, input)
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidToken))
+}
+
+func TestPasswordReset_NewRequestInvalidatesPreviousToken(t *testing.T) {
+	userRepo := new(outputtest.UserRepository)
+	tokens, notifier := &fakeTokenRepo{}, &fakeNotifier{}
+	uc := user.NewUserUseCase(userRepo, nil, nil, tokens, nil, nil, notifier, user.Config{PasswordResetExpiration: time.Hour, PasswordHasher: testHasher})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+
+	userRepo.On("FindByEmail", ctx, u.Email).Return(u, nil)
+
+	require.NoError(t, uc.ResetPassword(ctx, u.Email))
+	first := notifier.token
+	require.NoError(t, uc.ResetPassword(ctx, u.Email))
+
+	err := uc.SetPassword(ctx, &dto.PasswordInput{Token: first, Password: "newpassword", PasswordConfirm: "newpassword"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidToken))
+}
+
+func TestPasswordReset_ProfilePolicy(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	tokens, notifier := &fakeTokenRepo{}, &fakeNotifier{}
+	uc := user.NewUserUseCase(userRepo, nil, sessionRepo, tokens, memory.NewRevocationStore(), nil, notifier, user.Config{
+		PasswordResetExpiration: time.Hour,
+		PasswordPolicy:          passwd.Policy{MinLength: 8},
+		PasswordHasher:          testHasher,
+	})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+	u.Auth.Profile = &entity.Profile{ID: 1, Name: "ADMIN", PasswordPolicy: &passwd.Policy{MinLength: 12, RequireDigit: true}}
+
+	userRepo.On("FindByEmail", ctx, u.Email).Return(u, nil)
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	userRepo.On("Update", ctx, u).Return(nil)
+	sessionRepo.On("RevokeByUser", ctx, u.ID).Return(nil)
+
+	require.NoError(t, uc.ResetPassword(ctx, u.Email))
+
+	err := uc.SetPassword(ctx, &dto.PasswordInput{Token: notifier.token, Password: "newpassword", PasswordConfirm: "newpassword"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidInput), "the profile's policy replaces the environment's")
+
+	err = uc.SetPassword(ctx, &dto.PasswordInput{Token: notifier.token, Password: "new-password-2024", PasswordConfirm: "new-password-2024"})
+	assert.NoError(t, err, "a rejected password must not consume the token")
+}
+
+func TestPasswordReset_ExpiredToken(t *testing.T) {
+	userRepo := new(outputtest.UserRepository)
+	tokens, notifier := &fakeTokenRepo{}, &fakeNotifier{}
+	uc := user.NewUserUseCase(userRepo, nil, nil, tokens, nil, nil, notifier, user.Config{PasswordResetExpiration: -time.Minute, PasswordHasher: testHasher})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+
+	userRepo.On("FindByEmail", ctx, u.Email).Return(u, nil)
+
+	require.NoError(t, uc.ResetPassword(ctx, u.Email))
+
+	err := uc.SetPassword(ctx, &dto.PasswordInput{Token: notifier.token, Password: "newpassword", PasswordConfirm: "newpassword"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidToken))
+}
+
+func TestPasswordReset_UnknownEmailIsSilent(t *testing.T) {
+	userRepo := new(outputtest.UserRepository)
+	notifier := &fakeNotifier{}
+	uc := user.NewUserUseCase(userRepo, nil, nil, &fakeTokenRepo{}, nil, nil, notifier, user.Config{PasswordResetExpiration: time.Hour, PasswordHasher: testHasher})
+	ctx := context.Background()
+
+	userRepo.On("FindByEmail", ctx, "nobody@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	assert.NoError(t, uc.ResetPassword(ctx, "nobody@example.com"))
+	assert.Empty(t, notifier.token)
+}
+
+func TestUpdateUser_DisableNotifiesUser(t *testing.T) {
+	userRepo, notifier := new(outputtest.UserRepository), &fakeNotifier{}
+	uc := user.NewUserUseCase(userRepo, nil, nil, &fakeTokenRepo{}, nil, nil, notifier, user.Config{})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+	status := false
+
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	userRepo.On("Update", ctx, u).Return(nil)
+
+	_, err := uc.UpdateUser(ctx, u.ID, &dto.UserInput{Status: &status})
+
+	assert.NoError(t, err)
+	assert.True(t, notifier.disabled)
+}
+
+func TestPatchUser(t *testing.T) {
+	userRepo, notifier := new(outputtest.UserRepository), &fakeNotifier{}
+	uc := user.NewUserUseCase(userRepo, nil, nil, &fakeTokenRepo{}, nil, nil, notifier, user.Config{})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	userRepo.On("Update", ctx, u).Return(nil)
+
+	_, err := uc.PatchUser(ctx, u.ID, &dto.PatchInput{Format: dto.MergePatch, Patch: []byte(`{"name":"John Smith"}`)})
+	require.NoError(t, err)
+	assert.Equal(t, "John Smith", u.Name)
+	assert.Equal(t, "johndoe", u.Username, "members left out of a merge patch are kept")
+
+	_, err = uc.PatchUser(ctx, u.ID, &dto.PatchInput{Format: dto.JSONPatch, Patch: []byte(`[
+		{"op":"test","path":"/status","value":true},
+		{"op":"replace","path":"/status","value":false}
+	]`)})
+	require.NoError(t, err)
+	assert.False(t, u.Auth.Status)
+	assert.True(t, notifier.disabled)
+}
+
+func TestPatchUser_Refused(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch *dto.PatchInput
+		code  apperror.Code
+	}{
+		{"failed test", &dto.PatchInput{Format: dto.JSONPatch, Patch: []byte(`[{"op":"test","path":"/name","value":"Jane Doe"},{"op":"remove","path":"/name"}]`)}, apperror.CodeConflict},
+		{"unknown member", &dto.PatchInput{Format: dto.MergePatch, Patch: []byte(`{"password":"12345678"}`)}, apperror.CodeInvalidInput},
+		{"missing path", &dto.PatchInput{Format: dto.JSONPatch, Patch: []byte(`[{"op":"remove","path":"/profile"}]`)}, apperror.CodeInvalidInput},
+		{"cleared name", &dto.PatchInput{Format: dto.MergePatch, Patch: []byte(`{"name":null}`)}, entity.ErrUserNameTooShort().Code},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(outputtest.UserRepository)
+			uc := user.NewUserUseCase(userRepo, nil, nil, &fakeTokenRepo{}, nil, nil, &fakeNotifier{}, user.Config{})
+			ctx := context.Background()
+			u := newResetTestUser(t)
+
+			userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+
+			_, err := uc.PatchUser(ctx, u.ID, tt.patch)
+			assert.True(t, apperror.IsCode(err, tt.code), "got %v", err)
+			userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPatchUser_MembersAreRequired(t *testing.T) {
+	for _, member := range []string{"name", "username", "email", "status", "profile_id"} {
+		for _, patch := range []*dto.PatchInput{
+			{Format: dto.JSONPatch, Patch: []byte(`[{"op":"remove","path":"/` + member + `"}]`)},
+			{Format: dto.JSONPatch, Patch: []byte(`[{"op":"replace","path":"/` + member + `","value":null}]`)},
+			{Format: dto.MergePatch, Patch: []byte(`{"` + member + `":null}`)},
+		} {
+			t.Run(member+" "+string(patch.Patch), func(t *testing.T) {
+				userRepo := new(outputtest.UserRepository)
+				uc := user.NewUserUseCase(userRepo, nil, nil, &fakeTokenRepo{}, nil, nil, &fakeNotifier{}, user.Config{})
+				ctx := context.Background()
+				u := newResetTestUser(t)
+
+				userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+
+				_, err := uc.PatchUser(ctx, u.ID, patch)
+				var appErr *apperror.Error
+				require.ErrorAs(t, err, &appErr)
+				assert.Equal(t, apperror.CodeInvalidInput, appErr.Code)
+				assert.Equal(t, member, appErr.Field)
+				assert.True(t, u.Auth.Status, "a removed status does not disable the user")
+				userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			})
+		}
+	}
+}
+
+func TestUnlock_ClearsAccountFailures(t *testing.T) {
+	userRepo, throttle := new(outputtest.UserRepository), memory.NewLoginThrottle()
+	uc := user.NewUserUseCase(userRepo, nil, nil, &fakeTokenRepo{}, nil, throttle, &fakeNotifier{}, user.Config{})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	_, err := throttle.AddFailure(ctx, entity.AccountThrottleKey(u.ID), time.Minute)
+	require.NoError(t, err)
+
+	require.NoError(t, uc.Unlock(ctx, u.ID))
+
+	failures, err := throttle.Failures(ctx, entity.AccountThrottleKey(u.ID))
+	require.NoError(t, err)
+	assert.Nil(t, failures)
+}
+
+func TestGetSessions_FlagsCurrentSession(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	uc := user.NewUserUseCase(userRepo, nil, sessionRepo, &fakeTokenRepo{}, nil, nil, &fakeNotifier{}, user.Config{})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+	current := entity.NewSession(u.ID, "Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0", "10.0.0.1", nil)
+	other := entity.NewSession(u.ID, "curl/8.4.0", "10.0.0.2", nil)
+
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	sessionRepo.On("FindActiveByUser", ctx, u.ID).Return([]*entity.Session{current, other}, nil)
+
+	sessions, err := uc.GetSessions(ctx, u.ID, current.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.True(t, sessions[0].Current)
+	assert.Equal(t, "Firefox on Linux", sessions[0].Device)
+	assert.False(t, sessions[1].Current)
+	assert.Equal(t, "10.0.0.2", sessions[1].IP)
+}
+
+func TestRevokeSession(t *testing.T) {
+	sessionRepo, revocations := new(outputtest.SessionRepository), memory.NewRevocationStore()
+	userRepo := new(outputtest.UserRepository)
+	uc := user.NewUserUseCase(userRepo, nil, sessionRepo, &fakeTokenRepo{}, revocations, nil, &fakeNotifier{}, user.Config{})
+	ctx := context.Background()
+	session := entity.NewSession(7, "curl/8.4.0", "10.0.0.1", nil)
+
+	userRepo.On("FindByID", ctx, mock.AnythingOfType("uint")).Return(newResetTestUser(t), nil)
+	sessionRepo.On("FindByID", ctx, session.ID).Return(session, nil)
+	sessionRepo.On("Revoke", ctx, session.ID).Return(nil)
+
+	err := uc.RevokeSession(ctx, 8, session.ID)
+	assert.True(t, apperror.IsCode(err, apperror.CodeSessionNotFound), "sessions of other users cannot be revoked")
+	sessionRepo.AssertNotCalled(t, "Revoke", ctx, session.ID)
+
+	require.NoError(t, uc.RevokeSession(ctx, 7, session.ID))
+	revoked, err := revocations.IsSessionRevoked(ctx, session.ID)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestCreateUser_JoinsOrganizationOfProfile(t *testing.T) {
+	acme, globex := uint(1), uint(2)
+	profiles := outputtest.NewProfileRepository(
+		&entity.Profile{ID: 1, Name: "ADMIN", OrganizationID: &acme},
+		&entity.Profile{ID: 2, Name: "ADMIN", OrganizationID: &globex},
+	)
+	userRepo := new(outputtest.UserRepository)
+	uc := user.NewUserUseCase(userRepo, profiles, nil, &fakeTokenRepo{}, nil, nil, &fakeNotifier{}, user.Config{PasswordResetExpiration: time.Hour, PasswordHasher: testHasher})
+
+	var created *entity.User
+	userRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.User")).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*entity.User)
+	}).Return(nil)
+	userRepo.On("FindByID", mock.Anything, uint(0)).Return(newResetTestUser(t), nil)
+
+	input := func(profileID uint) *dto.UserInput {
+		name, username, email := "John Doe", "johndoe", "john@example.com"
+		return &dto.UserInput{Name: &name, Username: &username, Email: &email, ProfileID: &profileID}
+	}
+
+	acmeCtx := tenant.WithOrganization(context.Background(), &acme)
+	_, err := uc.CreateUser(acmeCtx, input(2))
+	assert.True(t, apperror.IsCode(err, apperror.CodeProfileNotFound), "organizations cannot grant profiles of others")
+	userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	_, err = uc.CreateUser(acmeCtx, input(1))
+	require.NoError(t, err)
+	assert.Equal(t, &acme, created.OrganizationID)
+
+	_, err = uc.CreateUser(context.Background(), input(2))
+	require.NoError(t, err)
+	assert.Equal(t, &globex, created.OrganizationID, "super-admins create users in the organization of the chosen profile")
+}
+
+func TestUpdateUser_CannotMoveToOtherOrganization(t *testing.T) {
+	acme, globex := uint(1), uint(2)
+	profiles := outputtest.NewProfileRepository(&entity.Profile{ID: 2, Name: "ADMIN", OrganizationID: &globex})
+	userRepo := new(outputtest.UserRepository)
+	uc := user.NewUserUseCase(userRepo, profiles, nil, &fakeTokenRepo{}, nil, nil, &fakeNotifier{}, user.Config{})
+	ctx := tenant.WithOrganization(context.Background(), &acme)
+	u := newResetTestUser(t)
+	u.OrganizationID = &acme
+
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+
+	profileID := uint(2)
+	_, err := uc.UpdateUser(ctx, u.ID, &dto.UserInput{ProfileID: &profileID})
+	assert.True(t, apperror.IsCode(err, apperror.CodeProfileNotFound))
+	assert.Equal(t, &acme, u.OrganizationID)
+	userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestInvitation_Accept(t *testing.T) {
+	userRepo, tokens, notifier := new(outputtest.UserRepository), &fakeTokenRepo{}, &fakeNotifier{}
+	uc := user.NewUserUseCase(userRepo, outputtest.NewProfileRepository(&entity.Profile{ID: 1, Name: "USER"}), nil, tokens, nil, nil, notifier, user.Config{InvitationExpiration: time.Hour, PasswordHasher: testHasher})
+	ctx := context.Background()
+
+	invited := &entity.User{}
+	userRepo.On("Create", ctx, mock.AnythingOfType("*entity.User")).Run(func(args mock.Arguments) {
+		created := args.Get(1).(*entity.User)
+		created.ID = 7
+		*invited = *created
+	}).Return(nil)
+	userRepo.On("FindByID", ctx, uint(7)).Return(invited, nil)
+	userRepo.On("Update", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+
+	name, username, email, profileID := "John Doe", "johndoe", "john@example.com", uint(1)
+	out, err := uc.InviteUser(ctx, &dto.UserInput{Name: &name, Username: &username, Email: &email, ProfileID: &profileID})
+	require.NoError(t, err)
+	assert.True(t, *out.Pending)
+	assert.True(t, invited.IsNew())
+	require.NotEmpty(t, notifier.token)
+
+	invitations, err := uc.GetInvitations(ctx)
+	require.NoError(t, err)
+	require.Len(t, invitations, 1)
+	assert.False(t, invitations[0].Expired)
+
+	// Pending users accept the invitation instead of resetting the password
+	userRepo.On("FindByEmail", ctx, email).Return(invited, nil)
+	require.NoError(t, uc.ResetPassword(ctx, email))
+	resets, err := tokens.FindUnused(ctx, entity.TokenPurposePasswordReset)
+	require.NoError(t, err)
+	assert.Empty(t, resets)
+
+	input := &dto.PasswordInput{Token: notifier.token, Password: "new-password-1", PasswordConfirm: "new-password-1"}
+	require.NoError(t, uc.AcceptInvitation(ctx, input))
+	assert.False(t, invited.IsPending())
+	assert.True(t, invited.ValidatePassword("new-password-1", testHasher))
+
+	err = uc.AcceptInvitation(ctx, input)
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidToken), "invitations can be accepted only once")
+
+	invitations, err = uc.GetInvitations(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, invitations)
+}
+
+func TestInvitation_ResendInvalidatesPrevious(t *testing.T) {
+	userRepo, tokens, notifier := new(outputtest.UserRepository), &fakeTokenRepo{}, &fakeNotifier{}
+	uc := user.NewUserUseCase(userRepo, nil, nil, tokens, nil, nil, notifier, user.Config{InvitationExpiration: time.Hour, PasswordHasher: testHasher})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+	u.Auth.Pending = true
+
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+
+	require.NoError(t, uc.ResendInvitation(ctx, u.ID))
+	first := notifier.token
+	require.NoError(t, uc.ResendInvitation(ctx, u.ID))
+
+	err := uc.AcceptInvitation(ctx, &dto.PasswordInput{Token: first, Password: "new-password-1", PasswordConfirm: "new-password-1"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidToken))
+	assert.True(t, u.IsPending())
+}
+
+func TestInvitation_ExpiredCannotBeAccepted(t *testing.T) {
+	userRepo, tokens, notifier := new(outputtest.UserRepository), &fakeTokenRepo{}, &fakeNotifier{}
+	uc := user.NewUserUseCase(userRepo, nil, nil, tokens, nil, nil, notifier, user.Config{InvitationExpiration: -time.Minute, PasswordHasher: testHasher})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+	u.Auth.Pending = true
+
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	require.NoError(t, uc.ResendInvitation(ctx, u.ID))
+
+	invitations, err := uc.GetInvitations(ctx)
+	require.NoError(t, err)
+	require.Len(t, invitations, 1)
+	assert.True(t, invitations[0].Expired)
+
+	err = uc.AcceptInvitation(ctx, &dto.PasswordInput{Token: notifier.token, Password: "new-password-1", PasswordConfirm: "new-password-1"})
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidToken))
+}
+
+func TestInvitation_RevokeOnlyPendingUsers(t *testing.T) {
+	userRepo := new(outputtest.UserRepository)
+	uc := user.NewUserUseCase(userRepo, nil, nil, &fakeTokenRepo{}, nil, nil, &fakeNotifier{}, user.Config{})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	userRepo.On("Delete", ctx, []uint{u.ID}).Return(nil)
+
+	err := uc.RevokeInvitation(ctx, u.ID)
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvitationNotFound), "users who accepted are not deleted")
+	userRepo.AssertNotCalled(t, "Delete", ctx, []uint{u.ID})
+
+	u.Auth.Pending = true
+	require.NoError(t, uc.RevokeInvitation(ctx, u.ID))
+	userRepo.AssertCalled(t, "Delete", ctx, []uint{u.ID})
+}
+
+func TestUpdateUser_EmailChangeIsPendingUntilVerified(t *testing.T) {
+	userRepo, tokens, notifier := new(outputtest.UserRepository), &fakeTokenRepo{}, &fakeNotifier{}
+	uc := user.NewUserUseCase(userRepo, nil, nil, tokens, nil, nil, notifier, user.Config{EmailVerificationExpiration: time.Hour})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+	u.MarkEmailVerified()
+
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	userRepo.On("Update", ctx, u).Return(nil)
+
+	email := "john.doe@example.com"
+	_, err := uc.UpdateUser(ctx, u.ID, &dto.UserInput{Email: &email})
+	require.NoError(t, err)
+	assert.Equal(t, "john@example.com", u.Email, "the current email stays until the new one is confirmed")
+	assert.Equal(t, &email, u.PendingEmail)
+	assert.Equal(t, email, notifier.tokenEmail, "the token goes to the new address")
+	assert.Equal(t, email, notifier.changeEmail, "the current address is warned")
+
+	input := &dto.EmailVerificationInput{Token: notifier.token}
+	require.NoError(t, uc.VerifyEmail(ctx, input))
+	assert.Equal(t, email, u.Email)
+	assert.Nil(t, u.PendingEmail)
+	assert.True(t, u.IsEmailVerified())
+
+	err = uc.VerifyEmail(ctx, input)
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidToken), "verification tokens can be used only once")
+}
+
+func TestUpdateUser_CancelledEmailChangeInvalidatesToken(t *testing.T) {
+	userRepo, tokens, notifier := new(outputtest.UserRepository), &fakeTokenRepo{}, &fakeNotifier{}
+	uc := user.NewUserUseCase(userRepo, nil, nil, tokens, nil, nil, notifier, user.Config{EmailVerificationExpiration: time.Hour})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	userRepo.On("Update", ctx, u).Return(nil)
+
+	email, current := "intruder@example.com", u.Email
+	_, err := uc.UpdateUser(ctx, u.ID, &dto.UserInput{Email: &email})
+	require.NoError(t, err)
+	_, err = uc.UpdateUser(ctx, u.ID, &dto.UserInput{Email: &current})
+	require.NoError(t, err)
+	assert.Nil(t, u.PendingEmail)
+
+	err = uc.VerifyEmail(ctx, &dto.EmailVerificationInput{Token: notifier.token})
+	assert.True(t, apperror.IsCode(err, apperror.CodeInvalidToken), "a token sent to another address cannot verify the current one")
+	assert.False(t, u.IsEmailVerified())
+}
+
+func TestRequestEmailVerification(t *testing.T) {
+	userRepo, notifier := new(outputtest.UserRepository), &fakeNotifier{}
+	uc := user.NewUserUseCase(userRepo, nil, nil, &fakeTokenRepo{}, nil, nil, notifier, user.Config{EmailVerificationExpiration: time.Hour})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+
+	userRepo.On("FindByEmail", ctx, u.Email).Return(u, nil)
+	userRepo.On("FindByEmail", ctx, "nobody@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	require.NoError(t, uc.RequestEmailVerification(ctx, "nobody@example.com"))
+	assert.Empty(t, notifier.token)
+
+	require.NoError(t, uc.RequestEmailVerification(ctx, u.Email))
+	assert.Equal(t, u.Email, notifier.tokenEmail)
+
+	u.MarkEmailVerified()
+	notifier.token = ""
+	require.NoError(t, uc.RequestEmailVerification(ctx, u.Email))
+	assert.Empty(t, notifier.token, "verified emails have nothing to verify")
+}
+
+func TestDeleteUsers_SignsOutOnlyVisibleUsers(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	uc := user.NewUserUseCase(userRepo, nil, sessionRepo, &fakeTokenRepo{}, memory.NewRevocationStore(), nil, &fakeNotifier{}, user.Config{})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+
+	// User 8 belongs to another organization
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	userRepo.On("FindByID", ctx, uint(8)).Return(nil, gorm.ErrRecordNotFound)
+	userRepo.On("Delete", ctx, []uint{u.ID}).Return(nil)
+	sessionRepo.On("RevokeByUser", ctx, u.ID).Return(nil)
+
+	require.NoError(t, uc.DeleteUsers(ctx, []uint{u.ID, 8}))
+	userRepo.AssertCalled(t, "Delete", ctx, []uint{u.ID})
+	sessionRepo.AssertCalled(t, "RevokeByUser", ctx, u.ID)
+	sessionRepo.AssertNotCalled(t, "RevokeByUser", ctx, uint(8))
+
+	err := uc.DeleteUsers(ctx, []uint{8})
+	assert.True(t, apperror.IsCode(err, apperror.CodeUserNotFound))
+}
+
+func TestUpdateUser_RecordsAudit(t *testing.T) {
+	userRepo, records := new(outputtest.UserRepository), &fakeAuditRepo{}
+	uc := user.NewUserUseCase(userRepo, nil, nil, &fakeTokenRepo{}, nil, nil, &fakeNotifier{}, user.Config{Audit: audit.NewRecorder(records, nil)})
+	admin := uint(1)
+	ctx := actor.WithUser(actor.WithRequest(context.Background(), "req-1", "10.0.0.1"), &admin, nil)
+	u := newResetTestUser(t)
+	name := "John Smith"
+
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	userRepo.On("Update", ctx, u).Return(nil)
+
+	_, err := uc.UpdateUser(ctx, u.ID, &dto.UserInput{Name: &name})
+	require.NoError(t, err)
+
+	require.Len(t, records.records, 1)
+	record := records.records[0]
+	assert.Equal(t, entity.AuditActionUpdate, record.Action)
+	assert.Equal(t, entity.AuditEntityUser, record.EntityType)
+	assert.Equal(t, "7", record.EntityID)
+	assert.Equal(t, &admin, record.ActorID)
+	assert.Equal(t, "req-1", record.RequestID)
+	assert.Equal(t, "10.0.0.1", record.IP)
+	assert.Equal(t, map[string]entity.AuditChange{"name": {Before: "John Doe", After: "John Smith"}}, record.Changes)
+}
+
+func TestUpdateUser_StaleIfMatchIsRefused(t *testing.T) {
+	userRepo := new(outputtest.UserRepository)
+	uc := user.NewUserUseCase(userRepo, nil, nil, &fakeTokenRepo{}, nil, nil, &fakeNotifier{}, user.Config{})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+	u.Version = 3
+	name, read := "John Smith", uint(2)
+
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+
+	_, err := uc.UpdateUser(ctx, u.ID, &dto.UserInput{Name: &name, Versions: []uint{read}})
+	assert.True(t, apperror.IsCode(err, apperror.CodeConflict))
+	userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateUser_ConcurrentUpdateIsRefused(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		versions []uint
+	}{
+		{"with If-Match", []uint{1}},
+		{"without If-Match", nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(outputtest.UserRepository)
+			uc := user.NewUserUseCase(userRepo, nil, nil, &fakeTokenRepo{}, nil, nil, &fakeNotifier{}, user.Config{})
+			ctx := context.Background()
+			u := newResetTestUser(t)
+			name := "John Smith"
+
+			userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+			userRepo.On("Update", ctx, u).Return(output.ErrStaleVersion)
+
+			_, err := uc.UpdateUser(ctx, u.ID, &dto.UserInput{Name: &name, Versions: tt.versions})
+			assert.True(t, apperror.IsCode(err, apperror.CodeConflict))
+			assert.NotSame(t, output.ErrStaleVersion, err, "repository errors are not returned as is")
+		})
+	}
+}
+
+func TestSetPassword_AuditIsAttributedToTokenHolder(t *testing.T) {
+	userRepo, sessionRepo := new(outputtest.UserRepository), new(outputtest.SessionRepository)
+	tokens, notifier, records := &fakeTokenRepo{}, &fakeNotifier{}, &fakeAuditRepo{}
+	uc := user.NewUserUseCase(userRepo, nil, sessionRepo, tokens, memory.NewRevocationStore(), nil, notifier, user.Config{
+		PasswordResetExpiration: time.Hour,
+		PasswordHasher:          testHasher,
+		Audit:                   audit.NewRecorder(records, nil),
+	})
+	ctx := context.Background()
+	u := newResetTestUser(t)
+
+	userRepo.On("FindByEmail", ctx, u.Email).Return(u, nil)
+	userRepo.On("FindByID", ctx, u.ID).Return(u, nil)
+	userRepo.On("Update", ctx, u).Return(nil)
+	sessionRepo.On("RevokeByUser", ctx, u.ID).Return(nil)
+
+	require.NoError(t, uc.ResetPassword(ctx, u.Email))
+	require.NoError(t, uc.SetPassword(ctx, &dto.PasswordInput{Token: notifier.token, Password: "new-password-1", PasswordConfirm: "new-password-1"}))
+
+	require.Len(t, records.records, 1)
+	record := records.records[0]
+	assert.Equal(t, entity.AuditActionSetPassword, record.Action)
+	assert.Equal(t, &u.ID, record.ActorID)
+	assert.Contains(t, record.Changes, "password_changed_at")
+	assert.NotContains(t, record.Changes, "password", "secrets are never recorded")
+}
@@ -3,18 +3,34 @@
 package di
 
 import (
+	"errors"
 	"log/slog"
+	"net/http"
+	"time"
 
 	"gorm.io/gorm"
 
 	"github.com/raulaguila/go-api/config"
+	"github.com/raulaguila/go-api/internal/adapter/driven/identity"
+	"github.com/raulaguila/go-api/internal/adapter/driven/mail"
+	"github.com/raulaguila/go-api/internal/adapter/driven/notification"
 	"github.com/raulaguila/go-api/internal/adapter/driven/persistence/postgres/repository"
+	"github.com/raulaguila/go-api/internal/adapter/driven/storage/memory"
 	"github.com/raulaguila/go-api/internal/adapter/driven/storage/redis"
 	"github.com/raulaguila/go-api/internal/app"
+	"github.com/raulaguila/go-api/internal/core/domain/entity"
+	"github.com/raulaguila/go-api/internal/core/port/output"
+	"github.com/raulaguila/go-api/internal/core/usecase/apikey"
+	"github.com/raulaguila/go-api/internal/core/usecase/audit"
 	"github.com/raulaguila/go-api/internal/core/usecase/auth"
+	"github.com/raulaguila/go-api/internal/core/usecase/oauth"
+	"github.com/raulaguila/go-api/internal/core/usecase/organization"
 	"github.com/raulaguila/go-api/internal/core/usecase/profile"
 	"github.com/raulaguila/go-api/internal/core/usecase/user"
+	"github.com/raulaguila/go-api/pkg/jwtx"
 	"github.com/raulaguila/go-api/pkg/loggerx"
+	"github.com/raulaguila/go-api/pkg/oidc"
+	"github.com/raulaguila/go-api/pkg/passwd"
 )
 
 // Container holds all application dependencies.
@@ -26,8 +42,28 @@ type Container struct {
 	DB     *gorm.DB
 	Redis  *redis.Service
 
+	// Token signing keys
+	AccessKeys  *jwtx.Keyring
+	RefreshKeys *jwtx.Keyring
+
 	// Repositories
 	repositories *app.Repositories
+
+	// Session activity, saved in batches
+	sessionActivity *memory.SessionActivityBatcher
+
+	// Purges of the trash
+	purger *app.Purger
+
+	// Notifications
+	mailQueue *mail.AsyncTransport
+	notifier  output.Notifier
+
+	// External identity providers
+	identityProviders map[string]auth.ExternalProvider
+
+	// Password hashing
+	passwordHasher output.PasswordHasher
 }
 
 // NewContainer creates and initializes a new dependency container
@@ -39,43 +75,259 @@ func NewContainer(cfg *config.Environment, log *loggerx.Logger, db *gorm.DB, red
 		Redis:  redis,
 	}
 
+	c.initKeys()
 	c.initRepositories()
+	c.initNotifier()
+	c.initIdentityProviders()
+	c.initPasswordHasher()
 
-	log.Info("Dependency container initialized", slog.Int("repositories", 2), slog.Int("use_cases", 3))
+	log.Info("Dependency container initialized", slog.Int("repositories", 9), slog.Int("use_cases", 6),
+		slog.Int("identity_providers", len(c.identityProviders)))
 
 	return c
 }
 
+// initKeys initializes the keyrings signing tokens; retired keys only verify tokens issued before a rotation
+func (c *Container) initKeys() {
+	c.AccessKeys = jwtx.NewKeyring(c.Config.AccessPrivateKey, c.Config.AccessRetiredKeys...)
+	c.RefreshKeys = jwtx.NewKeyring(c.Config.RefreshPrivateKey, c.Config.RefreshRetiredKeys...)
+}
+
 // initRepositories initializes all repository implementations
 func (c *Container) initRepositories() {
 	profileRepo := repository.NewProfileRepository(c.DB)
 	userRepo := repository.NewUserRepository(c.DB)
+	sessionRepo := repository.NewSessionRepository(c.DB)
+	userTokenRepo := repository.NewUserTokenRepository(c.DB)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(c.DB)
+	apiKeyRepo := repository.NewAPIKeyRepository(c.DB)
+	externalIdentityRepo := repository.NewExternalIdentityRepository(c.DB)
+	oauthClientRepo := repository.NewOAuthClientRepository(c.DB)
+	organizationRepo := repository.NewOrganizationRepository(c.DB)
+	auditRepo := repository.NewAuditRepository(c.DB)
+	revocations := memory.NewRevocationStore()
+	loginThrottle := memory.NewLoginThrottle()
+	externalLogins := memory.NewExternalLoginStore()
+	authorizationCodes := memory.NewAuthorizationCodeStore()
+	c.sessionActivity = memory.NewSessionActivityBatcher(sessionRepo, c.Config.SessionActivityFlush, c.Log)
 
-	// Apply caching decorator if Redis is available
+	// Apply caching decorator and shared stores if Redis is available
 	if c.Redis != nil {
 		profileRepo = repository.NewCachedProfileRepository(profileRepo, c.Redis)
 		userRepo = repository.NewCachedUserRepository(userRepo, c.Redis)
+		revocations = redis.NewRevocationStore(c.Redis)
+		loginThrottle = redis.NewLoginThrottle(c.Redis)
+		externalLogins = redis.NewExternalLoginStore(c.Redis)
+		authorizationCodes = redis.NewAuthorizationCodeStore(c.Redis)
 	}
 
 	c.repositories = &app.Repositories{
-		User:    userRepo,
-		Profile: profileRepo,
+		User:              userRepo,
+		Profile:           profileRepo,
+		Session:           sessionRepo,
+		UserToken:         userTokenRepo,
+		LoginAttempt:      loginAttemptRepo,
+		APIKey:            apiKeyRepo,
+		ExternalIdentity:  externalIdentityRepo,
+		OAuthClient:       oauthClientRepo,
+		Organization:      organizationRepo,
+		Revocation:        revocations,
+		LoginThrottle:     loginThrottle,
+		ExternalLogin:     externalLogins,
+		AuthorizationCode: authorizationCodes,
+		SessionActivity:   c.sessionActivity,
+		Audit:             auditRepo,
+		Transactor:        repository.NewTransactor(c.DB),
 	}
+
+	c.purger = app.NewPurger(userRepo, profileRepo, c.Config.DeletedRetention, c.Config.PurgeInterval, c.Log)
+}
+
+// initNotifier initializes the mailer used to reach users
+func (c *Container) initNotifier() {
+	var transport mail.Transport
+	switch c.Config.MailDriver {
+	case "smtp":
+		transport = mail.NewSMTPTransport(mail.SMTPConfig{
+			Host:     c.Config.MailHost,
+			Port:     c.Config.MailPort,
+			User:     c.Config.MailUser,
+			Password: c.Config.MailPassword,
+		})
+	case "memory":
+		transport = mail.NewMemoryTransport()
+	default:
+		fileTransport, err := mail.NewFileTransport(c.Config.MailDir)
+		if err != nil {
+			panic(err)
+		}
+		transport = fileTransport
+	}
+
+	// Deliveries never block requests and failed ones are retried in background
+	c.mailQueue = mail.NewAsyncTransport(transport, c.Log, mail.WithRetries(c.Config.MailRetries))
+
+	renderer := mail.MustNewRenderer(config.Locales, c.Config.MailLanguage)
+	mailer := mail.NewMailer(c.mailQueue, renderer, c.Config.MailFrom)
+	c.notifier = notification.NewMailNotifier(mailer, c.Config.ServiceName, c.Config.InvitationURL)
+}
+
+// initIdentityProviders initializes the OpenID Connect providers users can log in with
+func (c *Container) initIdentityProviders() {
+	c.identityProviders = make(map[string]auth.ExternalProvider, len(c.Config.OIDCProviders))
+	for name, provider := range c.Config.OIDCProviders {
+		client := oidc.NewClient(oidc.Config{
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			RedirectURL:  provider.RedirectURL,
+			Scopes:       provider.Scopes,
+			Leeway:       c.Config.TokenLeeway,
+			HTTPClient:   &http.Client{Timeout: 10 * time.Second},
+		})
+
+		c.identityProviders[name] = auth.ExternalProvider{
+			IdentityProvider: identity.NewOIDCProvider(client, identity.ClaimMapping{
+				Username: provider.UsernameClaim,
+				Name:     provider.NameClaim,
+				Email:    provider.EmailClaim,
+			}),
+			DefaultProfileID: provider.DefaultProfile,
+		}
+	}
+}
+
+// initPasswordHasher initializes the password hasher; hashes of either algorithm are
+// verified, and upgraded to the configured one on login
+func (c *Container) initPasswordHasher() {
+	argon2id := passwd.Argon2id{
+		Memory:      c.Config.Argon2Memory,
+		Iterations:  c.Config.Argon2Iterations,
+		Parallelism: c.Config.Argon2Parallelism,
+		SaltLength:  passwd.DefaultArgon2id.SaltLength,
+		KeyLength:   passwd.DefaultArgon2id.KeyLength,
+	}
+	bcrypt := passwd.Bcrypt{Cost: c.Config.BcryptCost}
+
+	switch c.Config.PasswordHash {
+	case "bcrypt":
+		c.passwordHasher = passwd.NewHasher(bcrypt, argon2id)
+	default:
+		c.passwordHasher = passwd.NewHasher(argon2id, bcrypt)
+	}
+}
+
+// Close releases resources held by the container, waiting for queued mails to be delivered
+// and saving the session activity not saved yet
+func (c *Container) Close() error {
+	var errs []error
+	if c.purger != nil {
+		errs = append(errs, c.purger.Close())
+	}
+	if c.sessionActivity != nil {
+		errs = append(errs, c.sessionActivity.Close())
+	}
+	if c.mailQueue != nil {
+		errs = append(errs, c.mailQueue.Close())
+	}
+	return errors.Join(errs...)
 }
 
 // Application returns a fully configured Application instance
 func (c *Container) Application() *app.Application {
+	passwordPolicy := passwd.Policy{
+		MinLength:     c.Config.PasswordMinLength,
+		RequireUpper:  c.Config.PasswordRequireUpper,
+		RequireLower:  c.Config.PasswordRequireLower,
+		RequireDigit:  c.Config.PasswordRequireDigit,
+		RequireSymbol: c.Config.PasswordRequireSymbol,
+		RejectCommon:  c.Config.PasswordRejectCommon,
+		RejectSimilar: c.Config.PasswordRejectSimilar,
+		History:       c.Config.PasswordHistory,
+		MaxAgeDays:    c.Config.PasswordMaxAgeDays,
+	}
+
+	recorder := audit.NewRecorder(c.repositories.Audit, c.repositories.Transactor)
+
+	authUC := auth.NewAuthUseCase(
+		c.repositories.User,
+		c.repositories.Session,
+		c.repositories.LoginAttempt,
+		c.repositories.ExternalIdentity,
+		c.repositories.Revocation,
+		c.repositories.LoginThrottle,
+		c.repositories.ExternalLogin,
+		auth.Config{
+			AccessKeys:          c.AccessKeys,
+			AccessExpiration:    c.Config.AccessExpiration,
+			RefreshKeys:         c.RefreshKeys,
+			RefreshExpiration:   c.Config.RefreshExpiration,
+			ChallengeExpiration: c.Config.TwoFactorChallengeExpiration,
+			TokenIssuer:         c.Config.TokenIssuer,
+			TokenAudience:       c.Config.TokenAudience,
+			TokenLeeway:         c.Config.TokenLeeway,
+			TwoFactorIssuer:     c.Config.ServiceName,
+			AccountLockout: entity.LockoutPolicy{
+				MaxFailures: c.Config.LoginMaxFailures,
+				Backoff:     c.Config.LoginBackoff,
+				Lockout:     c.Config.LoginLockout,
+			},
+			IPLockout: entity.LockoutPolicy{
+				MaxFailures: c.Config.LoginMaxFailuresPerIP,
+				Backoff:     c.Config.LoginBackoff,
+				Lockout:     c.Config.LoginLockout,
+			},
+			CaseInsensitiveLogin:    c.Config.LoginCaseInsensitive,
+			ImpersonationExpiration: c.Config.ImpersonationExpiration,
+			PasswordPolicy:          passwordPolicy,
+			PasswordHasher:          c.passwordHasher,
+			Audit:                   recorder,
+			ExternalProviders:       c.identityProviders,
+			ExternalLoginExpiration: c.Config.OIDCLoginExpiration,
+		},
+	)
+
 	return app.New(
 		c.Config,
 		c.Log,
-		auth.NewAuthUseCase(c.repositories.User, auth.Config{
-			AccessPrivateKey:  c.Config.AccessPrivateKey,
-			AccessExpiration:  c.Config.AccessExpiration,
-			RefreshPrivateKey: c.Config.RefreshPrivateKey,
-			RefreshExpiration: c.Config.RefreshExpiration,
-		}),
-		profile.NewProfileUseCase(c.repositories.Profile),
-		user.NewUserUseCase(c.repositories.User),
+		authUC,
+		profile.NewProfileUseCase(c.repositories.Profile, recorder),
+		user.NewUserUseCase(
+			c.repositories.User,
+			c.repositories.Profile,
+			c.repositories.Session,
+			c.repositories.UserToken,
+			c.repositories.Revocation,
+			c.repositories.LoginThrottle,
+			c.notifier,
+			user.Config{
+				PasswordResetExpiration:     c.Config.PasswordResetExpiration,
+				InvitationExpiration:        c.Config.InvitationExpiration,
+				EmailVerificationExpiration: c.Config.EmailVerificationExpiration,
+				ImportMaxRows:               c.Config.ImportMaxRows,
+				PasswordPolicy:              passwordPolicy,
+				PasswordHasher:              c.passwordHasher,
+				Audit:                       recorder,
+			},
+		),
+		apikey.NewAPIKeyUseCase(c.repositories.APIKey, c.repositories.User),
+		oauth.NewOAuthUseCase(
+			authUC,
+			c.repositories.User,
+			c.repositories.OAuthClient,
+			c.repositories.AuthorizationCode,
+			oauth.Config{
+				Issuer:           c.Config.OAuthIssuer,
+				LoginURL:         c.Config.OAuthLoginURL,
+				Keys:             c.AccessKeys,
+				TokenIssuer:      c.Config.TokenIssuer,
+				TokenAudience:    c.Config.TokenAudience,
+				AccessExpiration: c.Config.AccessExpiration,
+				CodeExpiration:   c.Config.OAuthCodeExpiration,
+			},
+		),
+		organization.NewOrganizationUseCase(c.repositories.Organization),
+		audit.NewAuditUseCase(c.repositories.Audit),
 		c.repositories,
 	)
 }
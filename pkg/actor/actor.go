@@ -0,0 +1,45 @@
+// Package actor carries who performs a request, and from where, through a context.Context,
+// so that the changes a request makes can be attributed to it.
+//
+// The request is known as soon as it arrives; the user only once it is authenticated.
+//
+// Usage:
+//
+//	ctx = actor.WithRequest(ctx, requestID, ip)
+//	ctx = actor.WithUser(ctx, &user.ID, nil)
+//	who := actor.FromContext(ctx)
+package actor
+
+import "context"
+
+// key is the context key of the actor
+type key struct{}
+
+// Actor is who performs a request, and from where
+type Actor struct {
+	UserID         *uint  // Nil for anonymous requests
+	ImpersonatorID *uint  // Set when UserID is impersonated by another user
+	RequestID      string // ID of the request, shared with the request logs
+	IP             string // Address of the client
+}
+
+// WithRequest returns a copy of ctx carrying the request ID and the client IP
+func WithRequest(ctx context.Context, requestID, ip string) context.Context {
+	a := FromContext(ctx)
+	a.RequestID, a.IP = requestID, ip
+	return context.WithValue(ctx, key{}, a)
+}
+
+// WithUser returns a copy of ctx acting as a user, impersonated by another one when
+// impersonatorID is not nil
+func WithUser(ctx context.Context, userID, impersonatorID *uint) context.Context {
+	a := FromContext(ctx)
+	a.UserID, a.ImpersonatorID = userID, impersonatorID
+	return context.WithValue(ctx, key{}, a)
+}
+
+// FromContext returns the actor of ctx; the zero Actor when there is none
+func FromContext(ctx context.Context) Actor {
+	a, _ := ctx.Value(key{}).(Actor)
+	return a
+}
@@ -0,0 +1,28 @@
+package actor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/raulaguila/go-api/pkg/actor"
+)
+
+func TestActor(t *testing.T) {
+	assert.Equal(t, actor.Actor{}, actor.FromContext(context.Background()))
+
+	user, admin := uint(7), uint(1)
+	ctx := actor.WithRequest(context.Background(), "req-1", "10.0.0.1")
+	ctx = actor.WithUser(ctx, &user, &admin)
+
+	a := actor.FromContext(ctx)
+	assert.Equal(t, &user, a.UserID)
+	assert.Equal(t, &admin, a.ImpersonatorID)
+	assert.Equal(t, "req-1", a.RequestID, "the user keeps the request it was authenticated in")
+	assert.Equal(t, "10.0.0.1", a.IP)
+
+	a = actor.FromContext(actor.WithUser(ctx, &admin, nil))
+	assert.Equal(t, &admin, a.UserID)
+	assert.Nil(t, a.ImpersonatorID)
+}
@@ -6,6 +6,8 @@ package apperror
 import (
 	"errors"
 	"fmt"
+	"math"
+	"time"
 )
 
 // Code represents an application error code
@@ -189,6 +191,12 @@ func ResourceInUse(resource string) *Error {
 	}
 }
 
+// StaleVersion creates the conflict of a write to a resource modified since the version it is
+// based on was read. Its field is "version", so that adapters can tell it from other conflicts.
+func StaleVersion(resource string) *Error {
+	return Conflict(resource, "modified by another request").WithField("version")
+}
+
 // Helper functions
 
 // IsCode checks if an error has a specific code
@@ -222,11 +230,32 @@ func GetCode(err error) Code {
 // Domain-specific error codes
 const (
 	// User errors
-	CodeUserNotFound    Code = "userNotFound"
-	CodeUserHasPassword Code = "userHasPassword"
+	CodeUserNotFound     Code = "userNotFound"
+	CodeUserHasPassword  Code = "userHasPassword"
+	CodeInvalidToken     Code = "invalidToken"
+	CodeEmailNotVerified Code = "emailNotVerified"
+
+	// Two-factor authentication errors
+	CodeTwoFactorRequired Code = "twoFactorRequired"
+
+	// Session errors
+	CodeSessionNotFound    Code = "sessionNotFound"
+	CodeInvalidSession     Code = "invalidSession"
+	CodeRefreshTokenReused Code = "refreshTokenReused"
+
+	// Invitation errors
+	CodeInvitationNotFound Code = "invitationNotFound"
+
+	// Authentication throttling errors
+	CodeTooManyAttempts Code = "tooManyAttempts"
+
+	// External login errors
+	CodeExternalLoginFailed    Code = "externalLoginFailed"
+	CodeExternalAccountUnknown Code = "externalAccountUnknown"
 
 	// Profile errors
 	CodeProfileNotFound Code = "PROFILE_NOT_FOUND"
+	CodeProfileInUse    Code = "profileUsed"
 
 	// Password errors
 	CodePasswordMismatch Code = "PASSWORD_MISMATCH"
@@ -242,6 +271,38 @@ func UserNotFound() *Error {
 	}
 }
 
+// SessionNotFound creates a session not found error
+func SessionNotFound() *Error {
+	return &Error{
+		Code:    CodeSessionNotFound,
+		Message: "session not found",
+	}
+}
+
+// InvalidSession creates an error for sessions that expired or were revoked
+func InvalidSession() *Error {
+	return &Error{
+		Code:    CodeInvalidSession,
+		Message: "session expired or revoked",
+	}
+}
+
+// RefreshTokenReused creates an error for refresh tokens presented again after their rotation
+func RefreshTokenReused() *Error {
+	return &Error{
+		Code:    CodeRefreshTokenReused,
+		Message: "refresh token reuse detected, session revoked",
+	}
+}
+
+// InvitationNotFound creates an invitation not found error
+func InvitationNotFound() *Error {
+	return &Error{
+		Code:    CodeInvitationNotFound,
+		Message: "invitation not found",
+	}
+}
+
 // ProfileNotFound creates a profile not found error
 func ProfileNotFound() *Error {
 	return &Error{
@@ -250,6 +311,14 @@ func ProfileNotFound() *Error {
 	}
 }
 
+// ProfileInUse creates an error for profiles granted to users, which cannot be deleted
+func ProfileInUse() *Error {
+	return &Error{
+		Code:    CodeProfileInUse,
+		Message: "profile is granted to users",
+	}
+}
+
 // UserHasPassword creates an error when user already has a password
 func UserHasPassword() *Error {
 	return &Error{
@@ -258,6 +327,14 @@ func UserHasPassword() *Error {
 	}
 }
 
+// InvalidToken creates an error for unknown, expired or already used user tokens
+func InvalidToken() *Error {
+	return &Error{
+		Code:    CodeInvalidToken,
+		Message: "invalid or expired token",
+	}
+}
+
 // PasswordMismatch creates a password mismatch error
 func PasswordMismatch() *Error {
 	return &Error{
@@ -274,6 +351,22 @@ func DisabledUser() *Error {
 	}
 }
 
+// EmailNotVerified creates an error for users who must verify their email before logging in
+func EmailNotVerified() *Error {
+	return &Error{
+		Code:    CodeEmailNotVerified,
+		Message: "email not verified",
+	}
+}
+
+// TwoFactorRequired creates an error for disabling two-factor authentication a profile requires
+func TwoFactorRequired() *Error {
+	return &Error{
+		Code:    CodeTwoFactorRequired,
+		Message: "two-factor authentication is required by the user profile",
+	}
+}
+
 // InvalidCredentials creates an invalid credentials error
 func InvalidCredentials() *Error {
 	return &Error{
@@ -281,3 +374,32 @@ func InvalidCredentials() *Error {
 		Message: "invalid credentials",
 	}
 }
+
+// TooManyAttempts creates an error for logins refused after repeated failures.
+// The retry_after detail holds the seconds to wait before trying again.
+func TooManyAttempts(retryAfter time.Duration) *Error {
+	err := &Error{
+		Code:    CodeTooManyAttempts,
+		Message: "too many failed attempts, try again later",
+	}
+	return err.WithDetails("retry_after", int(math.Ceil(retryAfter.Seconds())))
+}
+
+// ExternalLoginFailed creates an error for logins at an external identity provider
+// that could not be completed: unknown state, rejected code or invalid ID token
+func ExternalLoginFailed(cause error) *Error {
+	return &Error{
+		Code:    CodeExternalLoginFailed,
+		Message: "external login failed",
+		Cause:   cause,
+	}
+}
+
+// ExternalAccountUnknown creates an error for external identities matching no user
+// when the provider does not provision users
+func ExternalAccountUnknown() *Error {
+	return &Error{
+		Code:    CodeExternalAccountUnknown,
+		Message: "no user is linked to the external account",
+	}
+}
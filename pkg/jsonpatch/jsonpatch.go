@@ -0,0 +1,311 @@
+// Package jsonpatch applies partial updates to JSON documents, written either as a
+// JSON Merge Patch (RFC 7396) or as a JSON Patch (RFC 6902).
+//
+// Usage:
+//
+//	patched, err := jsonpatch.MergePatch(doc, []byte(`{"name":"ACME","policy":null}`))
+//	patched, err := jsonpatch.Apply(doc, []byte(`[{"op":"add","path":"/permissions/-","value":"users:read"}]`))
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPatch is returned for patches that are not valid JSON or hold unknown operations
+	ErrInvalidPatch = errors.New("jsonpatch: invalid patch")
+	// ErrPathNotFound is returned for operations on locations missing from the document
+	ErrPathNotFound = errors.New("jsonpatch: path not found")
+	// ErrTestFailed is returned when a test operation does not match, leaving the document unchanged
+	ErrTestFailed = errors.New("jsonpatch: test failed")
+)
+
+// Operation is a single operation of a JSON Patch
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"` // Nil when absent, "null" when null
+}
+
+// MergePatch applies a JSON Merge Patch to doc: members of the patch replace those of the
+// document, objects are merged recursively and null members are removed.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target, p any
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return json.Marshal(mergePatch(target, p))
+}
+
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+		} else {
+			t[key] = mergePatch(t[key], value)
+		}
+	}
+	return t
+}
+
+// Apply applies a JSON Patch to doc. Operations are applied in order and the patch is atomic:
+// if any operation fails, the error is returned and doc is left as is.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var ops []Operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	var root any
+	if err := json.Unmarshal(doc, &root); err != nil {
+		return nil, err
+	}
+	for i, op := range ops {
+		var err error
+		if root, err = op.apply(root); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(root)
+}
+
+// apply applies the operation to root, returning the new root
+func (o Operation) apply(root any) (any, error) {
+	path, err := parsePointer(o.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o.Op {
+	case "add", "replace", "test":
+		if o.Value == nil {
+			return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+		}
+		var value any
+		if err := json.Unmarshal(o.Value, &value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		switch o.Op {
+		case "add":
+			return add(root, path, value)
+		case "replace":
+			if root, _, err = remove(root, path); err != nil {
+				return nil, err
+			}
+			return add(root, path, value)
+		default:
+			current, err := get(root, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, ErrTestFailed
+			}
+			return root, nil
+		}
+	case "remove":
+		root, _, err = remove(root, path)
+		return root, err
+	case "move", "copy":
+		from, err := parsePointer(o.From)
+		if err != nil {
+			return nil, err
+		}
+		var value any
+		if o.Op == "move" {
+			if isPrefix(from, path) && len(from) < len(path) {
+				return nil, fmt.Errorf("%w: cannot move a value into itself", ErrInvalidPatch)
+			}
+			root, value, err = remove(root, from)
+		} else {
+			value, err = get(root, from)
+			value = deepCopy(value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, value)
+	default:
+		return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch, o.Op)
+	}
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: invalid pointer %q", ErrInvalidPatch, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	return len(prefix) <= len(path) && reflect.DeepEqual(prefix, path[:len(prefix)])
+}
+
+// get returns the value at path
+func get(node any, path []string) (any, error) {
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]any:
+			child, ok := n[token]
+			if !ok {
+				return nil, ErrPathNotFound
+			}
+			node = child
+		case []any:
+			i, err := index(token, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = n[i]
+		default:
+			return nil, ErrPathNotFound
+		}
+	}
+	return node, nil
+}
+
+// add adds value at path, inserting it into arrays, and returns the new root
+func add(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(root, path, func(container any, token string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			c[token] = value
+			return c, nil
+		case []any:
+			if token == "-" {
+				return append(c, value), nil
+			}
+			i, err := index(token, len(c))
+			if err != nil {
+				return nil, err
+			}
+			return append(c[:i], append([]any{value}, c[i:]...)...), nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+}
+
+// remove removes the value at path, returning the new root and the value removed
+func remove(root any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, root, nil
+	}
+	var removed any
+	root, err := update(root, path, func(container any, token string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			value, ok := c[token]
+			if !ok {
+				return nil, ErrPathNotFound
+			}
+			removed = value
+			delete(c, token)
+			return c, nil
+		case []any:
+			i, err := index(token, len(c)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = c[i]
+			return append(c[:i], c[i+1:]...), nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+	return root, removed, err
+}
+
+// update walks node down to the container of the last token of path, replaces that container
+// by what change returns and returns the new node
+func update(node any, path []string, change func(container any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return change(node, path[0])
+	}
+
+	switch n := node.(type) {
+	case map[string]any:
+		child, ok := n[path[0]]
+		if !ok {
+			return nil, ErrPathNotFound
+		}
+		child, err := update(child, path[1:], change)
+		if err != nil {
+			return nil, err
+		}
+		n[path[0]] = child
+		return n, nil
+	case []any:
+		i, err := index(path[0], len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		child, err := update(n[i], path[1:], change)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = child
+		return n, nil
+	default:
+		return nil, ErrPathNotFound
+	}
+}
+
+// index parses an array index token, which must not be greater than maxIndex
+func index(token string, maxIndex int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, ErrPathNotFound
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > maxIndex {
+		return 0, ErrPathNotFound
+	}
+	return i, nil
+}
+
+func deepCopy(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for key, child := range v {
+			c[key] = deepCopy(child)
+		}
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, child := range v {
+			c[i] = deepCopy(child)
+		}
+		return c
+	default:
+		return v
+	}
+}
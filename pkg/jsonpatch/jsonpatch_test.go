@@ -0,0 +1,97 @@
+package jsonpatch_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/pkg/jsonpatch"
+)
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name, doc, patch, want string
+	}{
+		{"replaces members", `{"a":"b","c":"d"}`, `{"a":"z"}`, `{"a":"z","c":"d"}`},
+		{"null removes members", `{"a":"b","c":"d"}`, `{"a":null}`, `{"c":"d"}`},
+		{"merges objects", `{"a":{"b":"c","d":"e"}}`, `{"a":{"d":null,"f":"g"}}`, `{"a":{"b":"c","f":"g"}}`},
+		{"replaces arrays", `{"a":["b","c"]}`, `{"a":["d"]}`, `{"a":["d"]}`},
+		{"non-object patch replaces the document", `{"a":"b"}`, `["c"]`, `["c"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonpatch.MergePatch([]byte(tt.doc), []byte(tt.patch))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+
+	_, err := jsonpatch.MergePatch([]byte(`{}`), []byte(`{`))
+	assert.ErrorIs(t, err, jsonpatch.ErrInvalidPatch)
+}
+
+func TestApply(t *testing.T) {
+	doc := `{"name":"ADMIN","permissions":["users","profiles"],"policy":{"min_length":8},"a/b":1}`
+	tests := []struct {
+		name, patch, want string
+	}{
+		{"add member", `[{"op":"add","path":"/require_2fa","value":true}]`,
+			`{"name":"ADMIN","permissions":["users","profiles"],"policy":{"min_length":8},"a/b":1,"require_2fa":true}`},
+		{"append to array", `[{"op":"add","path":"/permissions/-","value":"audit:read"}]`,
+			`{"name":"ADMIN","permissions":["users","profiles","audit:read"],"policy":{"min_length":8},"a/b":1}`},
+		{"insert into array", `[{"op":"add","path":"/permissions/0","value":"audit:read"}]`,
+			`{"name":"ADMIN","permissions":["audit:read","users","profiles"],"policy":{"min_length":8},"a/b":1}`},
+		{"remove from array", `[{"op":"remove","path":"/permissions/0"}]`,
+			`{"name":"ADMIN","permissions":["profiles"],"policy":{"min_length":8},"a/b":1}`},
+		{"remove member", `[{"op":"remove","path":"/policy"}]`,
+			`{"name":"ADMIN","permissions":["users","profiles"],"a/b":1}`},
+		{"replace escaped member", `[{"op":"replace","path":"/a~1b","value":null}]`,
+			`{"name":"ADMIN","permissions":["users","profiles"],"policy":{"min_length":8},"a/b":null}`},
+		{"move", `[{"op":"move","from":"/permissions/1","path":"/permissions/0"}]`,
+			`{"name":"ADMIN","permissions":["profiles","users"],"policy":{"min_length":8},"a/b":1}`},
+		{"copy", `[{"op":"copy","from":"/name","path":"/title"}]`,
+			`{"name":"ADMIN","title":"ADMIN","permissions":["users","profiles"],"policy":{"min_length":8},"a/b":1}`},
+		{"test then replace", `[{"op":"test","path":"/policy","value":{"min_length":8}},{"op":"replace","path":"/name","value":"ROOT"}]`,
+			`{"name":"ROOT","permissions":["users","profiles"],"policy":{"min_length":8},"a/b":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonpatch.Apply([]byte(doc), []byte(tt.patch))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestApply_Errors(t *testing.T) {
+	doc := []byte(`{"name":"ADMIN","permissions":["users"]}`)
+	tests := []struct {
+		name, patch string
+		want        error
+	}{
+		{"not an array", `{"op":"remove","path":"/name"}`, jsonpatch.ErrInvalidPatch},
+		{"unknown operation", `[{"op":"merge","path":"/name"}]`, jsonpatch.ErrInvalidPatch},
+		{"missing value", `[{"op":"add","path":"/name"}]`, jsonpatch.ErrInvalidPatch},
+		{"invalid pointer", `[{"op":"remove","path":"name"}]`, jsonpatch.ErrInvalidPatch},
+		{"move into itself", `[{"op":"move","from":"/permissions","path":"/permissions/0"}]`, jsonpatch.ErrInvalidPatch},
+		{"remove missing member", `[{"op":"remove","path":"/policy"}]`, jsonpatch.ErrPathNotFound},
+		{"replace missing member", `[{"op":"replace","path":"/policy","value":{}}]`, jsonpatch.ErrPathNotFound},
+		{"index out of range", `[{"op":"add","path":"/permissions/2","value":"x"}]`, jsonpatch.ErrPathNotFound},
+		{"leading zero index", `[{"op":"remove","path":"/permissions/00"}]`, jsonpatch.ErrPathNotFound},
+		{"failed test", `[{"op":"test","path":"/name","value":"ROOT"}]`, jsonpatch.ErrTestFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := jsonpatch.Apply(doc, []byte(tt.patch))
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestApply_IsAtomic(t *testing.T) {
+	doc := []byte(`{"permissions":["users"]}`)
+	_, err := jsonpatch.Apply(doc, []byte(`[{"op":"add","path":"/permissions/-","value":"profiles"},{"op":"test","path":"/name","value":"x"}]`))
+	require.ErrorIs(t, err, jsonpatch.ErrPathNotFound)
+	assert.JSONEq(t, `{"permissions":["users"]}`, string(doc))
+}
@@ -0,0 +1,259 @@
+// Package jwtx signs and verifies RS256 JWTs with a keyring of RSA keys,
+// allowing signing keys to be rotated without invalidating issued tokens.
+//
+// Every key is identified by its RFC 7638 thumbprint, carried in the "kid"
+// header of the tokens it signs. The active key signs new tokens; retired
+// keys only verify tokens issued before a rotation, until they are removed.
+//
+// Usage:
+//
+//	keys := jwtx.NewKeyring(activeKey, previousKey)
+//	token, _ := keys.Sign(jwt.MapClaims{"sub": "7"})
+//
+//	parsed, err := jwt.Parse(token, keys.Keyfunc, jwt.WithValidMethods(jwtx.ValidMethods))
+package jwtx
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeyStatus is the state of a key in a keyring
+type KeyStatus string
+
+const (
+	// KeyActive signs new tokens and verifies tokens
+	KeyActive KeyStatus = "active"
+	// KeyRetired only verifies tokens issued before a rotation
+	KeyRetired KeyStatus = "retired"
+)
+
+// Token types, carried in the typ claim so a token cannot be used in place of another
+const (
+	TypeAccess  = "access"
+	TypeRefresh = "refresh"
+)
+
+// ValidMethods lists the signing methods accepted when verifying tokens
+var ValidMethods = []string{jwt.SigningMethodRS256.Alg()}
+
+var (
+	// ErrNoActiveKey is returned when signing with a keyring without keys
+	ErrNoActiveKey = errors.New("jwtx: keyring has no active key")
+	// ErrUnknownKey is returned when a token names a key that is not in the keyring
+	ErrUnknownKey = errors.New("jwtx: unknown signing key")
+)
+
+// Key is an RSA signing key of a keyring
+type Key struct {
+	ID         string
+	Status     KeyStatus
+	PrivateKey *rsa.PrivateKey
+}
+
+// Keyring holds the signing keys of a token type
+type Keyring struct {
+	mu     sync.RWMutex
+	active *Key
+	keys   []*Key
+}
+
+// NewKeyring creates a keyring signing with active and still accepting tokens signed by retired.
+// A nil active key creates an empty keyring.
+func NewKeyring(active *rsa.PrivateKey, retired ...*rsa.PrivateKey) *Keyring {
+	k := &Keyring{}
+	if active != nil {
+		k.Rotate(active)
+	}
+	for _, key := range retired {
+		k.add(key, KeyRetired)
+	}
+	return k
+}
+
+// Rotate makes key the active key, retiring the previous one
+func (k *Keyring) Rotate(key *rsa.PrivateKey) *Key {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.active != nil {
+		k.active.Status = KeyRetired
+	}
+	k.active = k.addLocked(key, KeyActive)
+	k.active.Status = KeyActive
+	return k.active
+}
+
+// Remove drops a retired key; tokens it signed are no longer accepted
+func (k *Keyring) Remove(kid string) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for i, key := range k.keys {
+		if key.ID == kid && key.Status == KeyRetired {
+			k.keys = append(k.keys[:i], k.keys[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Active returns the key signing new tokens, or nil if there is none
+func (k *Keyring) Active() *Key {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.active
+}
+
+// Keys returns every key of the keyring, the active one first
+func (k *Keyring) Keys() []*Key {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return append([]*Key(nil), k.keys...)
+}
+
+// Sign signs the claims with the active key, naming it in the kid header
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	active := k.Active()
+	if active == nil {
+		return "", ErrNoActiveKey
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = active.ID
+	return token.SignedString(active.PrivateKey)
+}
+
+// Keyfunc selects the public key verifying a token by its kid header, to be used with jwt.Parse.
+// Tokens without kid, issued before keys were named, are checked against every key.
+func (k *Keyring) Keyfunc(token *jwt.Token) (any, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+		return nil, jwt.ErrTokenSignatureInvalid
+	}
+
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		set := jwt.VerificationKeySet{}
+		for _, key := range k.Keys() {
+			set.Keys = append(set.Keys, key.PrivateKey.Public())
+		}
+		return set, nil
+	}
+
+	for _, key := range k.Keys() {
+		if key.ID == kid {
+			return key.PrivateKey.Public(), nil
+		}
+	}
+	return nil, ErrUnknownKey
+}
+
+// JWKS returns the public keys of the keyring as a JSON Web Key Set (RFC 7517)
+func (k *Keyring) JWKS() *JWKS {
+	keys := k.Keys()
+	set := &JWKS{Keys: make([]JWK, 0, len(keys))}
+	for _, key := range keys {
+		set.Keys = append(set.Keys, NewJWK(key.ID, &key.PrivateKey.PublicKey))
+	}
+	return set
+}
+
+// add inserts a key unless it is already in the keyring
+func (k *Keyring) add(key *rsa.PrivateKey, status KeyStatus) *Key {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.addLocked(key, status)
+}
+
+// addLocked inserts a key unless it is already in the keyring; must be called with the lock held
+func (k *Keyring) addLocked(key *rsa.PrivateKey, status KeyStatus) *Key {
+	kid := Thumbprint(&key.PublicKey)
+	for i, existing := range k.keys {
+		if existing.ID == kid {
+			if status == KeyActive {
+				// Keep the active key first
+				k.keys = append(append([]*Key{existing}, k.keys[:i]...), k.keys[i+1:]...)
+			}
+			return existing
+		}
+	}
+
+	entry := &Key{ID: kid, Status: status, PrivateKey: key}
+	if status == KeyActive {
+		k.keys = append([]*Key{entry}, k.keys...)
+	} else {
+		k.keys = append(k.keys, entry)
+	}
+	return entry
+}
+
+// JWK is the public part of an RSA signing key (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty" example:"RSA"`
+	Use       string `json:"use" example:"sig"`
+	Algorithm string `json:"alg" example:"RS256"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e" example:"AQAB"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK creates the JWK of an RSA public key
+func NewJWK(kid string, key *rsa.PublicKey) JWK {
+	return JWK{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: jwt.SigningMethodRS256.Alg(),
+		KeyID:     kid,
+		Modulus:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// PublicKey decodes the RSA public key of the JWK
+func (j JWK) PublicKey() (*rsa.PublicKey, error) {
+	if j.KeyType != "RSA" {
+		return nil, fmt.Errorf("jwtx: unsupported key type %q", j.KeyType)
+	}
+
+	n, err := base64.RawURLEncoding.DecodeString(j.Modulus)
+	if err != nil {
+		return nil, fmt.Errorf("jwtx: invalid modulus: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(j.Exponent)
+	if err != nil {
+		return nil, fmt.Errorf("jwtx: invalid exponent: %w", err)
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 {
+		return nil, errors.New("jwtx: invalid RSA key")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+// Thumbprint returns the RFC 7638 SHA-256 thumbprint of an RSA public key, used as its kid
+func Thumbprint(key *rsa.PublicKey) string {
+	jwk := NewJWK("", key)
+	// Required members only, in lexicographic order, without whitespace
+	canonical, _ := json.Marshal(struct {
+		E   string `json:"e"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+	}{E: jwk.Exponent, Kty: jwk.KeyType, N: jwk.Modulus})
+
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
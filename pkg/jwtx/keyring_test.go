@@ -0,0 +1,113 @@
+package jwtx_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/pkg/jwtx"
+)
+
+func newKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func parse(keys *jwtx.Keyring, token string) error {
+	_, err := jwt.Parse(token, keys.Keyfunc, jwt.WithValidMethods(jwtx.ValidMethods))
+	return err
+}
+
+func TestThumbprint_RFC7638(t *testing.T) {
+	n, err := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	require.NoError(t, err)
+	key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", jwtx.Thumbprint(key))
+}
+
+func TestKeyring_SignCarriesKid(t *testing.T) {
+	keys := jwtx.NewKeyring(newKey(t))
+
+	token, err := keys.Sign(jwt.MapClaims{"sub": "7"})
+	require.NoError(t, err)
+
+	parsed, err := jwt.Parse(token, keys.Keyfunc, jwt.WithValidMethods(jwtx.ValidMethods))
+	require.NoError(t, err)
+	assert.Equal(t, keys.Active().ID, parsed.Header["kid"])
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	first, second := newKey(t), newKey(t)
+	keys := jwtx.NewKeyring(first)
+
+	oldToken, err := keys.Sign(jwt.MapClaims{"sub": "7"})
+	require.NoError(t, err)
+
+	previous := keys.Active()
+	keys.Rotate(second)
+	assert.Equal(t, jwtx.KeyRetired, previous.Status)
+	assert.Equal(t, jwtx.Thumbprint(&second.PublicKey), keys.Active().ID)
+
+	newToken, err := keys.Sign(jwt.MapClaims{"sub": "7"})
+	require.NoError(t, err)
+
+	assert.NoError(t, parse(keys, oldToken), "tokens of retired keys stay valid")
+	assert.NoError(t, parse(keys, newToken))
+
+	assert.False(t, keys.Remove(keys.Active().ID), "the active key cannot be removed")
+	assert.True(t, keys.Remove(previous.ID))
+	assert.ErrorIs(t, parse(keys, oldToken), jwtx.ErrUnknownKey)
+	assert.NoError(t, parse(keys, newToken))
+}
+
+func TestKeyring_TokensWithoutKid(t *testing.T) {
+	active, retired, other := newKey(t), newKey(t), newKey(t)
+	keys := jwtx.NewKeyring(active, retired)
+
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "7"}).SignedString(retired)
+	require.NoError(t, err)
+	assert.NoError(t, parse(keys, legacy))
+
+	foreign, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "7"}).SignedString(other)
+	require.NoError(t, err)
+	assert.Error(t, parse(keys, foreign))
+}
+
+func TestKeyring_JWKS(t *testing.T) {
+	active, retired := newKey(t), newKey(t)
+	keys := jwtx.NewKeyring(active, retired)
+
+	set := keys.JWKS()
+	require.Len(t, set.Keys, 2)
+	assert.Equal(t, jwtx.Thumbprint(&active.PublicKey), set.Keys[0].KeyID, "the active key comes first")
+	assert.Equal(t, jwtx.Thumbprint(&retired.PublicKey), set.Keys[1].KeyID)
+	assert.Equal(t, "RSA", set.Keys[0].KeyType)
+	assert.Equal(t, "RS256", set.Keys[0].Algorithm)
+	assert.Equal(t, "AQAB", set.Keys[0].Exponent)
+}
+
+func TestJWK_PublicKey(t *testing.T) {
+	key := newKey(t)
+
+	decoded, err := jwtx.NewJWK("", &key.PublicKey).PublicKey()
+	require.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(decoded))
+
+	_, err = jwtx.JWK{KeyType: "EC"}.PublicKey()
+	assert.Error(t, err)
+}
+
+func TestKeyring_Empty(t *testing.T) {
+	keys := jwtx.NewKeyring(nil)
+
+	_, err := keys.Sign(jwt.MapClaims{"sub": "7"})
+	assert.ErrorIs(t, err, jwtx.ErrNoActiveKey)
+}
@@ -0,0 +1,394 @@
+// Package oidc implements the relying party side of OpenID Connect: provider
+// discovery, the authorization code flow with PKCE (RFC 7636) and the
+// verification of ID tokens against the provider's published keys. Its
+// protocol types (metadata, token responses and errors) are also used by
+// the API's own provider endpoints.
+//
+// Usage:
+//
+//	client := oidc.NewClient(oidc.Config{Issuer: "https://idp.example.com", ClientID: "api", ...})
+//
+//	state, _ := oidc.RandomString()
+//	nonce, _ := oidc.RandomString()
+//	verifier, _ := oidc.RandomString()
+//	url, _ := client.AuthCodeURL(ctx, state, nonce, verifier)
+//
+//	// Back on the redirect URL, with the same nonce and verifier
+//	tokens, _ := client.Exchange(ctx, code, verifier)
+//	claims, err := client.VerifyIDToken(ctx, tokens.IDToken, nonce)
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/raulaguila/go-api/pkg/jwtx"
+)
+
+const (
+	// DiscoveryPath is where providers publish their metadata, relative to the issuer
+	DiscoveryPath = "/.well-known/openid-configuration"
+
+	// keysRefreshInterval limits how often unknown key IDs trigger a new fetch of the provider's keys
+	keysRefreshInterval = time.Minute
+
+	randomBytes = 32
+)
+
+// Error codes of OAuth 2.0 (RFC 6749, RFC 6750 and RFC 7009)
+const (
+	ErrorInvalidRequest          = "invalid_request"
+	ErrorInvalidClient           = "invalid_client"
+	ErrorInvalidGrant            = "invalid_grant"
+	ErrorInvalidScope            = "invalid_scope"
+	ErrorInvalidToken            = "invalid_token"
+	ErrorUnauthorizedClient      = "unauthorized_client"
+	ErrorUnsupportedGrantType    = "unsupported_grant_type"
+	ErrorUnsupportedResponseType = "unsupported_response_type"
+	ErrorAccessDenied            = "access_denied"
+	ErrorInsufficientScope       = "insufficient_scope"
+	ErrorServerError             = "server_error"
+)
+
+var (
+	// ErrIssuerMismatch is returned when the discovered issuer differs from the configured one
+	ErrIssuerMismatch = errors.New("oidc: issuer mismatch")
+	// ErrMissingIDToken is returned when the token response has no ID token
+	ErrMissingIDToken = errors.New("oidc: token response without id_token")
+	// ErrNonceMismatch is returned when the ID token was not issued for the login being completed
+	ErrNonceMismatch = errors.New("oidc: nonce mismatch")
+	// ErrUnknownKey is returned when the ID token is signed by a key the provider does not publish
+	ErrUnknownKey = errors.New("oidc: unknown signing key")
+)
+
+// Config holds the registration of a client at a provider
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string      // Defaults to openid, email and profile
+	Leeway       time.Duration // Clock skew tolerated on ID token times
+	HTTPClient   *http.Client  // Defaults to http.DefaultClient
+}
+
+// Metadata is the provider metadata (OpenID Connect Discovery 1.0). The client
+// only relies on the issuer and the endpoints.
+type Metadata struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint,omitempty"`
+	RevocationEndpoint                string   `json:"revocation_endpoint,omitempty"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ResponseTypesSupported            []string `json:"response_types_supported,omitempty"`
+	GrantTypesSupported               []string `json:"grant_types_supported,omitempty"`
+	SubjectTypesSupported             []string `json:"subject_types_supported,omitempty"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported,omitempty"`
+	ScopesSupported                   []string `json:"scopes_supported,omitempty"`
+	ClaimsSupported                   []string `json:"claims_supported,omitempty"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported,omitempty"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported,omitempty"`
+}
+
+// Tokens is the successful response of the token endpoint
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// Error is an error response of the provider (RFC 6749 section 5.2)
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("oidc: %s: %s", e.Code, e.Description)
+	}
+	return "oidc: " + e.Code
+}
+
+// Claims are the verified claims of an ID token
+type Claims struct {
+	Subject string
+	Raw     map[string]any
+}
+
+// String returns a string claim, or an empty string when it is missing
+func (c *Claims) String(name string) string {
+	value, _ := c.Raw[name].(string)
+	return value
+}
+
+// Bool returns a boolean claim; some providers send booleans as strings
+func (c *Claims) Bool(name string) bool {
+	switch value := c.Raw[name].(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	}
+	return false
+}
+
+// Client is an OpenID Connect relying party of a single provider.
+// The provider metadata and keys are fetched on first use and cached.
+type Client struct {
+	config Config
+
+	mu          sync.Mutex
+	metadata    *Metadata
+	keys        map[string]*rsa.PublicKey
+	keysFetched time.Time
+}
+
+// NewClient creates a client for a provider
+func NewClient(config Config) *Client {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	config.Issuer = strings.TrimSuffix(config.Issuer, "/")
+	return &Client{config: config}
+}
+
+// Metadata returns the provider metadata, discovering it on first use
+func (c *Client) Metadata(ctx context.Context) (*Metadata, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.metadata != nil {
+		return c.metadata, nil
+	}
+
+	metadata := &Metadata{}
+	if err := c.getJSON(ctx, c.config.Issuer+DiscoveryPath, metadata); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(metadata.Issuer, "/") != c.config.Issuer {
+		return nil, ErrIssuerMismatch
+	}
+
+	c.metadata = metadata
+	return metadata, nil
+}
+
+// AuthCodeURL returns the URL of the provider's login page. The state is returned
+// unchanged to the redirect URL, the nonce is carried by the ID token and the
+// verifier must be kept to exchange the code.
+func (c *Client) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	metadata, err := c.Metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.config.ClientID},
+		"redirect_uri":          {c.config.RedirectURL},
+		"scope":                 {strings.Join(c.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {Challenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return metadata.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange redeems an authorization code at the token endpoint
+func (c *Client) Exchange(ctx context.Context, code, verifier string) (*Tokens, error) {
+	metadata, err := c.Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.config.RedirectURL},
+		"code_verifier": {verifier},
+		"client_id":     {c.config.ClientID},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.config.ClientID), url.QueryEscape(c.config.ClientSecret))
+	}
+
+	tokens := &Tokens{}
+	if err := c.do(req, tokens); err != nil {
+		return nil, err
+	}
+	if tokens.IDToken == "" {
+		return nil, ErrMissingIDToken
+	}
+	return tokens, nil
+}
+
+// VerifyIDToken checks the signature, issuer, audience, times and nonce of an ID token
+func (c *Client) VerifyIDToken(ctx context.Context, raw, nonce string) (*Claims, error) {
+	metadata, err := c.Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := jwt.Parse(raw, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return c.key(ctx, kid)
+	},
+		jwt.WithValidMethods(jwtx.ValidMethods),
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(c.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(c.config.Leeway),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	// Tokens issued to several clients name the one they were issued to
+	if audience, _ := claims.GetAudience(); len(audience) > 1 {
+		if azp, _ := claims["azp"].(string); azp != c.config.ClientID {
+			return nil, jwt.ErrTokenInvalidAudience
+		}
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, jwt.ErrTokenInvalidSubject
+	}
+
+	return &Claims{Subject: subject, Raw: claims}, nil
+}
+
+// key returns the provider key with the given ID, fetching the provider's keys
+// again when the ID is unknown, since providers rotate their keys
+func (c *Client) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key := c.lookupKey(kid); key != nil {
+		return key, nil
+	}
+	if time.Since(c.keysFetched) < keysRefreshInterval {
+		return nil, ErrUnknownKey
+	}
+
+	set := &jwtx.JWKS{}
+	if err := c.getJSON(ctx, c.metadata.JWKSURI, set); err != nil {
+		return nil, err
+	}
+
+	c.keys = make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if key, err := jwk.PublicKey(); err == nil {
+			c.keys[jwk.KeyID] = key
+		}
+	}
+	c.keysFetched = time.Now()
+
+	if key := c.lookupKey(kid); key != nil {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// lookupKey finds a cached key; tokens without kid are accepted from providers publishing a single key.
+// Must be called with the lock held.
+func (c *Client) lookupKey(kid string) *rsa.PublicKey {
+	if kid == "" && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key
+		}
+	}
+	return c.keys[kid]
+}
+
+// getJSON fetches a JSON document
+func (c *Client) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	return c.do(req, v)
+}
+
+// do sends a request and decodes its JSON response, or the provider's error
+func (c *Client) do(req *http.Request, v any) error {
+	resp, err := c.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		providerErr := &Error{}
+		if err := json.NewDecoder(resp.Body).Decode(providerErr); err != nil || providerErr.Code == "" {
+			return fmt.Errorf("oidc: %s %s: unexpected status %d", req.Method, req.URL, resp.StatusCode)
+		}
+		return providerErr
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// RandomString returns a random URL-safe string, used as state, nonce and PKCE verifier
+func RandomString() (string, error) {
+	raw := make([]byte, randomBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Challenge returns the S256 PKCE challenge of a verifier
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
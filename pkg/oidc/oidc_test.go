@@ -0,0 +1,120 @@
+package oidc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/pkg/oidc"
+	"github.com/raulaguila/go-api/pkg/oidc/oidctest"
+)
+
+const redirectURL = "https://api.example.com/auth/oidc/corp/callback"
+
+func newClient(idp *oidctest.Server, secret string) *oidc.Client {
+	return oidc.NewClient(oidc.Config{
+		Issuer:       idp.Issuer(),
+		ClientID:     idp.ClientID,
+		ClientSecret: secret,
+		RedirectURL:  redirectURL,
+	})
+}
+
+func TestChallenge_RFC7636(t *testing.T) {
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", oidc.Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func TestClient_AuthorizationCodeFlow(t *testing.T) {
+	idp := oidctest.NewServer(t, "api", "s3cr3t")
+	client := newClient(idp, "s3cr3t")
+	ctx := context.Background()
+
+	authURL, err := client.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-of-at-least-43-characters-000000000")
+	require.NoError(t, err)
+
+	code, state := idp.Authorize(t, authURL, oidctest.User{Subject: "42", Email: "john@example.com", EmailVerified: true})
+	assert.Equal(t, "state-1", state)
+
+	tokens, err := client.Exchange(ctx, code, "verifier-of-at-least-43-characters-000000000")
+	require.NoError(t, err)
+
+	claims, err := client.VerifyIDToken(ctx, tokens.IDToken, "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, "42", claims.Subject)
+	assert.Equal(t, "john@example.com", claims.String("email"))
+	assert.True(t, claims.Bool("email_verified"))
+
+	_, err = client.Exchange(ctx, code, "verifier-of-at-least-43-characters-000000000")
+	var providerErr *oidc.Error
+	require.ErrorAs(t, err, &providerErr, "codes are single use")
+	assert.Equal(t, "invalid_grant", providerErr.Code)
+}
+
+func TestClient_ExchangeRequiresVerifier(t *testing.T) {
+	idp := oidctest.NewServer(t, "api", "s3cr3t")
+	client := newClient(idp, "s3cr3t")
+	ctx := context.Background()
+
+	authURL, err := client.AuthCodeURL(ctx, "state", "nonce", "the-right-verifier")
+	require.NoError(t, err)
+	code, _ := idp.Authorize(t, authURL, oidctest.User{Subject: "42"})
+
+	_, err = client.Exchange(ctx, code, "another-verifier")
+	var providerErr *oidc.Error
+	require.ErrorAs(t, err, &providerErr)
+	assert.Equal(t, "invalid_grant", providerErr.Code)
+}
+
+func TestClient_ExchangeRequiresSecret(t *testing.T) {
+	idp := oidctest.NewServer(t, "api", "s3cr3t")
+	client := newClient(idp, "wrong")
+	ctx := context.Background()
+
+	authURL, err := client.AuthCodeURL(ctx, "state", "nonce", "verifier")
+	require.NoError(t, err)
+	code, _ := idp.Authorize(t, authURL, oidctest.User{Subject: "42"})
+
+	_, err = client.Exchange(ctx, code, "verifier")
+	var providerErr *oidc.Error
+	require.ErrorAs(t, err, &providerErr)
+	assert.Equal(t, "invalid_client", providerErr.Code)
+}
+
+func TestClient_VerifyIDToken(t *testing.T) {
+	idp := oidctest.NewServer(t, "api", "s3cr3t")
+	client := newClient(idp, "s3cr3t")
+	ctx := context.Background()
+	user := oidctest.User{Subject: "42"}
+
+	token, err := idp.IDToken(user, "nonce", time.Minute)
+	require.NoError(t, err)
+	_, err = client.VerifyIDToken(ctx, token, "other-nonce")
+	assert.ErrorIs(t, err, oidc.ErrNonceMismatch, "tokens of another login are rejected")
+
+	expired, err := idp.IDToken(user, "nonce", -time.Minute)
+	require.NoError(t, err)
+	_, err = client.VerifyIDToken(ctx, expired, "nonce")
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+
+	otherClient, err := idp.IDToken(oidctest.User{Subject: "42", Extra: map[string]any{"aud": "other"}}, "nonce", time.Minute)
+	require.NoError(t, err)
+	_, err = client.VerifyIDToken(ctx, otherClient, "nonce")
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+
+	forged, err := oidctest.NewServer(t, "api", "s3cr3t").IDToken(user, "nonce", time.Minute)
+	require.NoError(t, err)
+	_, err = client.VerifyIDToken(ctx, forged, "nonce")
+	assert.Error(t, err, "tokens signed by another provider are rejected")
+}
+
+func TestClient_DiscoveryFailure(t *testing.T) {
+	idp := oidctest.NewServer(t, "api", "s3cr3t")
+	client := oidc.NewClient(oidc.Config{Issuer: idp.Issuer() + "/tenant", ClientID: "api"})
+
+	_, err := client.Metadata(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,256 @@
+// Package oidctest provides an in-process OpenID Connect provider for tests.
+// It implements discovery, the authorization code flow with PKCE and the
+// publication of its signing keys, and logs in whichever user the test chooses.
+//
+// Usage:
+//
+//	idp := oidctest.NewServer(t, "client", "secret")
+//	client := oidc.NewClient(oidc.Config{Issuer: idp.Issuer(), ClientID: "client", ...})
+//
+//	authURL, _ := client.AuthCodeURL(ctx, state, nonce, verifier)
+//	code, returnedState := idp.Authorize(t, authURL, oidctest.User{Subject: "42", Email: "john@example.com"})
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/raulaguila/go-api/pkg/jwtx"
+	"github.com/raulaguila/go-api/pkg/oidc"
+)
+
+// User is the identity the provider logs in
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Username      string
+	Extra         map[string]any // Additional ID token claims
+}
+
+// grant is an issued authorization code waiting to be redeemed
+type grant struct {
+	user        User
+	redirectURI string
+	nonce       string
+	challenge   string
+}
+
+// Server is a fake OpenID Connect provider
+type Server struct {
+	ClientID     string
+	ClientSecret string
+	Keys         *jwtx.Keyring
+
+	server *httptest.Server
+	mu     sync.Mutex
+	grants map[string]grant
+}
+
+// NewServer starts a provider with a single registered client, stopped when the test ends
+func NewServer(t testing.TB, clientID, clientSecret string) *Server {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Keys:         jwtx.NewKeyring(key),
+		grants:       make(map[string]grant),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+oidc.DiscoveryPath, s.discovery)
+	mux.HandleFunc("GET /authorize", s.authorize)
+	mux.HandleFunc("POST /token", s.token)
+	mux.HandleFunc("GET /jwks", s.jwks)
+
+	s.server = httptest.NewServer(mux)
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// Issuer returns the issuer identifier of the provider
+func (s *Server) Issuer() string {
+	return s.server.URL
+}
+
+// Authorize plays the browser: it follows an authorization URL, logging in user,
+// and returns the code and state the provider sent back to the redirect URL
+func (s *Server) Authorize(t testing.TB, authURL string, user User) (code, state string) {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	query.Set("login_hint", user.Subject)
+	u.RawQuery = query.Encode()
+
+	s.mu.Lock()
+	s.grants["pending:"+user.Subject] = grant{user: user}
+	s.mu.Unlock()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("oidctest: authorization failed with status %d", resp.StatusCode)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+// IDToken signs an ID token for user, for tests of the verification itself
+func (s *Server) IDToken(user User, nonce string, lifetime time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":                s.Issuer(),
+		"sub":                user.Subject,
+		"aud":                s.ClientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(lifetime).Unix(),
+		"nonce":              nonce,
+		"email":              user.Email,
+		"email_verified":     user.EmailVerified,
+		"name":               user.Name,
+		"preferred_username": user.Username,
+	}
+	for name, value := range user.Extra {
+		claims[name] = value
+	}
+	return s.Keys.Sign(claims)
+}
+
+// discovery serves the provider metadata
+func (s *Server) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, oidc.Metadata{
+		Issuer:                s.Issuer(),
+		AuthorizationEndpoint: s.Issuer() + "/authorize",
+		TokenEndpoint:         s.Issuer() + "/token",
+		JWKSURI:               s.Issuer() + "/jwks",
+	})
+}
+
+// authorize logs in the user named by login_hint and redirects back with a code
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != s.ClientID || query.Get("response_type") != "code" {
+		writeError(w, oidc.ErrorUnauthorizedClient)
+		return
+	}
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		writeError(w, oidc.ErrorInvalidRequest)
+		return
+	}
+
+	s.mu.Lock()
+	pending, ok := s.grants["pending:"+query.Get("login_hint")]
+	delete(s.grants, "pending:"+query.Get("login_hint"))
+	code, _ := oidc.RandomString()
+	if ok {
+		s.grants[code] = grant{
+			user:        pending.user,
+			redirectURI: query.Get("redirect_uri"),
+			nonce:       query.Get("nonce"),
+			challenge:   query.Get("code_challenge"),
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, oidc.ErrorAccessDenied)
+		return
+	}
+
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil {
+		writeError(w, oidc.ErrorInvalidRequest)
+		return
+	}
+	values := redirect.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	redirect.RawQuery = values.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// token redeems a code, once, for an ID token
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, _ := r.BasicAuth()
+	clientID, _ = url.QueryUnescape(clientID)
+	clientSecret, _ = url.QueryUnescape(clientSecret)
+	if clientID != s.ClientID || clientSecret != s.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, oidc.Error{Code: oidc.ErrorInvalidClient})
+		return
+	}
+
+	if r.PostFormValue("grant_type") != "authorization_code" {
+		writeError(w, oidc.ErrorUnsupportedGrantType)
+		return
+	}
+
+	s.mu.Lock()
+	g, ok := s.grants[r.PostFormValue("code")]
+	delete(s.grants, r.PostFormValue("code"))
+	s.mu.Unlock()
+
+	if !ok || g.redirectURI != r.PostFormValue("redirect_uri") || oidc.Challenge(r.PostFormValue("code_verifier")) != g.challenge {
+		writeError(w, oidc.ErrorInvalidGrant)
+		return
+	}
+
+	idToken, err := s.IDToken(g.user, g.nonce, time.Minute)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, oidc.Error{Code: oidc.ErrorServerError})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, oidc.Tokens{
+		AccessToken: "access-" + g.user.Subject,
+		TokenType:   "Bearer",
+		IDToken:     idToken,
+		ExpiresIn:   60,
+	})
+}
+
+// jwks publishes the signing keys
+func (s *Server) jwks(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.Keys.JWKS())
+}
+
+func writeError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, oidc.Error{Code: code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,196 @@
+package passwd
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Hasher hashes passwords into PHC strings and verifies passwords against them
+type Hasher interface {
+	// Hash returns the PHC string of password, with a new random salt
+	Hash(password string) (string, error)
+
+	// Verify checks if password matches hash
+	Verify(password, hash string) bool
+
+	// NeedsRehash checks if hash was made with another algorithm or other parameters
+	NeedsRehash(hash string) bool
+}
+
+// Algorithm is a Hasher for a single algorithm, which recognizes its own hashes
+type Algorithm interface {
+	Hasher
+
+	// Identifies checks if hash was made with the algorithm, whatever its parameters
+	Identifies(hash string) bool
+}
+
+// Argon2id hashes passwords with argon2id (RFC 9106) into PHC strings like
+// $argon2id$v=19$m=19456,t=2,p=1$<salt>$<key>
+type Argon2id struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2id holds the parameters recommended by OWASP
+var DefaultArgon2id = Argon2id{Memory: 19 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+// argon2idPrefix starts every argon2id hash
+const argon2idPrefix = "$argon2id$"
+
+// Hash implements Hasher
+func (a Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, a.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, a.Iterations, a.Memory, a.Parallelism, a.KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, a.Memory, a.Iterations, a.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify implements Hasher, using the parameters stored in hash
+func (a Argon2id) Verify(password, hash string) bool {
+	params, salt, key, err := parseArgon2id(hash)
+	if err != nil {
+		return false
+	}
+
+	computed := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(computed, key) == 1
+}
+
+// NeedsRehash implements Hasher
+func (a Argon2id) NeedsRehash(hash string) bool {
+	params, salt, key, err := parseArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	return params.Memory != a.Memory || params.Iterations != a.Iterations || params.Parallelism != a.Parallelism ||
+		uint32(len(salt)) != a.SaltLength || uint32(len(key)) != a.KeyLength
+}
+
+// Identifies implements Algorithm
+func (a Argon2id) Identifies(hash string) bool {
+	return strings.HasPrefix(hash, argon2idPrefix)
+}
+
+// parseArgon2id parses an argon2id PHC string into its parameters, salt and key
+func parseArgon2id(hash string) (Argon2id, []byte, []byte, error) {
+	var params Argon2id
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, fmt.Errorf("passwd: not an argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("passwd: unsupported argon2 version %q", parts[2])
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, fmt.Errorf("passwd: invalid argon2id parameters %q", parts[3])
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("passwd: invalid argon2id salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, fmt.Errorf("passwd: invalid argon2id key")
+	}
+
+	params.SaltLength, params.KeyLength = uint32(len(salt)), uint32(len(key))
+	return params, salt, key, nil
+}
+
+// Bcrypt hashes passwords with bcrypt, whose modular crypt format ($2a$10$...)
+// predates and is accepted as a PHC string. Passwords over 72 bytes cannot be hashed.
+type Bcrypt struct {
+	Cost int
+}
+
+// DefaultBcrypt holds the default bcrypt cost
+var DefaultBcrypt = Bcrypt{Cost: bcrypt.DefaultCost}
+
+// Hash implements Hasher
+func (b Bcrypt) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), b.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Verify implements Hasher
+func (b Bcrypt) Verify(password, hash string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// NeedsRehash implements Hasher
+func (b Bcrypt) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != b.Cost
+}
+
+// Identifies implements Algorithm
+func (b Bcrypt) Identifies(hash string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(hash, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// migratingHasher hashes with a preferred algorithm and verifies hashes of any accepted one
+type migratingHasher struct {
+	preferred Algorithm
+	accepted  []Algorithm
+}
+
+// NewHasher returns a Hasher that hashes with preferred and verifies hashes of
+// preferred and of the accepted algorithms. Every hash that is not of preferred,
+// with its current parameters, needs a rehash.
+//
+// Usage:
+//
+//	hasher := passwd.NewHasher(passwd.DefaultArgon2id, passwd.DefaultBcrypt)
+//	if hasher.Verify(password, hash) && hasher.NeedsRehash(hash) {
+//	    hash, err = hasher.Hash(password)
+//	}
+func NewHasher(preferred Algorithm, accepted ...Algorithm) Hasher {
+	return &migratingHasher{preferred: preferred, accepted: append([]Algorithm{preferred}, accepted...)}
+}
+
+// Hash implements Hasher
+func (h *migratingHasher) Hash(password string) (string, error) {
+	return h.preferred.Hash(password)
+}
+
+// Verify implements Hasher
+func (h *migratingHasher) Verify(password, hash string) bool {
+	for _, algorithm := range h.accepted {
+		if algorithm.Identifies(hash) {
+			return algorithm.Verify(password, hash)
+		}
+	}
+	return false
+}
+
+// NeedsRehash implements Hasher
+func (h *migratingHasher) NeedsRehash(hash string) bool {
+	return !h.preferred.Identifies(hash) || h.preferred.NeedsRehash(hash)
+}
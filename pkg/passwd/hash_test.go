@@ -0,0 +1,64 @@
+package passwd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/pkg/passwd"
+)
+
+func TestArgon2id(t *testing.T) {
+	hasher := passwd.DefaultArgon2id
+
+	hash, err := hasher.Hash("correct horse")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=19456,t=2,p=1$"), hash)
+	assert.True(t, hasher.Identifies(hash))
+
+	assert.True(t, hasher.Verify("correct horse", hash))
+	assert.False(t, hasher.Verify("battery staple", hash))
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	other, err := hasher.Hash("correct horse")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other, "every hash must have its own salt")
+
+	stronger := hasher
+	stronger.Iterations = 3
+	assert.True(t, stronger.NeedsRehash(hash))
+	assert.True(t, stronger.Verify("correct horse", hash), "hashes are verified with their own parameters")
+
+	assert.False(t, hasher.Verify("correct horse", "$argon2id$v=19$m=19456,t=2,p=1$broken"))
+}
+
+func TestBcrypt(t *testing.T) {
+	hasher := passwd.Bcrypt{Cost: 4}
+
+	hash, err := hasher.Hash("correct horse")
+	require.NoError(t, err)
+	assert.True(t, hasher.Identifies(hash))
+	assert.True(t, hasher.Verify("correct horse", hash))
+	assert.False(t, hasher.NeedsRehash(hash))
+	assert.True(t, passwd.Bcrypt{Cost: 5}.NeedsRehash(hash))
+}
+
+func TestNewHasher_MigratesAlgorithms(t *testing.T) {
+	legacy := passwd.Bcrypt{Cost: 4}
+	hasher := passwd.NewHasher(passwd.DefaultArgon2id, legacy)
+
+	old, err := legacy.Hash("correct horse")
+	require.NoError(t, err)
+	assert.True(t, hasher.Verify("correct horse", old), "hashes of accepted algorithms must be verified")
+	assert.True(t, hasher.NeedsRehash(old))
+
+	hash, err := hasher.Hash("correct horse")
+	require.NoError(t, err)
+	assert.True(t, passwd.DefaultArgon2id.Identifies(hash))
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	assert.False(t, hasher.Verify("correct horse", "plain"), "unknown formats never match")
+	assert.False(t, passwd.NewHasher(passwd.DefaultArgon2id).Verify("correct horse", old), "algorithms not accepted never match")
+}
@@ -0,0 +1,163 @@
+// Package passwd checks passwords against a configurable policy: length,
+// character classes, a denylist of common and breached passwords and
+// similarity to the account's username or email. It also hashes passwords
+// with argon2id or bcrypt into PHC strings, see NewHasher.
+//
+// Usage:
+//
+//	policy := passwd.Policy{MinLength: 10, RequireDigit: true, RejectCommon: true}
+//	if err := policy.Check(password, user.Username, user.Email); err != nil {
+//	    // err lists every rule the password breaks
+//	}
+//
+// History and MaxAgeDays are not about the password itself: they are enforced
+// by whoever keeps the previous hashes and the time of the last change.
+package passwd
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/raulaguila/go-api/pkg/validator"
+)
+
+// Policy holds the rules a new password must follow. The zero value only
+// enforces the absolute limits of validator.MinPasswordLength and validator.MaxPasswordLength.
+type Policy struct {
+	MinLength     int  `json:"min_length" example:"10"`
+	RequireUpper  bool `json:"require_upper"`
+	RequireLower  bool `json:"require_lower"`
+	RequireDigit  bool `json:"require_digit"`
+	RequireSymbol bool `json:"require_symbol"`
+	RejectCommon  bool `json:"reject_common"`  // Reject passwords of the embedded denylist
+	RejectSimilar bool `json:"reject_similar"` // Reject passwords containing the username or email, or contained in them
+	History       int  `json:"history"`        // Previous passwords that cannot be reused
+	MaxAgeDays    int  `json:"max_age_days"`   // Days before a password must be changed, zero never expires
+}
+
+// PolicyError lists the rules a password breaks
+type PolicyError struct {
+	Violations []string
+}
+
+// Error implements the error interface
+func (e *PolicyError) Error() string {
+	return strings.Join(e.Violations, "; ")
+}
+
+// similarMinLength is the shortest username or email part compared with passwords,
+// below which most passwords would be rejected by chance
+const similarMinLength = 3
+
+// IsZero checks if the policy sets no rule
+func (p Policy) IsZero() bool {
+	return p == Policy{}
+}
+
+// MaxAge returns how long a password lasts, zero when it never expires
+func (p Policy) MaxAge() time.Duration {
+	return time.Duration(p.MaxAgeDays) * 24 * time.Hour
+}
+
+// Check returns a *PolicyError when password breaks a rule of the policy. personal
+// holds the values it must not resemble, usually the username and email.
+func (p Policy) Check(password string, personal ...string) error {
+	var violations []string
+
+	length := len([]rune(password))
+	if minLength := max(p.MinLength, validator.MinPasswordLength); length < minLength {
+		violations = append(violations, fmt.Sprintf("password must have at least %d characters", minLength))
+	}
+	if length > validator.MaxPasswordLength {
+		violations = append(violations, fmt.Sprintf("password must have at most %d characters", validator.MaxPasswordLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	for _, class := range []struct {
+		required, present bool
+		name              string
+	}{
+		{p.RequireUpper, upper, "an uppercase letter"},
+		{p.RequireLower, lower, "a lowercase letter"},
+		{p.RequireDigit, digit, "a digit"},
+		{p.RequireSymbol, symbol, "a symbol"},
+	} {
+		if class.required && !class.present {
+			violations = append(violations, "password must contain "+class.name)
+		}
+	}
+
+	if p.RejectCommon && IsCommon(password) {
+		violations = append(violations, "password is too common")
+	}
+	if p.RejectSimilar && isSimilar(password, personal) {
+		violations = append(violations, "password must not resemble the username or email")
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+//go:embed common.txt
+var commonFile string
+
+// common is the denylist of common.txt, loaded on first use
+var common = sync.OnceValue(func() map[string]struct{} {
+	passwords := make(map[string]struct{})
+	scanner := bufio.NewScanner(strings.NewReader(commonFile))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			passwords[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return passwords
+})
+
+// IsCommon checks if a password is in the denylist of common and breached passwords
+func IsCommon(password string) bool {
+	_, found := common()[strings.ToLower(password)]
+	return found
+}
+
+// isSimilar checks if a password contains, or is contained in, one of the personal
+// values. Emails are also compared by their local part.
+func isSimilar(password string, personal []string) bool {
+	password = strings.ToLower(password)
+	for _, value := range personal {
+		value = strings.ToLower(strings.TrimSpace(value))
+		candidates := []string{value}
+		if local, _, found := strings.Cut(value, "@"); found {
+			candidates = append(candidates, local)
+		}
+
+		for _, candidate := range candidates {
+			if len(candidate) < similarMinLength {
+				continue
+			}
+			if strings.Contains(password, candidate) || strings.Contains(candidate, password) {
+				return true
+			}
+		}
+	}
+	return false
+}
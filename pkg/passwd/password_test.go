@@ -0,0 +1,69 @@
+package passwd_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/pkg/passwd"
+)
+
+// violations returns the rules a password breaks
+func violations(t *testing.T, policy passwd.Policy, password string, personal ...string) []string {
+	err := policy.Check(password, personal...)
+	if err == nil {
+		return nil
+	}
+
+	var policyErr *passwd.PolicyError
+	require.True(t, errors.As(err, &policyErr))
+	return policyErr.Violations
+}
+
+func TestPolicy_Length(t *testing.T) {
+	assert.Len(t, violations(t, passwd.Policy{}, "abc12"), 1, "the absolute minimum applies to every policy")
+	assert.Empty(t, violations(t, passwd.Policy{}, "abc123"))
+
+	policy := passwd.Policy{MinLength: 10}
+	assert.Equal(t, []string{"password must have at least 10 characters"}, violations(t, policy, "abcdefghi"))
+	assert.Empty(t, violations(t, policy, "ábcdéfghíj"), "length counts characters, not bytes")
+	assert.NotEmpty(t, violations(t, policy, strings.Repeat("a", 129)))
+}
+
+func TestPolicy_CharacterClasses(t *testing.T) {
+	policy := passwd.Policy{RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+
+	assert.Len(t, violations(t, policy, "abcdefgh"), 3)
+	assert.Equal(t, []string{"password must contain a symbol"}, violations(t, policy, "Abcdefg1"))
+	assert.Empty(t, violations(t, policy, "Abcdef1!"))
+}
+
+func TestPolicy_RejectCommon(t *testing.T) {
+	policy := passwd.Policy{RejectCommon: true}
+
+	assert.Equal(t, []string{"password is too common"}, violations(t, policy, "Password123"))
+	assert.Empty(t, violations(t, passwd.Policy{}, "Password123"))
+	assert.Empty(t, violations(t, policy, "correct horse battery staple"))
+	assert.True(t, passwd.IsCommon("QWERTY"))
+}
+
+func TestPolicy_RejectSimilar(t *testing.T) {
+	policy := passwd.Policy{RejectSimilar: true}
+
+	assert.NotEmpty(t, violations(t, policy, "johndoe2024", "johndoe", "john@example.com"))
+	assert.NotEmpty(t, violations(t, policy, "JOHN!2024x", "johndoe", "john@example.com"), "the local part of emails counts")
+	assert.NotEmpty(t, violations(t, policy, "example.com", "johndoe", "john@example.com"))
+	assert.Empty(t, violations(t, policy, "tr0ub4dor&3", "johndoe", "john@example.com"))
+	assert.Empty(t, violations(t, policy, "pa-jo-word", "jo", "jo@x.io"), "short values are ignored")
+}
+
+func TestPolicy_MaxAge(t *testing.T) {
+	assert.Equal(t, 90*24*time.Hour, passwd.Policy{MaxAgeDays: 90}.MaxAge())
+	assert.Zero(t, passwd.Policy{}.MaxAge())
+	assert.True(t, passwd.Policy{}.IsZero())
+	assert.False(t, passwd.Policy{History: 3}.IsZero())
+}
@@ -0,0 +1,93 @@
+// Package spreadsheet reads the rows of CSV and XLSX files as text, for imports.
+//
+// Usage:
+//
+//	format, err := spreadsheet.FormatOf(header.Filename)
+//	rows, err := spreadsheet.Read(file, format)
+package spreadsheet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Format is the format of a spreadsheet file
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+// ErrUnsupportedFormat is returned for files that are neither CSV nor XLSX
+var ErrUnsupportedFormat = errors.New("spreadsheet: unsupported format")
+
+// utf8BOM starts the CSV files some spreadsheet applications export
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// FormatOf returns the format of a file by the extension of its name
+func FormatOf(filename string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))); format {
+	case CSV, XLSX:
+		return format, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// Read reads every row of a spreadsheet, of its first sheet for XLSX files.
+// Rows may have fewer cells than others, as trailing empty cells can be left out.
+func Read(r io.Reader, format Format) ([][]string, error) {
+	switch format {
+	case CSV:
+		return readCSV(r)
+	case XLSX:
+		return readXLSX(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// readCSV reads a CSV file separated by commas or, as exported in locales using the comma as
+// decimal separator, by semicolons
+func readCSV(r io.Reader) ([][]string, error) {
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(len(utf8BOM)); err == nil && bytes.Equal(bom, utf8BOM) {
+		_, _ = buffered.Discard(len(utf8BOM))
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if header, _ := buffered.Peek(buffered.Size()); isSemicolonSeparated(header) {
+		reader.Comma = ';'
+	}
+	return reader.ReadAll()
+}
+
+// isSemicolonSeparated reports whether the first line of data separates its cells by semicolons
+func isSemicolonSeparated(data []byte) bool {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	return bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(","))
+}
+
+func readXLSX(r io.Reader) ([][]string, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, nil
+	}
+	return file.GetRows(sheets[0])
+}
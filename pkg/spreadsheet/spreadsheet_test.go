@@ -0,0 +1,61 @@
+package spreadsheet_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/raulaguila/go-api/pkg/spreadsheet"
+)
+
+func TestFormatOf(t *testing.T) {
+	format, err := spreadsheet.FormatOf("users.CSV")
+	require.NoError(t, err)
+	assert.Equal(t, spreadsheet.CSV, format)
+
+	format, err = spreadsheet.FormatOf("department/users.xlsx")
+	require.NoError(t, err)
+	assert.Equal(t, spreadsheet.XLSX, format)
+
+	_, err = spreadsheet.FormatOf("users.xls")
+	assert.ErrorIs(t, err, spreadsheet.ErrUnsupportedFormat)
+}
+
+func TestRead_CSV(t *testing.T) {
+	want := [][]string{{"name", "email"}, {"John Doe", "john@example.com"}, {"Jane Roe"}}
+
+	tests := map[string]string{
+		"commas":             "name,email\nJohn Doe,john@example.com\nJane Roe\n",
+		"semicolons":         "name;email\nJohn Doe;john@example.com\nJane Roe\n",
+		"byte order mark":    "\xef\xbb\xbfname,email\nJohn Doe,john@example.com\nJane Roe\n",
+		"quotes and padding": "\"name\", email\n\"John Doe\", john@example.com\r\nJane Roe\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			rows, err := spreadsheet.Read(strings.NewReader(data), spreadsheet.CSV)
+			require.NoError(t, err)
+			assert.Equal(t, want, rows)
+		})
+	}
+}
+
+func TestRead_XLSX(t *testing.T) {
+	file := excelize.NewFile()
+	require.NoError(t, file.SetSheetRow("Sheet1", "A1", &[]any{"name", "email", "profile_id"}))
+	require.NoError(t, file.SetSheetRow("Sheet1", "A2", &[]any{"John Doe", "john@example.com", 2}))
+	var data bytes.Buffer
+	require.NoError(t, file.Write(&data))
+
+	rows, err := spreadsheet.Read(&data, spreadsheet.XLSX)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"name", "email", "profile_id"}, {"John Doe", "john@example.com", "2"}}, rows)
+}
+
+func TestRead_Unsupported(t *testing.T) {
+	_, err := spreadsheet.Read(strings.NewReader(""), spreadsheet.Format("xls"))
+	assert.ErrorIs(t, err, spreadsheet.ErrUnsupportedFormat)
+}
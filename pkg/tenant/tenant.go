@@ -0,0 +1,48 @@
+// Package tenant carries the organization a request acts for through a context.Context,
+// so that repositories can scope every query to it.
+//
+// A context without an organization is unscoped: it belongs to a super-admin, who sees
+// every organization, or to the API itself (e.g. while logging a user in).
+//
+// Usage:
+//
+//	ctx = tenant.WithOrganization(ctx, user.OrganizationID)
+//	if id, ok := tenant.Organization(ctx); ok {
+//	    query = query.Where("organization_id = ?", id)
+//	}
+package tenant
+
+import "context"
+
+// key is the context key of the organization ID
+type key struct{}
+
+// WithOrganization returns a copy of ctx scoped to an organization; a nil id leaves ctx unscoped
+func WithOrganization(ctx context.Context, id *uint) context.Context {
+	if id == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, key{}, *id)
+}
+
+// Organization returns the organization ctx is scoped to, if any
+func Organization(ctx context.Context) (uint, bool) {
+	id, ok := ctx.Value(key{}).(uint)
+	return id, ok
+}
+
+// Allows checks if a record of an organization (nil for records of no organization)
+// may be seen within ctx. Unscoped contexts see every record.
+func Allows(ctx context.Context, organizationID *uint) bool {
+	id, ok := Organization(ctx)
+	return !ok || (organizationID != nil && *organizationID == id)
+}
+
+// Resolve returns the organization new records created within ctx belong to: the one ctx is
+// scoped to, or, in unscoped contexts, the requested one
+func Resolve(ctx context.Context, requested *uint) *uint {
+	if id, ok := Organization(ctx); ok {
+		return &id
+	}
+	return requested
+}
@@ -0,0 +1,35 @@
+package tenant_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/raulaguila/go-api/pkg/tenant"
+)
+
+func TestOrganization(t *testing.T) {
+	acme, globex := uint(1), uint(2)
+	unscoped := context.Background()
+	scoped := tenant.WithOrganization(unscoped, &acme)
+
+	_, ok := tenant.Organization(unscoped)
+	assert.False(t, ok)
+	id, ok := tenant.Organization(scoped)
+	assert.True(t, ok)
+	assert.Equal(t, acme, id)
+
+	_, ok = tenant.Organization(tenant.WithOrganization(unscoped, nil))
+	assert.False(t, ok, "users of no organization stay unscoped")
+
+	assert.True(t, tenant.Allows(scoped, &acme))
+	assert.False(t, tenant.Allows(scoped, &globex))
+	assert.False(t, tenant.Allows(scoped, nil), "records of no organization belong to super-admins")
+	assert.True(t, tenant.Allows(unscoped, &globex))
+	assert.True(t, tenant.Allows(unscoped, nil))
+
+	assert.Equal(t, &acme, tenant.Resolve(scoped, &globex), "organizations cannot create records for others")
+	assert.Equal(t, &globex, tenant.Resolve(unscoped, &globex))
+	assert.Nil(t, tenant.Resolve(unscoped, nil))
+}
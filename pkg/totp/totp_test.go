@@ -0,0 +1,69 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 appendix B test vectors (SHA1), truncated to 6 digits
+func TestCode_RFC6238Vectors(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(secret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+
+	now := time.Now()
+	code, _ := Code(secret, Step(now.Add(-Period)))
+
+	step, ok := Validate(secret, code, now)
+	if !ok || step != Step(now)-1 {
+		t.Errorf("Validate() = %d, %v, want previous step accepted", step, ok)
+	}
+
+	old, _ := Code(secret, Step(now.Add(-3*Period)))
+	if _, ok := Validate(secret, old, now); ok && old != code {
+		t.Error("Validate() accepted a code outside the allowed skew")
+	}
+
+	if _, ok := Validate(secret, "12345", now); ok {
+		t.Error("Validate() accepted a code with the wrong length")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("My API", "john@example.com", "JBSWY3DPEHPK3PXP")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/My%20API:john@example.com?") {
+		t.Errorf("URI() = %s", uri)
+	}
+	if !strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP") || !strings.Contains(uri, "issuer=My+API") {
+		t.Errorf("URI() = %s", uri)
+	}
+}
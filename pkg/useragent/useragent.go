@@ -0,0 +1,69 @@
+// Package useragent describes the device behind a User-Agent header in a few words,
+// enough for users to recognize where they are signed in.
+//
+// Usage:
+//
+//	useragent.Device("Mozilla/5.0 (Windows NT 10.0; Win64; x64) ... Chrome/120.0 Safari/537.36")
+//	// "Chrome on Windows"
+package useragent
+
+import "strings"
+
+// match maps a token found in a User-Agent to a name; the first match wins,
+// so tokens shared by several agents come after the more specific ones
+type match struct {
+	token string
+	name  string
+}
+
+var browsers = []match{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"SamsungBrowser/", "Samsung Internet"},
+	{"Firefox/", "Firefox"},
+	{"FxiOS/", "Firefox"},
+	{"CriOS/", "Chrome"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"curl/", "curl"},
+	{"PostmanRuntime/", "Postman"},
+	{"okhttp/", "OkHttp"},
+	{"Go-http-client/", "Go"},
+	{"python-requests/", "Python"},
+}
+
+var systems = []match{
+	{"Android", "Android"},
+	{"iPhone", "iOS"},
+	{"iPad", "iPadOS"},
+	{"Windows", "Windows"},
+	{"Mac OS X", "macOS"},
+	{"CrOS", "ChromeOS"},
+	{"Linux", "Linux"},
+}
+
+// Device returns the browser and operating system of a User-Agent, like "Firefox on Linux",
+// or "Unknown device" when neither is recognized
+func Device(userAgent string) string {
+	browser, system := find(browsers, userAgent), find(systems, userAgent)
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	default:
+		return "Unknown device"
+	}
+}
+
+// find returns the name of the first match found in userAgent
+func find(matches []match, userAgent string) string {
+	for _, m := range matches {
+		if strings.Contains(userAgent, m.token) {
+			return m.name
+		}
+	}
+	return ""
+}